@@ -38,6 +38,7 @@ var (
 	pageSize      int
 	qps           int
 	testTypes     string
+	batchWriter   string
 	duration      time.Duration
 )
 
@@ -54,6 +55,7 @@ func init() {
 	hammerCmd.Flags().IntVar(&pageSize, "batch", 10, "Number of entries to process at once")
 	hammerCmd.Flags().IntVar(&maxWorkers, "workers", 1000, "Number of parallel workers. Best when workers = QPS * timeout")
 	hammerCmd.Flags().IntVar(&maxOperations, "operations", 10000, "Number of operations")
+	hammerCmd.Flags().StringVar(&batchWriter, "batch-writer", "", "Principal authorized to update any user in the directory, used to send batches")
 	hammerCmd.Flags().StringVarP(&masterPassword, "password", "p", "", "The master key to the local keyset")
 }
 
@@ -102,6 +104,7 @@ var hammerCmd = &cobra.Command{
 			BatchWriteQPS:   qps,
 			BatchWriteSize:  pageSize,
 			BatchWriteCount: maxOperations,
+			BatchWriter:     batchWriter,
 
 			WriteQPS:   qps,
 			WriteCount: maxOperations,
@@ -83,6 +83,7 @@ func init() {
 
 	RootCmd.PersistentFlags().String("log-key", "genfiles/trillian-log.pem", "Path to public key PEM for Trillian Log server")
 	RootCmd.PersistentFlags().String("map-key", "genfiles/trillian-map.pem", "Path to public key PEM for Trillian Map server")
+	RootCmd.PersistentFlags().String("receipt-key", "", "Path to public key PEM for receipts of the directory. If set, receipts are required")
 
 	RootCmd.PersistentFlags().String("client-secret", "", "Path to client_secret.json file for user creds")
 	RootCmd.PersistentFlags().String("fake-auth-userid", "", "userid to present to the server as identity for authentication. Only succeeds if fake auth is enabled on the server side.")
@@ -225,15 +226,41 @@ func GetClient(ctx context.Context) (*client.Client, error) {
 }
 
 // config selects a source for and returns the client configuration.
-func config(ctx context.Context, client pb.KeyTransparencyClient) (*pb.Directory, error) {
+func config(ctx context.Context, ktCli pb.KeyTransparencyClient) (*pb.Directory, error) {
 	autoConfig := viper.GetBool("autoconfig")
 	directory := viper.GetString("directory")
+	var config *pb.Directory
+	var err error
 	switch {
 	case autoConfig:
-		return client.GetDirectory(ctx, &pb.GetDirectoryRequest{DirectoryId: directory})
+		config, err = ktCli.GetDirectory(ctx, &pb.GetDirectoryRequest{DirectoryId: directory})
 	default:
-		return readConfigFromDisk()
+		config, err = readConfigFromDisk()
 	}
+	if err != nil {
+		return nil, err
+	}
+	if err := pinReceiptKey(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// pinReceiptKey pins the receipt key in --receipt-key, if set, in config.
+func pinReceiptKey(config *pb.Directory) error {
+	receiptPEMFile := viper.GetString("receipt-key")
+	if receiptPEMFile == "" {
+		return nil
+	}
+	receiptPubKey, err := pem.ReadPublicKeyFile(receiptPEMFile)
+	if err != nil {
+		return fmt.Errorf("error reading receipt public key %v: %v", receiptPEMFile, err)
+	}
+	receiptPubPB, err := der.ToPublicProto(receiptPubKey)
+	if err != nil {
+		return fmt.Errorf("error serializing receipt public key: %v", err)
+	}
+	return client.PinReceiptKey(config, receiptPubPB)
 }
 
 func readConfigFromDisk() (*pb.Directory, error) {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/google/trillian/crypto"
+	"github.com/google/trillian/crypto/keys/der"
 	"github.com/google/trillian/crypto/keys/pem"
 	"github.com/google/trillian/types"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -31,6 +32,7 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"github.com/google/keytransparency/cmd/serverutil"
+	"github.com/google/keytransparency/core/client"
 	"github.com/google/keytransparency/core/fake"
 	"github.com/google/keytransparency/core/monitor"
 	"github.com/google/keytransparency/core/monitorserver"
@@ -50,6 +52,7 @@ var (
 	ktURL              = flag.String("kt-url", "localhost:8080", "URL of key-server.")
 	insecure           = flag.Bool("insecure", false, "Skip TLS checks")
 	directoryID        = flag.String("directoryid", "", "KT Directory identifier to monitor")
+	receiptKey         = flag.String("receipt-key", "", "Path to the public key PEM that receipts of the directory must be signed with")
 
 	// TODO(ismail): expose prometheus metrics: a variable that tracks valid/invalid MHs
 	// metricsAddr = flag.String("metrics-addr", ":8081", "The ip:port to publish metrics on")
@@ -71,6 +74,20 @@ func main() {
 		glog.Exitf("Could not read directory info %v:", err)
 	}
 
+	if *receiptKey != "" {
+		pk, err := pem.ReadPublicKeyFile(*receiptKey)
+		if err != nil {
+			glog.Exitf("Could not read receipt key %v: %v", *receiptKey, err)
+		}
+		pkpb, err := der.ToPublicProto(pk)
+		if err != nil {
+			glog.Exitf("Invalid receipt key %v: %v", *receiptKey, err)
+		}
+		if err := client.PinReceiptKey(config, pkpb); err != nil {
+			glog.Exitf("Could not pin receipt key: %v", err)
+		}
+	}
+
 	// Read signing key:
 	key, err := pem.ReadPrivateKeyFile(*signingKey, *signingKeyPassword)
 	if err != nil {
@@ -94,7 +111,7 @@ func main() {
 	}()
 
 	// Monitor Server.
-	srv := monitorserver.New(store)
+	srv := monitorserver.New(store, mon)
 
 	// Create gRPC server.
 	creds, err := credentials.NewServerTLSFromFile(*certFile, *keyFile)
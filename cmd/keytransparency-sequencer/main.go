@@ -20,6 +20,7 @@ import (
 	"flag"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"time"
 
@@ -33,11 +34,16 @@ import (
 	"github.com/google/trillian/util/etcd"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/oauth"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/google/keytransparency/cmd/serverutil"
 	"github.com/google/keytransparency/core/adminserver"
 	"github.com/google/keytransparency/core/sequencer"
 	"github.com/google/keytransparency/core/sequencer/election"
+	"github.com/google/keytransparency/impl/authentication"
+	"github.com/google/keytransparency/impl/authorization"
+	"github.com/google/keytransparency/impl/events"
 	"github.com/google/keytransparency/impl/sql/directory"
 	"github.com/google/keytransparency/impl/sql/engine"
 	"github.com/google/keytransparency/impl/sql/mutationstorage"
@@ -45,23 +51,36 @@ import (
 	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
 	dir "github.com/google/keytransparency/core/directory"
 	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
+	sqlelection "github.com/google/keytransparency/impl/sql/election"
 	etcdelect "github.com/google/trillian/util/election2/etcd"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 
+	_ "github.com/google/keytransparency/core/mutator/entry" // Register mutator
 	_ "github.com/google/trillian/crypto/keys/der/proto"
 	_ "github.com/google/trillian/merkle/coniks"  // Register hasher
 	_ "github.com/google/trillian/merkle/rfc6962" // Register hasher
 )
 
 var (
-	keyFile     = flag.String("tls-key", "genfiles/server.key", "TLS private key file")
-	certFile    = flag.String("tls-cert", "genfiles/server.crt", "TLS cert file")
-	listenAddr  = flag.String("addr", ":8080", "The ip:port to serve on")
-	metricsAddr = flag.String("metrics-addr", ":8081", "The ip:port to publish metrics on")
+	keyFile       = flag.String("tls-key", "genfiles/server.key", "TLS private key file")
+	certFile      = flag.String("tls-cert", "genfiles/server.crt", "TLS cert file")
+	listenAddr    = flag.String("addr", ":8080", "The ip:port to serve on")
+	metricsAddr   = flag.String("metrics-addr", ":8081", "The ip:port to publish metrics on")
+	authType      = flag.String("auth-type", "google", "Sets the type of authentication required from admin API clients. Accepted values are google (oauth tokens) and insecure-fake (for testing only).")
+	authzPolicy   = flag.String("authz-policy", "", "Path to an AuthorizationPolicy text or JSON proto granting admin roles. The sequencer itself needs the operator role for all directories to prune queues")
+	selfPrincipal = flag.String("self-principal", "", "Identity of the application default credentials the sequencer prunes queues with. Required with --auth-type=google and --queue-retention")
+	authzReload   = flag.Duration("authz-policy-refresh", 10*time.Second, "How often to check --authz-policy for changes")
+	auditFile     = flag.String("audit-file", "", "File to append authentication and authorization decisions to, one JSON object per line")
+	auditSQL      = flag.Bool("audit-sql", false, "Record authentication and authorization decisions in the AuditLog table of --db")
 
 	forceMaster = flag.Bool("force_master", false, "If true, assume master for all directories")
 	etcdServers = flag.String("etcd_servers", "", "A comma-separated list of etcd servers; no etcd registration if empty")
 	lockDir     = flag.String("lock_file_path", "/keytransparency/master", "etcd lock file directory path")
+	sqlElection = flag.Bool("sql_election", false, "If true, elect masters with leases in the database given by --db instead of etcd")
+	leaseTTL    = flag.Duration("lease_duration", 10*time.Second, "Duration of mastership leases when --sql_election is set")
+	leaseSkew   = flag.Duration("lease_max_clock_skew", time.Second, "Maximum difference between the clocks of sequencers when --sql_election is set. Must be less than --lease_duration/3")
 
 	serverDBPath = flag.String("db", "db", "Database connection string")
 
@@ -69,8 +88,18 @@ var (
 	mapURL = flag.String("map-url", "", "URL of Trillian Map Server")
 	logURL = flag.String("log-url", "", "URL of Trillian Log Server for Signed Map Heads")
 
-	refresh   = flag.Duration("directory-refresh", 5*time.Second, "Time to detect new directory")
-	batchSize = flag.Int("batch-size", 100, "Maximum number of mutations to process per map revision")
+	refresh          = flag.Duration("directory-refresh", 5*time.Second, "Time to detect new directory")
+	batchSize        = flag.Int("batch-size", 100, "Maximum number of mutations to process per map revision")
+	maxLatency       = flag.Duration("max-latency", 0, "Maximum time a mutation may wait in the queue before a revision is created. 0 disables this limit")
+	batchConcurrency = flag.Int("batch-concurrency", 4, "Maximum number of directories to sequence at the same time")
+	batchTimeout     = flag.Duration("batch-timeout", sequencer.DefaultBatchTimeout, "Deadline for sequencing a single directory")
+
+	queueRetention   = flag.Duration("queue-retention", 0, "Time to keep published mutations in the queue before deleting them. 0 disables pruning")
+	queuePrunePeriod = flag.Duration("queue-prune-period", 1*time.Hour, "Time between runs of queue pruning")
+
+	eventWebhookURL     = flag.String("event-webhook-url", "", "URL to POST entry change events to after revisions are published")
+	eventWebhookTimeout = flag.Duration("event-webhook-timeout", events.DefaultWebhookTimeout, "Maximum time to wait for each POST to --event-webhook-url")
+	eventFile           = flag.String("event-file", "", "File to append entry change events to after revisions are published")
 )
 
 func openDB() *sql.DB {
@@ -84,15 +113,80 @@ func openDB() *sql.DB {
 	return db
 }
 
+// fakeSequencerPrincipal is the principal the sequencer calls its own admin API
+// as when --auth-type=insecure-fake.
+const fakeSequencerPrincipal = "keytransparency-sequencer"
+
+// getAuthentication returns the AuthFunc selected by flags.
+func getAuthentication() grpc_auth.AuthFunc {
+	switch *authType {
+	case "insecure-fake":
+		glog.Warning("INSECURE! Using fake authentication.")
+		return authentication.FakeAuthFunc
+	case "google":
+		gauth, err := authentication.NewGoogleAuth()
+		if err != nil {
+			glog.Exitf("Failed to create authentication library instance: %v", err)
+		}
+		return gauth.AuthFunc
+	default:
+		glog.Exitf("Invalid auth-type parameter: %v.", *authType)
+	}
+	return nil
+}
+
+// selfCredentials returns the credentials the sequencer uses to call its own
+// admin API, and the principal they authenticate as.
+func selfCredentials(ctx context.Context) (credentials.PerRPCCredentials, string) {
+	switch *authType {
+	case "insecure-fake":
+		return authentication.GetFakeCredential(fakeSequencerPrincipal), fakeSequencerPrincipal
+	case "google":
+		if *selfPrincipal == "" {
+			glog.Exit("--self-principal is required to prune queues with --auth-type=google")
+		}
+		creds, err := oauth.NewApplicationDefault(ctx, authentication.RequiredScopes...)
+		if err != nil {
+			glog.Exitf("Failed to load application default credentials: %v", err)
+		}
+		return creds, *selfPrincipal
+	default:
+		glog.Exitf("Invalid auth-type parameter: %v.", *authType)
+	}
+	return nil, ""
+}
+
+// checkSelfAuthorized exits unless authorizeAdmin lets principal prune the
+// queues of all directories, so that a missing role is reported at startup
+// rather than by every PruneQueue call.
+func checkSelfAuthorized(authorizeAdmin authorization.AuthzFunc, principal string) {
+	ctx := authentication.NewContext(context.Background(),
+		&authentication.SecurityContext{Email: principal, EmailVerified: true})
+	if err := authorizeAdmin(ctx, &pb.PruneQueueRequest{}); err != nil {
+		glog.Exitf("--queue-retention needs --authz-policy to give %v the operator role for all directories: %v",
+			principal, err)
+	}
+}
+
 // getElectionFactory returns an election factory based on flags, and a
 // function which releases the resources associated with the factory.
-func getElectionFactory() (election2.Factory, func()) {
+func getElectionFactory(db *sql.DB) (election2.Factory, func()) {
+	hostname, _ := os.Hostname()
+	instanceID := fmt.Sprintf("%s.%d", hostname, os.Getpid())
+
 	if *forceMaster {
 		glog.Warning("Acting as master for all directories")
 		return election2.NoopFactory{}, func() {}
 	}
+	if *sqlElection {
+		factory, err := sqlelection.NewFactory(db, instanceID, *leaseTTL, *leaseSkew)
+		if err != nil {
+			glog.Exitf("Failed to create SQL election factory: %v", err)
+		}
+		return factory, func() {}
+	}
 	if len(*etcdServers) == 0 {
-		glog.Exit("Either --force_master or --etcd_servers must be supplied")
+		glog.Exit("One of --force_master, --sql_election or --etcd_servers must be supplied")
 	}
 
 	cli, err := etcd.NewClientFromString(*etcdServers)
@@ -105,8 +199,6 @@ func getElectionFactory() (election2.Factory, func()) {
 		}
 	}
 
-	hostname, _ := os.Hostname()
-	instanceID := fmt.Sprintf("%s.%d", hostname, os.Getpid())
 	factory := etcdelect.NewFactory(instanceID, cli, *lockDir)
 
 	return factory, closeFn
@@ -141,9 +233,27 @@ func main() {
 		glog.Exitf("Failed to create directory storage object: %v", err)
 	}
 
+	authFunc := getAuthentication()
+	authorizeAdmin := (&authorization.AuthzPolicy{}).AuthorizeAdmin
+	if *authzPolicy != "" {
+		policy, err := authorization.NewPolicyFile(*authzPolicy, prometheus.MetricFactory{})
+		if err != nil {
+			glog.Exitf("Failed to read authorization policy: %v", err)
+		}
+		go policy.Watch(ctx, *authzReload)
+		authorizeAdmin = policy.AuthorizeAdmin
+	}
+	adminAuth := authorization.AdminAuthPairs(authFunc, authorizeAdmin)
+	audits, err := serverutil.AuditSink(*auditFile, *auditSQL, sqldb)
+	if err != nil {
+		glog.Exitf("Failed to open audit sink: %v", err)
+	}
 	grpcServer := grpc.NewServer(
 		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
-		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+			grpc_prometheus.UnaryServerInterceptor,
+			authorization.UnaryServerInterceptor(adminAuth, audits),
+		)),
 	)
 
 	// Listen and create empty grpc client connection.
@@ -159,21 +269,53 @@ func main() {
 	}
 	dopts := []grpc.DialOption{grpc.WithTransportCredentials(tcreds)}
 	addr := lis.Addr().String()
-	conn, err := grpc.DialContext(ctx, addr, dopts...)
+	// The gateway forwards the credentials of each HTTP request, so only the
+	// sequencer's own connection carries the sequencer's credentials. They
+	// are only needed to prune queues through the admin API.
+	selfOpts := []grpc.DialOption{grpc.WithTransportCredentials(tcreds)}
+	if *queueRetention > 0 {
+		selfCreds, principal := selfCredentials(ctx)
+		checkSelfAuthorized(authorizeAdmin, principal)
+		selfOpts = append(selfOpts, grpc.WithPerRPCCredentials(selfCreds))
+	}
+	conn, err := grpc.DialContext(ctx, addr, selfOpts...)
 	if err != nil {
 		glog.Exitf("error connecting to %v: %v", addr, err)
 	}
 	defer conn.Close()
 
-	spb.RegisterKeyTransparencySequencerServer(grpcServer, sequencer.NewServer(
+	// The sequencer service has no authorization of its own, so it is only
+	// served to this process, on a loopback listener.
+	seqServer := grpc.NewServer(
+		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
+		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
+	)
+	seqLis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		glog.Exitf("error creating sequencer listener: %v", err)
+	}
+	seqConn, err := grpc.DialContext(ctx, seqLis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		glog.Exitf("error connecting to %v: %v", seqLis.Addr(), err)
+	}
+	defer seqConn.Close()
+
+	ssvr := sequencer.NewServer(
 		directoryStorage,
 		trillian.NewTrillianLogClient(lconn),
 		trillian.NewTrillianMapClient(mconn),
-		mutations, mutations,
-		spb.NewKeyTransparencySequencerClient(conn),
-		prometheus.MetricFactory{}))
+		trillian.NewTrillianAdminClient(mconn),
+		mutations, mutations, mutations,
+		spb.NewKeyTransparencySequencerClient(seqConn),
+		prometheus.MetricFactory{})
+	if sink := eventSink(); sink != nil {
+		ssvr.EventSink = sink
+		ssvr.EventCursors = mutations
+		go ssvr.RunEvents(ctx)
+	}
+	spb.RegisterKeyTransparencySequencerServer(seqServer, ssvr)
 
-	pb.RegisterKeyTransparencyAdminServer(grpcServer, adminserver.New(
+	asvr := adminserver.New(
 		trillian.NewTrillianLogClient(lconn),
 		trillian.NewTrillianMapClient(mconn),
 		trillian.NewTrillianAdminClient(lconn),
@@ -182,38 +324,83 @@ func main() {
 		mutations,
 		func(ctx context.Context, spec *keyspb.Specification) (proto.Message, error) {
 			return der.NewProtoFromSpec(spec)
-		}))
+		})
+	asvr.Rebuilder = ssvr
+	if ssvr.EventSink != nil {
+		asvr.EventCursors = mutations
+	}
+	pb.RegisterKeyTransparencyAdminServer(grpcServer, asvr)
+	if err := authorization.CheckAllMethods(grpcServer.GetServiceInfo(), authorization.AdminService, adminAuth, nil); err != nil {
+		glog.Exitf("Authorization self-check failed: %v", err)
+	}
 
 	reflection.Register(grpcServer)
 	grpc_prometheus.Register(grpcServer)
+	grpc_prometheus.Register(seqServer)
 	grpc_prometheus.EnableHandlingTimeHistogram()
 
 	glog.Infof("Signer starting")
 
 	// Run servers
 	go serveHTTPMetric(*metricsAddr)
+	go func() {
+		if err := seqServer.Serve(seqLis); err != nil {
+			glog.Errorf("Sequencer service: %v", err)
+		}
+	}()
 	go serveHTTPGateway(ctx, lis, dopts, grpcServer,
 		pb.RegisterKeyTransparencyAdminHandlerFromEndpoint,
 	)
-	runSequencer(ctx, conn, mconn, directoryStorage)
+	runSequencer(ctx, seqConn, conn, directoryStorage, sqldb)
 
 	// Shutdown.
 	glog.Errorf("Signer exiting")
 }
 
-func runSequencer(ctx context.Context, conn, mconn *grpc.ClientConn,
-	directoryStorage dir.Storage) {
-	electionFactory, closeFactory := getElectionFactory()
+// eventSink returns the sink selected by flags, or nil if events are disabled.
+func eventSink() sequencer.EventSink {
+	switch {
+	case *eventWebhookURL != "" && *eventFile != "":
+		glog.Exitf("Only one of --event-webhook-url and --event-file may be set")
+	case *eventWebhookURL != "":
+		return events.NewWebhook(*eventWebhookURL, &http.Client{Timeout: *eventWebhookTimeout})
+	case *eventFile != "":
+		f, err := events.NewFile(*eventFile)
+		if err != nil {
+			glog.Exitf("events.NewFile(%v): %v", *eventFile, err)
+		}
+		return f
+	}
+	return nil
+}
+
+// runSequencer schedules batches through the sequencer service on seqConn, and
+// prunes queues through the admin service on adminConn.
+func runSequencer(ctx context.Context, seqConn, adminConn *grpc.ClientConn,
+	directoryStorage dir.Storage, db *sql.DB) {
+	electionFactory, closeFactory := getElectionFactory(db)
 	defer closeFactory()
 	signer := sequencer.New(
-		spb.NewKeyTransparencySequencerClient(conn),
+		spb.NewKeyTransparencySequencerClient(seqConn),
+		pb.NewKeyTransparencyAdminClient(adminConn),
 		directoryStorage,
 		int32(*batchSize),
+		*maxLatency,
+		*batchConcurrency,
+		*batchTimeout,
 		election.NewTracker(electionFactory, 1*time.Hour, prometheus.MetricFactory{}),
 	)
 
 	go signer.TrackMasterships(ctx)
 
+	if *queueRetention > 0 {
+		go sequencer.PeriodicallyRun(ctx, time.Tick(*queuePrunePeriod), func(ctx context.Context) {
+			if err := signer.PruneQueueForAllMasterships(ctx, *queueRetention); err != nil {
+				glog.Errorf("PeriodicallyRun(PruneQueueForAllMasterships): %v", err)
+			}
+		})
+	}
+
 	sequencer.PeriodicallyRun(ctx, time.Tick(*refresh), func(ctx context.Context) {
 		if err := signer.AddAllDirectories(ctx); err != nil {
 			glog.Errorf("PeriodicallyRun(AddAllDirectories): %v", err)
@@ -16,12 +16,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"flag"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/pem"
 	"github.com/google/trillian/monitoring/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
@@ -30,18 +34,20 @@ import (
 
 	"github.com/google/keytransparency/cmd/serverutil"
 	"github.com/google/keytransparency/core/keyserver"
-	"github.com/google/keytransparency/core/mutator/entry"
 	"github.com/google/keytransparency/impl/authentication"
 	"github.com/google/keytransparency/impl/authorization"
+	"github.com/google/keytransparency/impl/ratelimit"
 	"github.com/google/keytransparency/impl/sql/directory"
 	"github.com/google/keytransparency/impl/sql/engine"
 	"github.com/google/keytransparency/impl/sql/mutationstorage"
 
 	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	tcrypto "github.com/google/trillian/crypto"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 
+	_ "github.com/google/keytransparency/core/mutator/entry" // Register mutator
 	_ "github.com/google/trillian/crypto/keys/der/proto"
 )
 
@@ -51,7 +57,24 @@ var (
 	serverDBPath = flag.String("db", "test:zaphod@tcp(localhost:3306)/test", "Database connection string")
 	keyFile      = flag.String("tls-key", "genfiles/server.key", "TLS private key file")
 	certFile     = flag.String("tls-cert", "genfiles/server.crt", "TLS cert file")
-	authType     = flag.String("auth-type", "google", "Sets the type of authentication required from clients to update their entries. Accepted values are google (oauth tokens) and insecure-fake (for testing only).")
+	authType     = flag.String("auth-type", "google", "Sets the type of authentication required from clients to update their entries. Accepted values are google (oauth tokens), oidc (ID tokens), mtls (client certificates over gRPC only; REST writes are rejected) and insecure-fake (for testing only).")
+	clientCA     = flag.String("client-ca", "", "PEM bundle of the certificate authorities that issue client certificates, used with --auth-type=mtls")
+	maxQueueLag  = flag.Duration("max-queue-lag", 0, "Reject writes while the oldest unapplied mutation of a directory is older than this. 0 disables")
+	rateLimits   = flag.String("write-rate-limits", "", "JSON file of per-directory token bucket limits on writes by each principal and to each user. No limits if empty")
+
+	authzPolicy = flag.String("authz-policy", "", "Path to an AuthorizationPolicy text or JSON proto. Users may only update their own entries if empty")
+	authzReload = flag.Duration("authz-policy-refresh", 10*time.Second, "How often to check --authz-policy for changes")
+	auditFile   = flag.String("audit-file", "", "File to append authentication and authorization decisions to, one JSON object per line")
+	auditSQL    = flag.Bool("audit-sql", false, "Record authentication and authorization decisions in the AuditLog table of --db")
+
+	oidcIssuer     = flag.String("oidc-issuer", "", "Issuer of the ID tokens accepted with --auth-type=oidc")
+	oidcAudience   = flag.String("oidc-audience", "", "Audience of the ID tokens accepted with --auth-type=oidc")
+	oidcJWKS       = flag.String("oidc-jwks", "", "File or https URL of the JSON Web Key Set that signs ID tokens, used with --auth-type=oidc")
+	oidcJWKSMaxAge = flag.Duration("oidc-jwks-max-age", time.Hour, "Time after which the JSON Web Key Set is fetched again")
+
+	receiptKey         = flag.String("receipt-key", "", "Path to private key PEM for signing receipts for queued mutations. No receipts are issued if empty")
+	receiptKeyPassword = flag.String("receipt-key-password", "", "Password of the receipt private key PEM file")
+	receiptDeadline    = flag.Duration("receipt-deadline", time.Hour, "Time after mutations are queued by which receipts promise they will be applied")
 
 	mapURL = flag.String("map-url", "", "URL of Trillian Map Server")
 	logURL = flag.String("log-url", "", "URL of Trillian Log Server for Signed Map Heads")
@@ -81,7 +104,18 @@ func main() {
 		glog.Exitf("Failed to load server credentials %v", err)
 	}
 
-	authz := &authorization.AuthzPolicy{}
+	defaultPolicy := &authorization.AuthzPolicy{}
+	authorize, authorizeRead := defaultPolicy.Authorize, defaultPolicy.AuthorizeRead
+	if *authzPolicy != "" {
+		policy, err := authorization.NewPolicyFile(*authzPolicy, prometheus.MetricFactory{})
+		if err != nil {
+			glog.Exitf("Failed to read authorization policy: %v", err)
+		}
+		go policy.Watch(ctx, *authzReload)
+		authorize, authorizeRead = policy.Authorize, policy.AuthorizeRead
+	}
+
+	tlsConfig := &tls.Config{}
 	var authFunc grpc_auth.AuthFunc
 	switch *authType {
 	case "insecure-fake":
@@ -94,6 +128,29 @@ func main() {
 			glog.Exitf("Failed to create authentication library instance: %v", err)
 		}
 		authFunc = gauth.AuthFunc
+	case "oidc":
+		if *oidcIssuer == "" || *oidcAudience == "" || *oidcJWKS == "" {
+			glog.Exitf("--auth-type=oidc requires --oidc-issuer, --oidc-audience and --oidc-jwks")
+		}
+		var keys *authentication.KeySet
+		if strings.HasPrefix(*oidcJWKS, "https://") {
+			keys = authentication.NewKeySetFromURL(*oidcJWKS, nil, *oidcJWKSMaxAge)
+		} else {
+			keys = authentication.NewKeySetFromFile(*oidcJWKS, *oidcJWKSMaxAge)
+		}
+		authFunc = authentication.NewJWTAuth(*oidcIssuer, *oidcAudience, keys).AuthFunc
+	case "mtls":
+		mauth, err := authentication.NewMTLSAuthFromFile(*clientCA)
+		if err != nil {
+			glog.Exitf("Failed to load client CA bundle: %v", err)
+		}
+		// Only gRPC clients that connect directly present a client
+		// certificate. The REST gateway forwards calls over its own
+		// loopback connection, which presents none, so every write made
+		// through REST is rejected as unauthenticated.
+		glog.Warning("--auth-type=mtls: writes through the REST gateway are unavailable; use gRPC with a client certificate.")
+		mauth.ConfigureTLS(tlsConfig)
+		authFunc = mauth.AuthFunc
 	default:
 		glog.Exitf("Invalid auth-type parameter: %v.", *authType)
 	}
@@ -121,27 +178,65 @@ func main() {
 	tmap := trillian.NewTrillianMapClient(mconn)
 
 	// Create gRPC server.
-	ksvr := keyserver.New(tlog, tmap, entry.MutateFn, directories, logs, logs,
+	ksvr := keyserver.New(tlog, tmap, directories, logs, logs, logs,
 		prometheus.MetricFactory{})
+	ksvr.MaxQueueLag = *maxQueueLag
+	if *receiptKey != "" {
+		key, err := pem.ReadPrivateKeyFile(*receiptKey, *receiptKeyPassword)
+		if err != nil {
+			glog.Exitf("Could not read receipt key from %v: %v", *receiptKey, err)
+		}
+		ksvr.ReceiptSigner = tcrypto.NewSHA256Signer(key)
+		ksvr.ReceiptDeadline = *receiptDeadline
+	}
+	unaryAuth := map[string]authorization.AuthPair{
+		"/" + authorization.KTService + "/QueueEntryUpdate": {
+			AuthnFunc: authFunc,
+			AuthzFunc: authorize,
+		},
+		"/" + authorization.KTService + "/BatchQueueUserUpdate": {
+			AuthnFunc: authFunc,
+			AuthzFunc: authorize,
+		},
+		"/" + authorization.KTService + "/ListUserRejections": {
+			AuthnFunc: authFunc,
+			AuthzFunc: authorize,
+		},
+	}
+	// Reads of private directories need a reader role. Other reads are public.
+	readAuth, streamAuth := authorization.PrivateReadAuthPairs(directories, authFunc, authorizeRead)
+	for method, pair := range readAuth {
+		unaryAuth[method] = pair
+	}
+	audits, err := serverutil.AuditSink(*auditFile, *auditSQL, sqldb)
+	if err != nil {
+		glog.Exitf("Failed to open audit sink: %v", err)
+	}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpc_prometheus.UnaryServerInterceptor,
+		authorization.UnaryServerInterceptor(unaryAuth, audits),
+	}
+	if *rateLimits != "" {
+		config, err := ratelimit.ReadConfig(*rateLimits)
+		if err != nil {
+			glog.Exitf("Failed to read write rate limits: %v", err)
+		}
+		// Rate limiting runs after authentication to key on the principal.
+		limiter := ratelimit.New(config, prometheus.MetricFactory{})
+		unaryInterceptors = append(unaryInterceptors, limiter.UnaryServerInterceptor())
+	}
 	grpcServer := grpc.NewServer(
 		grpc.Creds(creds),
 		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
 			grpc_prometheus.StreamServerInterceptor,
-			authorization.StreamServerInterceptor(map[string]authorization.AuthPair{
-				// All streaming methods are unauthenticated for now.
-			}),
-		)),
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			grpc_prometheus.UnaryServerInterceptor,
-			authorization.UnaryServerInterceptor(map[string]authorization.AuthPair{
-				"/google.keytransparency.v1.KeyTransparency/UpdateEntry": {
-					AuthnFunc: authFunc,
-					AuthzFunc: authz.Authorize,
-				},
-			}),
+			authorization.StreamServerInterceptor(streamAuth, audits),
 		)),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...)),
 	)
 	pb.RegisterKeyTransparencyServer(grpcServer, ksvr)
+	if err := authorization.CheckWriteMethods(grpcServer.GetServiceInfo(), authorization.KTService, unaryAuth, streamAuth); err != nil {
+		glog.Exitf("Authorization self-check failed: %v", err)
+	}
 	reflection.Register(grpcServer)
 	grpc_prometheus.Register(grpcServer)
 	grpc_prometheus.EnableHandlingTimeHistogram()
@@ -172,8 +267,12 @@ func main() {
 	}()
 	// Serve HTTP2 server over TLS.
 	glog.Infof("Listening on %v", *addr)
-	if err := http.ListenAndServeTLS(*addr, *certFile, *keyFile,
-		serverutil.GrpcHandlerFunc(grpcServer, mux)); err != nil {
+	server := &http.Server{
+		Addr:      *addr,
+		Handler:   serverutil.GrpcHandlerFunc(grpcServer, mux),
+		TLSConfig: tlsConfig,
+	}
+	if err := server.ListenAndServeTLS(*certFile, *keyFile); err != nil {
 		glog.Errorf("ListenAndServeTLS: %v", err)
 	}
 }
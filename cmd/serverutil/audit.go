@@ -0,0 +1,47 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serverutil
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/keytransparency/impl/audit"
+	"github.com/google/keytransparency/impl/authorization"
+	"github.com/google/keytransparency/impl/sql/auditlog"
+)
+
+// AuditSink returns the sink for authorization decisions that appends to file,
+// or that writes to the AuditLog table of db if useSQL is set. It returns nil
+// if neither is set. Records are written in batches in the background.
+func AuditSink(file string, useSQL bool, db *sql.DB) (authorization.AuditSink, error) {
+	switch {
+	case file != "" && useSQL:
+		return nil, errors.New("only one of an audit file and the audit table may be used")
+	case file != "":
+		f, err := audit.NewFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return audit.NewBuffered(f), nil
+	case useSQL:
+		t, err := auditlog.New(db)
+		if err != nil {
+			return nil, err
+		}
+		return audit.NewBuffered(t), nil
+	}
+	return nil, nil
+}
@@ -18,6 +18,7 @@ package adminserver
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/golang/glog"
@@ -30,6 +31,7 @@ import (
 
 	"github.com/google/keytransparency/core/crypto/vrf/p256"
 	"github.com/google/keytransparency/core/directory"
+	"github.com/google/keytransparency/core/mutator"
 	"github.com/google/trillian/client"
 	"github.com/google/trillian/crypto/keys"
 	"github.com/google/trillian/crypto/keys/der"
@@ -43,6 +45,8 @@ import (
 
 const (
 	maxDisplayNameLength = 20
+	// maxPausedReasonLength is the size of the PausedReason column.
+	maxPausedReasonLength = 255
 )
 
 var (
@@ -82,7 +86,39 @@ var (
 // LogsAdmin controls the lifecycle and scaling of mutation logs.
 type LogsAdmin interface {
 	// AddLogs creates and adds new logs for writing to a directory.
+	// Adding a log that already exists has no effect.
 	AddLogs(ctx context.Context, directoryID string, logIDs ...int64) error
+	// AddNextLog adds a writable log whose ID is one more than the highest
+	// log ID of directoryID, and returns its ID.
+	AddNextLog(ctx context.Context, directoryID string) (int64, error)
+	// ListLogs returns the logIDs associated with directoryID that have their write bits set,
+	// or all logIDs associated with directoryID if writable is false.
+	ListLogs(ctx context.Context, directoryID string, writable bool) ([]int64, error)
+	// SetWritable enables or disables writes to a log. Disabling the last
+	// writable log of a directory fails with FailedPrecondition.
+	SetWritable(ctx context.Context, directoryID string, logID int64, enabled bool) error
+	// PruneLogs deletes mutations that were included in revision or earlier
+	// and were queued before the given time. It returns the number of
+	// mutations deleted.
+	PruneLogs(ctx context.Context, directoryID string, revision int64, before time.Time) (int64, error)
+}
+
+// MapRebuilder replays the revisions of a directory into a new map.
+type MapRebuilder interface {
+	// StartRebuild starts rebuilding the map of directoryID in the
+	// background, into a new map if mapID is 0, and returns its progress.
+	StartRebuild(ctx context.Context, directoryID string, mapID int64) (*pb.MapRebuild, error)
+	// RebuildStatus returns the progress of the rebuild into mapID.
+	RebuildStatus(directoryID string, mapID int64) (*pb.MapRebuild, error)
+	// CancelRebuild stops the rebuild into mapID.
+	CancelRebuild(directoryID string, mapID int64) (*pb.MapRebuild, error)
+}
+
+// EventCursors reports how far change events have been delivered.
+type EventCursors interface {
+	// ReadEventCursor returns the highest revision whose events have been
+	// delivered, or 0 if none have.
+	ReadEventCursor(ctx context.Context, directoryID string) (int64, error)
 }
 
 // Server implements pb.KeyTransparencyAdminServer
@@ -94,6 +130,13 @@ type Server struct {
 	directories directory.Storage
 	logsAdmin   LogsAdmin
 	keygen      keys.ProtoGenerator
+
+	// EventCursors, if set, keeps PruneQueue from deleting mutations whose
+	// change events have not been delivered yet.
+	EventCursors EventCursors
+	// Rebuilder, if set, serves RebuildMap, GetMapRebuild and
+	// CancelMapRebuild.
+	Rebuilder MapRebuilder
 }
 
 // New returns a KeyTransparencyAdmin implementation.
@@ -139,13 +182,18 @@ func (s *Server) ListDirectories(ctx context.Context, in *pb.ListDirectoriesRequ
 // by fetching the relevant info from Trillian.
 func (s *Server) fetchDirectory(ctx context.Context, d *directory.Directory) (*pb.Directory, error) {
 	return &pb.Directory{
-		DirectoryId: d.DirectoryID,
-		Log:         d.Log,
-		Map:         d.Map,
-		Vrf:         d.VRF,
-		MinInterval: ptypes.DurationProto(d.MinInterval),
-		MaxInterval: ptypes.DurationProto(d.MaxInterval),
-		Deleted:     d.Deleted,
+		DirectoryId:  d.DirectoryID,
+		Log:          d.Log,
+		Map:          d.Map,
+		Vrf:          d.VRF,
+		MinInterval:  ptypes.DurationProto(d.MinInterval),
+		MaxInterval:  ptypes.DurationProto(d.MaxInterval),
+		Deleted:      d.Deleted,
+		Mutator:      d.Mutator,
+		Paused:       d.Paused,
+		PausedReason: d.PausedReason,
+		Private:      d.Private,
+		ReceiptKey:   d.ReceiptKey,
 	}, nil
 }
 
@@ -209,6 +257,14 @@ func (s *Server) CreateDirectory(ctx context.Context, in *pb.CreateDirectoryRequ
 		// Directory already exists.
 		return nil, status.Errorf(codes.AlreadyExists, "Directory %v already exists or is soft deleted.", in.GetDirectoryId())
 	}
+	if _, err := mutator.Lookup(in.GetMutator()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "adminserver: %v, want one of %v", err, mutator.Names())
+	}
+	if in.GetReceiptKey() != nil {
+		if _, err := der.FromPublicProto(in.GetReceiptKey()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "adminserver: invalid receipt_key: %v", err)
+		}
+	}
 
 	// Generate VRF key.
 	wrapped, err := privKeyOrGen(ctx, in.GetVrfPrivateKey(), s.keygen)
@@ -269,13 +325,16 @@ func (s *Server) CreateDirectory(ctx context.Context, in *pb.CreateDirectoryRequ
 		VRFPriv:     wrapped,
 		MinInterval: minInterval,
 		MaxInterval: maxInterval,
+		Mutator:     in.GetMutator(),
+		Private:     in.GetPrivate(),
+		ReceiptKey:  in.GetReceiptKey(),
 	}
 	if err := s.directories.Write(ctx, dir); err != nil {
 		return nil, fmt.Errorf("adminserver: directories.Write(): %v", err)
 	}
 
 	// Create initial logs for writing.
-	// TODO(#1063): Additional logs can be added at a later point to support increased server load.
+	// Additional logs can be added with CreateInputLog to support increased server load.
 	logIDs := []int64{1, 2}
 	if err := s.logsAdmin.AddLogs(ctx, in.GetDirectoryId(), logIDs...); err != nil {
 		return nil, fmt.Errorf("adminserver: AddLogs(%+v): %v", logIDs, err)
@@ -288,6 +347,9 @@ func (s *Server) CreateDirectory(ctx context.Context, in *pb.CreateDirectoryRequ
 		Vrf:         vrfPublicPB,
 		MinInterval: in.MinInterval,
 		MaxInterval: in.MaxInterval,
+		Mutator:     in.GetMutator(),
+		Private:     in.GetPrivate(),
+		ReceiptKey:  in.GetReceiptKey(),
 	}
 	glog.Infof("Created directory: %+v", d)
 	return d, nil
@@ -370,6 +432,114 @@ func (s *Server) UndeleteDirectory(ctx context.Context, in *pb.UndeleteDirectory
 	return nil, status.Errorf(codes.Unimplemented, "not implemented")
 }
 
+// PauseDirectory stops the sequencer from creating new revisions for a directory.
+func (s *Server) PauseDirectory(ctx context.Context, in *pb.PauseDirectoryRequest) (*empty.Empty, error) {
+	if len(in.GetReason()) > maxPausedReasonLength {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"adminserver: reason is %v bytes, want at most %v", len(in.GetReason()), maxPausedReasonLength)
+	}
+	if err := s.directories.SetPaused(ctx, in.GetDirectoryId(), true, in.GetReason()); err != nil {
+		return nil, err
+	}
+	glog.Infof("adminserver: paused directory %v: %q", in.GetDirectoryId(), in.GetReason())
+	return &empty.Empty{}, nil
+}
+
+// ResumeDirectory resumes sequencing for a paused directory.
+func (s *Server) ResumeDirectory(ctx context.Context, in *pb.ResumeDirectoryRequest) (*empty.Empty, error) {
+	if err := s.directories.SetPaused(ctx, in.GetDirectoryId(), false, ""); err != nil {
+		return nil, err
+	}
+	glog.Infof("adminserver: resumed directory %v", in.GetDirectoryId())
+	return &empty.Empty{}, nil
+}
+
+// ListInputLogs returns the input logs of a directory.
+func (s *Server) ListInputLogs(ctx context.Context, in *pb.ListInputLogsRequest) (*pb.ListInputLogsResponse, error) {
+	if _, err := s.directories.Read(ctx, in.GetDirectoryId(), false); err != nil {
+		return nil, err
+	}
+	logs, err := s.inputLogs(ctx, in.GetDirectoryId())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListInputLogsResponse{Logs: logs}, nil
+}
+
+// CreateInputLog adds a writable input log to a directory.
+func (s *Server) CreateInputLog(ctx context.Context, in *pb.CreateInputLogRequest) (*pb.InputLog, error) {
+	if in.GetLogId() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "adminserver: invalid log_id %v", in.GetLogId())
+	}
+	if _, err := s.directories.Read(ctx, in.GetDirectoryId(), false); err != nil {
+		return nil, err
+	}
+	logID := in.GetLogId()
+	if logID == 0 {
+		var err error
+		if logID, err = s.logsAdmin.AddNextLog(ctx, in.GetDirectoryId()); err != nil {
+			return nil, status.Errorf(codes.Internal, "adminserver: AddNextLog(): %v", err)
+		}
+	} else if err := s.logsAdmin.AddLogs(ctx, in.GetDirectoryId(), logID); err != nil {
+		return nil, status.Errorf(codes.Internal, "adminserver: AddLogs(%v): %v", logID, err)
+	}
+	glog.Infof("adminserver: added input log %v to directory %v", logID, in.GetDirectoryId())
+	return s.inputLog(ctx, in.GetDirectoryId(), logID)
+}
+
+// UpdateInputLog enables or disables writes to an input log.
+func (s *Server) UpdateInputLog(ctx context.Context, in *pb.UpdateInputLogRequest) (*pb.InputLog, error) {
+	if _, err := s.directories.Read(ctx, in.GetDirectoryId(), false); err != nil {
+		return nil, err
+	}
+	// SetWritable refuses to disable the last writable log, since writes
+	// would fail.
+	if err := s.logsAdmin.SetWritable(ctx, in.GetDirectoryId(), in.GetLogId(), in.GetWritable()); err != nil {
+		return nil, err
+	}
+	glog.Infof("adminserver: set input log %v of directory %v writable: %v",
+		in.GetLogId(), in.GetDirectoryId(), in.GetWritable())
+	return s.inputLog(ctx, in.GetDirectoryId(), in.GetLogId())
+}
+
+// inputLogs returns the input logs of directoryID, ordered by LogId.
+func (s *Server) inputLogs(ctx context.Context, directoryID string) ([]*pb.InputLog, error) {
+	all, err := s.logsAdmin.ListLogs(ctx, directoryID, false)
+	if status.Code(err) == codes.NotFound {
+		return []*pb.InputLog{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	writable, err := s.logsAdmin.ListLogs(ctx, directoryID, true)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+	isWritable := make(map[int64]bool)
+	for _, logID := range writable {
+		isWritable[logID] = true
+	}
+	logs := make([]*pb.InputLog, 0, len(all))
+	for _, logID := range all {
+		logs = append(logs, &pb.InputLog{LogId: logID, Writable: isWritable[logID]})
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].LogId < logs[j].LogId })
+	return logs, nil
+}
+
+// inputLog returns the input log logID of directoryID.
+func (s *Server) inputLog(ctx context.Context, directoryID string, logID int64) (*pb.InputLog, error) {
+	logs, err := s.inputLogs(ctx, directoryID)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range logs {
+		if l.LogId == logID {
+			return l, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "adminserver: log %v not found in directory %v", logID, directoryID)
+}
+
 // GarbageCollect looks for directories that have been deleted before the specified timestamp and fully deletes them.
 func (s *Server) GarbageCollect(ctx context.Context, in *pb.GarbageCollectRequest) (*pb.GarbageCollectResponse, error) {
 	before, err := ptypes.Timestamp(in.GetBefore())
@@ -401,3 +571,80 @@ func (s *Server) GarbageCollect(ctx context.Context, in *pb.GarbageCollectReques
 
 	return &pb.GarbageCollectResponse{Directories: deleted}, nil
 }
+
+// PruneQueue deletes queued mutations that are older than in.Retention and
+// have been included in a published revision whose events, if any, have been
+// delivered.
+func (s *Server) PruneQueue(ctx context.Context, in *pb.PruneQueueRequest) (*pb.PruneQueueResponse, error) {
+	retention, err := ptypes.Duration(in.GetRetention())
+	if err != nil || retention < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "adminserver: invalid retention %v: %v", in.GetRetention(), err)
+	}
+	d, err := s.directories.Read(ctx, in.GetDirectoryId(), false)
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.NotFound, "Directory %v not found", in.GetDirectoryId())
+	} else if err != nil {
+		return nil, err
+	}
+
+	// The log of map roots is the authoritative list of published revisions.
+	resp, err := s.tlog.GetLatestSignedLogRoot(ctx, &tpb.GetLatestSignedLogRootRequest{LogId: d.Log.GetTreeId()})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "adminserver: GetLatestSignedLogRoot(%v): %v", d.Log.GetTreeId(), err)
+	}
+	var logRoot types.LogRootV1
+	if err := logRoot.UnmarshalBinary(resp.GetSignedLogRoot().GetLogRoot()); err != nil {
+		return nil, status.Errorf(codes.Internal, "adminserver: could not parse log root: %v", err)
+	}
+	if logRoot.TreeSize == 0 {
+		return &pb.PruneQueueResponse{}, nil
+	}
+	revision := int64(logRoot.TreeSize) - 1
+	if s.EventCursors != nil {
+		// Events are computed from the queue, so keep every mutation
+		// that the event sink has not received yet.
+		cursor, err := s.EventCursors.ReadEventCursor(ctx, d.DirectoryID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "adminserver: ReadEventCursor(%v): %v", d.DirectoryID, err)
+		}
+		if cursor < revision {
+			revision = cursor
+		}
+	}
+
+	pruned, err := s.logsAdmin.PruneLogs(ctx, d.DirectoryID, revision, time.Now().Add(-retention))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "adminserver: PruneLogs(%v, %v): %v", d.DirectoryID, revision, err)
+	}
+	glog.Infof("PruneQueue(%v): pruned %v mutations up to revision %v", d.DirectoryID, pruned, revision)
+	return &pb.PruneQueueResponse{Revision: revision, Pruned: pruned}, nil
+}
+
+// RebuildMap starts replaying the revisions of a directory into a new map.
+func (s *Server) RebuildMap(ctx context.Context, in *pb.RebuildMapRequest) (*pb.MapRebuild, error) {
+	if s.Rebuilder == nil {
+		return nil, status.Errorf(codes.Unimplemented, "adminserver: map rebuilds are not supported by this server")
+	}
+	if _, err := s.directories.Read(ctx, in.GetDirectoryId(), false); status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.NotFound, "Directory %v not found", in.GetDirectoryId())
+	} else if err != nil {
+		return nil, err
+	}
+	return s.Rebuilder.StartRebuild(ctx, in.GetDirectoryId(), in.GetMapId())
+}
+
+// GetMapRebuild returns the progress of a rebuild started by RebuildMap.
+func (s *Server) GetMapRebuild(ctx context.Context, in *pb.GetMapRebuildRequest) (*pb.MapRebuild, error) {
+	if s.Rebuilder == nil {
+		return nil, status.Errorf(codes.Unimplemented, "adminserver: map rebuilds are not supported by this server")
+	}
+	return s.Rebuilder.RebuildStatus(in.GetDirectoryId(), in.GetMapId())
+}
+
+// CancelMapRebuild stops a rebuild started by RebuildMap.
+func (s *Server) CancelMapRebuild(ctx context.Context, in *pb.CancelMapRebuildRequest) (*pb.MapRebuild, error) {
+	if s.Rebuilder == nil {
+		return nil, status.Errorf(codes.Unimplemented, "adminserver: map rebuilds are not supported by this server")
+	}
+	return s.Rebuilder.CancelRebuild(in.GetDirectoryId(), in.GetMapId())
+}
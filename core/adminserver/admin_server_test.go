@@ -17,12 +17,14 @@ package adminserver
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/keytransparency/core/directory"
 	"github.com/google/keytransparency/core/fake"
 	"github.com/google/trillian/crypto/keys/der"
@@ -30,15 +32,17 @@ import (
 	"github.com/google/trillian/storage/testdb"
 	"github.com/google/trillian/testonly"
 	"github.com/google/trillian/testonly/integration"
+	"github.com/google/trillian/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
 	tpb "github.com/google/trillian"
 
-	_ "github.com/google/trillian/crypto/keys/der/proto" // Register PrivateKey ProtoHandler
-	_ "github.com/google/trillian/merkle/coniks"         // Register hasher
-	_ "github.com/google/trillian/merkle/rfc6962"        // Register hasher
+	_ "github.com/google/keytransparency/core/mutator/entry" // Register mutator
+	_ "github.com/google/trillian/crypto/keys/der/proto"     // Register PrivateKey ProtoHandler
+	_ "github.com/google/trillian/merkle/coniks"             // Register hasher
+	_ "github.com/google/trillian/merkle/rfc6962"            // Register hasher
 )
 
 func vrfKeyGen(ctx context.Context, spec *keyspb.Specification) (proto.Message, error) {
@@ -85,10 +89,103 @@ func (fakeQueueAdmin) AddLogs(ctx context.Context, directoryID string, logIDs ..
 	return nil
 }
 
+func (fakeQueueAdmin) AddNextLog(ctx context.Context, directoryID string) (int64, error) {
+	return 3, nil
+}
+
+func (fakeQueueAdmin) ListLogs(ctx context.Context, directoryID string, writable bool) ([]int64, error) {
+	return []int64{1, 2}, nil
+}
+
+func (fakeQueueAdmin) SetWritable(ctx context.Context, directoryID string, logID int64, enabled bool) error {
+	return nil
+}
+
+func (fakeQueueAdmin) PruneLogs(ctx context.Context, directoryID string, revision int64, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// fakeLogsAdmin stores the writable state of logs by logID.
+type fakeLogsAdmin struct {
+	fakeQueueAdmin
+	logs map[int64]bool
+}
+
+func (f *fakeLogsAdmin) AddLogs(ctx context.Context, directoryID string, logIDs ...int64) error {
+	for _, logID := range logIDs {
+		if _, ok := f.logs[logID]; !ok {
+			f.logs[logID] = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeLogsAdmin) AddNextLog(ctx context.Context, directoryID string) (int64, error) {
+	var logID int64
+	for id := range f.logs {
+		if id > logID {
+			logID = id
+		}
+	}
+	f.logs[logID+1] = true
+	return logID + 1, nil
+}
+
+func (f *fakeLogsAdmin) ListLogs(ctx context.Context, directoryID string, writable bool) ([]int64, error) {
+	var logIDs []int64
+	for logID, enabled := range f.logs {
+		if enabled || !writable {
+			logIDs = append(logIDs, logID)
+		}
+	}
+	if len(logIDs) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no log found for directory %v", directoryID)
+	}
+	return logIDs, nil
+}
+
+func (f *fakeLogsAdmin) SetWritable(ctx context.Context, directoryID string, logID int64, enabled bool) error {
+	if _, ok := f.logs[logID]; !ok {
+		return status.Errorf(codes.NotFound, "log %v not found", logID)
+	}
+	if !enabled {
+		others := 0
+		for id, writable := range f.logs {
+			if writable && id != logID {
+				others++
+			}
+		}
+		if others == 0 {
+			return status.Errorf(codes.FailedPrecondition, "log %v is the last writable log", logID)
+		}
+	}
+	f.logs[logID] = enabled
+	return nil
+}
+
+// fakePruner records the arguments of PruneLogs.
+type fakePruner struct {
+	fakeQueueAdmin
+	revision int64
+	before   time.Time
+}
+
+func (f *fakePruner) PruneLogs(ctx context.Context, directoryID string, revision int64, before time.Time) (int64, error) {
+	f.revision, f.before = revision, before
+	return 3, nil
+}
+
+type fakeCursors map[string]int64
+
+func (c fakeCursors) ReadEventCursor(_ context.Context, directoryID string) (int64, error) {
+	return c[directoryID], nil
+}
+
 func TestCreateDirectory(t *testing.T) {
 	for _, tc := range []struct {
 		desc        string
 		directoryID string
+		mutator     string
 		wantCode    codes.Code
 		expect      func(*miniEnv)
 	}{
@@ -98,6 +195,13 @@ func TestCreateDirectory(t *testing.T) {
 			wantCode:    codes.AlreadyExists,
 			expect:      func(e *miniEnv) {},
 		},
+		{
+			desc:        "Unknown mutator",
+			directoryID: "newdirectory",
+			mutator:     "unknown",
+			wantCode:    codes.InvalidArgument,
+			expect:      func(e *miniEnv) {},
+		},
 		{
 			desc:        "Create map fails",
 			directoryID: "mapinitfails",
@@ -145,6 +249,7 @@ func TestCreateDirectory(t *testing.T) {
 				DirectoryId: tc.directoryID,
 				MinInterval: ptypes.DurationProto(60 * time.Hour),
 				MaxInterval: ptypes.DurationProto(60 * time.Hour),
+				Mutator:     tc.mutator,
 			}); status.Code(err) != tc.wantCode {
 				t.Errorf("CreateDirectory(): %v, want %v", err, tc.wantCode)
 			}
@@ -312,3 +417,308 @@ func TestListDirectories(t *testing.T) {
 		}
 	}
 }
+
+func TestPruneQueue(t *testing.T) {
+	for _, tc := range []struct {
+		desc        string
+		directoryID string
+		retention   time.Duration
+		treeSize    uint64
+		cursors     fakeCursors
+		wantCode    codes.Code
+		want        *pb.PruneQueueResponse
+	}{
+		{desc: "prune", directoryID: "existingdirectory", retention: time.Hour, treeSize: 5,
+			want: &pb.PruneQueueResponse{Revision: 4, Pruned: 3}},
+		{desc: "events delivered", directoryID: "existingdirectory", retention: time.Hour, treeSize: 5,
+			cursors: fakeCursors{"existingdirectory": 4},
+			want:    &pb.PruneQueueResponse{Revision: 4, Pruned: 3}},
+		{desc: "events pending", directoryID: "existingdirectory", retention: time.Hour, treeSize: 5,
+			cursors: fakeCursors{"existingdirectory": 2},
+			want:    &pb.PruneQueueResponse{Revision: 2, Pruned: 3}},
+		{desc: "unpublished", directoryID: "existingdirectory", retention: time.Hour,
+			want: &pb.PruneQueueResponse{}},
+		{desc: "negative retention", directoryID: "existingdirectory", retention: -time.Hour,
+			wantCode: codes.InvalidArgument},
+		{desc: "not found", directoryID: "unknown", retention: time.Hour, wantCode: codes.NotFound},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			e, err := newMiniEnv(ctx, t)
+			if err != nil {
+				t.Fatalf("newMiniEnv(): %v", err)
+			}
+			defer e.Close()
+			pruner := &fakePruner{}
+			e.srv.logsAdmin = pruner
+			if tc.cursors != nil {
+				e.srv.EventCursors = tc.cursors
+			}
+
+			if tc.wantCode == codes.OK {
+				logRoot, err := (&types.LogRootV1{TreeSize: tc.treeSize}).MarshalBinary()
+				if err != nil {
+					t.Fatalf("MarshalBinary(): %v", err)
+				}
+				e.ms.Log.EXPECT().GetLatestSignedLogRoot(gomock.Any(), gomock.Any()).Return(
+					&tpb.GetLatestSignedLogRootResponse{SignedLogRoot: &tpb.SignedLogRoot{LogRoot: logRoot}}, nil)
+			}
+
+			start := time.Now()
+			got, err := e.srv.PruneQueue(ctx, &pb.PruneQueueRequest{
+				DirectoryId: tc.directoryID,
+				Retention:   ptypes.DurationProto(tc.retention),
+			})
+			if status.Code(err) != tc.wantCode {
+				t.Fatalf("PruneQueue(): %v, want %v", err, tc.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if !proto.Equal(got, tc.want) {
+				t.Errorf("PruneQueue(): %v, want %v", got, tc.want)
+			}
+			if tc.want.Pruned > 0 {
+				if pruner.revision != tc.want.Revision {
+					t.Errorf("PruneLogs(revision: %v), want %v", pruner.revision, tc.want.Revision)
+				}
+				if cutoff := start.Add(-tc.retention); pruner.before.Before(cutoff) {
+					t.Errorf("PruneLogs(before: %v), want >= %v", pruner.before, cutoff)
+				}
+			}
+		})
+	}
+}
+
+func TestPauseResumeDirectory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	e, err := newMiniEnv(ctx, t)
+	if err != nil {
+		t.Fatalf("newMiniEnv(): %v", err)
+	}
+	defer e.Close()
+
+	for _, tc := range []struct {
+		desc        string
+		directoryID string
+		pause       bool
+		reason      string
+		wantCode    codes.Code
+		want        *pb.Directory
+	}{
+		{desc: "pause", directoryID: "existingdirectory", pause: true, reason: "maintenance",
+			want: &pb.Directory{Paused: true, PausedReason: "maintenance"}},
+		{desc: "resume", directoryID: "existingdirectory", want: &pb.Directory{}},
+		{desc: "reason too long", directoryID: "existingdirectory", pause: true,
+			reason: strings.Repeat("x", maxPausedReasonLength+1), wantCode: codes.InvalidArgument},
+		{desc: "pause not found", directoryID: "unknown", pause: true, wantCode: codes.NotFound},
+		{desc: "resume not found", directoryID: "unknown", wantCode: codes.NotFound},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if tc.pause {
+				_, err = e.srv.PauseDirectory(ctx, &pb.PauseDirectoryRequest{DirectoryId: tc.directoryID, Reason: tc.reason})
+			} else {
+				_, err = e.srv.ResumeDirectory(ctx, &pb.ResumeDirectoryRequest{DirectoryId: tc.directoryID})
+			}
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("Pause/ResumeDirectory(): %v, want %v", err, want)
+			}
+			if err != nil {
+				return
+			}
+			d, err := e.srv.GetDirectory(ctx, &pb.GetDirectoryRequest{DirectoryId: tc.directoryID})
+			if err != nil {
+				t.Fatalf("GetDirectory(): %v", err)
+			}
+			if d.Paused != tc.want.Paused || d.PausedReason != tc.want.PausedReason {
+				t.Errorf("GetDirectory(): Paused: %v, PausedReason: %q, want %v, %q",
+					d.Paused, d.PausedReason, tc.want.Paused, tc.want.PausedReason)
+			}
+		})
+	}
+}
+
+func TestInputLogs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	e, err := newMiniEnv(ctx, t)
+	if err != nil {
+		t.Fatalf("newMiniEnv(): %v", err)
+	}
+	defer e.Close()
+	e.srv.logsAdmin = &fakeLogsAdmin{logs: map[int64]bool{1: true, 2: true}}
+	const dirID = "existingdirectory"
+
+	for _, tc := range []struct {
+		desc     string
+		call     func() (*pb.InputLog, error)
+		want     *pb.InputLog
+		wantCode codes.Code
+		wantLogs []*pb.InputLog
+	}{
+		{
+			desc: "create next",
+			call: func() (*pb.InputLog, error) {
+				return e.srv.CreateInputLog(ctx, &pb.CreateInputLogRequest{DirectoryId: dirID})
+			},
+			want:     &pb.InputLog{LogId: 3, Writable: true},
+			wantLogs: []*pb.InputLog{{LogId: 1, Writable: true}, {LogId: 2, Writable: true}, {LogId: 3, Writable: true}},
+		},
+		{
+			desc: "disable",
+			call: func() (*pb.InputLog, error) {
+				return e.srv.UpdateInputLog(ctx, &pb.UpdateInputLogRequest{DirectoryId: dirID, LogId: 1})
+			},
+			want:     &pb.InputLog{LogId: 1},
+			wantLogs: []*pb.InputLog{{LogId: 1}, {LogId: 2, Writable: true}, {LogId: 3, Writable: true}},
+		},
+		{
+			desc: "create existing",
+			call: func() (*pb.InputLog, error) {
+				return e.srv.CreateInputLog(ctx, &pb.CreateInputLogRequest{DirectoryId: dirID, LogId: 1})
+			},
+			want:     &pb.InputLog{LogId: 1},
+			wantLogs: []*pb.InputLog{{LogId: 1}, {LogId: 2, Writable: true}, {LogId: 3, Writable: true}},
+		},
+		{
+			desc: "disable last",
+			call: func() (*pb.InputLog, error) {
+				if _, err := e.srv.UpdateInputLog(ctx, &pb.UpdateInputLogRequest{DirectoryId: dirID, LogId: 2}); err != nil {
+					return nil, err
+				}
+				return e.srv.UpdateInputLog(ctx, &pb.UpdateInputLogRequest{DirectoryId: dirID, LogId: 3})
+			},
+			wantCode: codes.FailedPrecondition,
+			wantLogs: []*pb.InputLog{{LogId: 1}, {LogId: 2}, {LogId: 3, Writable: true}},
+		},
+		{
+			desc: "enable",
+			call: func() (*pb.InputLog, error) {
+				return e.srv.UpdateInputLog(ctx, &pb.UpdateInputLogRequest{DirectoryId: dirID, LogId: 2, Writable: true})
+			},
+			want:     &pb.InputLog{LogId: 2, Writable: true},
+			wantLogs: []*pb.InputLog{{LogId: 1}, {LogId: 2, Writable: true}, {LogId: 3, Writable: true}},
+		},
+		{
+			desc: "unknown log",
+			call: func() (*pb.InputLog, error) {
+				return e.srv.UpdateInputLog(ctx, &pb.UpdateInputLogRequest{DirectoryId: dirID, LogId: 9, Writable: true})
+			},
+			wantCode: codes.NotFound,
+			wantLogs: []*pb.InputLog{{LogId: 1}, {LogId: 2, Writable: true}, {LogId: 3, Writable: true}},
+		},
+		{
+			desc: "unknown directory",
+			call: func() (*pb.InputLog, error) {
+				return e.srv.CreateInputLog(ctx, &pb.CreateInputLogRequest{DirectoryId: "unknown"})
+			},
+			wantCode: codes.NotFound,
+			wantLogs: []*pb.InputLog{{LogId: 1}, {LogId: 2, Writable: true}, {LogId: 3, Writable: true}},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tc.call()
+			if status.Code(err) != tc.wantCode {
+				t.Fatalf("%v: %v, want %v", tc.desc, err, tc.wantCode)
+			}
+			if err == nil && !proto.Equal(got, tc.want) {
+				t.Errorf("%v: %v, want %v", tc.desc, got, tc.want)
+			}
+			resp, err := e.srv.ListInputLogs(ctx, &pb.ListInputLogsRequest{DirectoryId: dirID})
+			if err != nil {
+				t.Fatalf("ListInputLogs(): %v", err)
+			}
+			if got, want := resp.Logs, tc.wantLogs; !cmp.Equal(got, want, cmp.Comparer(proto.Equal)) {
+				t.Errorf("ListInputLogs(): %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// fakeRebuilder records the rebuilds it is asked to start.
+type fakeRebuilder map[int64]*pb.MapRebuild
+
+func (f fakeRebuilder) StartRebuild(_ context.Context, directoryID string, mapID int64) (*pb.MapRebuild, error) {
+	if mapID == 0 {
+		mapID = int64(len(f) + 1)
+	}
+	f[mapID] = &pb.MapRebuild{DirectoryId: directoryID, MapId: mapID}
+	return f[mapID], nil
+}
+
+func (f fakeRebuilder) RebuildStatus(directoryID string, mapID int64) (*pb.MapRebuild, error) {
+	r, ok := f[mapID]
+	if !ok || r.DirectoryId != directoryID {
+		return nil, status.Errorf(codes.NotFound, "map %v not found", mapID)
+	}
+	return r, nil
+}
+
+func (f fakeRebuilder) CancelRebuild(directoryID string, mapID int64) (*pb.MapRebuild, error) {
+	r, err := f.RebuildStatus(directoryID, mapID)
+	if err != nil {
+		return nil, err
+	}
+	r.Done = true
+	return r, nil
+}
+
+func TestRebuildMap(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	e, err := newMiniEnv(ctx, t)
+	if err != nil {
+		t.Fatalf("newMiniEnv(): %v", err)
+	}
+	defer e.Close()
+
+	req := &pb.RebuildMapRequest{DirectoryId: "existingdirectory"}
+	if _, err := e.srv.RebuildMap(ctx, req); status.Code(err) != codes.Unimplemented {
+		t.Errorf("RebuildMap() without Rebuilder: %v, want %v", err, codes.Unimplemented)
+	}
+	e.srv.Rebuilder = fakeRebuilder{}
+
+	for _, tc := range []struct {
+		desc        string
+		directoryID string
+		mapID       int64
+		wantCode    codes.Code
+		want        *pb.MapRebuild
+	}{
+		{desc: "start", directoryID: "existingdirectory",
+			want: &pb.MapRebuild{DirectoryId: "existingdirectory", MapId: 1}},
+		{desc: "resume", directoryID: "existingdirectory", mapID: 1,
+			want: &pb.MapRebuild{DirectoryId: "existingdirectory", MapId: 1}},
+		{desc: "not found", directoryID: "unknown", wantCode: codes.NotFound},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := e.srv.RebuildMap(ctx, &pb.RebuildMapRequest{DirectoryId: tc.directoryID, MapId: tc.mapID})
+			if status.Code(err) != tc.wantCode {
+				t.Fatalf("RebuildMap(): %v, want %v", err, tc.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if !proto.Equal(got, tc.want) {
+				t.Errorf("RebuildMap(): %v, want %v", got, tc.want)
+			}
+			got, err = e.srv.GetMapRebuild(ctx, &pb.GetMapRebuildRequest{DirectoryId: tc.directoryID, MapId: got.MapId})
+			if err != nil {
+				t.Fatalf("GetMapRebuild(): %v", err)
+			}
+			if !proto.Equal(got, tc.want) {
+				t.Errorf("GetMapRebuild(): %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	got, err := e.srv.CancelMapRebuild(ctx, &pb.CancelMapRebuildRequest{DirectoryId: "existingdirectory", MapId: 1})
+	if err != nil {
+		t.Fatalf("CancelMapRebuild(): %v", err)
+	}
+	if !got.Done {
+		t.Errorf("CancelMapRebuild(): %v, want done", got)
+	}
+}
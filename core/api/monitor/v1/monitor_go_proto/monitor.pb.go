@@ -12,7 +12,9 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	keytransparency_go_proto "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
 	trillian "github.com/google/trillian"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	status "google.golang.org/genproto/googleapis/rpc/status"
@@ -157,44 +159,110 @@ func (m *State) GetErrors() []*status.Status {
 	return nil
 }
 
+// AddReceiptRequest asks the monitor to check that the mutations of a receipt
+// are applied to the directory by the receipt's deadline.
+type AddReceiptRequest struct {
+	// kt_url is the URL of the keytransparency server that issued the receipt.
+	KtUrl string `protobuf:"bytes,1,opt,name=kt_url,json=ktUrl,proto3" json:"kt_url,omitempty"`
+	// directory_id identifies the directory the receipt is for.
+	DirectoryId string `protobuf:"bytes,2,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// receipt is the receipt returned by the keytransparency server.
+	Receipt              *keytransparency_go_proto.SignedReceipt `protobuf:"bytes,3,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                `json:"-"`
+	XXX_unrecognized     []byte                                  `json:"-"`
+	XXX_sizecache        int32                                   `json:"-"`
+}
+
+func (m *AddReceiptRequest) Reset()         { *m = AddReceiptRequest{} }
+func (m *AddReceiptRequest) String() string { return proto.CompactTextString(m) }
+func (*AddReceiptRequest) ProtoMessage()    {}
+func (*AddReceiptRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6c9cdd4901f6b9a2, []int{2}
+}
+
+func (m *AddReceiptRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddReceiptRequest.Unmarshal(m, b)
+}
+func (m *AddReceiptRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddReceiptRequest.Marshal(b, m, deterministic)
+}
+func (m *AddReceiptRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddReceiptRequest.Merge(m, src)
+}
+func (m *AddReceiptRequest) XXX_Size() int {
+	return xxx_messageInfo_AddReceiptRequest.Size(m)
+}
+func (m *AddReceiptRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddReceiptRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddReceiptRequest proto.InternalMessageInfo
+
+func (m *AddReceiptRequest) GetKtUrl() string {
+	if m != nil {
+		return m.KtUrl
+	}
+	return ""
+}
+
+func (m *AddReceiptRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *AddReceiptRequest) GetReceipt() *keytransparency_go_proto.SignedReceipt {
+	if m != nil {
+		return m.Receipt
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*GetStateRequest)(nil), "google.keytransparency.monitor.v1.GetStateRequest")
 	proto.RegisterType((*State)(nil), "google.keytransparency.monitor.v1.State")
+	proto.RegisterType((*AddReceiptRequest)(nil), "google.keytransparency.monitor.v1.AddReceiptRequest")
 }
 
 func init() { proto.RegisterFile("monitor/v1/monitor.proto", fileDescriptor_6c9cdd4901f6b9a2) }
 
 var fileDescriptor_6c9cdd4901f6b9a2 = []byte{
-	// 453 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x93, 0xc1, 0x8a, 0xd4, 0x4c,
-	0x10, 0xc7, 0xc9, 0x86, 0x9d, 0x6f, 0xb6, 0xe7, 0x43, 0xa1, 0x41, 0x36, 0x04, 0xc1, 0xd9, 0x39,
-	0x8d, 0x1e, 0xba, 0xd9, 0x78, 0x10, 0x3c, 0x2e, 0xe8, 0xba, 0xc8, 0x1c, 0xcc, 0xe8, 0xc5, 0x4b,
-	0xe8, 0xcd, 0x94, 0xb1, 0x99, 0xa4, 0x3b, 0x56, 0x57, 0x06, 0x86, 0x61, 0x2e, 0x5e, 0x3d, 0x7a,
-	0xf0, 0x51, 0x7c, 0x02, 0x9f, 0xc0, 0x57, 0xf0, 0x41, 0x24, 0x9d, 0x64, 0x58, 0xd6, 0x83, 0x82,
-	0x78, 0x49, 0x52, 0xe9, 0x7f, 0x25, 0xf5, 0xff, 0x55, 0x15, 0x8b, 0x2a, 0x6b, 0x34, 0x59, 0x94,
-	0x9b, 0x73, 0xd9, 0x3f, 0x8a, 0x1a, 0x2d, 0x59, 0x7e, 0x56, 0x58, 0x5b, 0x94, 0x20, 0xd6, 0xb0,
-	0x25, 0x54, 0xc6, 0xd5, 0x0a, 0xc1, 0xe4, 0x5b, 0x31, 0xa8, 0x36, 0xe7, 0xf1, 0xfd, 0x4e, 0x22,
-	0x55, 0xad, 0xa5, 0x32, 0xc6, 0x92, 0x22, 0x6d, 0x8d, 0xeb, 0x3e, 0x10, 0x3f, 0xe8, 0x4f, 0x7d,
-	0x74, 0xdd, 0xbc, 0x93, 0xa4, 0x2b, 0x70, 0xa4, 0xaa, 0xba, 0x17, 0x9c, 0xf6, 0x02, 0xac, 0x73,
-	0xe9, 0x48, 0x51, 0x33, 0x64, 0xde, 0x21, 0xd4, 0x65, 0xa9, 0x95, 0xe9, 0xe2, 0x59, 0xc1, 0xee,
-	0x5e, 0x02, 0x2d, 0x49, 0x11, 0xa4, 0xf0, 0xa1, 0x01, 0x47, 0xfc, 0x1e, 0x1b, 0xad, 0x29, 0x6b,
-	0xb0, 0x8c, 0x8e, 0xa6, 0xc1, 0xfc, 0x24, 0x3d, 0x5e, 0xd3, 0x1b, 0x2c, 0xf9, 0x19, 0xfb, 0x7f,
-	0xa5, 0x11, 0x72, 0xb2, 0xb8, 0xcd, 0xf4, 0x2a, 0x0a, 0xfd, 0xe1, 0xe4, 0xf0, 0xee, 0x6a, 0xc5,
-	0x63, 0x36, 0x46, 0xd8, 0x68, 0xa7, 0xad, 0x89, 0x82, 0x69, 0x30, 0x0f, 0xd3, 0x43, 0x3c, 0xfb,
-	0x12, 0xb0, 0x63, 0xff, 0x1b, 0xfe, 0x90, 0x85, 0xae, 0x42, 0x2f, 0x98, 0x24, 0xa7, 0xe2, 0x50,
-	0xd0, 0x52, 0x17, 0x06, 0x56, 0x0b, 0x55, 0xa7, 0xd6, 0x52, 0xda, 0x6a, 0xf8, 0x13, 0x76, 0xe2,
-	0x00, 0x4c, 0xd6, 0xda, 0xf3, 0xd5, 0x4c, 0x92, 0x58, 0xf4, 0xf0, 0x06, 0xef, 0xe2, 0xf5, 0xe0,
-	0x3d, 0x1d, 0xb7, 0xe2, 0x36, 0xe4, 0x8f, 0xd8, 0x08, 0x10, 0x2d, 0xba, 0x28, 0x9c, 0x86, 0xf3,
-	0x49, 0xc2, 0x87, 0x2c, 0xac, 0x73, 0xb1, 0xf4, 0x40, 0xd2, 0x5e, 0x91, 0x7c, 0x0a, 0xd9, 0x7f,
-	0x8b, 0x8e, 0x3c, 0xff, 0x1a, 0xb0, 0xf1, 0xc0, 0x83, 0x27, 0xe2, 0xb7, 0x7d, 0x12, 0xb7, 0xe0,
-	0xc5, 0xf3, 0x3f, 0xc8, 0xf1, 0x09, 0xb3, 0xc5, 0xc7, 0xef, 0x3f, 0x3e, 0x1f, 0x5d, 0xf2, 0x67,
-	0xf2, 0xc6, 0x9c, 0x38, 0xc0, 0x0d, 0xa0, 0x93, 0xbb, 0xae, 0x03, 0x7b, 0x39, 0xe0, 0xd5, 0xe0,
-	0xe4, 0xee, 0x26, 0xff, 0xbd, 0xef, 0x2b, 0xb8, 0xa7, 0x65, 0x7b, 0x25, 0xfe, 0x2d, 0x60, 0x7c,
-	0x28, 0xe6, 0x62, 0x9b, 0xf6, 0xd8, 0xff, 0xb1, 0x87, 0x57, 0xde, 0xc3, 0x4b, 0x7e, 0xf5, 0x77,
-	0x1e, 0xe4, 0x6e, 0x18, 0x93, 0xfd, 0xc5, 0x8b, 0xb7, 0xcf, 0x0b, 0x4d, 0xef, 0x9b, 0x6b, 0x91,
-	0xdb, 0x4a, 0xf6, 0x63, 0x7c, 0xab, 0x10, 0x99, 0x5b, 0xec, 0x56, 0xe3, 0xd7, 0x15, 0xcb, 0x0a,
-	0x9b, 0x75, 0xa3, 0x31, 0xf2, 0xb7, 0xc7, 0x3f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xdb, 0xae, 0xd1,
-	0xd7, 0x88, 0x03, 0x00, 0x00,
+	// 544 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0xc1, 0x8e, 0xd3, 0x3a,
+	0x14, 0x55, 0xda, 0x37, 0x9d, 0x8e, 0xfb, 0x04, 0xc2, 0x12, 0x4c, 0x14, 0x90, 0xe8, 0x74, 0x55,
+	0x58, 0xc4, 0x6a, 0x40, 0x42, 0x9a, 0x15, 0x54, 0x1a, 0x86, 0x11, 0xea, 0x82, 0x14, 0x36, 0x6c,
+	0xaa, 0x4c, 0x72, 0x09, 0x56, 0x93, 0x38, 0xd8, 0xb7, 0x91, 0xaa, 0xaa, 0x1b, 0xfe, 0x00, 0xb1,
+	0xe0, 0x2b, 0x58, 0xb3, 0x60, 0xcd, 0x17, 0xf0, 0x0b, 0x7c, 0x08, 0x8a, 0x63, 0x97, 0x51, 0x2b,
+	0x60, 0x04, 0x62, 0xd3, 0xe6, 0xe6, 0x9e, 0x6b, 0x9f, 0x73, 0x7c, 0x62, 0xe2, 0xe6, 0xa2, 0xe0,
+	0x28, 0x24, 0xab, 0x46, 0xcc, 0x3c, 0xfa, 0xa5, 0x14, 0x28, 0xe8, 0x51, 0x2a, 0x44, 0x9a, 0x81,
+	0x3f, 0x87, 0x25, 0xca, 0xa8, 0x50, 0x65, 0x24, 0xa1, 0x88, 0x97, 0xbe, 0x45, 0x55, 0x23, 0xef,
+	0x56, 0x03, 0x61, 0x51, 0xc9, 0x59, 0x54, 0x14, 0x02, 0x23, 0xe4, 0xa2, 0x50, 0xcd, 0x02, 0xde,
+	0x4d, 0xd3, 0xd5, 0xd5, 0xf9, 0xe2, 0x15, 0x83, 0xbc, 0xc4, 0xa5, 0x69, 0xde, 0xde, 0x6e, 0x22,
+	0xcf, 0x41, 0x61, 0x94, 0x97, 0x06, 0x70, 0x68, 0x00, 0xb2, 0x8c, 0x99, 0xc2, 0x08, 0x17, 0x76,
+	0xd9, 0x2b, 0x28, 0x79, 0x96, 0xf1, 0xa8, 0x30, 0xb5, 0x5b, 0x8d, 0xd8, 0x36, 0x47, 0xdd, 0x19,
+	0xa4, 0xe4, 0xea, 0x29, 0xe0, 0x14, 0x23, 0x84, 0x10, 0xde, 0x2c, 0x40, 0x21, 0xbd, 0x4e, 0x3a,
+	0x73, 0x9c, 0x2d, 0x64, 0xe6, 0xb6, 0xfa, 0xce, 0xf0, 0x20, 0xdc, 0x9b, 0xe3, 0x0b, 0x99, 0xd1,
+	0x23, 0xf2, 0x7f, 0xc2, 0x25, 0xc4, 0x28, 0xe4, 0x72, 0xc6, 0x13, 0xb7, 0xad, 0x9b, 0xbd, 0xcd,
+	0xbb, 0xb3, 0x84, 0x7a, 0xa4, 0x2b, 0xa1, 0xe2, 0x8a, 0x8b, 0xc2, 0x75, 0xfa, 0xce, 0xb0, 0x1d,
+	0x6e, 0xea, 0xc1, 0x07, 0x87, 0xec, 0xe9, 0x6d, 0xe8, 0x1d, 0xd2, 0x56, 0xb9, 0xd4, 0x80, 0x5e,
+	0x70, 0xe8, 0x6f, 0xa8, 0x4e, 0x79, 0x5a, 0x40, 0x32, 0x89, 0xca, 0x50, 0x08, 0x0c, 0x6b, 0x0c,
+	0x7d, 0x40, 0x0e, 0x14, 0x40, 0x31, 0xab, 0x85, 0x6b, 0x36, 0xbd, 0xc0, 0xf3, 0x8d, 0xe7, 0xd6,
+	0x15, 0xff, 0xb9, 0x75, 0x25, 0xec, 0xd6, 0xe0, 0xba, 0xa4, 0x77, 0x49, 0x07, 0xa4, 0x14, 0x52,
+	0xb9, 0xed, 0x7e, 0x7b, 0xd8, 0x0b, 0xa8, 0x9d, 0x92, 0x65, 0xec, 0x4f, 0xb5, 0x55, 0xa1, 0x41,
+	0x0c, 0xde, 0x39, 0xe4, 0xda, 0xa3, 0x24, 0x09, 0x21, 0x06, 0x5e, 0xe2, 0xae, 0x0b, 0xce, 0xaf,
+	0x5c, 0x68, 0xed, 0xba, 0x30, 0x26, 0xfb, 0xb2, 0x59, 0x4b, 0x7b, 0xd4, 0x0b, 0x86, 0xfe, 0x4f,
+	0x62, 0x52, 0x8d, 0x8c, 0x68, 0xbb, 0xb7, 0x1d, 0x0c, 0x3e, 0xff, 0x47, 0xf6, 0x27, 0x4d, 0x88,
+	0xe8, 0x27, 0x87, 0x74, 0xed, 0x19, 0xd1, 0xc0, 0xff, 0x6d, 0xe4, 0xfc, 0xad, 0x03, 0xf5, 0x86,
+	0x97, 0x98, 0xd1, 0x03, 0x83, 0xc9, 0xdb, 0xaf, 0xdf, 0xde, 0xb7, 0x4e, 0xe9, 0x09, 0xbb, 0x10,
+	0x79, 0x05, 0xb2, 0x02, 0xa9, 0xd8, 0xaa, 0xf1, 0x63, 0xcd, 0xac, 0x58, 0x0e, 0x8a, 0xad, 0x2e,
+	0xba, 0xb1, 0xd6, 0x29, 0x04, 0x75, 0x9c, 0xd5, 0xbf, 0x48, 0xbf, 0x38, 0x84, 0x5a, 0x32, 0xe3,
+	0x65, 0x68, 0xa2, 0xf0, 0x8f, 0x35, 0x3c, 0xd3, 0x1a, 0x9e, 0xd2, 0xb3, 0xbf, 0xd3, 0xc0, 0x56,
+	0x36, 0xba, 0x6b, 0xfa, 0xd1, 0x21, 0xe4, 0x47, 0x42, 0xe8, 0xfd, 0x4b, 0x70, 0xd9, 0x09, 0x94,
+	0x77, 0x63, 0x27, 0xb8, 0x27, 0xf5, 0xb7, 0x6e, 0xf9, 0x0e, 0x1e, 0xfe, 0x29, 0x5f, 0x93, 0x19,
+	0x75, 0x6c, 0xd3, 0x33, 0x7e, 0xf2, 0xf2, 0x71, 0xca, 0xf1, 0xf5, 0xe2, 0xdc, 0x8f, 0x45, 0xce,
+	0xcc, 0x25, 0xb1, 0x45, 0x96, 0xc5, 0x42, 0x36, 0xb7, 0xd2, 0xee, 0xed, 0x36, 0x4b, 0xc5, 0xac,
+	0x61, 0xd9, 0xd1, 0x7f, 0xf7, 0xbe, 0x0f, 0x00, 0x1b, 0xac, 0x31, 0x57, 0x03, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -226,6 +294,10 @@ type MonitorClient interface {
 	// mutations from the previous to the current revision it won't sign the map
 	// root and additional data will be provided to reproduce the failure.
 	GetStateByRevision(ctx context.Context, in *GetStateRequest, opts ...grpc.CallOption) (*State, error)
+	// AddReceipt verifies a receipt and checks that its mutations are applied
+	// by its deadline. A missed deadline is reported in the errors of the state
+	// of the first revision published after it.
+	AddReceipt(ctx context.Context, in *AddReceiptRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
 type monitorClient struct {
@@ -254,6 +326,15 @@ func (c *monitorClient) GetStateByRevision(ctx context.Context, in *GetStateRequ
 	return out, nil
 }
 
+func (c *monitorClient) AddReceipt(ctx context.Context, in *AddReceiptRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.monitor.v1.Monitor/AddReceipt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MonitorServer is the server API for Monitor service.
 type MonitorServer interface {
 	// GetSignedMapRoot returns the latest valid signed map root the monitor
@@ -273,6 +354,10 @@ type MonitorServer interface {
 	// mutations from the previous to the current revision it won't sign the map
 	// root and additional data will be provided to reproduce the failure.
 	GetStateByRevision(context.Context, *GetStateRequest) (*State, error)
+	// AddReceipt verifies a receipt and checks that its mutations are applied
+	// by its deadline. A missed deadline is reported in the errors of the state
+	// of the first revision published after it.
+	AddReceipt(context.Context, *AddReceiptRequest) (*empty.Empty, error)
 }
 
 func RegisterMonitorServer(s *grpc.Server, srv MonitorServer) {
@@ -315,6 +400,24 @@ func _Monitor_GetStateByRevision_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Monitor_AddReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonitorServer).AddReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.monitor.v1.Monitor/AddReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonitorServer).AddReceipt(ctx, req.(*AddReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Monitor_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "google.keytransparency.monitor.v1.Monitor",
 	HandlerType: (*MonitorServer)(nil),
@@ -327,6 +430,10 @@ var _Monitor_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStateByRevision",
 			Handler:    _Monitor_GetStateByRevision_Handler,
 		},
+		{
+			MethodName: "AddReceipt",
+			Handler:    _Monitor_AddReceipt_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "monitor/v1/monitor.proto",
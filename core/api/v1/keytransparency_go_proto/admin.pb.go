@@ -52,7 +52,21 @@ type Directory struct {
 	// Deleted indicates whether the directory has been marked as deleted.
 	// By its presence in a response, this directory has not been garbage
 	// collected.
-	Deleted              bool     `protobuf:"varint,7,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Deleted bool `protobuf:"varint,7,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// mutator is the name of the function that applies mutations to this
+	// directory. Empty selects the default, "entry".
+	Mutator string `protobuf:"bytes,8,opt,name=mutator,proto3" json:"mutator,omitempty"`
+	// paused indicates that the sequencer does not create new revisions for
+	// this directory. Mutations are still queued while paused.
+	Paused bool `protobuf:"varint,9,opt,name=paused,proto3" json:"paused,omitempty"`
+	// paused_reason is the reason given when the directory was paused.
+	PausedReason string `protobuf:"bytes,10,opt,name=paused_reason,json=pausedReason,proto3" json:"paused_reason,omitempty"`
+	// receipt_key is the public key that signs receipts for queued mutations.
+	// It is unset if the directory does not issue receipts.
+	ReceiptKey *keyspb.PublicKey `protobuf:"bytes,11,opt,name=receipt_key,json=receiptKey,proto3" json:"receipt_key,omitempty"`
+	// private indicates that only authorized readers may look up users and
+	// mutations of this directory.
+	Private              bool     `protobuf:"varint,12,opt,name=private,proto3" json:"private,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -132,6 +146,41 @@ func (m *Directory) GetDeleted() bool {
 	return false
 }
 
+func (m *Directory) GetMutator() string {
+	if m != nil {
+		return m.Mutator
+	}
+	return ""
+}
+
+func (m *Directory) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+func (m *Directory) GetPausedReason() string {
+	if m != nil {
+		return m.PausedReason
+	}
+	return ""
+}
+
+func (m *Directory) GetReceiptKey() *keyspb.PublicKey {
+	if m != nil {
+		return m.ReceiptKey
+	}
+	return nil
+}
+
+func (m *Directory) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
 // ListDirectories request.
 // No pagination options are provided.
 type ListDirectoriesRequest struct {
@@ -271,12 +320,21 @@ type CreateDirectoryRequest struct {
 	MinInterval *duration.Duration `protobuf:"bytes,2,opt,name=min_interval,json=minInterval,proto3" json:"min_interval,omitempty"`
 	MaxInterval *duration.Duration `protobuf:"bytes,3,opt,name=max_interval,json=maxInterval,proto3" json:"max_interval,omitempty"`
 	// The private_key fields allows callers to set the private key.
-	VrfPrivateKey        *any.Any `protobuf:"bytes,4,opt,name=vrf_private_key,json=vrfPrivateKey,proto3" json:"vrf_private_key,omitempty"`
-	LogPrivateKey        *any.Any `protobuf:"bytes,5,opt,name=log_private_key,json=logPrivateKey,proto3" json:"log_private_key,omitempty"`
-	MapPrivateKey        *any.Any `protobuf:"bytes,6,opt,name=map_private_key,json=mapPrivateKey,proto3" json:"map_private_key,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	VrfPrivateKey *any.Any `protobuf:"bytes,4,opt,name=vrf_private_key,json=vrfPrivateKey,proto3" json:"vrf_private_key,omitempty"`
+	LogPrivateKey *any.Any `protobuf:"bytes,5,opt,name=log_private_key,json=logPrivateKey,proto3" json:"log_private_key,omitempty"`
+	MapPrivateKey *any.Any `protobuf:"bytes,6,opt,name=map_private_key,json=mapPrivateKey,proto3" json:"map_private_key,omitempty"`
+	// mutator is the name of a registered mutation function.
+	// Empty selects the default.
+	Mutator string `protobuf:"bytes,7,opt,name=mutator,proto3" json:"mutator,omitempty"`
+	// private restricts reads of users and mutations to authorized readers.
+	Private bool `protobuf:"varint,8,opt,name=private,proto3" json:"private,omitempty"`
+	// receipt_key is the public key of the key servers' receipt signing key.
+	// Clients reject unsigned receipts from directories with a receipt key.
+	// Empty if the directory does not issue receipts.
+	ReceiptKey           *keyspb.PublicKey `protobuf:"bytes,9,opt,name=receipt_key,json=receiptKey,proto3" json:"receipt_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *CreateDirectoryRequest) Reset()         { *m = CreateDirectoryRequest{} }
@@ -346,6 +404,27 @@ func (m *CreateDirectoryRequest) GetMapPrivateKey() *any.Any {
 	return nil
 }
 
+func (m *CreateDirectoryRequest) GetMutator() string {
+	if m != nil {
+		return m.Mutator
+	}
+	return ""
+}
+
+func (m *CreateDirectoryRequest) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *CreateDirectoryRequest) GetReceiptKey() *keyspb.PublicKey {
+	if m != nil {
+		return m.ReceiptKey
+	}
+	return nil
+}
+
 // DeleteDirectoryRequest deletes a directory
 type DeleteDirectoryRequest struct {
 	DirectoryId          string   `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
@@ -426,6 +505,332 @@ func (m *UndeleteDirectoryRequest) GetDirectoryId() string {
 	return ""
 }
 
+// PauseDirectoryRequest stops sequencing a directory.
+type PauseDirectoryRequest struct {
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// reason is an optional explanation that is shown in GetDirectory.
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PauseDirectoryRequest) Reset()         { *m = PauseDirectoryRequest{} }
+func (m *PauseDirectoryRequest) String() string { return proto.CompactTextString(m) }
+func (*PauseDirectoryRequest) ProtoMessage()    {}
+func (*PauseDirectoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{7}
+}
+
+func (m *PauseDirectoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseDirectoryRequest.Unmarshal(m, b)
+}
+func (m *PauseDirectoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseDirectoryRequest.Marshal(b, m, deterministic)
+}
+func (m *PauseDirectoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseDirectoryRequest.Merge(m, src)
+}
+func (m *PauseDirectoryRequest) XXX_Size() int {
+	return xxx_messageInfo_PauseDirectoryRequest.Size(m)
+}
+func (m *PauseDirectoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseDirectoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PauseDirectoryRequest proto.InternalMessageInfo
+
+func (m *PauseDirectoryRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *PauseDirectoryRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// ResumeDirectoryRequest resumes sequencing a paused directory.
+type ResumeDirectoryRequest struct {
+	DirectoryId          string   `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResumeDirectoryRequest) Reset()         { *m = ResumeDirectoryRequest{} }
+func (m *ResumeDirectoryRequest) String() string { return proto.CompactTextString(m) }
+func (*ResumeDirectoryRequest) ProtoMessage()    {}
+func (*ResumeDirectoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{8}
+}
+
+func (m *ResumeDirectoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeDirectoryRequest.Unmarshal(m, b)
+}
+func (m *ResumeDirectoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeDirectoryRequest.Marshal(b, m, deterministic)
+}
+func (m *ResumeDirectoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeDirectoryRequest.Merge(m, src)
+}
+func (m *ResumeDirectoryRequest) XXX_Size() int {
+	return xxx_messageInfo_ResumeDirectoryRequest.Size(m)
+}
+func (m *ResumeDirectoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeDirectoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResumeDirectoryRequest proto.InternalMessageInfo
+
+func (m *ResumeDirectoryRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+// InputLog is a log that mutations are queued in before they are sequenced.
+type InputLog struct {
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// writable logs receive new mutations. Logs that are not writable are
+	// still read by the sequencer until they are empty.
+	Writable             bool     `protobuf:"varint,2,opt,name=writable,proto3" json:"writable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InputLog) Reset()         { *m = InputLog{} }
+func (m *InputLog) String() string { return proto.CompactTextString(m) }
+func (*InputLog) ProtoMessage()    {}
+func (*InputLog) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{9}
+}
+
+func (m *InputLog) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InputLog.Unmarshal(m, b)
+}
+func (m *InputLog) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InputLog.Marshal(b, m, deterministic)
+}
+func (m *InputLog) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InputLog.Merge(m, src)
+}
+func (m *InputLog) XXX_Size() int {
+	return xxx_messageInfo_InputLog.Size(m)
+}
+func (m *InputLog) XXX_DiscardUnknown() {
+	xxx_messageInfo_InputLog.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InputLog proto.InternalMessageInfo
+
+func (m *InputLog) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *InputLog) GetWritable() bool {
+	if m != nil {
+		return m.Writable
+	}
+	return false
+}
+
+// ListInputLogsRequest lists the input logs of a directory.
+type ListInputLogsRequest struct {
+	DirectoryId          string   `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListInputLogsRequest) Reset()         { *m = ListInputLogsRequest{} }
+func (m *ListInputLogsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListInputLogsRequest) ProtoMessage()    {}
+func (*ListInputLogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{10}
+}
+
+func (m *ListInputLogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListInputLogsRequest.Unmarshal(m, b)
+}
+func (m *ListInputLogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListInputLogsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListInputLogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListInputLogsRequest.Merge(m, src)
+}
+func (m *ListInputLogsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListInputLogsRequest.Size(m)
+}
+func (m *ListInputLogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListInputLogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListInputLogsRequest proto.InternalMessageInfo
+
+func (m *ListInputLogsRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+// ListInputLogsResponse contains the input logs of a directory, ordered by
+// log_id.
+type ListInputLogsResponse struct {
+	Logs                 []*InputLog `protobuf:"bytes,1,rep,name=logs,proto3" json:"logs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListInputLogsResponse) Reset()         { *m = ListInputLogsResponse{} }
+func (m *ListInputLogsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListInputLogsResponse) ProtoMessage()    {}
+func (*ListInputLogsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{11}
+}
+
+func (m *ListInputLogsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListInputLogsResponse.Unmarshal(m, b)
+}
+func (m *ListInputLogsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListInputLogsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListInputLogsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListInputLogsResponse.Merge(m, src)
+}
+func (m *ListInputLogsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListInputLogsResponse.Size(m)
+}
+func (m *ListInputLogsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListInputLogsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListInputLogsResponse proto.InternalMessageInfo
+
+func (m *ListInputLogsResponse) GetLogs() []*InputLog {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+// CreateInputLogRequest adds a writable input log to a directory.
+type CreateInputLogRequest struct {
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// log_id is the ID of the new log. If zero, the next unused ID is chosen.
+	// Creating a log that already exists has no effect.
+	LogId                int64    `protobuf:"varint,2,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateInputLogRequest) Reset()         { *m = CreateInputLogRequest{} }
+func (m *CreateInputLogRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateInputLogRequest) ProtoMessage()    {}
+func (*CreateInputLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{12}
+}
+
+func (m *CreateInputLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateInputLogRequest.Unmarshal(m, b)
+}
+func (m *CreateInputLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateInputLogRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateInputLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateInputLogRequest.Merge(m, src)
+}
+func (m *CreateInputLogRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateInputLogRequest.Size(m)
+}
+func (m *CreateInputLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateInputLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateInputLogRequest proto.InternalMessageInfo
+
+func (m *CreateInputLogRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *CreateInputLogRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+// UpdateInputLogRequest enables or disables writes to an input log.
+type UpdateInputLogRequest struct {
+	DirectoryId          string   `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	LogId                int64    `protobuf:"varint,2,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	Writable             bool     `protobuf:"varint,3,opt,name=writable,proto3" json:"writable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateInputLogRequest) Reset()         { *m = UpdateInputLogRequest{} }
+func (m *UpdateInputLogRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateInputLogRequest) ProtoMessage()    {}
+func (*UpdateInputLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{13}
+}
+
+func (m *UpdateInputLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateInputLogRequest.Unmarshal(m, b)
+}
+func (m *UpdateInputLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateInputLogRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateInputLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateInputLogRequest.Merge(m, src)
+}
+func (m *UpdateInputLogRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateInputLogRequest.Size(m)
+}
+func (m *UpdateInputLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateInputLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateInputLogRequest proto.InternalMessageInfo
+
+func (m *UpdateInputLogRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *UpdateInputLogRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *UpdateInputLogRequest) GetWritable() bool {
+	if m != nil {
+		return m.Writable
+	}
+	return false
+}
+
 // GarbageCollect request.
 type GarbageCollectRequest struct {
 	// Soft-deleted directories with a deleted timestamp before this will be fully
@@ -440,7 +845,7 @@ func (m *GarbageCollectRequest) Reset()         { *m = GarbageCollectRequest{} }
 func (m *GarbageCollectRequest) String() string { return proto.CompactTextString(m) }
 func (*GarbageCollectRequest) ProtoMessage()    {}
 func (*GarbageCollectRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_599f1e5eaea78ae3, []int{7}
+	return fileDescriptor_599f1e5eaea78ae3, []int{14}
 }
 
 func (m *GarbageCollectRequest) XXX_Unmarshal(b []byte) error {
@@ -479,7 +884,7 @@ func (m *GarbageCollectResponse) Reset()         { *m = GarbageCollectResponse{}
 func (m *GarbageCollectResponse) String() string { return proto.CompactTextString(m) }
 func (*GarbageCollectResponse) ProtoMessage()    {}
 func (*GarbageCollectResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_599f1e5eaea78ae3, []int{8}
+	return fileDescriptor_599f1e5eaea78ae3, []int{15}
 }
 
 func (m *GarbageCollectResponse) XXX_Unmarshal(b []byte) error {
@@ -507,103 +912,607 @@ func (m *GarbageCollectResponse) GetDirectories() []*Directory {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*Directory)(nil), "google.keytransparency.v1.Directory")
-	proto.RegisterType((*ListDirectoriesRequest)(nil), "google.keytransparency.v1.ListDirectoriesRequest")
-	proto.RegisterType((*ListDirectoriesResponse)(nil), "google.keytransparency.v1.ListDirectoriesResponse")
-	proto.RegisterType((*GetDirectoryRequest)(nil), "google.keytransparency.v1.GetDirectoryRequest")
-	proto.RegisterType((*CreateDirectoryRequest)(nil), "google.keytransparency.v1.CreateDirectoryRequest")
-	proto.RegisterType((*DeleteDirectoryRequest)(nil), "google.keytransparency.v1.DeleteDirectoryRequest")
-	proto.RegisterType((*UndeleteDirectoryRequest)(nil), "google.keytransparency.v1.UndeleteDirectoryRequest")
-	proto.RegisterType((*GarbageCollectRequest)(nil), "google.keytransparency.v1.GarbageCollectRequest")
-	proto.RegisterType((*GarbageCollectResponse)(nil), "google.keytransparency.v1.GarbageCollectResponse")
+// PruneQueue request.
+type PruneQueueRequest struct {
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// retention is how long mutations are kept after they are queued, even if
+	// they have already been published. ListMutations needs them for auditing.
+	Retention            *duration.Duration `protobuf:"bytes,2,opt,name=retention,proto3" json:"retention,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
-func init() { proto.RegisterFile("v1/admin.proto", fileDescriptor_599f1e5eaea78ae3) }
+func (m *PruneQueueRequest) Reset()         { *m = PruneQueueRequest{} }
+func (m *PruneQueueRequest) String() string { return proto.CompactTextString(m) }
+func (*PruneQueueRequest) ProtoMessage()    {}
+func (*PruneQueueRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{16}
+}
 
-var fileDescriptor_599f1e5eaea78ae3 = []byte{
-	// 765 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x96, 0xcd, 0x6e, 0xd3, 0x4a,
-	0x14, 0xc7, 0xe5, 0xe4, 0x36, 0xbd, 0x9d, 0xf4, 0x26, 0xea, 0xdc, 0x92, 0xba, 0x06, 0x95, 0x60,
-	0x10, 0x84, 0x2e, 0x6c, 0x92, 0xee, 0x4a, 0x59, 0x94, 0x96, 0x96, 0x2a, 0x2c, 0x2a, 0xab, 0x6c,
-	0x60, 0x11, 0x26, 0xf1, 0xc4, 0x1d, 0xd5, 0xf6, 0x98, 0xf1, 0xc4, 0xd4, 0x42, 0x6c, 0x10, 0x62,
-	0xc5, 0xa6, 0xe2, 0x39, 0x78, 0x1a, 0x78, 0x04, 0x5e, 0x80, 0x37, 0x40, 0xb6, 0xc7, 0xf9, 0x70,
-	0x52, 0xf7, 0x03, 0x56, 0xd6, 0xf8, 0x9c, 0xdf, 0x99, 0x33, 0xff, 0x73, 0xce, 0xd8, 0xa0, 0x12,
-	0x34, 0x75, 0x64, 0x3a, 0xc4, 0xd5, 0x3c, 0x46, 0x39, 0x85, 0xab, 0x16, 0xa5, 0x96, 0x8d, 0xb5,
-	0x13, 0x1c, 0x72, 0x86, 0x5c, 0xdf, 0x43, 0x0c, 0xbb, 0xbd, 0x50, 0x0b, 0x9a, 0x8a, 0xd2, 0x63,
-	0xa1, 0xc7, 0xa9, 0x7e, 0x82, 0x43, 0xdf, 0xeb, 0x8a, 0x47, 0x82, 0x29, 0xb7, 0x12, 0x4c, 0x47,
-	0x1e, 0xd1, 0x91, 0xeb, 0x52, 0x8e, 0x38, 0xa1, 0xae, 0x2f, 0xac, 0x22, 0xa8, 0x1e, 0xaf, 0xba,
-	0x83, 0xbe, 0x8e, 0xdc, 0x50, 0x98, 0xd6, 0xb2, 0x26, 0x73, 0xc0, 0x62, 0x56, 0xd8, 0x6f, 0x66,
-	0xed, 0xd8, 0xf1, 0x78, 0x0a, 0xdf, 0xce, 0x1a, 0x39, 0x71, 0xb0, 0xcf, 0x91, 0xe3, 0x09, 0x87,
-	0x0a, 0x67, 0xc4, 0xb6, 0x09, 0x12, 0xd1, 0xd4, 0x6f, 0x05, 0xb0, 0xb0, 0x4b, 0x18, 0xee, 0x71,
-	0xca, 0x42, 0x78, 0x07, 0x2c, 0x9a, 0xe9, 0xa2, 0x43, 0x4c, 0x59, 0xaa, 0x4b, 0x8d, 0x05, 0xa3,
-	0x3c, 0x7c, 0x77, 0x60, 0xc2, 0x3a, 0x28, 0xda, 0xd4, 0x92, 0x0b, 0x75, 0xa9, 0x51, 0x6e, 0x55,
-	0xb4, 0x61, 0xb8, 0x23, 0x86, 0xb1, 0x11, 0x99, 0x22, 0x0f, 0x07, 0x79, 0x72, 0x71, 0xb6, 0x87,
-	0x83, 0x3c, 0x78, 0x17, 0x14, 0x03, 0xd6, 0x97, 0xff, 0x89, 0x3d, 0x96, 0x34, 0xa1, 0xdb, 0xe1,
-	0xa0, 0x6b, 0x93, 0x5e, 0x1b, 0x87, 0x46, 0x64, 0x85, 0x5b, 0x60, 0xd1, 0x21, 0x6e, 0x87, 0xb8,
-	0x1c, 0xb3, 0x00, 0xd9, 0xf2, 0x5c, 0xec, 0xbd, 0xaa, 0x89, 0x72, 0xa4, 0x27, 0xd4, 0x76, 0x85,
-	0x3c, 0x46, 0xd9, 0x21, 0xee, 0x81, 0xf0, 0x8e, 0x69, 0x74, 0x3a, 0xa2, 0x4b, 0x17, 0xd3, 0xe8,
-	0x74, 0x48, 0xcb, 0x60, 0xde, 0xc4, 0x36, 0xe6, 0xd8, 0x94, 0xe7, 0xeb, 0x52, 0xe3, 0x5f, 0x23,
-	0x5d, 0xaa, 0x8f, 0x41, 0xed, 0x05, 0xf1, 0x79, 0x2a, 0x19, 0xc1, 0xbe, 0x81, 0xdf, 0x0e, 0xb0,
-	0xcf, 0x23, 0xed, 0xfc, 0x63, 0xfa, 0xae, 0x93, 0x82, 0x52, 0x0c, 0x96, 0xa3, 0x77, 0xbb, 0x02,
-	0x46, 0x60, 0x65, 0x0a, 0xf6, 0x3d, 0xea, 0xfa, 0x18, 0xee, 0x81, 0xa1, 0xca, 0x04, 0xfb, 0xb2,
-	0x54, 0x2f, 0x36, 0xca, 0xad, 0x7b, 0xda, 0xb9, 0xbd, 0xa7, 0x0d, 0x8b, 0x66, 0x8c, 0x83, 0xea,
-	0x6b, 0xf0, 0xff, 0x3e, 0xe6, 0x23, 0xe3, 0x28, 0xb9, 0x8b, 0x0a, 0x9b, 0xcd, 0xbf, 0x30, 0x9d,
-	0xff, 0xaf, 0x02, 0xa8, 0xed, 0x30, 0x8c, 0x38, 0xbe, 0xce, 0x06, 0xd9, 0x82, 0x16, 0xfe, 0xa8,
-	0xa0, 0xc5, 0x2b, 0x15, 0x74, 0x0b, 0x54, 0x03, 0xd6, 0xef, 0x78, 0x8c, 0x04, 0x88, 0xe3, 0xce,
-	0x09, 0x0e, 0x45, 0xf7, 0x2d, 0x4f, 0x05, 0xd8, 0x76, 0x43, 0xe3, 0xbf, 0x80, 0xf5, 0x0f, 0x13,
-	0xdf, 0x36, 0x0e, 0x23, 0xda, 0xa6, 0xd6, 0x04, 0x3d, 0x97, 0x47, 0xdb, 0xd4, 0x9a, 0xa4, 0x1d,
-	0xe4, 0x4d, 0xd0, 0xa5, 0x3c, 0xda, 0x41, 0xde, 0x88, 0x8e, 0x1a, 0x2e, 0x91, 0xff, 0x1a, 0x92,
-	0xab, 0x4f, 0x80, 0xfc, 0xd2, 0x35, 0xaf, 0x8d, 0xb7, 0xc1, 0x8d, 0x7d, 0xc4, 0xba, 0xc8, 0xc2,
-	0x3b, 0xd4, 0xb6, 0x71, 0x8f, 0xa7, 0x6c, 0x0b, 0x94, 0xba, 0xb8, 0x4f, 0x19, 0x8e, 0xa9, 0x72,
-	0x4b, 0x99, 0x3a, 0xc9, 0x51, 0x7a, 0xef, 0x18, 0xc2, 0x53, 0x7d, 0x03, 0x6a, 0xd9, 0x60, 0x7f,
-	0xb7, 0xf7, 0x5b, 0x3f, 0x4a, 0x60, 0xb9, 0x8d, 0xc3, 0xa3, 0x31, 0xef, 0xed, 0xe8, 0x22, 0x87,
-	0x67, 0x12, 0xa8, 0x66, 0x06, 0x0f, 0x36, 0x73, 0xe2, 0xcf, 0x9e, 0x70, 0xa5, 0x75, 0x15, 0x24,
-	0x39, 0x9b, 0xba, 0xf2, 0xf1, 0xfb, 0xcf, 0xaf, 0x85, 0x25, 0x58, 0xd5, 0x83, 0xa6, 0x3e, 0x96,
-	0x2c, 0xfc, 0x22, 0x81, 0xc5, 0xf1, 0x49, 0x85, 0x5a, 0x4e, 0xf4, 0x19, 0x23, 0xad, 0x5c, 0x4a,
-	0x20, 0xf5, 0x7e, 0xbc, 0x7f, 0x1d, 0xae, 0x65, 0xf6, 0xd7, 0xdf, 0x8f, 0x17, 0xff, 0x03, 0xfc,
-	0x2c, 0x81, 0x6a, 0x66, 0xb4, 0x73, 0x25, 0x9a, 0x7d, 0x0d, 0x5c, 0x32, 0x29, 0x25, 0x4e, 0x6a,
-	0x59, 0xcd, 0x8a, 0xb2, 0x29, 0xad, 0xc3, 0x4f, 0x12, 0xa8, 0x66, 0x1a, 0x3e, 0x37, 0x91, 0xd9,
-	0xc3, 0xa1, 0xd4, 0xa6, 0x3a, 0xf2, 0x59, 0xf4, 0x99, 0x4c, 0xf5, 0x58, 0xbf, 0x48, 0x8f, 0x33,
-	0x09, 0x2c, 0x4d, 0x8d, 0x0e, 0xdc, 0xc8, 0x49, 0xe4, 0xbc, 0x41, 0x3b, 0x37, 0x15, 0x3d, 0x4e,
-	0xe5, 0xe1, 0xfa, 0x83, 0xfc, 0x54, 0x36, 0x07, 0x22, 0x30, 0x1c, 0x80, 0xca, 0xe4, 0x04, 0xc1,
-	0x47, 0x79, 0x3d, 0x33, 0x6b, 0x72, 0x95, 0xe6, 0x15, 0x88, 0xa4, 0x85, 0x9f, 0x3e, 0x7f, 0xb5,
-	0x67, 0x11, 0x7e, 0x3c, 0xe8, 0x6a, 0x3d, 0xea, 0xe8, 0xe2, 0x07, 0x23, 0x83, 0xeb, 0x3d, 0xca,
-	0x92, 0x7f, 0x9d, 0xa0, 0x99, 0xb5, 0x75, 0x2c, 0xda, 0x49, 0x8e, 0x5e, 0x8a, 0x1f, 0x1b, 0xbf,
-	0x03, 0x00, 0x00, 0xff, 0xff, 0x8b, 0xd9, 0x2e, 0x51, 0x63, 0x09, 0x00, 0x00,
+func (m *PruneQueueRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruneQueueRequest.Unmarshal(m, b)
+}
+func (m *PruneQueueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruneQueueRequest.Marshal(b, m, deterministic)
+}
+func (m *PruneQueueRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruneQueueRequest.Merge(m, src)
+}
+func (m *PruneQueueRequest) XXX_Size() int {
+	return xxx_messageInfo_PruneQueueRequest.Size(m)
+}
+func (m *PruneQueueRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruneQueueRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_PruneQueueRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *PruneQueueRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
 
-// KeyTransparencyAdminClient is the client API for KeyTransparencyAdmin service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type KeyTransparencyAdminClient interface {
-	// ListDirectories returns a list of all directories this Key Transparency
-	// server operates on.
-	ListDirectories(ctx context.Context, in *ListDirectoriesRequest, opts ...grpc.CallOption) (*ListDirectoriesResponse, error)
-	// GetDirectory returns the confiuration information for a given directory.
-	GetDirectory(ctx context.Context, in *GetDirectoryRequest, opts ...grpc.CallOption) (*Directory, error)
-	// CreateDirectory creates a new Trillian log/map pair.  A unique directoryId
-	// must be provided.  To create a new directory with the same name as a
-	// previously deleted directory, a user must wait X days until the directory
-	// is garbage collected.
-	CreateDirectory(ctx context.Context, in *CreateDirectoryRequest, opts ...grpc.CallOption) (*Directory, error)
-	// DeleteDirectory marks a directory as deleted.  Directories will be garbage
-	// collected after X days.
-	DeleteDirectory(ctx context.Context, in *DeleteDirectoryRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// UndeleteDirectory marks a previously deleted directory as active if it has
+func (m *PruneQueueRequest) GetRetention() *duration.Duration {
+	if m != nil {
+		return m.Retention
+	}
+	return nil
+}
+
+// PruneQueue response.
+type PruneQueueResponse struct {
+	// revision is the latest published revision. Only mutations included in
+	// this revision or earlier were pruned.
+	Revision int64 `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	// pruned is the number of mutations deleted from the queue.
+	Pruned               int64    `protobuf:"varint,2,opt,name=pruned,proto3" json:"pruned,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PruneQueueResponse) Reset()         { *m = PruneQueueResponse{} }
+func (m *PruneQueueResponse) String() string { return proto.CompactTextString(m) }
+func (*PruneQueueResponse) ProtoMessage()    {}
+func (*PruneQueueResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{17}
+}
+
+func (m *PruneQueueResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruneQueueResponse.Unmarshal(m, b)
+}
+func (m *PruneQueueResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruneQueueResponse.Marshal(b, m, deterministic)
+}
+func (m *PruneQueueResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruneQueueResponse.Merge(m, src)
+}
+func (m *PruneQueueResponse) XXX_Size() int {
+	return xxx_messageInfo_PruneQueueResponse.Size(m)
+}
+func (m *PruneQueueResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruneQueueResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PruneQueueResponse proto.InternalMessageInfo
+
+func (m *PruneQueueResponse) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *PruneQueueResponse) GetPruned() int64 {
+	if m != nil {
+		return m.Pruned
+	}
+	return 0
+}
+
+// RebuildMap request.
+type RebuildMapRequest struct {
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// map_id resumes an interrupted rebuild into the map that an earlier
+	// RebuildMap call created. If unset, a new map is created.
+	MapId                int64    `protobuf:"varint,2,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RebuildMapRequest) Reset()         { *m = RebuildMapRequest{} }
+func (m *RebuildMapRequest) String() string { return proto.CompactTextString(m) }
+func (*RebuildMapRequest) ProtoMessage()    {}
+func (*RebuildMapRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{18}
+}
+
+func (m *RebuildMapRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RebuildMapRequest.Unmarshal(m, b)
+}
+func (m *RebuildMapRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RebuildMapRequest.Marshal(b, m, deterministic)
+}
+func (m *RebuildMapRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RebuildMapRequest.Merge(m, src)
+}
+func (m *RebuildMapRequest) XXX_Size() int {
+	return xxx_messageInfo_RebuildMapRequest.Size(m)
+}
+func (m *RebuildMapRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RebuildMapRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RebuildMapRequest proto.InternalMessageInfo
+
+func (m *RebuildMapRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *RebuildMapRequest) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+// GetMapRebuild request.
+type GetMapRebuildRequest struct {
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// map_id is the map that is being rebuilt.
+	MapId                int64    `protobuf:"varint,2,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMapRebuildRequest) Reset()         { *m = GetMapRebuildRequest{} }
+func (m *GetMapRebuildRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMapRebuildRequest) ProtoMessage()    {}
+func (*GetMapRebuildRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{19}
+}
+
+func (m *GetMapRebuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMapRebuildRequest.Unmarshal(m, b)
+}
+func (m *GetMapRebuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMapRebuildRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMapRebuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMapRebuildRequest.Merge(m, src)
+}
+func (m *GetMapRebuildRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMapRebuildRequest.Size(m)
+}
+func (m *GetMapRebuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMapRebuildRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMapRebuildRequest proto.InternalMessageInfo
+
+func (m *GetMapRebuildRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *GetMapRebuildRequest) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+// CancelMapRebuild request.
+type CancelMapRebuildRequest struct {
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// map_id is the map that is being rebuilt.
+	MapId                int64    `protobuf:"varint,2,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelMapRebuildRequest) Reset()         { *m = CancelMapRebuildRequest{} }
+func (m *CancelMapRebuildRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelMapRebuildRequest) ProtoMessage()    {}
+func (*CancelMapRebuildRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{20}
+}
+
+func (m *CancelMapRebuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelMapRebuildRequest.Unmarshal(m, b)
+}
+func (m *CancelMapRebuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelMapRebuildRequest.Marshal(b, m, deterministic)
+}
+func (m *CancelMapRebuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelMapRebuildRequest.Merge(m, src)
+}
+func (m *CancelMapRebuildRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelMapRebuildRequest.Size(m)
+}
+func (m *CancelMapRebuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelMapRebuildRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelMapRebuildRequest proto.InternalMessageInfo
+
+func (m *CancelMapRebuildRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *CancelMapRebuildRequest) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+// Divergence describes a rebuilt revision that does not match the map root
+// published in the log of map roots.
+type Divergence struct {
+	// revision is the first revision that does not match.
+	Revision int64 `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	// reason describes the mismatch.
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// published_root_hash is the root hash found in the log of map roots.
+	PublishedRootHash []byte `protobuf:"bytes,3,opt,name=published_root_hash,json=publishedRootHash,proto3" json:"published_root_hash,omitempty"`
+	// rebuilt_root_hash is the root hash computed from the replayed mutations.
+	RebuiltRootHash      []byte   `protobuf:"bytes,4,opt,name=rebuilt_root_hash,json=rebuiltRootHash,proto3" json:"rebuilt_root_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Divergence) Reset()         { *m = Divergence{} }
+func (m *Divergence) String() string { return proto.CompactTextString(m) }
+func (*Divergence) ProtoMessage()    {}
+func (*Divergence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{21}
+}
+
+func (m *Divergence) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Divergence.Unmarshal(m, b)
+}
+func (m *Divergence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Divergence.Marshal(b, m, deterministic)
+}
+func (m *Divergence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Divergence.Merge(m, src)
+}
+func (m *Divergence) XXX_Size() int {
+	return xxx_messageInfo_Divergence.Size(m)
+}
+func (m *Divergence) XXX_DiscardUnknown() {
+	xxx_messageInfo_Divergence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Divergence proto.InternalMessageInfo
+
+func (m *Divergence) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Divergence) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Divergence) GetPublishedRootHash() []byte {
+	if m != nil {
+		return m.PublishedRootHash
+	}
+	return nil
+}
+
+func (m *Divergence) GetRebuiltRootHash() []byte {
+	if m != nil {
+		return m.RebuiltRootHash
+	}
+	return nil
+}
+
+// MapRebuild reports the progress of replaying the revisions of a directory
+// into a new map.
+type MapRebuild struct {
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// map_id is the tree ID of the new map.
+	MapId int64 `protobuf:"varint,2,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	// revision is the latest revision replayed into the new map and checked.
+	Revision int64 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	// target is the latest published revision when the rebuild started. The
+	// rebuild stops after replaying it.
+	Target int64 `protobuf:"varint,4,opt,name=target,proto3" json:"target,omitempty"`
+	// done is set once the rebuild has stopped.
+	Done bool `protobuf:"varint,5,opt,name=done,proto3" json:"done,omitempty"`
+	// divergence is set if the rebuild stopped at a revision that does not
+	// match the log of map roots.
+	Divergence *Divergence `protobuf:"bytes,6,opt,name=divergence,proto3" json:"divergence,omitempty"`
+	// error is set if the rebuild stopped because of an error. It can be
+	// resumed by calling RebuildMap with map_id.
+	Error                string   `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MapRebuild) Reset()         { *m = MapRebuild{} }
+func (m *MapRebuild) String() string { return proto.CompactTextString(m) }
+func (*MapRebuild) ProtoMessage()    {}
+func (*MapRebuild) Descriptor() ([]byte, []int) {
+	return fileDescriptor_599f1e5eaea78ae3, []int{22}
+}
+
+func (m *MapRebuild) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapRebuild.Unmarshal(m, b)
+}
+func (m *MapRebuild) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapRebuild.Marshal(b, m, deterministic)
+}
+func (m *MapRebuild) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapRebuild.Merge(m, src)
+}
+func (m *MapRebuild) XXX_Size() int {
+	return xxx_messageInfo_MapRebuild.Size(m)
+}
+func (m *MapRebuild) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapRebuild.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapRebuild proto.InternalMessageInfo
+
+func (m *MapRebuild) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *MapRebuild) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+func (m *MapRebuild) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *MapRebuild) GetTarget() int64 {
+	if m != nil {
+		return m.Target
+	}
+	return 0
+}
+
+func (m *MapRebuild) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+func (m *MapRebuild) GetDivergence() *Divergence {
+	if m != nil {
+		return m.Divergence
+	}
+	return nil
+}
+
+func (m *MapRebuild) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Directory)(nil), "google.keytransparency.v1.Directory")
+	proto.RegisterType((*ListDirectoriesRequest)(nil), "google.keytransparency.v1.ListDirectoriesRequest")
+	proto.RegisterType((*ListDirectoriesResponse)(nil), "google.keytransparency.v1.ListDirectoriesResponse")
+	proto.RegisterType((*GetDirectoryRequest)(nil), "google.keytransparency.v1.GetDirectoryRequest")
+	proto.RegisterType((*CreateDirectoryRequest)(nil), "google.keytransparency.v1.CreateDirectoryRequest")
+	proto.RegisterType((*DeleteDirectoryRequest)(nil), "google.keytransparency.v1.DeleteDirectoryRequest")
+	proto.RegisterType((*UndeleteDirectoryRequest)(nil), "google.keytransparency.v1.UndeleteDirectoryRequest")
+	proto.RegisterType((*PauseDirectoryRequest)(nil), "google.keytransparency.v1.PauseDirectoryRequest")
+	proto.RegisterType((*ResumeDirectoryRequest)(nil), "google.keytransparency.v1.ResumeDirectoryRequest")
+	proto.RegisterType((*InputLog)(nil), "google.keytransparency.v1.InputLog")
+	proto.RegisterType((*ListInputLogsRequest)(nil), "google.keytransparency.v1.ListInputLogsRequest")
+	proto.RegisterType((*ListInputLogsResponse)(nil), "google.keytransparency.v1.ListInputLogsResponse")
+	proto.RegisterType((*CreateInputLogRequest)(nil), "google.keytransparency.v1.CreateInputLogRequest")
+	proto.RegisterType((*UpdateInputLogRequest)(nil), "google.keytransparency.v1.UpdateInputLogRequest")
+	proto.RegisterType((*GarbageCollectRequest)(nil), "google.keytransparency.v1.GarbageCollectRequest")
+	proto.RegisterType((*GarbageCollectResponse)(nil), "google.keytransparency.v1.GarbageCollectResponse")
+	proto.RegisterType((*PruneQueueRequest)(nil), "google.keytransparency.v1.PruneQueueRequest")
+	proto.RegisterType((*PruneQueueResponse)(nil), "google.keytransparency.v1.PruneQueueResponse")
+	proto.RegisterType((*RebuildMapRequest)(nil), "google.keytransparency.v1.RebuildMapRequest")
+	proto.RegisterType((*GetMapRebuildRequest)(nil), "google.keytransparency.v1.GetMapRebuildRequest")
+	proto.RegisterType((*CancelMapRebuildRequest)(nil), "google.keytransparency.v1.CancelMapRebuildRequest")
+	proto.RegisterType((*Divergence)(nil), "google.keytransparency.v1.Divergence")
+	proto.RegisterType((*MapRebuild)(nil), "google.keytransparency.v1.MapRebuild")
+}
+
+func init() { proto.RegisterFile("v1/admin.proto", fileDescriptor_599f1e5eaea78ae3) }
+
+var fileDescriptor_599f1e5eaea78ae3 = []byte{
+	// 1461 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcf, 0x73, 0xdb, 0xc4,
+	0x17, 0x1f, 0xc5, 0x49, 0xea, 0x3c, 0x27, 0xf6, 0xd7, 0xdb, 0x24, 0x55, 0xf5, 0x65, 0x4a, 0x50,
+	0x5b, 0x48, 0x33, 0xd4, 0x8a, 0x5d, 0x86, 0x42, 0x08, 0x87, 0xb6, 0xe9, 0x8f, 0x4c, 0xda, 0x99,
+	0x54, 0xb4, 0x17, 0x38, 0x98, 0xb5, 0xb5, 0x71, 0x34, 0x95, 0xb4, 0x62, 0xb5, 0x72, 0xeb, 0xe9,
+	0xf4, 0xc2, 0x30, 0x5c, 0x60, 0x98, 0xe9, 0x70, 0xe0, 0xd2, 0x19, 0x86, 0x19, 0xf8, 0x03, 0xf8,
+	0x57, 0xe0, 0xc4, 0x9d, 0x1b, 0x67, 0xee, 0x8c, 0x56, 0x2b, 0xd9, 0x96, 0x1d, 0xd9, 0x4e, 0x7b,
+	0x8a, 0x57, 0xef, 0x7d, 0xde, 0x7e, 0xf6, 0xfd, 0x58, 0x7d, 0x14, 0x28, 0x77, 0xeb, 0x06, 0xb6,
+	0x5c, 0xdb, 0xab, 0xf9, 0x8c, 0x72, 0x8a, 0xce, 0x77, 0x28, 0xed, 0x38, 0xa4, 0xf6, 0x84, 0xf4,
+	0x38, 0xc3, 0x5e, 0xe0, 0x63, 0x46, 0xbc, 0x76, 0xaf, 0xd6, 0xad, 0x6b, 0x5a, 0x9b, 0xf5, 0x7c,
+	0x4e, 0x8d, 0x27, 0xa4, 0x17, 0xf8, 0x2d, 0xf9, 0x27, 0x86, 0x69, 0x6f, 0xc5, 0x30, 0x03, 0xfb,
+	0xb6, 0x81, 0x3d, 0x8f, 0x72, 0xcc, 0x6d, 0xea, 0x05, 0xd2, 0x2a, 0x83, 0x1a, 0x62, 0xd5, 0x0a,
+	0x8f, 0x0c, 0xec, 0xf5, 0xa4, 0xe9, 0x42, 0xd6, 0x64, 0x85, 0x4c, 0x60, 0xa5, 0xfd, 0xff, 0x59,
+	0x3b, 0x71, 0x7d, 0x9e, 0x80, 0xdf, 0xce, 0x1a, 0xb9, 0xed, 0x92, 0x80, 0x63, 0xd7, 0x97, 0x0e,
+	0x65, 0xce, 0x6c, 0xc7, 0xb1, 0xb1, 0x8c, 0xa6, 0xff, 0x51, 0x80, 0xa5, 0x3d, 0x9b, 0x91, 0x36,
+	0xa7, 0xac, 0x87, 0xde, 0x81, 0x65, 0x2b, 0x59, 0x34, 0x6d, 0x4b, 0x55, 0x36, 0x94, 0xcd, 0x25,
+	0xb3, 0x94, 0x3e, 0xdb, 0xb7, 0xd0, 0x06, 0x14, 0x1c, 0xda, 0x51, 0xe7, 0x36, 0x94, 0xcd, 0x52,
+	0xa3, 0x5c, 0x4b, 0xc3, 0x3d, 0x62, 0x84, 0x98, 0x91, 0x29, 0xf2, 0x70, 0xb1, 0xaf, 0x16, 0xc6,
+	0x7b, 0xb8, 0xd8, 0x47, 0x17, 0xa1, 0xd0, 0x65, 0x47, 0xea, 0xbc, 0xf0, 0xa8, 0xd6, 0x64, 0xde,
+	0x0e, 0xc3, 0x96, 0x63, 0xb7, 0x0f, 0x48, 0xcf, 0x8c, 0xac, 0x68, 0x17, 0x96, 0x5d, 0xdb, 0x6b,
+	0xda, 0x1e, 0x27, 0xac, 0x8b, 0x1d, 0x75, 0x41, 0x78, 0x9f, 0xaf, 0xc9, 0x72, 0x24, 0x27, 0xac,
+	0xed, 0xc9, 0xf4, 0x98, 0x25, 0xd7, 0xf6, 0xf6, 0xa5, 0xb7, 0x40, 0xe3, 0x67, 0x7d, 0xf4, 0xe2,
+	0x64, 0x34, 0x7e, 0x96, 0xa2, 0x55, 0x38, 0x63, 0x11, 0x87, 0x70, 0x62, 0xa9, 0x67, 0x36, 0x94,
+	0xcd, 0xa2, 0x99, 0x2c, 0x23, 0x8b, 0x1b, 0x72, 0xcc, 0x29, 0x53, 0x8b, 0x22, 0x39, 0xc9, 0x12,
+	0xad, 0xc3, 0xa2, 0x8f, 0xc3, 0x80, 0x58, 0xea, 0x92, 0x80, 0xc8, 0x15, 0xba, 0x08, 0x2b, 0xf1,
+	0xaf, 0x26, 0x23, 0x38, 0xa0, 0x9e, 0x0a, 0x02, 0xb7, 0x1c, 0x3f, 0x34, 0xc5, 0x33, 0xd4, 0x80,
+	0x12, 0x23, 0x6d, 0x62, 0xfb, 0xbc, 0xf9, 0x84, 0xf4, 0xd4, 0xd2, 0x49, 0x99, 0x01, 0xe9, 0x75,
+	0x40, 0x7a, 0x11, 0x15, 0x9f, 0xd9, 0x5d, 0xcc, 0x89, 0xba, 0x1c, 0x93, 0x94, 0x4b, 0xfd, 0x13,
+	0x58, 0xbf, 0x6f, 0x07, 0x3c, 0xa9, 0xab, 0x4d, 0x02, 0x93, 0x7c, 0x15, 0x92, 0x80, 0x47, 0x05,
+	0x0e, 0x8e, 0xe9, 0xd3, 0x66, 0x72, 0x3a, 0x45, 0x00, 0x4b, 0xd1, 0xb3, 0xbd, 0xf8, 0x91, 0x8e,
+	0xe1, 0xdc, 0x08, 0x38, 0xf0, 0xa9, 0x17, 0x10, 0x74, 0x07, 0xd2, 0x56, 0xb0, 0x49, 0xa0, 0x2a,
+	0x1b, 0x85, 0xcd, 0x52, 0xe3, 0x52, 0xed, 0xc4, 0x01, 0xa9, 0xa5, 0x9d, 0x65, 0x0e, 0x02, 0xf5,
+	0x2f, 0xe0, 0xec, 0x5d, 0xc2, 0xfb, 0xc6, 0x3e, 0xb9, 0x49, 0xdd, 0x97, 0xe5, 0x3f, 0x37, 0xca,
+	0xff, 0xaf, 0x02, 0xac, 0xdf, 0x62, 0x04, 0x73, 0x72, 0x9a, 0x0d, 0xb2, 0x5d, 0x37, 0xf7, 0x5a,
+	0x5d, 0x57, 0x98, 0xa9, 0xeb, 0x76, 0xa1, 0xd2, 0x65, 0x47, 0x4d, 0x59, 0x45, 0xd1, 0x08, 0xf1,
+	0x88, 0xac, 0x8e, 0x04, 0xb8, 0xe1, 0xf5, 0xcc, 0x95, 0x2e, 0x3b, 0x3a, 0x8c, 0x7d, 0xa3, 0x76,
+	0xd8, 0x85, 0x8a, 0x43, 0x3b, 0x43, 0xe8, 0x85, 0x3c, 0xb4, 0x43, 0x3b, 0xc3, 0x68, 0x17, 0xfb,
+	0x43, 0xe8, 0xc5, 0x3c, 0xb4, 0x8b, 0xfd, 0x01, 0xf4, 0xc0, 0x54, 0x9c, 0x19, 0x9e, 0x8a, 0x81,
+	0x26, 0x2d, 0x0e, 0x35, 0x69, 0xb6, 0xe5, 0x97, 0xa6, 0x68, 0xf9, 0xa8, 0xb1, 0xe3, 0x32, 0x9f,
+	0xa2, 0xb4, 0xfa, 0xa7, 0xa0, 0x3e, 0xf6, 0xac, 0x53, 0xc3, 0x4d, 0x58, 0x3b, 0x8c, 0x46, 0xf6,
+	0x34, 0x5d, 0xb5, 0x0e, 0x8b, 0x72, 0xf8, 0xe7, 0x84, 0x51, 0xae, 0xa2, 0xf3, 0x98, 0x24, 0x08,
+	0xdd, 0x53, 0x9e, 0xa7, 0xb8, 0xef, 0xf9, 0x21, 0xbf, 0x4f, 0x3b, 0x68, 0x0d, 0x16, 0xa3, 0xe2,
+	0x4b, 0xc7, 0x82, 0xb9, 0xe0, 0xd0, 0xce, 0xbe, 0x85, 0x34, 0x28, 0x3e, 0x65, 0x36, 0xc7, 0x2d,
+	0x87, 0xc8, 0x51, 0x49, 0xd7, 0xfa, 0xc7, 0xb0, 0x1a, 0xcd, 0x79, 0x12, 0x22, 0x98, 0x61, 0xe7,
+	0x43, 0x58, 0xcb, 0x40, 0xe5, 0x05, 0x71, 0x1d, 0xe6, 0x1d, 0xda, 0x49, 0x6e, 0x86, 0x8b, 0x39,
+	0x37, 0x43, 0x82, 0x35, 0x05, 0x40, 0x7f, 0x08, 0x6b, 0xf1, 0xcc, 0xa6, 0xcf, 0xa7, 0x4f, 0x6e,
+	0xff, 0xec, 0x73, 0x03, 0x67, 0xd7, 0x5d, 0x58, 0x7b, 0xec, 0x5b, 0x6f, 0x32, 0xe4, 0x50, 0x3a,
+	0x0b, 0x99, 0x74, 0x1e, 0xc0, 0xda, 0x5d, 0xcc, 0x5a, 0xb8, 0x43, 0x6e, 0x51, 0xc7, 0x21, 0x6d,
+	0x9e, 0x6c, 0xd7, 0x80, 0xc5, 0x16, 0x39, 0xa2, 0x8c, 0x88, 0x8d, 0x4a, 0x0d, 0x6d, 0x64, 0xa0,
+	0x1e, 0x25, 0xef, 0x68, 0x53, 0x7a, 0xea, 0x5f, 0xc2, 0x7a, 0x36, 0xd8, 0x1b, 0xbe, 0x82, 0x29,
+	0x54, 0x0f, 0x59, 0xe8, 0x91, 0x87, 0x21, 0x09, 0xc9, 0x0c, 0x99, 0xb9, 0x0e, 0x4b, 0x8c, 0x70,
+	0xe2, 0x45, 0xb7, 0xd7, 0xe4, 0xcb, 0xb1, 0xef, 0xab, 0xdf, 0x03, 0x34, 0xb8, 0xa1, 0x3c, 0x8e,
+	0x06, 0x45, 0x46, 0xba, 0x76, 0x10, 0x45, 0x8b, 0x3b, 0x37, 0x5d, 0x8b, 0x17, 0x6a, 0x84, 0x48,
+	0x8a, 0x20, 0x57, 0xfa, 0x03, 0xa8, 0x9a, 0xa4, 0x15, 0xda, 0x8e, 0xf5, 0x00, 0xfb, 0xb3, 0x15,
+	0x35, 0xba, 0xe2, 0xfa, 0x45, 0x75, 0xb1, 0x2f, 0x9a, 0x79, 0xf5, 0x2e, 0xe1, 0x22, 0x94, 0x08,
+	0xfa, 0xfa, 0x11, 0x3f, 0x83, 0x73, 0xb7, 0xb0, 0xd7, 0x26, 0xce, 0x9b, 0x0c, 0xfa, 0x4a, 0x01,
+	0xd8, 0xb3, 0xbb, 0x84, 0x75, 0x88, 0xd7, 0x9e, 0x98, 0xb8, 0x71, 0xb7, 0x0d, 0xaa, 0xc1, 0x59,
+	0x3f, 0xba, 0x56, 0x83, 0xe3, 0x48, 0x8c, 0x50, 0xca, 0x9b, 0xc7, 0x38, 0x38, 0x16, 0x9d, 0xbc,
+	0x6c, 0x56, 0x53, 0x93, 0x49, 0x29, 0xbf, 0x87, 0x83, 0x63, 0xb4, 0x05, 0x55, 0x26, 0xe8, 0xf3,
+	0x01, 0xef, 0x79, 0xe1, 0x5d, 0x91, 0x86, 0xc4, 0x57, 0xff, 0x47, 0x01, 0xe8, 0x1f, 0xf7, 0xf4,
+	0xe7, 0x1c, 0x3a, 0x58, 0x61, 0xf4, 0x60, 0x1c, 0xb3, 0x0e, 0xe1, 0x82, 0x45, 0xc1, 0x94, 0x2b,
+	0x84, 0x60, 0xde, 0xa2, 0x1e, 0x11, 0xef, 0xbb, 0xa2, 0x29, 0x7e, 0xa3, 0xdb, 0x00, 0x56, 0x9a,
+	0x2e, 0xf9, 0x2e, 0xbb, 0x9c, 0x3b, 0x27, 0x89, 0xb3, 0x39, 0x00, 0x44, 0xab, 0xb0, 0x40, 0x18,
+	0x4b, 0xdf, 0x6b, 0xf1, 0xa2, 0xf1, 0x6f, 0x15, 0x56, 0x0f, 0x48, 0xef, 0xd1, 0x40, 0x8c, 0x1b,
+	0xd1, 0x27, 0x03, 0x7a, 0xa9, 0x40, 0x25, 0xa3, 0x9e, 0x50, 0x3d, 0x67, 0xd7, 0xf1, 0x32, 0x4d,
+	0x6b, 0xcc, 0x02, 0x89, 0x47, 0x49, 0x3f, 0xf7, 0xf5, 0x9f, 0x7f, 0xff, 0x38, 0x57, 0x45, 0x15,
+	0xa3, 0x5b, 0x37, 0x06, 0x46, 0x1d, 0x7d, 0xaf, 0xc0, 0xf2, 0xa0, 0xdc, 0x42, 0xb5, 0x9c, 0xe8,
+	0x63, 0x74, 0x99, 0x36, 0xd5, 0xf5, 0xa2, 0xbf, 0x2b, 0xf6, 0xdf, 0x40, 0x17, 0x32, 0xfb, 0x1b,
+	0xcf, 0x07, 0x3b, 0xe1, 0x05, 0xfa, 0x56, 0x81, 0x4a, 0x46, 0x9f, 0xe5, 0xa6, 0x68, 0xbc, 0x96,
+	0x9b, 0x92, 0x94, 0x26, 0x48, 0xad, 0xea, 0xd9, 0xa4, 0xec, 0x28, 0x5b, 0xe8, 0x1b, 0x05, 0x2a,
+	0x19, 0x35, 0x91, 0x4b, 0x64, 0xbc, 0xf2, 0xd0, 0xd6, 0x47, 0xae, 0xbf, 0xdb, 0xd1, 0x07, 0x59,
+	0x92, 0x8f, 0xad, 0x49, 0xf9, 0x78, 0xa9, 0x40, 0x75, 0x44, 0x97, 0xa0, 0x6b, 0x39, 0x44, 0x4e,
+	0x52, 0x31, 0x27, 0x52, 0x31, 0x04, 0x95, 0x2b, 0x5b, 0xef, 0xe5, 0x53, 0xd9, 0x09, 0x65, 0x60,
+	0xf4, 0x9d, 0x02, 0xe5, 0x61, 0xb1, 0x83, 0xb6, 0x73, 0x08, 0x8d, 0xd5, 0x45, 0x93, 0xd8, 0xe8,
+	0x97, 0x26, 0xb0, 0x11, 0x1f, 0x48, 0x51, 0xa1, 0x7e, 0x50, 0xa0, 0x92, 0x91, 0x49, 0xb9, 0x85,
+	0x1a, 0x2f, 0xa9, 0x4e, 0xe4, 0xb3, 0x2d, 0xf8, 0x6c, 0xe9, 0x97, 0x27, 0xf0, 0x61, 0x22, 0x6c,
+	0x44, 0xe8, 0x57, 0x05, 0x56, 0x86, 0x04, 0x10, 0x32, 0x26, 0x0c, 0x6c, 0x56, 0x65, 0x69, 0xdb,
+	0xd3, 0x03, 0xe4, 0x7c, 0x4b, 0x9a, 0x68, 0x33, 0x9f, 0xa6, 0x61, 0xa7, 0xa4, 0x5e, 0x29, 0x50,
+	0x1e, 0x56, 0x55, 0xb9, 0x55, 0x1c, 0x2b, 0xc0, 0xb4, 0x69, 0x44, 0x9c, 0x7e, 0x4d, 0x70, 0xbb,
+	0xaa, 0x4f, 0xcd, 0x2d, 0xca, 0xe2, 0x6f, 0x0a, 0x94, 0x87, 0x15, 0x5a, 0x2e, 0xbd, 0xb1, 0x62,
+	0x6e, 0x3a, 0x7a, 0xbb, 0x82, 0xde, 0x87, 0x8d, 0xfa, 0xb4, 0xf4, 0x8c, 0xe7, 0xb1, 0xfc, 0x7b,
+	0x11, 0xf1, 0x0c, 0xa1, 0x3c, 0x2c, 0xc6, 0x72, 0x69, 0x8e, 0x15, 0x81, 0x5a, 0x7d, 0x06, 0x84,
+	0x94, 0x46, 0x3f, 0x2b, 0x00, 0x7d, 0xc5, 0x84, 0xde, 0xcf, 0x9b, 0xbf, 0xac, 0x92, 0xd3, 0xae,
+	0x4e, 0xe9, 0x2d, 0x7b, 0xeb, 0x03, 0x91, 0xa0, 0x9a, 0x7e, 0x65, 0xd2, 0x48, 0xa6, 0xd0, 0x28,
+	0x31, 0x3f, 0x29, 0x00, 0x7d, 0x25, 0x96, 0xcb, 0x70, 0x44, 0xb0, 0x69, 0x79, 0xef, 0xe2, 0xbe,
+	0x90, 0x98, 0x9a, 0x19, 0x4b, 0x37, 0x88, 0x98, 0xfd, 0xa2, 0xc0, 0xca, 0x90, 0xa8, 0xcb, 0x1d,
+	0xd0, 0x71, 0xf2, 0x6f, 0x5a, 0x7e, 0x1f, 0x09, 0x7e, 0x0d, 0xb4, 0x3d, 0xa1, 0xb5, 0x24, 0xbf,
+	0xc0, 0x78, 0x1e, 0x8b, 0x9e, 0x17, 0xe8, 0x77, 0x05, 0xfe, 0x97, 0x95, 0x89, 0x28, 0xef, 0xc5,
+	0x7f, 0x82, 0xa6, 0x9c, 0x96, 0xe9, 0x4d, 0xc1, 0x74, 0x57, 0xbf, 0x3e, 0x2b, 0xd3, 0x9d, 0xb6,
+	0xd8, 0x78, 0x47, 0xd9, 0xba, 0x79, 0xef, 0xf3, 0x3b, 0x1d, 0x9b, 0x1f, 0x87, 0xad, 0x5a, 0x9b,
+	0xba, 0x46, 0xbc, 0xad, 0x91, 0xd9, 0xd6, 0x68, 0x53, 0x16, 0xff, 0xdb, 0xb3, 0x5b, 0xcf, 0xda,
+	0x9a, 0x1d, 0xda, 0x8c, 0x6f, 0xdf, 0x45, 0xf1, 0xe7, 0xda, 0x7f, 0x03, 0x00, 0xbf, 0x9d, 0xb3,
+	0xdb, 0x6e, 0x15, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// KeyTransparencyAdminClient is the client API for KeyTransparencyAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type KeyTransparencyAdminClient interface {
+	// ListDirectories returns a list of all directories this Key Transparency
+	// server operates on.
+	ListDirectories(ctx context.Context, in *ListDirectoriesRequest, opts ...grpc.CallOption) (*ListDirectoriesResponse, error)
+	// GetDirectory returns the confiuration information for a given directory.
+	GetDirectory(ctx context.Context, in *GetDirectoryRequest, opts ...grpc.CallOption) (*Directory, error)
+	// CreateDirectory creates a new Trillian log/map pair.  A unique directoryId
+	// must be provided.  To create a new directory with the same name as a
+	// previously deleted directory, a user must wait X days until the directory
+	// is garbage collected.
+	CreateDirectory(ctx context.Context, in *CreateDirectoryRequest, opts ...grpc.CallOption) (*Directory, error)
+	// DeleteDirectory marks a directory as deleted.  Directories will be garbage
+	// collected after X days.
+	DeleteDirectory(ctx context.Context, in *DeleteDirectoryRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// UndeleteDirectory marks a previously deleted directory as active if it has
 	// not already been garbage collected.
 	UndeleteDirectory(ctx context.Context, in *UndeleteDirectoryRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// PauseDirectory stops the sequencer from creating new revisions for a
+	// directory. Writes are still queued and will be sequenced after
+	// ResumeDirectory.
+	PauseDirectory(ctx context.Context, in *PauseDirectoryRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// ResumeDirectory resumes sequencing for a paused directory.
+	ResumeDirectory(ctx context.Context, in *ResumeDirectoryRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// ListInputLogs returns the input logs of a directory.
+	ListInputLogs(ctx context.Context, in *ListInputLogsRequest, opts ...grpc.CallOption) (*ListInputLogsResponse, error)
+	// CreateInputLog adds a writable input log to a directory to increase its
+	// write throughput.
+	CreateInputLog(ctx context.Context, in *CreateInputLogRequest, opts ...grpc.CallOption) (*InputLog, error)
+	// UpdateInputLog enables or disables writes to an input log. At least one
+	// log must remain writable.
+	UpdateInputLog(ctx context.Context, in *UpdateInputLogRequest, opts ...grpc.CallOption) (*InputLog, error)
 	// Fully delete soft-deleted directories that have been soft-deleted before
 	// the specified timestamp.
 	GarbageCollect(ctx context.Context, in *GarbageCollectRequest, opts ...grpc.CallOption) (*GarbageCollectResponse, error)
+	// PruneQueue deletes queued mutations that are older than the retention
+	// period and have been included in a published revision. Mutations whose
+	// change events have not been delivered yet are kept.
+	PruneQueue(ctx context.Context, in *PruneQueueRequest, opts ...grpc.CallOption) (*PruneQueueResponse, error)
+	// RebuildMap starts replaying every published revision of a directory into
+	// a new map tree from the Batches and Queue tables, and returns without
+	// waiting for it to finish. Each revision is checked against the log of map
+	// roots, and the rebuild stops at the first revision that does not match.
+	// The directory keeps using its original map.
+	RebuildMap(ctx context.Context, in *RebuildMapRequest, opts ...grpc.CallOption) (*MapRebuild, error)
+	// GetMapRebuild returns the progress of a rebuild started by RebuildMap.
+	GetMapRebuild(ctx context.Context, in *GetMapRebuildRequest, opts ...grpc.CallOption) (*MapRebuild, error)
+	// CancelMapRebuild stops a rebuild started by RebuildMap. The map keeps the
+	// revisions replayed so far, and the rebuild can be resumed by calling
+	// RebuildMap with its map_id.
+	CancelMapRebuild(ctx context.Context, in *CancelMapRebuildRequest, opts ...grpc.CallOption) (*MapRebuild, error)
 }
 
 type keyTransparencyAdminClient struct {
@@ -659,6 +1568,51 @@ func (c *keyTransparencyAdminClient) UndeleteDirectory(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *keyTransparencyAdminClient) PauseDirectory(ctx context.Context, in *PauseDirectoryRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/PauseDirectory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyTransparencyAdminClient) ResumeDirectory(ctx context.Context, in *ResumeDirectoryRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/ResumeDirectory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyTransparencyAdminClient) ListInputLogs(ctx context.Context, in *ListInputLogsRequest, opts ...grpc.CallOption) (*ListInputLogsResponse, error) {
+	out := new(ListInputLogsResponse)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/ListInputLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyTransparencyAdminClient) CreateInputLog(ctx context.Context, in *CreateInputLogRequest, opts ...grpc.CallOption) (*InputLog, error) {
+	out := new(InputLog)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/CreateInputLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyTransparencyAdminClient) UpdateInputLog(ctx context.Context, in *UpdateInputLogRequest, opts ...grpc.CallOption) (*InputLog, error) {
+	out := new(InputLog)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/UpdateInputLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *keyTransparencyAdminClient) GarbageCollect(ctx context.Context, in *GarbageCollectRequest, opts ...grpc.CallOption) (*GarbageCollectResponse, error) {
 	out := new(GarbageCollectResponse)
 	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/GarbageCollect", in, out, opts...)
@@ -668,6 +1622,42 @@ func (c *keyTransparencyAdminClient) GarbageCollect(ctx context.Context, in *Gar
 	return out, nil
 }
 
+func (c *keyTransparencyAdminClient) PruneQueue(ctx context.Context, in *PruneQueueRequest, opts ...grpc.CallOption) (*PruneQueueResponse, error) {
+	out := new(PruneQueueResponse)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/PruneQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyTransparencyAdminClient) RebuildMap(ctx context.Context, in *RebuildMapRequest, opts ...grpc.CallOption) (*MapRebuild, error) {
+	out := new(MapRebuild)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/RebuildMap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyTransparencyAdminClient) GetMapRebuild(ctx context.Context, in *GetMapRebuildRequest, opts ...grpc.CallOption) (*MapRebuild, error) {
+	out := new(MapRebuild)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/GetMapRebuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyTransparencyAdminClient) CancelMapRebuild(ctx context.Context, in *CancelMapRebuildRequest, opts ...grpc.CallOption) (*MapRebuild, error) {
+	out := new(MapRebuild)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparencyAdmin/CancelMapRebuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KeyTransparencyAdminServer is the server API for KeyTransparencyAdmin service.
 type KeyTransparencyAdminServer interface {
 	// ListDirectories returns a list of all directories this Key Transparency
@@ -686,9 +1676,39 @@ type KeyTransparencyAdminServer interface {
 	// UndeleteDirectory marks a previously deleted directory as active if it has
 	// not already been garbage collected.
 	UndeleteDirectory(context.Context, *UndeleteDirectoryRequest) (*empty.Empty, error)
+	// PauseDirectory stops the sequencer from creating new revisions for a
+	// directory. Writes are still queued and will be sequenced after
+	// ResumeDirectory.
+	PauseDirectory(context.Context, *PauseDirectoryRequest) (*empty.Empty, error)
+	// ResumeDirectory resumes sequencing for a paused directory.
+	ResumeDirectory(context.Context, *ResumeDirectoryRequest) (*empty.Empty, error)
+	// ListInputLogs returns the input logs of a directory.
+	ListInputLogs(context.Context, *ListInputLogsRequest) (*ListInputLogsResponse, error)
+	// CreateInputLog adds a writable input log to a directory to increase its
+	// write throughput.
+	CreateInputLog(context.Context, *CreateInputLogRequest) (*InputLog, error)
+	// UpdateInputLog enables or disables writes to an input log. At least one
+	// log must remain writable.
+	UpdateInputLog(context.Context, *UpdateInputLogRequest) (*InputLog, error)
 	// Fully delete soft-deleted directories that have been soft-deleted before
 	// the specified timestamp.
 	GarbageCollect(context.Context, *GarbageCollectRequest) (*GarbageCollectResponse, error)
+	// PruneQueue deletes queued mutations that are older than the retention
+	// period and have been included in a published revision. Mutations whose
+	// change events have not been delivered yet are kept.
+	PruneQueue(context.Context, *PruneQueueRequest) (*PruneQueueResponse, error)
+	// RebuildMap starts replaying every published revision of a directory into
+	// a new map tree from the Batches and Queue tables, and returns without
+	// waiting for it to finish. Each revision is checked against the log of map
+	// roots, and the rebuild stops at the first revision that does not match.
+	// The directory keeps using its original map.
+	RebuildMap(context.Context, *RebuildMapRequest) (*MapRebuild, error)
+	// GetMapRebuild returns the progress of a rebuild started by RebuildMap.
+	GetMapRebuild(context.Context, *GetMapRebuildRequest) (*MapRebuild, error)
+	// CancelMapRebuild stops a rebuild started by RebuildMap. The map keeps the
+	// revisions replayed so far, and the rebuild can be resumed by calling
+	// RebuildMap with its map_id.
+	CancelMapRebuild(context.Context, *CancelMapRebuildRequest) (*MapRebuild, error)
 }
 
 func RegisterKeyTransparencyAdminServer(s *grpc.Server, srv KeyTransparencyAdminServer) {
@@ -785,6 +1805,96 @@ func _KeyTransparencyAdmin_UndeleteDirectory_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KeyTransparencyAdmin_PauseDirectory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseDirectoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyAdminServer).PauseDirectory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparencyAdmin/PauseDirectory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyAdminServer).PauseDirectory(ctx, req.(*PauseDirectoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyTransparencyAdmin_ResumeDirectory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeDirectoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyAdminServer).ResumeDirectory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparencyAdmin/ResumeDirectory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyAdminServer).ResumeDirectory(ctx, req.(*ResumeDirectoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyTransparencyAdmin_ListInputLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInputLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyAdminServer).ListInputLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparencyAdmin/ListInputLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyAdminServer).ListInputLogs(ctx, req.(*ListInputLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyTransparencyAdmin_CreateInputLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInputLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyAdminServer).CreateInputLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparencyAdmin/CreateInputLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyAdminServer).CreateInputLog(ctx, req.(*CreateInputLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyTransparencyAdmin_UpdateInputLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateInputLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyAdminServer).UpdateInputLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparencyAdmin/UpdateInputLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyAdminServer).UpdateInputLog(ctx, req.(*UpdateInputLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _KeyTransparencyAdmin_GarbageCollect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GarbageCollectRequest)
 	if err := dec(in); err != nil {
@@ -803,6 +1913,78 @@ func _KeyTransparencyAdmin_GarbageCollect_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KeyTransparencyAdmin_PruneQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyAdminServer).PruneQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparencyAdmin/PruneQueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyAdminServer).PruneQueue(ctx, req.(*PruneQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyTransparencyAdmin_RebuildMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebuildMapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyAdminServer).RebuildMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparencyAdmin/RebuildMap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyAdminServer).RebuildMap(ctx, req.(*RebuildMapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyTransparencyAdmin_GetMapRebuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMapRebuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyAdminServer).GetMapRebuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparencyAdmin/GetMapRebuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyAdminServer).GetMapRebuild(ctx, req.(*GetMapRebuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyTransparencyAdmin_CancelMapRebuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelMapRebuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyAdminServer).CancelMapRebuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparencyAdmin/CancelMapRebuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyAdminServer).CancelMapRebuild(ctx, req.(*CancelMapRebuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _KeyTransparencyAdmin_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "google.keytransparency.v1.KeyTransparencyAdmin",
 	HandlerType: (*KeyTransparencyAdminServer)(nil),
@@ -827,10 +2009,46 @@ var _KeyTransparencyAdmin_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UndeleteDirectory",
 			Handler:    _KeyTransparencyAdmin_UndeleteDirectory_Handler,
 		},
+		{
+			MethodName: "PauseDirectory",
+			Handler:    _KeyTransparencyAdmin_PauseDirectory_Handler,
+		},
+		{
+			MethodName: "ResumeDirectory",
+			Handler:    _KeyTransparencyAdmin_ResumeDirectory_Handler,
+		},
+		{
+			MethodName: "ListInputLogs",
+			Handler:    _KeyTransparencyAdmin_ListInputLogs_Handler,
+		},
+		{
+			MethodName: "CreateInputLog",
+			Handler:    _KeyTransparencyAdmin_CreateInputLog_Handler,
+		},
+		{
+			MethodName: "UpdateInputLog",
+			Handler:    _KeyTransparencyAdmin_UpdateInputLog_Handler,
+		},
 		{
 			MethodName: "GarbageCollect",
 			Handler:    _KeyTransparencyAdmin_GarbageCollect_Handler,
 		},
+		{
+			MethodName: "PruneQueue",
+			Handler:    _KeyTransparencyAdmin_PruneQueue_Handler,
+		},
+		{
+			MethodName: "RebuildMap",
+			Handler:    _KeyTransparencyAdmin_RebuildMap_Handler,
+		},
+		{
+			MethodName: "GetMapRebuild",
+			Handler:    _KeyTransparencyAdmin_GetMapRebuild_Handler,
+		},
+		{
+			MethodName: "CancelMapRebuild",
+			Handler:    _KeyTransparencyAdmin_CancelMapRebuild_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/admin.proto",
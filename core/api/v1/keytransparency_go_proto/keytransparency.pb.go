@@ -13,7 +13,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
-	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	tink_go_proto "github.com/google/tink/proto/tink_go_proto"
 	trillian "github.com/google/trillian"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
@@ -269,7 +269,9 @@ func (m *SignedEntry) GetSignatures() [][]byte {
 
 // MutationProof contains the information necessary to compute the new leaf
 // value. It contains a) the old leaf value with it's inclusion proof and b) the
-// mutation. The new leaf value is computed via:
+// mutation. Multiple mutations for the same leaf are applied in order of
+// (queue_timestamp, local_id, log_id), skipping invalid mutations.
+// The new leaf value is computed via:
 //       Mutate(leaf_value, mutation)
 type MutationProof struct {
 	// mutation contains the information needed to modify the old leaf.
@@ -278,10 +280,17 @@ type MutationProof struct {
 	Mutation *SignedEntry `protobuf:"bytes,1,opt,name=mutation,proto3" json:"mutation,omitempty"`
 	// leaf_proof contains the leaf and its inclusion proof for a particular map
 	// revision.
-	LeafProof            *trillian.MapLeafInclusion `protobuf:"bytes,2,opt,name=leaf_proof,json=leafProof,proto3" json:"leaf_proof,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
-	XXX_unrecognized     []byte                     `json:"-"`
-	XXX_sizecache        int32                      `json:"-"`
+	LeafProof *trillian.MapLeafInclusion `protobuf:"bytes,2,opt,name=leaf_proof,json=leafProof,proto3" json:"leaf_proof,omitempty"`
+	// log_id is the input log the mutation was read from.
+	LogId int64 `protobuf:"varint,3,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// queue_timestamp is the time, in nanoseconds since the epoch, the mutation
+	// was written to the input log.
+	QueueTimestamp int64 `protobuf:"varint,4,opt,name=queue_timestamp,json=queueTimestamp,proto3" json:"queue_timestamp,omitempty"`
+	// local_id distinguishes mutations with the same queue_timestamp.
+	LocalId              int64    `protobuf:"varint,5,opt,name=local_id,json=localId,proto3" json:"local_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *MutationProof) Reset()         { *m = MutationProof{} }
@@ -323,6 +332,27 @@ func (m *MutationProof) GetLeafProof() *trillian.MapLeafInclusion {
 	return nil
 }
 
+func (m *MutationProof) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *MutationProof) GetQueueTimestamp() int64 {
+	if m != nil {
+		return m.QueueTimestamp
+	}
+	return 0
+}
+
+func (m *MutationProof) GetLocalId() int64 {
+	if m != nil {
+		return m.LocalId
+	}
+	return 0
+}
+
 // MapperMetadata tracks the mutations that have been mapped so far. It is
 // embedded in the Trillian SignedMapHead.
 type MapperMetadata struct {
@@ -1386,6 +1416,134 @@ func (m *BatchQueueUserUpdateRequest) GetUpdates() []*EntryUpdate {
 	return nil
 }
 
+// Receipt is the key server's promise to apply queued mutations to the map.
+type Receipt struct {
+	// directory_id identifies the directory the mutations were queued in.
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// mutation_hashes contains the SHA256 hash of each queued SignedEntry, in
+	// the order they were queued.
+	MutationHashes [][]byte `protobuf:"bytes,2,rep,name=mutation_hashes,json=mutationHashes,proto3" json:"mutation_hashes,omitempty"`
+	// log_id is the input log the mutations were written to.
+	LogId int64 `protobuf:"varint,3,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// watermark is the queue_timestamp the mutations were written with.
+	Watermark int64 `protobuf:"varint,4,opt,name=watermark,proto3" json:"watermark,omitempty"`
+	// deadline is the time by which the mutations must appear in a revision.
+	Deadline             *timestamp.Timestamp `protobuf:"bytes,5,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Receipt) Reset()         { *m = Receipt{} }
+func (m *Receipt) String() string { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()    {}
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e925e13aa3e8f7d, []int{23}
+}
+
+func (m *Receipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Receipt.Unmarshal(m, b)
+}
+func (m *Receipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Receipt.Marshal(b, m, deterministic)
+}
+func (m *Receipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Receipt.Merge(m, src)
+}
+func (m *Receipt) XXX_Size() int {
+	return xxx_messageInfo_Receipt.Size(m)
+}
+func (m *Receipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_Receipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Receipt proto.InternalMessageInfo
+
+func (m *Receipt) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *Receipt) GetMutationHashes() [][]byte {
+	if m != nil {
+		return m.MutationHashes
+	}
+	return nil
+}
+
+func (m *Receipt) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *Receipt) GetWatermark() int64 {
+	if m != nil {
+		return m.Watermark
+	}
+	return 0
+}
+
+func (m *Receipt) GetDeadline() *timestamp.Timestamp {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+// SignedReceipt is a Receipt signed by the key server.
+type SignedReceipt struct {
+	// receipt is a serialized Receipt.
+	Receipt []byte `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	// signature is a signature over receipt by the directory's receipt_key.
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedReceipt) Reset()         { *m = SignedReceipt{} }
+func (m *SignedReceipt) String() string { return proto.CompactTextString(m) }
+func (*SignedReceipt) ProtoMessage()    {}
+func (*SignedReceipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e925e13aa3e8f7d, []int{24}
+}
+
+func (m *SignedReceipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedReceipt.Unmarshal(m, b)
+}
+func (m *SignedReceipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedReceipt.Marshal(b, m, deterministic)
+}
+func (m *SignedReceipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedReceipt.Merge(m, src)
+}
+func (m *SignedReceipt) XXX_Size() int {
+	return xxx_messageInfo_SignedReceipt.Size(m)
+}
+func (m *SignedReceipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedReceipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedReceipt proto.InternalMessageInfo
+
+func (m *SignedReceipt) GetReceipt() []byte {
+	if m != nil {
+		return m.Receipt
+	}
+	return nil
+}
+
+func (m *SignedReceipt) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 // GetRevisionRequest identifies a particular revision.
 type GetRevisionRequest struct {
 	// directory_id is the directory for which revisions are being requested.
@@ -1405,7 +1563,7 @@ func (m *GetRevisionRequest) Reset()         { *m = GetRevisionRequest{} }
 func (m *GetRevisionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetRevisionRequest) ProtoMessage()    {}
 func (*GetRevisionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e925e13aa3e8f7d, []int{23}
+	return fileDescriptor_9e925e13aa3e8f7d, []int{25}
 }
 
 func (m *GetRevisionRequest) XXX_Unmarshal(b []byte) error {
@@ -1464,7 +1622,7 @@ func (m *GetLatestRevisionRequest) Reset()         { *m = GetLatestRevisionReque
 func (m *GetLatestRevisionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLatestRevisionRequest) ProtoMessage()    {}
 func (*GetLatestRevisionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e925e13aa3e8f7d, []int{24}
+	return fileDescriptor_9e925e13aa3e8f7d, []int{26}
 }
 
 func (m *GetLatestRevisionRequest) XXX_Unmarshal(b []byte) error {
@@ -1515,7 +1673,7 @@ func (m *MapRoot) Reset()         { *m = MapRoot{} }
 func (m *MapRoot) String() string { return proto.CompactTextString(m) }
 func (*MapRoot) ProtoMessage()    {}
 func (*MapRoot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e925e13aa3e8f7d, []int{25}
+	return fileDescriptor_9e925e13aa3e8f7d, []int{27}
 }
 
 func (m *MapRoot) XXX_Unmarshal(b []byte) error {
@@ -1566,7 +1724,7 @@ func (m *LogRoot) Reset()         { *m = LogRoot{} }
 func (m *LogRoot) String() string { return proto.CompactTextString(m) }
 func (*LogRoot) ProtoMessage()    {}
 func (*LogRoot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e925e13aa3e8f7d, []int{26}
+	return fileDescriptor_9e925e13aa3e8f7d, []int{28}
 }
 
 func (m *LogRoot) XXX_Unmarshal(b []byte) error {
@@ -1620,7 +1778,7 @@ func (m *Revision) Reset()         { *m = Revision{} }
 func (m *Revision) String() string { return proto.CompactTextString(m) }
 func (*Revision) ProtoMessage()    {}
 func (*Revision) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e925e13aa3e8f7d, []int{27}
+	return fileDescriptor_9e925e13aa3e8f7d, []int{29}
 }
 
 func (m *Revision) XXX_Unmarshal(b []byte) error {
@@ -1684,7 +1842,7 @@ func (m *ListMutationsRequest) Reset()         { *m = ListMutationsRequest{} }
 func (m *ListMutationsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListMutationsRequest) ProtoMessage()    {}
 func (*ListMutationsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e925e13aa3e8f7d, []int{28}
+	return fileDescriptor_9e925e13aa3e8f7d, []int{30}
 }
 
 func (m *ListMutationsRequest) XXX_Unmarshal(b []byte) error {
@@ -1749,7 +1907,7 @@ func (m *ListMutationsResponse) Reset()         { *m = ListMutationsResponse{} }
 func (m *ListMutationsResponse) String() string { return proto.CompactTextString(m) }
 func (*ListMutationsResponse) ProtoMessage()    {}
 func (*ListMutationsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e925e13aa3e8f7d, []int{29}
+	return fileDescriptor_9e925e13aa3e8f7d, []int{31}
 }
 
 func (m *ListMutationsResponse) XXX_Unmarshal(b []byte) error {
@@ -1784,6 +1942,187 @@ func (m *ListMutationsResponse) GetNextPageToken() string {
 	return ""
 }
 
+// ListUserRejectionsRequest requests the mutations for a user that were
+// rejected when they were applied to the map.
+type ListUserRejectionsRequest struct {
+	// directory_id identifies the directory in which the user lives.
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// user_id is the user identifier.
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// page_size is the maximum number of rejections to return. If page_size is
+	// unspecified, the server will decide how many results to return.
+	PageSize             int32    `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListUserRejectionsRequest) Reset()         { *m = ListUserRejectionsRequest{} }
+func (m *ListUserRejectionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListUserRejectionsRequest) ProtoMessage()    {}
+func (*ListUserRejectionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e925e13aa3e8f7d, []int{32}
+}
+
+func (m *ListUserRejectionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUserRejectionsRequest.Unmarshal(m, b)
+}
+func (m *ListUserRejectionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUserRejectionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListUserRejectionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUserRejectionsRequest.Merge(m, src)
+}
+func (m *ListUserRejectionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListUserRejectionsRequest.Size(m)
+}
+func (m *ListUserRejectionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUserRejectionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUserRejectionsRequest proto.InternalMessageInfo
+
+func (m *ListUserRejectionsRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *ListUserRejectionsRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *ListUserRejectionsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+// RejectedMutation describes a mutation that was not applied to the map.
+type RejectedMutation struct {
+	// log_id is the input log the mutation was read from.
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// queue_timestamp is the time, in nanoseconds since the epoch, the mutation
+	// was written to the input log.
+	QueueTimestamp int64 `protobuf:"varint,2,opt,name=queue_timestamp,json=queueTimestamp,proto3" json:"queue_timestamp,omitempty"`
+	// local_id distinguishes mutations with the same queue_timestamp.
+	LocalId int64 `protobuf:"varint,3,opt,name=local_id,json=localId,proto3" json:"local_id,omitempty"`
+	// revision is the map revision the mutation was rejected from.
+	Revision int64 `protobuf:"varint,4,opt,name=revision,proto3" json:"revision,omitempty"`
+	// reason describes why the mutation was rejected.
+	Reason               string   `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RejectedMutation) Reset()         { *m = RejectedMutation{} }
+func (m *RejectedMutation) String() string { return proto.CompactTextString(m) }
+func (*RejectedMutation) ProtoMessage()    {}
+func (*RejectedMutation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e925e13aa3e8f7d, []int{33}
+}
+
+func (m *RejectedMutation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RejectedMutation.Unmarshal(m, b)
+}
+func (m *RejectedMutation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RejectedMutation.Marshal(b, m, deterministic)
+}
+func (m *RejectedMutation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RejectedMutation.Merge(m, src)
+}
+func (m *RejectedMutation) XXX_Size() int {
+	return xxx_messageInfo_RejectedMutation.Size(m)
+}
+func (m *RejectedMutation) XXX_DiscardUnknown() {
+	xxx_messageInfo_RejectedMutation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RejectedMutation proto.InternalMessageInfo
+
+func (m *RejectedMutation) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *RejectedMutation) GetQueueTimestamp() int64 {
+	if m != nil {
+		return m.QueueTimestamp
+	}
+	return 0
+}
+
+func (m *RejectedMutation) GetLocalId() int64 {
+	if m != nil {
+		return m.LocalId
+	}
+	return 0
+}
+
+func (m *RejectedMutation) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *RejectedMutation) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// ListUserRejectionsResponse contains the most recently rejected mutations for
+// a user.
+type ListUserRejectionsResponse struct {
+	// rejections are ordered from most to least recent.
+	Rejections           []*RejectedMutation `protobuf:"bytes,1,rep,name=rejections,proto3" json:"rejections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ListUserRejectionsResponse) Reset()         { *m = ListUserRejectionsResponse{} }
+func (m *ListUserRejectionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListUserRejectionsResponse) ProtoMessage()    {}
+func (*ListUserRejectionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e925e13aa3e8f7d, []int{34}
+}
+
+func (m *ListUserRejectionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUserRejectionsResponse.Unmarshal(m, b)
+}
+func (m *ListUserRejectionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUserRejectionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListUserRejectionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUserRejectionsResponse.Merge(m, src)
+}
+func (m *ListUserRejectionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListUserRejectionsResponse.Size(m)
+}
+func (m *ListUserRejectionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUserRejectionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUserRejectionsResponse proto.InternalMessageInfo
+
+func (m *ListUserRejectionsResponse) GetRejections() []*RejectedMutation {
+	if m != nil {
+		return m.Rejections
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Committed)(nil), "google.keytransparency.v1.Committed")
 	proto.RegisterType((*EntryUpdate)(nil), "google.keytransparency.v1.EntryUpdate")
@@ -1811,6 +2150,8 @@ func init() {
 	proto.RegisterType((*BatchListUserRevisionsResponse)(nil), "google.keytransparency.v1.BatchListUserRevisionsResponse")
 	proto.RegisterType((*UpdateEntryRequest)(nil), "google.keytransparency.v1.UpdateEntryRequest")
 	proto.RegisterType((*BatchQueueUserUpdateRequest)(nil), "google.keytransparency.v1.BatchQueueUserUpdateRequest")
+	proto.RegisterType((*Receipt)(nil), "google.keytransparency.v1.Receipt")
+	proto.RegisterType((*SignedReceipt)(nil), "google.keytransparency.v1.SignedReceipt")
 	proto.RegisterType((*GetRevisionRequest)(nil), "google.keytransparency.v1.GetRevisionRequest")
 	proto.RegisterType((*GetLatestRevisionRequest)(nil), "google.keytransparency.v1.GetLatestRevisionRequest")
 	proto.RegisterType((*MapRoot)(nil), "google.keytransparency.v1.MapRoot")
@@ -1818,134 +2159,152 @@ func init() {
 	proto.RegisterType((*Revision)(nil), "google.keytransparency.v1.Revision")
 	proto.RegisterType((*ListMutationsRequest)(nil), "google.keytransparency.v1.ListMutationsRequest")
 	proto.RegisterType((*ListMutationsResponse)(nil), "google.keytransparency.v1.ListMutationsResponse")
+	proto.RegisterType((*ListUserRejectionsRequest)(nil), "google.keytransparency.v1.ListUserRejectionsRequest")
+	proto.RegisterType((*RejectedMutation)(nil), "google.keytransparency.v1.RejectedMutation")
+	proto.RegisterType((*ListUserRejectionsResponse)(nil), "google.keytransparency.v1.ListUserRejectionsResponse")
 }
 
 func init() { proto.RegisterFile("v1/keytransparency.proto", fileDescriptor_9e925e13aa3e8f7d) }
 
 var fileDescriptor_9e925e13aa3e8f7d = []byte{
-	// 1942 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x59, 0xcd, 0x8f, 0x1b, 0x49,
-	0x15, 0x57, 0xf9, 0xdb, 0xcf, 0xf3, 0xe1, 0x54, 0x66, 0x13, 0xc7, 0x21, 0x51, 0xa8, 0x85, 0x10,
-	0x82, 0xd6, 0x9d, 0x99, 0x6c, 0xb2, 0x93, 0x81, 0x90, 0x65, 0x66, 0x33, 0xd9, 0x99, 0xcc, 0x88,
-	0x6c, 0x4f, 0x16, 0x56, 0x5c, 0x5a, 0x3d, 0x76, 0x8d, 0xa7, 0x35, 0x76, 0x77, 0xa7, 0xab, 0x6c,
-	0xc5, 0x89, 0x72, 0x59, 0x0e, 0x48, 0x80, 0x90, 0xd0, 0x72, 0x40, 0x1c, 0x38, 0x70, 0xe0, 0x82,
-	0x84, 0x80, 0x03, 0x12, 0xac, 0x84, 0x40, 0x88, 0x0b, 0x37, 0xc4, 0x95, 0x23, 0x7f, 0x08, 0xaa,
-	0x8f, 0x6e, 0xb7, 0xbf, 0xda, 0xf6, 0xec, 0x44, 0x62, 0x4f, 0xee, 0xaa, 0xae, 0x57, 0xf5, 0x7b,
-	0xef, 0xfd, 0xde, 0x7b, 0xfd, 0xca, 0x50, 0xe9, 0xae, 0x1a, 0x27, 0xb4, 0xc7, 0x03, 0xdb, 0x65,
-	0xbe, 0x1d, 0x50, 0xb7, 0xde, 0xab, 0xf9, 0x81, 0xc7, 0x3d, 0x7c, 0xa9, 0xe9, 0x79, 0xcd, 0x16,
-	0xad, 0x0d, 0xbf, 0xed, 0xae, 0x56, 0xbf, 0xa0, 0x5e, 0x19, 0xb6, 0xef, 0x18, 0xb6, 0xeb, 0x7a,
-	0xdc, 0xe6, 0x8e, 0xe7, 0x32, 0x25, 0x58, 0xbd, 0xac, 0xdf, 0xca, 0xd1, 0x61, 0xe7, 0xc8, 0xa0,
-	0x6d, 0x9f, 0xeb, 0x5d, 0xab, 0xc0, 0x1d, 0xf7, 0x44, 0x3f, 0x2f, 0xf1, 0xc0, 0x69, 0xb5, 0x1c,
-	0xdb, 0xd5, 0xe3, 0x0b, 0xe1, 0xd8, 0x6a, 0xdb, 0xbe, 0x65, 0xfb, 0x4e, 0xb8, 0xae, 0xbb, 0x6a,
-	0xd8, 0x8d, 0xb6, 0xa3, 0xd7, 0x91, 0x55, 0x28, 0x6e, 0x79, 0xed, 0xb6, 0xc3, 0x39, 0x6d, 0xe0,
-	0x32, 0xa4, 0x4f, 0x68, 0xaf, 0x82, 0xae, 0xa1, 0x1b, 0x0b, 0xa6, 0x78, 0xc4, 0x18, 0x32, 0x0d,
-	0x9b, 0xdb, 0x95, 0x94, 0x9c, 0x92, 0xcf, 0xe4, 0xb7, 0x08, 0x4a, 0x0f, 0x5d, 0x1e, 0xf4, 0x3e,
-	0xf4, 0x1b, 0x36, 0xa7, 0xf8, 0x22, 0xe4, 0x3b, 0x8c, 0x06, 0x96, 0xd3, 0x90, 0x92, 0x45, 0x33,
-	0x27, 0x86, 0x3b, 0x0d, 0xbc, 0x09, 0x85, 0x76, 0x47, 0xe9, 0x23, 0x37, 0x28, 0xad, 0x5d, 0xaf,
-	0x4d, 0x34, 0x44, 0xed, 0xc0, 0x69, 0xba, 0xb4, 0x21, 0x37, 0x36, 0x23, 0x39, 0xbc, 0x09, 0xc5,
-	0x7a, 0x88, 0xaf, 0x92, 0x96, 0x9b, 0x7c, 0x29, 0x61, 0x93, 0x48, 0x17, 0xb3, 0x2f, 0x46, 0xfe,
-	0x88, 0x20, 0x2b, 0xf7, 0xc5, 0x2b, 0x90, 0x75, 0xdc, 0x06, 0x7d, 0x2e, 0x77, 0x5a, 0x30, 0xd5,
-	0x00, 0x5f, 0x05, 0x50, 0x8b, 0xdb, 0xd4, 0xe5, 0x95, 0x9c, 0x7c, 0x15, 0x9b, 0xc1, 0x5b, 0xb0,
-	0x6c, 0x77, 0xf8, 0xb1, 0x17, 0x38, 0x2f, 0x68, 0xc3, 0x3a, 0xa1, 0x3d, 0x56, 0xc9, 0x4b, 0x24,
-	0xd5, 0x10, 0x49, 0x3d, 0xe8, 0xf9, 0xdc, 0xab, 0x49, 0x7f, 0x3c, 0xa6, 0x3d, 0x46, 0xb9, 0xb9,
-	0xd4, 0x17, 0x11, 0x33, 0xb8, 0x0a, 0x05, 0x3f, 0xa0, 0x5d, 0xc7, 0xeb, 0xb0, 0x4a, 0x41, 0x1e,
-	0x11, 0x8d, 0x77, 0x33, 0x05, 0x54, 0x4e, 0xed, 0x66, 0x0a, 0xa9, 0x72, 0x7a, 0x37, 0x53, 0xc8,
-	0x94, 0xb3, 0xbb, 0x99, 0x42, 0xb6, 0x9c, 0x23, 0x5b, 0x50, 0x8a, 0x59, 0x45, 0xa0, 0xa7, 0xe2,
-	0x41, 0x3b, 0x48, 0x0d, 0x04, 0x7a, 0xe6, 0x34, 0x5d, 0x9b, 0x77, 0x02, 0xca, 0x2a, 0xa9, 0x6b,
-	0x69, 0x81, 0xbe, 0x3f, 0x43, 0x7e, 0x82, 0x60, 0x71, 0x5f, 0x9b, 0xf3, 0x49, 0xe0, 0x79, 0x47,
-	0x03, 0x7e, 0x41, 0xa7, 0xf4, 0xcb, 0x3d, 0x80, 0x16, 0xb5, 0x8f, 0x2c, 0x5f, 0xec, 0xa8, 0xbd,
-	0x5b, 0xad, 0x45, 0x24, 0xdc, 0xb7, 0xfd, 0x3d, 0x6a, 0x1f, 0xed, 0xb8, 0xf5, 0x56, 0x87, 0x39,
-	0x9e, 0x6b, 0x16, 0xc5, 0x6a, 0x79, 0x3c, 0xf9, 0x36, 0x2c, 0xed, 0xdb, 0xbe, 0x4f, 0x83, 0x7d,
-	0xca, 0x6d, 0xc1, 0x28, 0x7c, 0x1f, 0x2e, 0x1f, 0x3b, 0xcd, 0x63, 0xca, 0xb8, 0x75, 0xd4, 0x69,
-	0xb5, 0x7a, 0x56, 0xdd, 0x6b, 0xfb, 0x2d, 0xca, 0x69, 0xc3, 0x62, 0xf4, 0x99, 0xc4, 0x98, 0x36,
-	0x2b, 0x7a, 0xc9, 0xb6, 0x58, 0xb1, 0x15, 0x2e, 0x38, 0xa0, 0xcf, 0xc8, 0xf7, 0x11, 0x2c, 0x3d,
-	0xa2, 0xfc, 0x43, 0x46, 0x03, 0x93, 0x3e, 0xeb, 0x50, 0xc6, 0xf1, 0x17, 0x61, 0xa1, 0xe1, 0x04,
-	0xb4, 0xce, 0xbd, 0xa0, 0xd7, 0x27, 0x66, 0x29, 0x9a, 0xdb, 0x69, 0xc4, 0x69, 0x9b, 0x1a, 0xa0,
-	0xed, 0x1d, 0xb8, 0xd8, 0xb2, 0x19, 0xb7, 0xba, 0x34, 0x70, 0x8e, 0x1c, 0xda, 0xb0, 0x78, 0x40,
-	0xa9, 0xc5, 0x9c, 0x17, 0x54, 0xd2, 0x26, 0x6d, 0xae, 0x88, 0xd7, 0xdf, 0xd1, 0x6f, 0x9f, 0x06,
-	0x94, 0x1e, 0x38, 0x2f, 0x28, 0xf9, 0x0d, 0x82, 0xbc, 0x56, 0x1b, 0x5f, 0x86, 0x62, 0x37, 0x08,
-	0x8d, 0xa3, 0xbc, 0x55, 0xe8, 0x06, 0x4a, 0x7f, 0xfc, 0x00, 0x16, 0x45, 0x4c, 0x3a, 0xa1, 0x6d,
-	0x66, 0xb0, 0xde, 0x42, 0xdb, 0xf6, 0xa3, 0xd1, 0x99, 0xc4, 0xc4, 0x0f, 0x11, 0x2c, 0x47, 0x36,
-	0x63, 0xbe, 0xe7, 0x32, 0x8a, 0x1f, 0x40, 0x41, 0x30, 0x92, 0xf5, 0x79, 0xf1, 0x66, 0xc2, 0xb6,
-	0xa6, 0x5e, 0x6a, 0x46, 0x42, 0xf8, 0x2e, 0x64, 0x84, 0x9b, 0xb5, 0x42, 0x24, 0x41, 0x58, 0x6b,
-	0x68, 0xca, 0xf5, 0x02, 0xcc, 0xf9, 0x4d, 0x9b, 0xd7, 0x8f, 0xe7, 0xf7, 0xe2, 0x25, 0x28, 0x68,
-	0x2f, 0x2a, 0xee, 0x17, 0xcd, 0xbc, 0x72, 0x23, 0x3b, 0xad, 0x1f, 0x3f, 0x82, 0x4a, 0x1c, 0xcb,
-	0x8e, 0x48, 0x11, 0x67, 0x02, 0x88, 0xfc, 0x0e, 0xc1, 0xa5, 0x31, 0x5b, 0x6b, 0xeb, 0x7f, 0x04,
-	0x39, 0xc9, 0x17, 0x56, 0x41, 0xd7, 0xd2, 0x37, 0x4a, 0x6b, 0xef, 0x26, 0x98, 0x6f, 0xe2, 0x2e,
-	0x35, 0x49, 0x31, 0xa6, 0xa2, 0x55, 0xef, 0x57, 0xbd, 0x07, 0xa5, 0xd8, 0x74, 0x3c, 0xcb, 0x17,
-	0x55, 0x96, 0x5f, 0x81, 0x6c, 0xd7, 0x6e, 0x75, 0xa8, 0x4e, 0xf3, 0x6a, 0xb0, 0x91, 0x5a, 0x47,
-	0xe4, 0xd3, 0x14, 0xac, 0x0c, 0x7a, 0xe6, 0xac, 0xb8, 0xf2, 0x1c, 0xde, 0x10, 0x51, 0xd0, 0xa2,
-	0x76, 0x97, 0x32, 0xeb, 0xb0, 0x67, 0xf5, 0x83, 0x51, 0x68, 0xbf, 0x3d, 0xa3, 0xf6, 0x91, 0xe2,
-	0x8a, 0x51, 0x5d, 0xca, 0x36, 0x7b, 0xd2, 0x2a, 0x3a, 0x63, 0x9d, 0x6b, 0x0f, 0xcf, 0x57, 0x8f,
-	0xe1, 0xc2, 0xf8, 0xc5, 0x63, 0x2c, 0xb3, 0x1e, 0xb7, 0xcc, 0x6c, 0x94, 0x8e, 0x59, 0xef, 0x1f,
-	0x08, 0x2e, 0xee, 0x39, 0x8c, 0xcb, 0xdd, 0xdf, 0x77, 0x98, 0xa0, 0xc8, 0x24, 0x2a, 0xe5, 0x12,
-	0x33, 0xd4, 0x60, 0x61, 0x5d, 0x81, 0x2c, 0xe3, 0x76, 0xc0, 0x25, 0xaa, 0xb4, 0xa9, 0x06, 0x22,
-	0xe9, 0xf8, 0x76, 0x33, 0xc6, 0xf0, 0xac, 0x59, 0x10, 0x13, 0x82, 0xd5, 0x49, 0xc1, 0x90, 0x9d,
-	0x1c, 0x0c, 0xaa, 0x1a, 0x91, 0x57, 0x50, 0x19, 0x55, 0x43, 0x13, 0x61, 0x13, 0x72, 0x52, 0xe1,
-	0x90, 0xb6, 0x37, 0x13, 0x4c, 0x34, 0xe4, 0x33, 0x53, 0x4b, 0xe2, 0x2b, 0x00, 0x2e, 0x7d, 0xce,
-	0xad, 0xb8, 0x52, 0x45, 0x31, 0x73, 0x20, 0x26, 0xc8, 0x27, 0x29, 0x75, 0xbe, 0x92, 0x55, 0xfc,
-	0x61, 0x67, 0x91, 0xe9, 0xbf, 0x0c, 0x4b, 0xf2, 0x48, 0x2b, 0xa2, 0xb2, 0x4a, 0x0c, 0x8b, 0x72,
-	0x36, 0x3c, 0x4a, 0x1c, 0x41, 0xdd, 0x46, 0x7f, 0x51, 0x46, 0x2e, 0x2a, 0x51, 0xb7, 0x11, 0x2d,
-	0x19, 0xb0, 0x7d, 0x76, 0xc8, 0xf6, 0x57, 0x00, 0xe4, 0x4b, 0xee, 0x9d, 0x50, 0x57, 0x3b, 0x5a,
-	0x2e, 0x7f, 0x2a, 0x26, 0x92, 0x5c, 0x93, 0x4f, 0xc8, 0x53, 0x3f, 0x42, 0x50, 0xda, 0xb7, 0xfd,
-	0x08, 0xc2, 0x7d, 0x28, 0x88, 0x80, 0x0a, 0x3c, 0x8f, 0xeb, 0x88, 0x9c, 0xc2, 0x56, 0xd3, 0xf3,
-	0xb8, 0x99, 0x6f, 0xab, 0x87, 0x50, 0x7c, 0xce, 0xfc, 0x9d, 0x57, 0xe1, 0x75, 0x44, 0xfe, 0x83,
-	0xe0, 0xd2, 0x18, 0x1f, 0x69, 0x92, 0xec, 0xc2, 0x72, 0xcb, 0xe6, 0xa2, 0xbe, 0xb7, 0xbc, 0xe6,
-	0xac, 0x10, 0xf7, 0xbc, 0xa6, 0x84, 0xb8, 0xa8, 0x44, 0xf5, 0x10, 0x3f, 0x56, 0xe5, 0x33, 0xf4,
-	0x06, 0xd3, 0x09, 0xe3, 0xfa, 0x14, 0x65, 0xc3, 0x0c, 0x24, 0x4a, 0x69, 0x04, 0x10, 0x5f, 0x87,
-	0x65, 0xc9, 0xbc, 0x98, 0x7f, 0xd2, 0xd2, 0x3f, 0x8b, 0x62, 0xfa, 0x49, 0xe8, 0x23, 0xf2, 0x8b,
-	0x14, 0x5c, 0x91, 0x69, 0xe7, 0xb3, 0xf0, 0x30, 0xa1, 0x56, 0x7d, 0xae, 0x99, 0xf8, 0xfb, 0x14,
-	0x94, 0xa5, 0x71, 0xce, 0x90, 0x8e, 0x3c, 0xb9, 0x3c, 0x6c, 0x4e, 0x2b, 0x0f, 0x31, 0x28, 0xff,
-	0x97, 0xa5, 0xe1, 0x2f, 0x08, 0xae, 0x4e, 0x22, 0xd4, 0x6b, 0x08, 0x9a, 0x27, 0xe3, 0x83, 0xe6,
-	0x6b, 0x73, 0x98, 0x71, 0x30, 0x72, 0xc8, 0xcf, 0x10, 0x60, 0xd5, 0x00, 0x2a, 0x6b, 0x4e, 0x08,
-	0x83, 0xec, 0x68, 0x18, 0xec, 0x08, 0x12, 0xf3, 0xa0, 0x67, 0x75, 0xa4, 0xb8, 0x24, 0x71, 0x72,
-	0xfc, 0xc6, 0xba, 0x4d, 0x41, 0xf6, 0x68, 0x30, 0xd4, 0x38, 0xa5, 0xcb, 0x19, 0xf2, 0x31, 0x82,
-	0xcb, 0x12, 0xf9, 0x07, 0x1d, 0xda, 0xa1, 0xc2, 0xb0, 0x5a, 0x6e, 0xf6, 0x30, 0x7d, 0x17, 0xf2,
-	0x0a, 0xd9, 0x2c, 0xa9, 0x25, 0x0e, 0x2d, 0x14, 0x13, 0xdf, 0xb3, 0xf8, 0x11, 0x8d, 0xc2, 0x76,
-	0x0e, 0xdb, 0x54, 0x87, 0x3e, 0xab, 0xd2, 0xb1, 0x2f, 0xa6, 0x84, 0xe8, 0x4c, 0x25, 0x44, 0x27,
-	0x87, 0xca, 0x23, 0xca, 0xf7, 0x24, 0x1d, 0xa6, 0x21, 0x1a, 0x63, 0x8d, 0x53, 0x9e, 0x7a, 0x28,
-	0x9b, 0x21, 0xc9, 0xbd, 0xb5, 0x91, 0x4c, 0x70, 0xb1, 0xdf, 0xea, 0xa8, 0xee, 0x72, 0x24, 0xfc,
-	0xdf, 0x84, 0x45, 0x41, 0xfa, 0x78, 0x8f, 0x24, 0xfa, 0xda, 0x85, 0x96, 0xd7, 0x8c, 0xfa, 0x20,
-	0x72, 0x04, 0xf9, 0x90, 0xdf, 0x6b, 0x50, 0x18, 0x0a, 0x92, 0x91, 0x33, 0xc2, 0xc8, 0xc8, 0xb7,
-	0xb4, 0xcc, 0x57, 0x60, 0x59, 0xc8, 0xd4, 0x3d, 0x97, 0x39, 0x8c, 0x0b, 0x87, 0xea, 0x53, 0x96,
-	0x5a, 0x5e, 0x73, 0xab, 0x3f, 0x4b, 0xfe, 0x89, 0xa0, 0x10, 0x4f, 0xc1, 0xd3, 0x4c, 0x16, 0x4f,
-	0x7d, 0xd9, 0xf9, 0x53, 0xdf, 0x98, 0xb8, 0xcf, 0x9d, 0x32, 0xee, 0xe3, 0xa1, 0xa1, 0xbf, 0xe5,
-	0x7e, 0x8a, 0x60, 0x45, 0xe4, 0x9c, 0xf0, 0x4a, 0x80, 0x9d, 0x11, 0x3b, 0x07, 0x4b, 0x4b, 0x7a,
-	0xb8, 0xb4, 0x0c, 0x94, 0xa5, 0xcc, 0x60, 0x59, 0x22, 0x3f, 0x40, 0xf0, 0xc6, 0x10, 0x26, 0x9d,
-	0x03, 0xb7, 0xa1, 0x18, 0x5e, 0x39, 0xb0, 0x4a, 0x4e, 0x46, 0xe3, 0x8d, 0x24, 0x5b, 0xc6, 0xef,
-	0x39, 0xcc, 0xbe, 0xe8, 0xb8, 0x3a, 0x9f, 0x1f, 0x53, 0xe7, 0xd7, 0xfe, 0x76, 0x1e, 0x96, 0x1f,
-	0xd3, 0xde, 0xd3, 0xd8, 0xbe, 0xf8, 0xc7, 0x08, 0x16, 0x1e, 0x51, 0xfe, 0x5e, 0x68, 0x08, 0x5c,
-	0x4b, 0xfe, 0xc4, 0x8d, 0x16, 0x6a, 0xcb, 0x56, 0x93, 0x9a, 0xf3, 0x68, 0x31, 0xb9, 0xfe, 0xf1,
-	0xbf, 0xff, 0xfb, 0x49, 0xea, 0x1a, 0xbe, 0x6a, 0x74, 0x57, 0x8d, 0xd0, 0xea, 0x0e, 0x65, 0xc6,
-	0xcb, 0xb8, 0x5b, 0x5e, 0xe1, 0x5f, 0x22, 0x28, 0xc5, 0x92, 0x0b, 0x7e, 0x2b, 0x19, 0xcd, 0x50,
-	0xc8, 0x57, 0x67, 0x69, 0xd3, 0xc8, 0xd7, 0x25, 0x96, 0x3b, 0xf8, 0x76, 0x32, 0x16, 0x23, 0xaa,
-	0x27, 0xc6, 0xcb, 0xf0, 0xf1, 0x15, 0xfe, 0x35, 0x82, 0x73, 0x23, 0x19, 0x07, 0xdf, 0x4e, 0x86,
-	0x39, 0x36, 0x3f, 0xcd, 0x06, 0xf6, 0x1d, 0x09, 0x76, 0x15, 0x1b, 0xb3, 0x82, 0xdd, 0x50, 0x31,
-	0x82, 0x7f, 0xa5, 0x80, 0x86, 0x1b, 0x1d, 0xf0, 0x80, 0xda, 0xed, 0xd7, 0x62, 0xcf, 0xf9, 0x21,
-	0x32, 0x09, 0xe6, 0x16, 0xc2, 0x7f, 0x42, 0xb0, 0x38, 0x10, 0x1b, 0xd8, 0x48, 0x4a, 0x03, 0x63,
-	0x22, 0xbb, 0x7a, 0x6b, 0x76, 0x01, 0x15, 0x76, 0xe4, 0xa1, 0xc4, 0xfb, 0x00, 0xdf, 0x3f, 0x85,
-	0xff, 0x8d, 0x7e, 0xd4, 0xfd, 0x15, 0xc1, 0xf9, 0x81, 0x03, 0xb4, 0x89, 0xe7, 0xd6, 0x60, 0xe6,
-	0x98, 0x27, 0x7b, 0x12, 0xf9, 0x36, 0x7e, 0xef, 0x33, 0x21, 0xef, 0x9b, 0xff, 0xe7, 0x08, 0xf2,
-	0xba, 0x6d, 0xc5, 0x5f, 0x9d, 0xa5, 0xb5, 0x55, 0x80, 0xe7, 0xe8, 0x82, 0xc9, 0x5d, 0x09, 0xf9,
-	0x16, 0xae, 0x4d, 0x81, 0x2c, 0xbe, 0x80, 0x99, 0xf1, 0x52, 0x7f, 0x08, 0xcb, 0x38, 0x5b, 0x88,
-	0x5f, 0x85, 0x24, 0xe6, 0xa5, 0x31, 0xd7, 0x6b, 0x55, 0x63, 0xce, 0x3b, 0x16, 0x72, 0x47, 0x22,
-	0x35, 0xf0, 0x5b, 0xb3, 0x20, 0xdd, 0x38, 0xd4, 0x5b, 0xe0, 0x3f, 0x23, 0x38, 0x37, 0x72, 0x63,
-	0x95, 0x98, 0x10, 0x26, 0x5d, 0xc0, 0x55, 0xdf, 0x3e, 0xcd, 0xa5, 0x18, 0xd9, 0x90, 0xb8, 0xdf,
-	0xc6, 0x6b, 0x73, 0xe1, 0x56, 0x30, 0x3f, 0x45, 0x50, 0x1e, 0xbe, 0xfc, 0xc0, 0x6b, 0x53, 0x08,
-	0x3c, 0xe6, 0xc2, 0xa7, 0x7a, 0x7b, 0x2e, 0x19, 0x8d, 0xfc, 0x9b, 0x12, 0xf9, 0x3a, 0xbe, 0x3b,
-	0x1f, 0x37, 0x8c, 0x63, 0x0d, 0xf4, 0xef, 0x08, 0xce, 0x8d, 0x74, 0x18, 0x78, 0x1a, 0x94, 0x71,
-	0x0d, 0x6e, 0xa2, 0xe9, 0x27, 0x36, 0x31, 0x64, 0x4b, 0x2a, 0x70, 0x9f, 0xac, 0xcf, 0xa9, 0x40,
-	0x3f, 0x13, 0xa2, 0x9b, 0xf8, 0x5f, 0x08, 0x2e, 0x8c, 0x6f, 0x96, 0xf0, 0xfa, 0x34, 0x42, 0x4c,
-	0xd4, 0xe7, 0xde, 0x29, 0x24, 0xb5, 0x52, 0x9b, 0x52, 0xa9, 0x6f, 0x90, 0x77, 0x66, 0xe7, 0x93,
-	0xd8, 0xcc, 0x8c, 0xeb, 0xf4, 0x07, 0x04, 0x65, 0xd9, 0xa3, 0xc4, 0xff, 0x4a, 0x4b, 0xaa, 0x3d,
-	0xa3, 0xcd, 0x56, 0xf5, 0x42, 0xb8, 0x3c, 0xfc, 0x7b, 0xb0, 0xf6, 0xb0, 0xed, 0xf3, 0x1e, 0xf9,
-	0xae, 0xc4, 0xf7, 0x01, 0xf9, 0xd6, 0x6c, 0x46, 0x8f, 0x77, 0x63, 0xb5, 0xd0, 0x03, 0x1b, 0xcf,
-	0x04, 0xb8, 0x8d, 0x81, 0x56, 0x4d, 0x54, 0xcc, 0x95, 0x71, 0xdd, 0x15, 0xbe, 0x3b, 0xcd, 0x98,
-	0xe3, 0xdb, 0xb1, 0x89, 0x1a, 0xe8, 0x88, 0x25, 0x53, 0x0a, 0xa6, 0xb2, 0xad, 0xda, 0x5b, 0xee,
-	0xbb, 0x81, 0x6e, 0x6e, 0xbe, 0xff, 0xbd, 0xed, 0xa6, 0xc3, 0x8f, 0x3b, 0x87, 0xb5, 0xba, 0xd7,
-	0x36, 0xf4, 0x1f, 0xa8, 0x43, 0xb8, 0x8c, 0xba, 0x17, 0xa8, 0xff, 0x5c, 0x47, 0xff, 0xb3, 0xb5,
-	0x9a, 0x9e, 0xa5, 0xe0, 0xe4, 0xe4, 0xcf, 0xed, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0xe2, 0x24,
-	0xcd, 0x9f, 0xd9, 0x1d, 0x00, 0x00,
+	// 2180 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x5a, 0xcb, 0x6f, 0x1c, 0x49,
+	0x19, 0x57, 0xcd, 0x7b, 0x3e, 0xbf, 0x26, 0xb5, 0x4e, 0x32, 0x9e, 0x6c, 0x42, 0xe8, 0x85, 0x24,
+	0x2c, 0xda, 0xe9, 0xd8, 0x49, 0xbc, 0x8e, 0x97, 0x90, 0x5d, 0x7b, 0xd7, 0x8e, 0x1d, 0x5b, 0x64,
+	0xdb, 0x59, 0x58, 0x71, 0x69, 0xb5, 0xa7, 0xcb, 0xe3, 0xc6, 0x33, 0xdd, 0x9d, 0xae, 0x9a, 0x21,
+	0x93, 0x28, 0x97, 0xe5, 0x80, 0x04, 0x5c, 0xd0, 0x72, 0x40, 0x1c, 0x90, 0xe0, 0xc0, 0x05, 0x84,
+	0x40, 0x42, 0x48, 0xb0, 0x12, 0x42, 0x3c, 0x2e, 0xdc, 0x10, 0x57, 0x8e, 0x5c, 0xf8, 0x2f, 0x50,
+	0x3d, 0xba, 0xa7, 0xe7, 0xd5, 0x33, 0xe3, 0x75, 0x24, 0xf6, 0x94, 0xa9, 0xaf, 0xeb, 0xab, 0xfa,
+	0x7d, 0xef, 0xfa, 0xbe, 0x18, 0xca, 0xed, 0x65, 0xfd, 0x84, 0x74, 0x58, 0x60, 0xb9, 0xd4, 0xb7,
+	0x02, 0xe2, 0xd6, 0x3a, 0x55, 0x3f, 0xf0, 0x98, 0x87, 0x97, 0xea, 0x9e, 0x57, 0x6f, 0x90, 0x6a,
+	0xff, 0xd7, 0xf6, 0x72, 0xe5, 0x55, 0xf9, 0x49, 0xb7, 0x7c, 0x47, 0xb7, 0x5c, 0xd7, 0x63, 0x16,
+	0x73, 0x3c, 0x97, 0x4a, 0xc6, 0xca, 0xe7, 0xd4, 0x57, 0xb1, 0x3a, 0x6c, 0x1d, 0xe9, 0xcc, 0x69,
+	0x12, 0xca, 0xac, 0xa6, 0xaf, 0x36, 0x00, 0x73, 0xdc, 0x13, 0xf5, 0x7b, 0x9e, 0x05, 0x4e, 0xa3,
+	0xe1, 0x58, 0xae, 0x5a, 0x5f, 0x08, 0xd7, 0x66, 0xd3, 0xf2, 0x4d, 0xcb, 0x77, 0xc2, 0x7d, 0xed,
+	0x65, 0xdd, 0xb2, 0x9b, 0x8e, 0xda, 0xa7, 0x2d, 0x43, 0x71, 0xd3, 0x6b, 0x36, 0x1d, 0xc6, 0x88,
+	0x8d, 0x4b, 0x90, 0x3e, 0x21, 0x9d, 0x32, 0xba, 0x8a, 0x6e, 0xcc, 0x1a, 0xfc, 0x27, 0xc6, 0x90,
+	0xb1, 0x2d, 0x66, 0x95, 0x53, 0x82, 0x24, 0x7e, 0x6b, 0xbf, 0x46, 0x30, 0xf3, 0x9e, 0xcb, 0x82,
+	0xce, 0x07, 0xbe, 0x6d, 0x31, 0x82, 0x2f, 0x42, 0xbe, 0x45, 0x49, 0x60, 0x3a, 0xb6, 0xe0, 0x2c,
+	0x1a, 0x39, 0xbe, 0xdc, 0xb1, 0xf1, 0x06, 0x14, 0x9a, 0x2d, 0x29, 0x93, 0x38, 0x60, 0x66, 0xe5,
+	0x5a, 0x75, 0xa4, 0x32, 0xaa, 0x07, 0x4e, 0xdd, 0x25, 0xb6, 0x38, 0xd8, 0x88, 0xf8, 0xf0, 0x06,
+	0x14, 0x6b, 0x21, 0xbe, 0x72, 0x5a, 0x1c, 0xf2, 0x85, 0x84, 0x43, 0x22, 0x59, 0x8c, 0x2e, 0x9b,
+	0xf6, 0x7b, 0x04, 0x59, 0x71, 0x2e, 0x5e, 0x84, 0xac, 0xe3, 0xda, 0xe4, 0xa9, 0x38, 0x69, 0xd6,
+	0x90, 0x0b, 0x7c, 0x05, 0x40, 0x6e, 0x6e, 0x12, 0x97, 0x95, 0x73, 0xe2, 0x53, 0x8c, 0x82, 0x37,
+	0x61, 0xc1, 0x6a, 0xb1, 0x63, 0x2f, 0x70, 0x9e, 0x11, 0xdb, 0x3c, 0x21, 0x1d, 0x5a, 0xce, 0x0b,
+	0x24, 0x95, 0x10, 0x49, 0x2d, 0xe8, 0xf8, 0xcc, 0xab, 0x0a, 0x7b, 0x3c, 0x24, 0x1d, 0x4a, 0x98,
+	0x31, 0xdf, 0x65, 0xe1, 0x14, 0x5c, 0x81, 0x82, 0x1f, 0x90, 0xb6, 0xe3, 0xb5, 0x68, 0xb9, 0x20,
+	0xae, 0x88, 0xd6, 0xbb, 0x99, 0x02, 0x2a, 0xa5, 0x76, 0x33, 0x85, 0x54, 0x29, 0xbd, 0x9b, 0x29,
+	0x64, 0x4a, 0xd9, 0xdd, 0x4c, 0x21, 0x5b, 0xca, 0x69, 0x9b, 0x30, 0x13, 0xd3, 0x0a, 0x47, 0x4f,
+	0xf8, 0x0f, 0x65, 0x20, 0xb9, 0xe0, 0xe8, 0xa9, 0x53, 0x77, 0x2d, 0xd6, 0x0a, 0x08, 0x2d, 0xa7,
+	0xae, 0xa6, 0x39, 0xfa, 0x2e, 0x45, 0xfb, 0x2f, 0x82, 0xb9, 0x7d, 0xa5, 0xce, 0x47, 0x81, 0xe7,
+	0x1d, 0xf5, 0xd8, 0x05, 0x9d, 0xd2, 0x2e, 0x77, 0x01, 0x1a, 0xc4, 0x3a, 0x32, 0x7d, 0x7e, 0xa2,
+	0xb2, 0x6e, 0xa5, 0x1a, 0x39, 0xe1, 0xbe, 0xe5, 0xef, 0x11, 0xeb, 0x68, 0xc7, 0xad, 0x35, 0x5a,
+	0xd4, 0xf1, 0x5c, 0xa3, 0xc8, 0x77, 0xcb, 0xeb, 0xcf, 0x43, 0xae, 0xe1, 0xd5, 0xb9, 0xbb, 0x70,
+	0x2b, 0xa4, 0x8d, 0x6c, 0xc3, 0xab, 0xef, 0xd8, 0xf8, 0x3a, 0x2c, 0x3c, 0x69, 0x91, 0x16, 0x31,
+	0x23, 0x37, 0x2f, 0x67, 0xc4, 0xf7, 0x79, 0x41, 0x7e, 0x1c, 0x52, 0xf1, 0x12, 0x14, 0x1a, 0x5e,
+	0xcd, 0x6a, 0xf0, 0x13, 0xb2, 0x62, 0x47, 0x5e, 0xac, 0x77, 0x6c, 0xed, 0x6b, 0x30, 0xbf, 0x6f,
+	0xf9, 0x3e, 0x09, 0xf6, 0x09, 0xb3, 0xb8, 0xb3, 0xe2, 0x7b, 0x70, 0xe9, 0xd8, 0xa9, 0x1f, 0x13,
+	0xca, 0xcc, 0xa3, 0x56, 0xa3, 0xd1, 0x31, 0x6b, 0x5e, 0xd3, 0x6f, 0x10, 0x46, 0x6c, 0x93, 0x92,
+	0x27, 0x42, 0xfc, 0xb4, 0x51, 0x56, 0x5b, 0xb6, 0xf8, 0x8e, 0xcd, 0x70, 0xc3, 0x01, 0x79, 0xa2,
+	0x7d, 0x07, 0xc1, 0xfc, 0x36, 0x61, 0x1f, 0x50, 0x12, 0x18, 0xe4, 0x49, 0x8b, 0x50, 0x86, 0x3f,
+	0x0f, 0xb3, 0xb6, 0x13, 0x90, 0x1a, 0xf3, 0x82, 0x4e, 0xd7, 0xe7, 0x67, 0x22, 0xda, 0x8e, 0x1d,
+	0x8f, 0x88, 0x54, 0x4f, 0x44, 0xdc, 0x81, 0x8b, 0x0d, 0x8b, 0x32, 0xb3, 0x4d, 0x02, 0xe7, 0xc8,
+	0x21, 0xb6, 0xc9, 0x02, 0x42, 0x4c, 0xea, 0x3c, 0x23, 0x4a, 0x17, 0x8b, 0xfc, 0xf3, 0xd7, 0xd5,
+	0xd7, 0xc7, 0x01, 0x21, 0x07, 0xce, 0x33, 0xa2, 0xfd, 0x12, 0x41, 0x5e, 0x69, 0x14, 0x5f, 0x82,
+	0x62, 0x3b, 0x08, 0xf5, 0x2e, 0x1d, 0xa1, 0xd0, 0x0e, 0x94, 0x6a, 0xef, 0xc3, 0x1c, 0x0f, 0x77,
+	0x27, 0x54, 0xfb, 0x04, 0x86, 0x99, 0x6d, 0x5a, 0x7e, 0xb4, 0x3a, 0x93, 0x70, 0xfb, 0x1e, 0x82,
+	0x85, 0x48, 0x67, 0xd4, 0xf7, 0x5c, 0x4a, 0xf0, 0x7d, 0x28, 0x70, 0x67, 0xa7, 0x5d, 0x97, 0x7b,
+	0x2d, 0xe1, 0x58, 0x43, 0x6d, 0x35, 0x22, 0x26, 0xbc, 0x0a, 0x19, 0xee, 0x41, 0x4a, 0x20, 0x2d,
+	0x81, 0x59, 0x49, 0x68, 0x88, 0xfd, 0x1c, 0xcc, 0x2b, 0x1b, 0x16, 0xab, 0x1d, 0x4f, 0x6f, 0xc5,
+	0x25, 0x28, 0x28, 0x2b, 0xca, 0xb0, 0x2a, 0x1a, 0x79, 0x69, 0x46, 0x7a, 0x5a, 0x3b, 0x7e, 0x08,
+	0xe5, 0x38, 0x96, 0x1d, 0x9e, 0x7d, 0xce, 0x04, 0x90, 0xf6, 0x1b, 0x04, 0x4b, 0x43, 0x8e, 0x56,
+	0xda, 0xff, 0x10, 0x72, 0xc2, 0x5f, 0x68, 0x19, 0x5d, 0x4d, 0xdf, 0x98, 0x59, 0x79, 0x3b, 0x41,
+	0x7d, 0x23, 0x4f, 0xa9, 0x0a, 0x17, 0xa3, 0x32, 0x11, 0xa8, 0xf3, 0x2a, 0x77, 0x61, 0x26, 0x46,
+	0x8e, 0x17, 0x90, 0xa2, 0x2c, 0x20, 0x8b, 0x90, 0x6d, 0x5b, 0x8d, 0x16, 0x51, 0x15, 0x44, 0x2e,
+	0xd6, 0x53, 0x6b, 0x48, 0xfb, 0x24, 0x05, 0x8b, 0xbd, 0x96, 0x39, 0x2b, 0x5f, 0x79, 0x0a, 0xe7,
+	0x79, 0x14, 0x34, 0x88, 0xd5, 0x26, 0xd4, 0x3c, 0xec, 0x98, 0xdd, 0x60, 0xe4, 0xd2, 0x6f, 0x4d,
+	0x28, 0x7d, 0x24, 0xb8, 0xf4, 0xa8, 0x36, 0xa1, 0x1b, 0x1d, 0xa1, 0x15, 0x95, 0x0c, 0xcf, 0x35,
+	0xfb, 0xe9, 0x95, 0x63, 0xb8, 0x30, 0x7c, 0xf3, 0x10, 0xcd, 0xac, 0xc5, 0x35, 0x33, 0x99, 0x4b,
+	0xc7, 0xb4, 0xf7, 0x77, 0x04, 0x17, 0xf7, 0x1c, 0xca, 0xc4, 0xe9, 0x0f, 0x1c, 0xca, 0x5d, 0x64,
+	0x94, 0x2b, 0xe5, 0x12, 0x33, 0x54, 0x6f, 0xcd, 0x5e, 0x84, 0x2c, 0x65, 0x56, 0xc0, 0x04, 0xaa,
+	0xb4, 0x21, 0x17, 0x3c, 0xe9, 0xf8, 0x56, 0x3d, 0xe6, 0xe1, 0x59, 0xa3, 0xc0, 0x09, 0xdc, 0xab,
+	0x93, 0x82, 0x21, 0x3b, 0x3a, 0x18, 0x64, 0xa1, 0xd3, 0x5e, 0x40, 0x79, 0x50, 0x0c, 0xe5, 0x08,
+	0x1b, 0x90, 0x13, 0x02, 0x87, 0x6e, 0xfb, 0x7a, 0x82, 0x8a, 0xfa, 0x6c, 0x66, 0x28, 0x4e, 0x7c,
+	0x19, 0xc0, 0x25, 0x4f, 0x99, 0x19, 0x17, 0xaa, 0xc8, 0x29, 0x07, 0x9c, 0xa0, 0x7d, 0x9c, 0x92,
+	0xf7, 0x4b, 0x5e, 0xe9, 0x3f, 0xf4, 0x2c, 0x32, 0xfd, 0x17, 0x61, 0x5e, 0x5c, 0x69, 0x46, 0xae,
+	0x2c, 0x13, 0xc3, 0x9c, 0xa0, 0x86, 0x57, 0xf1, 0x2b, 0x88, 0x6b, 0x77, 0x37, 0xc9, 0x8a, 0x37,
+	0x43, 0x5c, 0x3b, 0xda, 0xd2, 0xa3, 0xfb, 0x6c, 0x9f, 0xee, 0x2f, 0x03, 0x88, 0x8f, 0xcc, 0x3b,
+	0x21, 0xae, 0x32, 0xb4, 0xd8, 0xfe, 0x98, 0x13, 0x92, 0x4c, 0x93, 0x4f, 0xc8, 0x53, 0xdf, 0x47,
+	0x30, 0xb3, 0x6f, 0xf9, 0x11, 0x84, 0x7b, 0x50, 0xe0, 0x01, 0x15, 0x78, 0x1e, 0x2b, 0xa3, 0x49,
+	0xbc, 0xd5, 0xf0, 0x3c, 0x66, 0xe4, 0x9b, 0xf2, 0x47, 0xc8, 0x3e, 0x65, 0xfe, 0xce, 0xcb, 0xf0,
+	0x3a, 0xd2, 0xfe, 0x8d, 0x60, 0x69, 0x88, 0x8d, 0x94, 0x93, 0xec, 0xc2, 0x42, 0xc3, 0x62, 0xbc,
+	0xbe, 0xf3, 0x47, 0xc5, 0x84, 0x10, 0xf7, 0xbc, 0xba, 0x80, 0x38, 0x27, 0x59, 0xd5, 0x12, 0x3f,
+	0x94, 0xe5, 0x33, 0xb4, 0x06, 0x55, 0x09, 0xe3, 0xda, 0x18, 0x61, 0xd5, 0x76, 0x51, 0x4a, 0xc3,
+	0x05, 0xc5, 0xd7, 0x60, 0x41, 0x78, 0x5e, 0xcc, 0x3e, 0x69, 0x61, 0x9f, 0x39, 0x4e, 0x7e, 0x14,
+	0xda, 0x48, 0xfb, 0x49, 0x0a, 0x2e, 0x8b, 0xb4, 0xf3, 0x69, 0xfc, 0x30, 0xa1, 0x56, 0x7d, 0xa6,
+	0x3d, 0xf1, 0xb7, 0x29, 0x28, 0x09, 0xe5, 0x9c, 0xa1, 0x3b, 0xb2, 0xe4, 0xf2, 0xb0, 0x31, 0xae,
+	0x3c, 0xc4, 0xa0, 0xfc, 0x5f, 0x96, 0x86, 0x3f, 0x21, 0xb8, 0x32, 0xca, 0xa1, 0x5e, 0x42, 0xd0,
+	0x3c, 0x1a, 0x1e, 0x34, 0x5f, 0x9e, 0x42, 0x8d, 0xbd, 0x91, 0xa3, 0xfd, 0x08, 0x01, 0x96, 0xbd,
+	0xa5, 0xd4, 0xe6, 0x88, 0x30, 0xc8, 0x0e, 0x86, 0xc1, 0x0e, 0x77, 0x62, 0x16, 0x74, 0xcc, 0x96,
+	0x60, 0x17, 0x4e, 0x9c, 0x1c, 0xbf, 0xb1, 0x46, 0x96, 0x3b, 0x7b, 0xb4, 0xe8, 0xeb, 0xc9, 0xd2,
+	0xa5, 0x8c, 0xf6, 0x11, 0x82, 0x4b, 0x02, 0xf9, 0xfb, 0xbc, 0x1f, 0xe1, 0x8a, 0x55, 0x7c, 0x93,
+	0x87, 0xe9, 0xdb, 0x90, 0x97, 0xc8, 0x26, 0x49, 0x2d, 0x71, 0x68, 0x21, 0x9b, 0xf6, 0x17, 0x04,
+	0x79, 0x83, 0xd4, 0x88, 0xe3, 0x4f, 0x74, 0xe1, 0x75, 0x58, 0x08, 0x5b, 0x36, 0xf3, 0xd8, 0xa2,
+	0xc7, 0x51, 0x87, 0x38, 0x1f, 0x92, 0x1f, 0x08, 0xea, 0xa8, 0xa6, 0xec, 0x55, 0x28, 0x7e, 0xdb,
+	0x62, 0x24, 0x68, 0x5a, 0xc1, 0x89, 0x4a, 0x09, 0x5d, 0x02, 0x5e, 0x85, 0x82, 0x4d, 0x2c, 0xbb,
+	0xe1, 0xb8, 0x32, 0x1f, 0xc4, 0x3a, 0xe2, 0x70, 0x68, 0x51, 0x8d, 0xfa, 0x36, 0x23, 0xda, 0xab,
+	0x6d, 0xc3, 0x9c, 0xec, 0x2a, 0x43, 0x49, 0xca, 0x90, 0x0f, 0xe4, 0x4f, 0xd5, 0xd2, 0x84, 0x4b,
+	0x0e, 0x20, 0xea, 0x65, 0xd5, 0x1b, 0xb2, 0x4b, 0xe0, 0xaf, 0x7b, 0xbc, 0x4d, 0xa2, 0x24, 0x36,
+	0x85, 0xa7, 0x54, 0xfa, 0x1e, 0x99, 0xe9, 0xd8, 0xfb, 0x31, 0x21, 0x57, 0xa5, 0x12, 0x72, 0x15,
+	0x83, 0xf2, 0x36, 0x61, 0x7b, 0x22, 0x38, 0xc6, 0x21, 0x1a, 0x62, 0xaa, 0x53, 0xde, 0x7a, 0x28,
+	0x5a, 0x43, 0x11, 0x89, 0x2b, 0x03, 0x79, 0xf1, 0x62, 0xb7, 0xf1, 0x93, 0x0a, 0x1f, 0x48, 0x86,
+	0xaf, 0xc1, 0x9c, 0xb0, 0x7b, 0xac, 0x63, 0xe4, 0xee, 0x31, 0xcb, 0xcd, 0x1f, 0xd2, 0xb4, 0x23,
+	0xc8, 0x87, 0xd1, 0xbe, 0x02, 0x85, 0xbe, 0x94, 0x31, 0x70, 0x87, 0xda, 0xca, 0xbb, 0x72, 0xc9,
+	0x73, 0x1d, 0x16, 0x38, 0x4f, 0xcd, 0x73, 0xa9, 0x43, 0x19, 0x77, 0xef, 0xd0, 0x09, 0x1b, 0x5e,
+	0x7d, 0xb3, 0x4b, 0xd5, 0xfe, 0x81, 0xa0, 0x10, 0x2f, 0x48, 0xe3, 0x54, 0x16, 0x2f, 0x04, 0xd9,
+	0xe9, 0x0b, 0xc1, 0x90, 0x2c, 0x98, 0x3b, 0x65, 0x16, 0x8c, 0x27, 0x0a, 0xf5, 0xb2, 0xfd, 0x21,
+	0x82, 0x45, 0x9e, 0x81, 0xc3, 0xd9, 0x0b, 0x3d, 0x23, 0xef, 0xec, 0x2d, 0xb4, 0xe9, 0xfe, 0x42,
+	0xdb, 0x53, 0xa4, 0x33, 0xbd, 0x45, 0x5a, 0xfb, 0x2e, 0x82, 0xf3, 0x7d, 0x98, 0x54, 0x45, 0xd8,
+	0x82, 0x62, 0x98, 0x11, 0x68, 0x39, 0x27, 0x72, 0xd3, 0x8d, 0x24, 0x5d, 0xc6, 0x07, 0x4a, 0x46,
+	0x97, 0x75, 0xd8, 0xab, 0x27, 0x3f, 0xec, 0xd5, 0xc3, 0xe2, 0x6f, 0xba, 0x6f, 0x91, 0x1a, 0x3b,
+	0xab, 0x87, 0x77, 0x52, 0xab, 0xa2, 0xfd, 0x0c, 0x41, 0x49, 0x5e, 0x47, 0xec, 0x50, 0x84, 0x58,
+	0xea, 0x43, 0x63, 0xe6, 0x51, 0xa9, 0xb1, 0xf3, 0xa8, 0x74, 0xcf, 0x3c, 0xaa, 0xc7, 0x8e, 0x99,
+	0x3e, 0x3b, 0x5e, 0x80, 0x5c, 0x40, 0x2c, 0xea, 0xb9, 0xca, 0x01, 0xd4, 0x4a, 0x73, 0xa0, 0x32,
+	0x4c, 0x33, 0xca, 0x4e, 0x0f, 0x01, 0x82, 0x88, 0x5a, 0x46, 0x63, 0x4b, 0x6d, 0xbf, 0xb4, 0x46,
+	0x8c, 0x7d, 0xe5, 0x57, 0xe7, 0x61, 0xe1, 0x21, 0xe9, 0x3c, 0x8e, 0xf1, 0xe0, 0x1f, 0x20, 0x98,
+	0xdd, 0x26, 0xec, 0xdd, 0x50, 0xd7, 0xb8, 0x9a, 0xdc, 0x75, 0x45, 0x1b, 0x95, 0xf1, 0x2a, 0x49,
+	0xf3, 0xa2, 0x68, 0xb3, 0x76, 0xed, 0xa3, 0x7f, 0xfd, 0xe7, 0xe3, 0xd4, 0x55, 0x7c, 0x45, 0x6f,
+	0x2f, 0xeb, 0xa1, 0x61, 0x1d, 0x42, 0xf5, 0xe7, 0x71, 0xcb, 0xbf, 0xc0, 0x3f, 0x45, 0x30, 0x13,
+	0xcb, 0xf0, 0xf8, 0x8d, 0x64, 0x34, 0x7d, 0x79, 0xb7, 0x32, 0xc9, 0xe4, 0x40, 0x7b, 0x4b, 0x60,
+	0xb9, 0x83, 0x6f, 0x25, 0x63, 0xd1, 0xa3, 0x27, 0x8e, 0xfe, 0x3c, 0xfc, 0xf9, 0x02, 0xff, 0x02,
+	0xc1, 0xb9, 0x81, 0xb4, 0x8f, 0x6f, 0x25, 0xc3, 0x1c, 0x5a, 0x24, 0x26, 0x03, 0xfb, 0xa6, 0x00,
+	0xbb, 0x8c, 0xf5, 0x49, 0xc1, 0xae, 0xcb, 0x44, 0x85, 0x7f, 0x2e, 0x81, 0x86, 0x07, 0x1d, 0xb0,
+	0x80, 0x58, 0xcd, 0x97, 0xa2, 0xcf, 0xe9, 0x21, 0x52, 0x01, 0xe6, 0x26, 0xc2, 0x7f, 0x40, 0x30,
+	0xd7, 0x93, 0xa0, 0xb0, 0x9e, 0x94, 0x8b, 0x87, 0xa4, 0xd7, 0xca, 0xcd, 0xc9, 0x19, 0x64, 0x4c,
+	0x69, 0xef, 0x09, 0xbc, 0xf7, 0xf1, 0xbd, 0x53, 0xd8, 0x5f, 0xef, 0xa6, 0xbe, 0x3f, 0x23, 0x78,
+	0xa5, 0xe7, 0x02, 0xa5, 0xe2, 0xa9, 0x25, 0x98, 0x38, 0xf1, 0x6a, 0x7b, 0x02, 0xf9, 0x16, 0x7e,
+	0xf7, 0x53, 0x21, 0xef, 0xaa, 0xff, 0xc7, 0x08, 0xf2, 0x6a, 0x92, 0x82, 0xbf, 0x34, 0xc9, 0xb4,
+	0x45, 0x02, 0x9e, 0x62, 0x30, 0xa3, 0xad, 0x0a, 0xc8, 0x37, 0x71, 0x75, 0x0c, 0x64, 0x9e, 0xce,
+	0xa9, 0xfe, 0x5c, 0x25, 0x79, 0x11, 0x67, 0xb3, 0xf1, 0xe9, 0x5c, 0x62, 0x5e, 0x1a, 0x32, 0xf1,
+	0xad, 0xe8, 0x53, 0x8e, 0xfd, 0xb4, 0x3b, 0x02, 0xa9, 0x8e, 0xdf, 0x98, 0x04, 0xe9, 0xfa, 0xa1,
+	0x3a, 0x02, 0xff, 0x11, 0xc1, 0xb9, 0x81, 0x21, 0x6a, 0x62, 0x42, 0x18, 0x35, 0x13, 0xae, 0xdc,
+	0x3e, 0xcd, 0x9c, 0x56, 0x5b, 0x17, 0xb8, 0x6f, 0xe3, 0x95, 0xa9, 0x70, 0x4b, 0x98, 0x9f, 0x20,
+	0x28, 0xf5, 0xcf, 0xe3, 0xf0, 0xca, 0x18, 0x07, 0x1e, 0x32, 0x83, 0xac, 0xdc, 0x9a, 0x8a, 0x47,
+	0x21, 0xff, 0xaa, 0x40, 0xbe, 0x86, 0x57, 0xa7, 0xf3, 0x0d, 0xfd, 0x58, 0x01, 0xfd, 0x2b, 0x82,
+	0x73, 0x03, 0x4d, 0x2f, 0x1e, 0x07, 0x65, 0xd8, 0xcc, 0xa5, 0x72, 0x7b, 0x3a, 0x26, 0x25, 0xc0,
+	0xa6, 0x10, 0xe0, 0x9e, 0xb6, 0x36, 0xa5, 0x00, 0xdd, 0x4c, 0x88, 0x5e, 0xc7, 0xff, 0x44, 0x70,
+	0x61, 0x78, 0xff, 0x8e, 0xd7, 0xc6, 0x39, 0xc4, 0x48, 0x79, 0xee, 0x9e, 0x82, 0x53, 0x09, 0xb5,
+	0x21, 0x84, 0xfa, 0x8a, 0xf6, 0xe6, 0xe4, 0xfe, 0xc4, 0x0f, 0x33, 0xe2, 0x32, 0xfd, 0x0d, 0x41,
+	0x49, 0xb4, 0xcd, 0xf1, 0xff, 0x38, 0x4e, 0xaa, 0x3d, 0x83, 0xfd, 0x7f, 0x62, 0x5a, 0xec, 0x69,
+	0x27, 0xb5, 0x6f, 0x08, 0xc4, 0xef, 0x6b, 0xef, 0x4c, 0x66, 0x86, 0xf8, 0xc8, 0xa0, 0x1a, 0xda,
+	0x64, 0x5d, 0xbc, 0xf2, 0xd6, 0x7b, 0xe6, 0x09, 0xf8, 0x77, 0x48, 0xfd, 0x9f, 0x45, 0xdf, 0x08,
+	0x00, 0xaf, 0x8e, 0x53, 0xef, 0xf0, 0x99, 0xc1, 0x14, 0x32, 0xa9, 0xa8, 0xd6, 0xc6, 0x14, 0xd5,
+	0xf5, 0xc3, 0xee, 0x6d, 0xe2, 0x26, 0xa5, 0x7d, 0x3c, 0xf8, 0xa6, 0xc4, 0x93, 0xf9, 0x78, 0xdf,
+	0xe3, 0xbc, 0x72, 0x67, 0x4a, 0x2e, 0xe5, 0x45, 0xef, 0x08, 0xfc, 0x6f, 0xe1, 0xbb, 0x53, 0x87,
+	0x46, 0x78, 0xd4, 0xc6, 0x83, 0x6f, 0x6e, 0xd5, 0x1d, 0x76, 0xdc, 0x3a, 0xac, 0xd6, 0xbc, 0xa6,
+	0xae, 0xfe, 0x3a, 0xa2, 0x0f, 0x85, 0x5e, 0xf3, 0x02, 0xf9, 0x07, 0x15, 0x83, 0x7f, 0x90, 0x61,
+	0xd6, 0x3d, 0x53, 0xce, 0x25, 0x72, 0xe2, 0x9f, 0x5b, 0xff, 0x1b, 0x00, 0xdd, 0xa9, 0x41, 0x48,
+	0xb6, 0x21, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1997,10 +2356,15 @@ type KeyTransparencyClient interface {
 	// QueueUserUpdate enqueues an update to a user's profile.
 	//
 	// Clients should poll GetUser until the update appears, and retry if no
-	// update appears after a timeout.
-	QueueEntryUpdate(ctx context.Context, in *UpdateEntryRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// BatchQueueUserUpdate enqueues a list of user profiles.
-	BatchQueueUserUpdate(ctx context.Context, in *BatchQueueUserUpdateRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// update appears after a timeout. If the server has a receipt_key, the
+	// response is a receipt that the update will appear by a deadline.
+	QueueEntryUpdate(ctx context.Context, in *UpdateEntryRequest, opts ...grpc.CallOption) (*SignedReceipt, error)
+	// BatchQueueUserUpdate enqueues a list of user profiles, and returns a
+	// receipt for all of them if the server has a receipt_key.
+	BatchQueueUserUpdate(ctx context.Context, in *BatchQueueUserUpdateRequest, opts ...grpc.CallOption) (*SignedReceipt, error)
+	// ListUserRejections returns the most recent mutations for a user that could
+	// not be applied to the map, along with the reason they were rejected.
+	ListUserRejections(ctx context.Context, in *ListUserRejectionsRequest, opts ...grpc.CallOption) (*ListUserRejectionsResponse, error)
 }
 
 type keyTransparencyClient struct {
@@ -2165,8 +2529,8 @@ func (c *keyTransparencyClient) BatchListUserRevisions(ctx context.Context, in *
 	return out, nil
 }
 
-func (c *keyTransparencyClient) QueueEntryUpdate(ctx context.Context, in *UpdateEntryRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
+func (c *keyTransparencyClient) QueueEntryUpdate(ctx context.Context, in *UpdateEntryRequest, opts ...grpc.CallOption) (*SignedReceipt, error) {
+	out := new(SignedReceipt)
 	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparency/QueueEntryUpdate", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -2174,8 +2538,8 @@ func (c *keyTransparencyClient) QueueEntryUpdate(ctx context.Context, in *Update
 	return out, nil
 }
 
-func (c *keyTransparencyClient) BatchQueueUserUpdate(ctx context.Context, in *BatchQueueUserUpdateRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
+func (c *keyTransparencyClient) BatchQueueUserUpdate(ctx context.Context, in *BatchQueueUserUpdateRequest, opts ...grpc.CallOption) (*SignedReceipt, error) {
+	out := new(SignedReceipt)
 	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparency/BatchQueueUserUpdate", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -2183,6 +2547,15 @@ func (c *keyTransparencyClient) BatchQueueUserUpdate(ctx context.Context, in *Ba
 	return out, nil
 }
 
+func (c *keyTransparencyClient) ListUserRejections(ctx context.Context, in *ListUserRejectionsRequest, opts ...grpc.CallOption) (*ListUserRejectionsResponse, error) {
+	out := new(ListUserRejectionsResponse)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.v1.KeyTransparency/ListUserRejections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KeyTransparencyServer is the server API for KeyTransparency service.
 type KeyTransparencyServer interface {
 	// GetDirectory returns the information needed to verify the specified
@@ -2222,10 +2595,15 @@ type KeyTransparencyServer interface {
 	// QueueUserUpdate enqueues an update to a user's profile.
 	//
 	// Clients should poll GetUser until the update appears, and retry if no
-	// update appears after a timeout.
-	QueueEntryUpdate(context.Context, *UpdateEntryRequest) (*empty.Empty, error)
-	// BatchQueueUserUpdate enqueues a list of user profiles.
-	BatchQueueUserUpdate(context.Context, *BatchQueueUserUpdateRequest) (*empty.Empty, error)
+	// update appears after a timeout. If the server has a receipt_key, the
+	// response is a receipt that the update will appear by a deadline.
+	QueueEntryUpdate(context.Context, *UpdateEntryRequest) (*SignedReceipt, error)
+	// BatchQueueUserUpdate enqueues a list of user profiles, and returns a
+	// receipt for all of them if the server has a receipt_key.
+	BatchQueueUserUpdate(context.Context, *BatchQueueUserUpdateRequest) (*SignedReceipt, error)
+	// ListUserRejections returns the most recent mutations for a user that could
+	// not be applied to the map, along with the reason they were rejected.
+	ListUserRejections(context.Context, *ListUserRejectionsRequest) (*ListUserRejectionsResponse, error)
 }
 
 func RegisterKeyTransparencyServer(s *grpc.Server, srv KeyTransparencyServer) {
@@ -2490,6 +2868,24 @@ func _KeyTransparency_BatchQueueUserUpdate_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KeyTransparency_ListUserRejections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserRejectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencyServer).ListUserRejections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.v1.KeyTransparency/ListUserRejections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencyServer).ListUserRejections(ctx, req.(*ListUserRejectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _KeyTransparency_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "google.keytransparency.v1.KeyTransparency",
 	HandlerType: (*KeyTransparencyServer)(nil),
@@ -2542,6 +2938,10 @@ var _KeyTransparency_serviceDesc = grpc.ServiceDesc{
 			MethodName: "BatchQueueUserUpdate",
 			Handler:    _KeyTransparency_BatchQueueUserUpdate_Handler,
 		},
+		{
+			MethodName: "ListUserRejections",
+			Handler:    _KeyTransparency_ListUserRejections_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
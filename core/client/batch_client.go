@@ -56,24 +56,34 @@ func (c *Client) BatchCreateUser(ctx context.Context, users []*tpb.User,
 		}
 		mutations = append(mutations, mutation)
 	}
-	return c.BatchQueueUserUpdate(ctx, mutations, signers, opts...)
+	_, err = c.BatchQueueUserUpdate(ctx, mutations, signers, opts...)
+	return err
 }
 
 // BatchQueueUserUpdate signs the mutations and sends them to the server.
+// BatchQueueUserUpdate returns the server's receipt for the
+// mutations, or nil if the directory does not issue receipts.
 func (c *Client) BatchQueueUserUpdate(ctx context.Context, mutations []*entry.Mutation,
-	signers []tink.Signer, opts ...grpc.CallOption) error {
+	signers []tink.Signer, opts ...grpc.CallOption) (*pb.SignedReceipt, error) {
 	updates := make([]*pb.EntryUpdate, 0, len(mutations))
 	for _, m := range mutations {
 		update, err := m.SerializeAndSign(signers)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		updates = append(updates, update)
 	}
 
 	req := &pb.BatchQueueUserUpdateRequest{DirectoryId: c.DirectoryID, Updates: updates}
-	_, err := c.cli.BatchQueueUserUpdate(ctx, req, opts...)
-	return err
+	var signed *pb.SignedReceipt
+	if err := retryWhenExhausted(ctx, func() error {
+		var err error
+		signed, err = c.cli.BatchQueueUserUpdate(ctx, req, opts...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return c.verifyReceipt(signed, updates)
 }
 
 // BatchCreateMutation fetches the current index and value for a list of users and prepares mutations.
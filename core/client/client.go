@@ -37,6 +37,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/tink/go/tink"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -80,6 +81,8 @@ type Verifier interface {
 	VerifyRevision(revision *pb.Revision, trusted types.LogRootV1) (*types.LogRootV1, *types.MapRootV1, error)
 	// VerifySignedMapRoot verifies the signature on the SignedMapRoot.
 	VerifySignedMapRoot(smr *trillian.SignedMapRoot) (*types.MapRootV1, error)
+	// VerifyReceipt verifies the signature on a receipt for queued mutations.
+	VerifyReceipt(in *pb.SignedReceipt) (*pb.Receipt, error)
 }
 
 // Client is a helper library for issuing updates to the key server.
@@ -240,7 +243,7 @@ func (c *Client) Update(ctx context.Context, u *tpb.User, signers []tink.Signer,
 	}
 
 	// 2. Queue Mutation.
-	if err := c.QueueMutation(ctx, m, signers, opts...); err != nil {
+	if _, err := c.QueueMutation(ctx, m, signers, opts...); err != nil {
 		return nil, err
 	}
 
@@ -249,16 +252,67 @@ func (c *Client) Update(ctx context.Context, u *tpb.User, signers []tink.Signer,
 }
 
 // QueueMutation signs an entry.Mutation and sends it to the server.
-func (c *Client) QueueMutation(ctx context.Context, m *entry.Mutation, signers []tink.Signer, opts ...grpc.CallOption) error {
+// QueueMutation returns the server's receipt for the mutation, or nil
+// if the directory does not issue receipts.
+func (c *Client) QueueMutation(ctx context.Context, m *entry.Mutation, signers []tink.Signer,
+	opts ...grpc.CallOption) (*pb.SignedReceipt, error) {
 	update, err := m.SerializeAndSign(signers)
 	if err != nil {
-		return fmt.Errorf("failed SerializeAndSign: %v", err)
+		return nil, fmt.Errorf("failed SerializeAndSign: %v", err)
 	}
 
 	Vlog.Printf("Sending Update request...")
 	req := &pb.UpdateEntryRequest{DirectoryId: c.DirectoryID, EntryUpdate: update}
-	_, err = c.cli.QueueEntryUpdate(ctx, req, opts...)
-	return err
+	var signed *pb.SignedReceipt
+	if err := retryWhenExhausted(ctx, func() error {
+		var err error
+		signed, err = c.cli.QueueEntryUpdate(ctx, req, opts...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return c.verifyReceipt(signed, []*pb.EntryUpdate{update})
+}
+
+// retryWhenExhausted calls f until it succeeds or returns an error other than
+// ResourceExhausted with a RetryInfo detail, waiting for the suggested retry
+// delay between calls. The server returns such errors when the sequencer has
+// fallen behind.
+func retryWhenExhausted(ctx context.Context, f func() error) error {
+	for {
+		err := f()
+		delay, ok := retryDelay(err)
+		if !ok {
+			return err
+		}
+		Vlog.Printf("Server is busy, retrying in %v: %v", delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// retryDelay returns the delay suggested by the server if err is
+// ResourceExhausted and carries a RetryInfo detail.
+func retryDelay(err error) (time.Duration, bool) {
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.RetryInfo)
+		if !ok {
+			continue
+		}
+		delay, err := ptypes.Duration(info.RetryDelay)
+		if err != nil {
+			return 0, false
+		}
+		return delay, true
+	}
+	return 0, false
 }
 
 // CreateMutation fetches the current index and value for a user and prepares a mutation.
@@ -20,11 +20,12 @@ import (
 	"testing"
 	"time"
 
-	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/keytransparency/core/testutil"
 	"github.com/google/trillian"
 	"github.com/google/trillian/types"
 	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -114,6 +115,57 @@ func TestCompressHistory(t *testing.T) {
 	}
 }
 
+func exhausted(t *testing.T, delay time.Duration) error {
+	t.Helper()
+	st, err := status.New(codes.ResourceExhausted, "busy").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(delay)})
+	if err != nil {
+		t.Fatalf("WithDetails(): %v", err)
+	}
+	return st.Err()
+}
+
+func TestRetryWhenExhausted(t *testing.T) {
+	busy := exhausted(t, 10*time.Millisecond)
+	for _, tc := range []struct {
+		desc      string
+		errs      []error // Returned by successive calls.
+		timeout   time.Duration
+		wantCalls int
+		wantCode  codes.Code
+	}{
+		{desc: "success", errs: []error{nil}, wantCalls: 1},
+		{desc: "retry", errs: []error{busy, busy, nil}, wantCalls: 3},
+		{desc: "no retry info", errs: []error{status.Error(codes.ResourceExhausted, "busy")},
+			wantCalls: 1, wantCode: codes.ResourceExhausted},
+		{desc: "other error", errs: []error{status.Error(codes.Internal, "oops")},
+			wantCalls: 1, wantCode: codes.Internal},
+		{desc: "deadline", errs: []error{exhausted(t, time.Hour)}, timeout: 10 * time.Millisecond,
+			wantCalls: 1, wantCode: codes.ResourceExhausted},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			timeout := tc.timeout
+			if timeout == 0 {
+				timeout = time.Minute
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			var calls int
+			err := retryWhenExhausted(ctx, func() error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Errorf("retryWhenExhausted(): %v, want %v", err, want)
+			}
+			if got, want := calls, tc.wantCalls; got != want {
+				t.Errorf("retryWhenExhausted(): %v calls, want %v", got, want)
+			}
+		})
+	}
+}
+
 func TestPaginateHistory(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
@@ -293,11 +345,16 @@ func (f *fakeKeyServer) BatchGetUserIndex(context.Context,
 	return nil, status.Error(codes.Unimplemented, "not implemented")
 }
 
-func (f *fakeKeyServer) QueueEntryUpdate(context.Context, *pb.UpdateEntryRequest) (*empty.Empty, error) {
+func (f *fakeKeyServer) QueueEntryUpdate(context.Context, *pb.UpdateEntryRequest) (*pb.SignedReceipt, error) {
 	return nil, status.Error(codes.Unimplemented, "not implemented")
 }
 
-func (f *fakeKeyServer) BatchQueueUserUpdate(context.Context, *pb.BatchQueueUserUpdateRequest) (*empty.Empty, error) {
+func (f *fakeKeyServer) BatchQueueUserUpdate(context.Context, *pb.BatchQueueUserUpdateRequest) (*pb.SignedReceipt, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
+func (f *fakeKeyServer) ListUserRejections(context.Context,
+	*pb.ListUserRejectionsRequest) (*pb.ListUserRejectionsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "not implemented")
 }
 
@@ -320,3 +377,7 @@ func (f *fakeVerifier) VerifyRevision(in *pb.Revision, trusted types.LogRootV1)
 func (f *fakeVerifier) VerifySignedMapRoot(smr *trillian.SignedMapRoot) (*types.MapRootV1, error) {
 	return &types.MapRootV1{Revision: uint64(smr.MapRoot[0])}, nil
 }
+
+func (f *fakeVerifier) VerifyReceipt(in *pb.SignedReceipt) (*pb.Receipt, error) {
+	return nil, nil
+}
@@ -49,6 +49,9 @@ type Config struct {
 	BatchWriteQPS   int
 	BatchWriteCount int
 	BatchWriteSize  int
+	// BatchWriter is the principal that sends batches of more than one
+	// user. It must be authorized to update any user in the directory.
+	BatchWriter string
 
 	ReadQPS      int
 	ReadCount    int
@@ -109,7 +112,7 @@ func New(ctx context.Context, dial DialFunc, callOptions CallOptions,
 // Run runs a total of operationCount operations across numWorkers.
 // The number of workers should roughly be (goal QPS) * (timeout seconds).
 func (h *Hammer) Run(ctx context.Context, numWorkers int, c Config) error {
-	workers, err := h.newWorkers(numWorkers)
+	workers, err := h.newWorkers(numWorkers, c.BatchWriter)
 	if err != nil {
 		return err
 	}
@@ -194,10 +197,11 @@ func genArgs(ctx context.Context, qps, batch, count int, duration time.Duration)
 
 type worker struct {
 	*Hammer
-	client *client.Client
+	client      *client.Client
+	batchWriter string
 }
 
-func (h *Hammer) newWorkers(n int) ([]worker, error) {
+func (h *Hammer) newWorkers(n int, batchWriter string) ([]worker, error) {
 	workers := make([]worker, 0, n)
 	for i := 0; i < n; i++ {
 		// Give each worker its own client.
@@ -207,8 +211,9 @@ func (h *Hammer) newWorkers(n int) ([]worker, error) {
 		}
 
 		workers = append(workers, worker{
-			Hammer: h,
-			client: client,
+			Hammer:      h,
+			client:      client,
+			batchWriter: batchWriter,
 		})
 	}
 	return workers, nil
@@ -232,8 +237,12 @@ func (w *worker) writeOp(ctx context.Context, req *reqArgs) error {
 		return err
 	}
 
+	principal := w.batchWriter
+	if len(req.UserIDs) == 1 {
+		principal = req.UserIDs[0]
+	}
 	cctx, cancel = context.WithTimeout(ctx, w.timeout)
-	err = w.client.BatchQueueUserUpdate(cctx, mutations, w.signers)
+	_, err = w.client.BatchQueueUserUpdate(cctx, mutations, w.signers, w.callOptions(principal)...)
 	cancel()
 	if err != nil {
 		return err
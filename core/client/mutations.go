@@ -19,6 +19,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/golang/glog"
@@ -35,13 +36,54 @@ type RevisionMutations struct {
 	Mutations []*pb.MutationProof
 }
 
-// StreamRevisions repeatedly fetches revisions and sends them to out until GetRevision
-// returns an error other than NotFound or until ctx.Done is closed.  When
-// GetRevision returns NotFound, it waits one pollPeriod before trying again.
+// StreamRevisions sends revisions to out, starting with startRevision, until the
+// server closes the stream, an error occurs, or ctx.Done is closed.
+// StreamRevisions uses GetRevisionStream and falls back to polling GetRevision
+// if the server does not implement it.
 func (c *Client) StreamRevisions(ctx context.Context, directoryID string, startRevision int64, out chan<- *pb.Revision) error {
 	defer close(out)
+	next, err := c.streamRevisions(ctx, directoryID, startRevision, out)
+	if status.Code(err) != codes.Unimplemented {
+		return err
+	}
+	glog.Infof("GetRevisionStream(%v) is unimplemented, polling GetRevision instead", directoryID)
+	return c.pollRevisions(ctx, directoryID, startRevision, next, out)
+}
+
+// streamRevisions forwards revisions from GetRevisionStream to out.
+// streamRevisions returns the next revision it expected to receive.
+func (c *Client) streamRevisions(ctx context.Context, directoryID string, startRevision int64, out chan<- *pb.Revision) (int64, error) {
+	stream, err := c.cli.GetRevisionStream(ctx, &pb.GetRevisionRequest{
+		DirectoryId:          directoryID,
+		Revision:             startRevision,
+		LastVerifiedTreeSize: startRevision,
+	})
+	if err != nil {
+		return startRevision, err
+	}
+	for i := startRevision; ; i++ {
+		revision, err := stream.Recv()
+		if err == io.EOF {
+			return i, nil
+		} else if err != nil {
+			glog.Warningf("GetRevisionStream(%v,%v).Recv(): %v", directoryID, i, err)
+			return i, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return i, ctx.Err()
+		case out <- revision:
+		}
+	}
+}
+
+// pollRevisions repeatedly fetches revisions and sends them to out until GetRevision
+// returns an error other than NotFound or until ctx.Done is closed.  When
+// GetRevision returns NotFound, it waits one pollPeriod before trying again.
+func (c *Client) pollRevisions(ctx context.Context, directoryID string, startRevision, next int64, out chan<- *pb.Revision) error {
 	wait := time.NewTicker(c.RetryDelay).C
-	for i := startRevision; ; {
+	for i := next; ; {
 		// time out if we exceed the poll period:
 		revision, err := c.cli.GetRevision(ctx, &pb.GetRevisionRequest{
 			DirectoryId:          directoryID,
@@ -71,22 +113,62 @@ func (c *Client) StreamRevisions(ctx context.Context, directoryID string, startR
 	}
 }
 
-// RevisionMutations fetches all the mutations in an revision
+// RevisionMutations fetches all the mutations in an revision.
+// RevisionMutations uses ListMutationsStream and falls back to paging through
+// ListMutations if the server does not implement it.
 func (c *Client) RevisionMutations(ctx context.Context, revision *pb.Revision) ([]*pb.MutationProof, error) {
 	mapRoot, err := c.VerifySignedMapRoot(revision.GetMapRoot().GetMapRoot())
 	if err != nil {
 		return nil, err
 	}
+	return c.mutationsAt(ctx, revision.GetDirectoryId(), int64(mapRoot.Revision))
+}
+
+// mutationsAt fetches all the mutations in revision of directoryID.
+func (c *Client) mutationsAt(ctx context.Context, directoryID string, revision int64) ([]*pb.MutationProof, error) {
+	mutations, err := c.streamMutations(ctx, directoryID, revision)
+	if status.Code(err) != codes.Unimplemented {
+		return mutations, err
+	}
+	glog.Infof("ListMutationsStream(%v) is unimplemented, paging through ListMutations instead", directoryID)
+	return c.pageMutations(ctx, directoryID, revision)
+}
+
+// streamMutations reads all the mutations in revision from ListMutationsStream.
+func (c *Client) streamMutations(ctx context.Context, directoryID string, revision int64) ([]*pb.MutationProof, error) {
+	stream, err := c.cli.ListMutationsStream(ctx, &pb.ListMutationsRequest{
+		DirectoryId: directoryID,
+		Revision:    revision,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mutations := []*pb.MutationProof{}
+	for {
+		m, err := stream.Recv()
+		if err == io.EOF {
+			return mutations, nil
+		} else if status.Code(err) == codes.Unimplemented {
+			return nil, err
+		} else if err != nil {
+			return nil, fmt.Errorf("list mutations stream on %v: %v", directoryID, err)
+		}
+		mutations = append(mutations, m)
+	}
+}
+
+// pageMutations reads all the mutations in revision, one ListMutations page at a time.
+func (c *Client) pageMutations(ctx context.Context, directoryID string, revision int64) ([]*pb.MutationProof, error) {
 	mutations := []*pb.MutationProof{}
 	token := ""
 	for {
 		resp, err := c.cli.ListMutations(ctx, &pb.ListMutationsRequest{
-			DirectoryId: revision.GetDirectoryId(),
-			Revision:    int64(mapRoot.Revision),
+			DirectoryId: directoryID,
+			Revision:    revision,
 			PageToken:   token,
 		})
 		if err != nil {
-			return nil, fmt.Errorf("list mutations on %v: %v", revision.GetDirectoryId(), err)
+			return nil, fmt.Errorf("list mutations on %v: %v", directoryID, err)
 		}
 		mutations = append(mutations, resp.GetMutations()...)
 		token = resp.GetNextPageToken()
@@ -0,0 +1,183 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/keytransparency/core/testutil"
+	"github.com/google/trillian"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+)
+
+// revisionServer serves numRevisions revisions, either with GetRevisionStream
+// or, if noStream is set, only with GetRevision.
+type revisionServer struct {
+	fakeKeyServer
+	numRevisions int64
+	noStream     bool
+}
+
+func (*revisionServer) revision(rev int64) *pb.Revision {
+	return &pb.Revision{MapRoot: &pb.MapRoot{MapRoot: &trillian.SignedMapRoot{MapRoot: []byte{byte(rev)}}}}
+}
+
+func (f *revisionServer) GetRevision(ctx context.Context, in *pb.GetRevisionRequest) (*pb.Revision, error) {
+	if in.Revision >= f.numRevisions {
+		return nil, status.Errorf(codes.NotFound, "revision %v not found", in.Revision)
+	}
+	return f.revision(in.Revision), nil
+}
+
+func (f *revisionServer) GetRevisionStream(in *pb.GetRevisionRequest, stream pb.KeyTransparency_GetRevisionStreamServer) error {
+	if f.noStream {
+		return status.Error(codes.Unimplemented, "not implemented")
+	}
+	for rev := in.Revision; rev < f.numRevisions; rev++ {
+		if err := stream.Send(f.revision(rev)); err != nil {
+			return err
+		}
+	}
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func TestStreamRevisions(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		noStream bool
+	}{
+		{desc: "stream"},
+		{desc: "poll", noStream: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			start, end := int64(2), int64(10)
+
+			s, stop, err := testutil.NewFakeKT(&revisionServer{numRevisions: end, noStream: tc.noStream})
+			if err != nil {
+				t.Fatalf("NewFakeKT(): %v", err)
+			}
+			defer stop()
+			c := Client{cli: s.Client, RetryDelay: 10 * time.Millisecond}
+
+			revisions := make(chan *pb.Revision)
+			errc := make(chan error)
+			go func() { errc <- c.StreamRevisions(ctx, "directory", start, revisions) }()
+
+			for want := start; want < end; want++ {
+				r, ok := <-revisions
+				if !ok {
+					t.Fatalf("StreamRevisions(): closed before revision %v: %v", want, <-errc)
+				}
+				if got := int64(r.GetMapRoot().GetMapRoot().GetMapRoot()[0]); got != want {
+					t.Errorf("StreamRevisions(): revision %v, want %v", got, want)
+				}
+			}
+			cancel()
+			for range revisions {
+			}
+			if err := <-errc; status.Code(err) != codes.Canceled && err != context.Canceled {
+				t.Errorf("StreamRevisions(): %v, want %v", err, codes.Canceled)
+			}
+		})
+	}
+}
+
+// mutationServer serves numMutations mutations in every revision, either with
+// ListMutationsStream or, if noStream is set, only with ListMutations.
+type mutationServer struct {
+	fakeKeyServer
+	numMutations int64
+	noStream     bool
+}
+
+func (f *mutationServer) ListMutations(ctx context.Context, in *pb.ListMutationsRequest) (*pb.ListMutationsResponse, error) {
+	start := int64(0)
+	if in.PageToken != "" {
+		var err error
+		if start, err = strconv.ParseInt(in.PageToken, 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token: %v", err)
+		}
+	}
+	end, next := start+3, ""
+	if end < f.numMutations {
+		next = strconv.FormatInt(end, 10)
+	} else {
+		end = f.numMutations
+	}
+	resp := &pb.ListMutationsResponse{NextPageToken: next}
+	for i := start; i < end; i++ {
+		resp.Mutations = append(resp.Mutations, &pb.MutationProof{LocalId: i})
+	}
+	return resp, nil
+}
+
+func (f *mutationServer) ListMutationsStream(in *pb.ListMutationsRequest, stream pb.KeyTransparency_ListMutationsStreamServer) error {
+	if f.noStream {
+		return status.Error(codes.Unimplemented, "not implemented")
+	}
+	for i := int64(0); i < f.numMutations; i++ {
+		if err := stream.Send(&pb.MutationProof{LocalId: i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRevisionMutations(t *testing.T) {
+	for _, tc := range []struct {
+		desc         string
+		numMutations int64
+		noStream     bool
+	}{
+		{desc: "stream", numMutations: 10},
+		{desc: "stream empty"},
+		{desc: "page", numMutations: 10, noStream: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			s, stop, err := testutil.NewFakeKT(&mutationServer{numMutations: tc.numMutations, noStream: tc.noStream})
+			if err != nil {
+				t.Fatalf("NewFakeKT(): %v", err)
+			}
+			defer stop()
+			c := Client{Verifier: &fakeVerifier{}, cli: s.Client}
+
+			mutations, err := c.RevisionMutations(ctx, &pb.Revision{
+				MapRoot: &pb.MapRoot{MapRoot: &trillian.SignedMapRoot{MapRoot: []byte{1}}},
+			})
+			if err != nil {
+				t.Fatalf("RevisionMutations(): %v", err)
+			}
+			if got, want := int64(len(mutations)), tc.numMutations; got != want {
+				t.Fatalf("RevisionMutations(): %v mutations, want %v", got, want)
+			}
+			for i, m := range mutations {
+				if got, want := m.LocalId, int64(i); got != want {
+					t.Errorf("RevisionMutations()[%v]: %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
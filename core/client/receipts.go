@@ -0,0 +1,174 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// This file contains functions that check receipts for queued mutations.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/types"
+
+	"github.com/google/keytransparency/core/mutator/entry"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+)
+
+var (
+	// ErrReceiptPending occurs when the mutations of a receipt have not all
+	// been applied yet, and its deadline has not passed.
+	ErrReceiptPending = errors.New("receipt: mutations not applied yet")
+	// ErrReceiptMissed occurs when a revision published after the deadline of a
+	// receipt does not complete the application of its mutations.
+	ErrReceiptMissed = errors.New("receipt: deadline missed")
+	// ErrReceiptMissing occurs when a directory with a receipt key returns an
+	// empty receipt.
+	ErrReceiptMissing = errors.New("receipt: missing")
+)
+
+// PinReceiptKey sets the receipt key of config to key, so that receipts are
+// required even if the server stops advertising a key. It returns an error if
+// config already has a different receipt key.
+func PinReceiptKey(config *pb.Directory, key *keyspb.PublicKey) error {
+	if got := config.GetReceiptKey(); got != nil && !bytes.Equal(got.GetDer(), key.GetDer()) {
+		return errors.New("receipt: directory receipt key does not match the pinned key")
+	}
+	config.ReceiptKey = key
+	return nil
+}
+
+// verifyReceipt verifies that signed is a receipt for updates, which were sent
+// to the server in a single request. verifyReceipt returns nil if the directory
+// does not issue receipts.
+func (c *Client) verifyReceipt(signed *pb.SignedReceipt, updates []*pb.EntryUpdate) (*pb.SignedReceipt, error) {
+	r, err := c.VerifyReceipt(signed)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	if r.DirectoryId != c.DirectoryID {
+		return nil, fmt.Errorf("receipt: directory %v, want %v", r.DirectoryId, c.DirectoryID)
+	}
+	if got, want := len(r.MutationHashes), len(updates); got != want {
+		return nil, fmt.Errorf("receipt: %v mutations, want %v", got, want)
+	}
+	for i, u := range updates {
+		h, err := entry.MutationHash(u.GetMutation())
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(r.MutationHashes[i], h) {
+			return nil, fmt.Errorf("receipt: mutation %v has hash %x, want %x", i, r.MutationHashes[i], h)
+		}
+	}
+	return signed, nil
+}
+
+// ReceiptChecker follows the mutations of a receipt through the revisions of
+// a directory. Monitors, which already read the mutations of every revision,
+// can use it directly.
+type ReceiptChecker struct {
+	receipt  *pb.Receipt
+	deadline time.Time
+	// pending contains the hashes of mutations that have not been applied.
+	pending map[string]bool
+}
+
+// NewReceiptChecker returns a ReceiptChecker for a verified receipt.
+func NewReceiptChecker(r *pb.Receipt) (*ReceiptChecker, error) {
+	deadline, err := ptypes.Timestamp(r.GetDeadline())
+	if err != nil {
+		return nil, fmt.Errorf("receipt: invalid deadline: %v", err)
+	}
+	pending := make(map[string]bool)
+	for _, h := range r.GetMutationHashes() {
+		pending[string(h)] = true
+	}
+	return &ReceiptChecker{receipt: r, deadline: deadline, pending: pending}, nil
+}
+
+// Receipt returns the receipt being checked.
+func (rc *ReceiptChecker) Receipt() *pb.Receipt { return rc.receipt }
+
+// Process records which of the receipt's mutations were applied in the
+// revision with mapRoot. Process returns nil once all of them have been applied
+// by the deadline, ErrReceiptMissed if mapRoot was published after the deadline
+// and they had not all been applied before it, and ErrReceiptPending otherwise.
+func (rc *ReceiptChecker) Process(mapRoot *types.MapRootV1, mutations []*pb.MutationProof) error {
+	if len(rc.pending) == 0 {
+		return nil
+	}
+	for _, m := range mutations {
+		if m.GetLogId() != rc.receipt.GetLogId() || m.GetQueueTimestamp() != rc.receipt.GetWatermark() {
+			continue
+		}
+		h, err := entry.MutationHash(m.GetMutation())
+		if err != nil {
+			return err
+		}
+		delete(rc.pending, string(h))
+	}
+	switch {
+	case time.Unix(0, int64(mapRoot.TimestampNanos)).After(rc.deadline):
+		return ErrReceiptMissed
+	case len(rc.pending) == 0:
+		return nil
+	default:
+		return ErrReceiptPending
+	}
+}
+
+// CheckReceipt reads the revisions of the directory from startRevision up to
+// the latest one, and returns the revision that completed the application of
+// the mutations in signed. CheckReceipt returns ErrReceiptPending if they have
+// not all been applied yet, and ErrReceiptMissed, along with the first revision
+// published after the deadline, if they were not all applied by the receipt's
+// deadline.
+func (c *Client) CheckReceipt(ctx context.Context, signed *pb.SignedReceipt, startRevision int64) (int64, error) {
+	r, err := c.VerifyReceipt(signed)
+	if err != nil {
+		return 0, err
+	}
+	if r == nil {
+		return 0, errors.New("receipt: directory does not issue receipts")
+	}
+	rc, err := NewReceiptChecker(r)
+	if err != nil {
+		return 0, err
+	}
+	_, latest, err := c.VerifiedGetLatestRevision(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for rev := startRevision; rev <= int64(latest.Revision); rev++ {
+		_, mapRoot, err := c.VerifiedGetRevision(ctx, rev)
+		if err != nil {
+			return 0, err
+		}
+		mutations, err := c.mutationsAt(ctx, r.GetDirectoryId(), rev)
+		if err != nil {
+			return 0, err
+		}
+		if err := rc.Process(mapRoot, mutations); err != ErrReceiptPending {
+			return rev, err
+		}
+	}
+	return 0, ErrReceiptPending
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/types"
+
+	"github.com/google/keytransparency/core/mutator/entry"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+func mutationHash(t *testing.T, m *pb.SignedEntry) []byte {
+	t.Helper()
+	h, err := entry.MutationHash(m)
+	if err != nil {
+		t.Fatalf("MutationHash(): %v", err)
+	}
+	return h
+}
+
+func TestVerifyReceipt(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	updates := []*pb.EntryUpdate{
+		{Mutation: &pb.SignedEntry{Entry: []byte("a")}},
+		{Mutation: &pb.SignedEntry{Entry: []byte("b")}},
+	}
+	hashes := [][]byte{mutationHash(t, updates[0].Mutation), mutationHash(t, updates[1].Mutation)}
+	sign := func(key *ecdsa.PrivateKey, r *pb.Receipt) *pb.SignedReceipt {
+		b, err := proto.Marshal(r)
+		if err != nil {
+			t.Fatalf("proto.Marshal(): %v", err)
+		}
+		sig, err := tcrypto.NewSHA256Signer(key).Sign(b)
+		if err != nil {
+			t.Fatalf("Sign(): %v", err)
+		}
+		return &pb.SignedReceipt{Receipt: b, Signature: sig}
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		noKey   bool
+		signed  *pb.SignedReceipt
+		want    bool
+		wantErr bool
+	}{
+		{desc: "valid", signed: sign(key, &pb.Receipt{DirectoryId: "dir", MutationHashes: hashes}), want: true},
+		{desc: "no receipt key", noKey: true, signed: &pb.SignedReceipt{}},
+		{desc: "missing receipt", signed: &pb.SignedReceipt{}, wantErr: true},
+		{desc: "missing signature", signed: &pb.SignedReceipt{Receipt: []byte("receipt")}, wantErr: true},
+		{desc: "wrong key", signed: sign(otherKey, &pb.Receipt{DirectoryId: "dir", MutationHashes: hashes}), wantErr: true},
+		{desc: "wrong directory", signed: sign(key, &pb.Receipt{DirectoryId: "other", MutationHashes: hashes}), wantErr: true},
+		{desc: "missing mutation", signed: sign(key, &pb.Receipt{DirectoryId: "dir", MutationHashes: hashes[:1]}), wantErr: true},
+		{desc: "reordered mutations", signed: sign(key, &pb.Receipt{DirectoryId: "dir",
+			MutationHashes: [][]byte{hashes[1], hashes[0]}}), wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			v := &RealVerifier{receiptKey: key.Public()}
+			if tc.noKey {
+				v.receiptKey = nil
+			}
+			c := &Client{Verifier: v, DirectoryID: "dir"}
+			got, err := c.verifyReceipt(tc.signed, updates)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("verifyReceipt(): %v, wantErr %v", err, tc.wantErr)
+			}
+			if (got != nil) != tc.want {
+				t.Errorf("verifyReceipt(): %v, want receipt: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReceiptCheckerProcess(t *testing.T) {
+	deadline := time.Unix(1000, 0)
+	deadlinePB, err := ptypes.TimestampProto(deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &pb.MutationProof{Mutation: &pb.SignedEntry{Entry: []byte("a")}, LogId: 1, QueueTimestamp: 5}
+	b := &pb.MutationProof{Mutation: &pb.SignedEntry{Entry: []byte("b")}, LogId: 1, QueueTimestamp: 5}
+	// otherLog has the same mutation as a, queued in another log.
+	otherLog := &pb.MutationProof{Mutation: a.Mutation, LogId: 2, QueueTimestamp: 5}
+	receipt := &pb.Receipt{
+		MutationHashes: [][]byte{mutationHash(t, a.Mutation), mutationHash(t, b.Mutation)},
+		LogId:          1,
+		Watermark:      5,
+		Deadline:       deadlinePB,
+	}
+	before := &types.MapRootV1{TimestampNanos: uint64(deadline.Add(-time.Second).UnixNano())}
+	after := &types.MapRootV1{TimestampNanos: uint64(deadline.Add(time.Second).UnixNano())}
+
+	type revision struct {
+		mapRoot   *types.MapRootV1
+		mutations []*pb.MutationProof
+		want      error
+	}
+	for _, tc := range []struct {
+		desc      string
+		revisions []revision
+	}{
+		{desc: "one revision", revisions: []revision{
+			{mapRoot: before, mutations: []*pb.MutationProof{a, b}, want: nil},
+		}},
+		{desc: "two revisions", revisions: []revision{
+			{mapRoot: before, want: ErrReceiptPending},
+			{mapRoot: before, mutations: []*pb.MutationProof{a}, want: ErrReceiptPending},
+			{mapRoot: before, mutations: []*pb.MutationProof{b}, want: nil},
+			{mapRoot: after, want: nil},
+		}},
+		{desc: "other log", revisions: []revision{
+			{mapRoot: before, mutations: []*pb.MutationProof{otherLog, b}, want: ErrReceiptPending},
+		}},
+		{desc: "late", revisions: []revision{
+			{mapRoot: before, mutations: []*pb.MutationProof{a}, want: ErrReceiptPending},
+			{mapRoot: after, mutations: []*pb.MutationProof{b}, want: ErrReceiptMissed},
+		}},
+		{desc: "missed", revisions: []revision{
+			{mapRoot: after, want: ErrReceiptMissed},
+		}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			rc, err := NewReceiptChecker(receipt)
+			if err != nil {
+				t.Fatalf("NewReceiptChecker(): %v", err)
+			}
+			for i, r := range tc.revisions {
+				if got := rc.Process(r.mapRoot, r.mutations); got != r.want {
+					t.Errorf("Process(revision %v): %v, want %v", i, got, r.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPinReceiptKey(t *testing.T) {
+	pinned := &keyspb.PublicKey{Der: []byte("pinned")}
+	for _, tc := range []struct {
+		desc    string
+		key     *keyspb.PublicKey
+		wantErr bool
+	}{
+		{desc: "not advertised"},
+		{desc: "same key", key: &keyspb.PublicKey{Der: []byte("pinned")}},
+		{desc: "other key", key: &keyspb.PublicKey{Der: []byte("other")}, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			config := &pb.Directory{ReceiptKey: tc.key}
+			err := PinReceiptKey(config, pinned)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("PinReceiptKey(): %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && !proto.Equal(config.ReceiptKey, pinned) {
+				t.Errorf("ReceiptKey: %v, want %v", config.ReceiptKey, pinned)
+			}
+		})
+	}
+}
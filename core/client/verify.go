@@ -16,11 +16,13 @@ package client
 
 import (
 	"bytes"
+	"crypto"
 	"errors"
 	"fmt"
 
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian/crypto/keys/der"
 	"github.com/google/trillian/types"
 	"github.com/kr/pretty"
 
@@ -31,6 +33,7 @@ import (
 
 	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
 	tclient "github.com/google/trillian/client"
+	tcrypto "github.com/google/trillian/crypto"
 	_ "github.com/google/trillian/merkle/coniks"  // Register hasher
 	_ "github.com/google/trillian/merkle/rfc6962" // Register hasher
 )
@@ -44,6 +47,8 @@ var (
 // Implements Verifier.
 type RealVerifier struct {
 	vrf vrf.PublicKey
+	// receiptKey verifies receipts. It is nil if the server does not issue receipts.
+	receiptKey crypto.PublicKey
 	*tclient.MapVerifier
 	*tclient.LogVerifier
 }
@@ -77,7 +82,14 @@ func NewVerifierFromDirectory(config *pb.Directory) (*RealVerifier, error) {
 		return nil, fmt.Errorf("error parsing vrf public key: %v", err)
 	}
 
-	return NewVerifier(vrfPubKey, mapVerifier, logVerifier), nil
+	v := NewVerifier(vrfPubKey, mapVerifier, logVerifier)
+	if config.GetReceiptKey() != nil {
+		v.receiptKey, err = der.FromPublicProto(config.GetReceiptKey())
+		if err != nil {
+			return nil, fmt.Errorf("error parsing receipt public key: %v", err)
+		}
+	}
+	return v, nil
 }
 
 // Index computes the index from a VRF proof.
@@ -177,3 +189,26 @@ func (v *RealVerifier) VerifyRevision(in *pb.Revision, trusted types.LogRootV1)
 	Vlog.Printf("✓ Log inclusion proof verified.")
 	return logRoot, mapRoot, nil
 }
+
+// VerifyReceipt verifies the signature on a receipt and returns its contents.
+// VerifyReceipt returns nil if the directory has no receipt key, and an error
+// for an empty receipt if it has one.
+func (v *RealVerifier) VerifyReceipt(in *pb.SignedReceipt) (*pb.Receipt, error) {
+	if v.receiptKey == nil {
+		return nil, nil
+	}
+	if len(in.GetReceipt()) == 0 {
+		Vlog.Printf("✗ Receipt missing.")
+		return nil, ErrReceiptMissing
+	}
+	if err := tcrypto.Verify(v.receiptKey, crypto.SHA256, in.GetReceipt(), in.GetSignature()); err != nil {
+		Vlog.Printf("✗ Receipt signature verification failed.")
+		return nil, fmt.Errorf("receipt signature: %v", err)
+	}
+	var r pb.Receipt
+	if err := proto.Unmarshal(in.GetReceipt(), &r); err != nil {
+		return nil, err
+	}
+	Vlog.Printf("✓ Receipt signature verified.")
+	return &r, nil
+}
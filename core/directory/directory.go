@@ -33,9 +33,20 @@ type Directory struct {
 
 	VRFPriv                  proto.Message
 	MinInterval, MaxInterval time.Duration
-	// TODO(gbelvin): specify mutation function
+	// Mutator is the name of the registered mutator.Mutator that applies
+	// mutations to this directory. The empty string selects the default.
+	Mutator string
+	// Paused directories are not sequenced. Mutations are still queued.
+	Paused           bool
+	PausedReason     string
 	Deleted          bool
 	DeletedTimestamp time.Time
+	// Private directories only serve users and mutations to authorized
+	// readers.
+	Private bool
+	// ReceiptKey verifies receipts for mutations queued to this directory.
+	// It is nil if the directory does not issue receipts.
+	ReceiptKey *keyspb.PublicKey
 }
 
 // Storage is an interface for storing multi-tenant configuration information.
@@ -48,6 +59,8 @@ type Storage interface {
 	Read(ctx context.Context, directoryID string, showDeleted bool) (*Directory, error)
 	// Soft-delete or undelete the directory
 	SetDelete(ctx context.Context, directoryID string, isDeleted bool) error
+	// SetPaused pauses or resumes sequencing of the directory.
+	SetPaused(ctx context.Context, directoryID string, isPaused bool, reason string) error
 	// HardDelete the directory.
 	Delete(ctx context.Context, directoryID string) error
 }
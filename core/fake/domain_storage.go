@@ -68,6 +68,19 @@ func (a *DirectoryStorage) SetDelete(ctx context.Context, id string, isDeleted b
 	return nil
 }
 
+// SetPaused pauses or resumes sequencing of a directory.
+func (a *DirectoryStorage) SetPaused(ctx context.Context, id string, isPaused bool, reason string) error {
+	d, ok := a.directories[id]
+	if !ok {
+		return status.Errorf(codes.NotFound, "Directory %v not found", id)
+	}
+	if !isPaused {
+		reason = ""
+	}
+	d.Paused, d.PausedReason = isPaused, reason
+	return nil
+}
+
 // Delete permanently deletes a directory.
 func (a *DirectoryStorage) Delete(ctx context.Context, id string) error {
 	_, ok := a.directories[id]
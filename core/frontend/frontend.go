@@ -59,7 +59,7 @@ func (f *Frontend) QueueKeyUpdate(ctx context.Context, in *pb.QueueKeyUpdateRequ
 	if err != nil {
 		return nil, err
 	}
-	if err := f.Client.QueueMutation(ctx, m, f.Signers); err != nil {
+	if _, err := f.Client.QueueMutation(ctx, m, f.Signers); err != nil {
 		return nil, err
 	}
 	return &empty.Empty{}, nil
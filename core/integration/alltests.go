@@ -33,11 +33,16 @@ type Env struct {
 	Client    *client.Client
 	Cli       pb.KeyTransparencyClient
 	Sequencer spb.KeyTransparencySequencerClient
+	Admin     pb.KeyTransparencyAdminClient
 	Directory *pb.Directory
 	Timeout   time.Duration
 	CallOpts  CallOptions
 }
 
+// DirectoryAdmin is a principal that may update any user in Env.Directory.
+// Environments must authorize it to write to Env.Directory.
+const DirectoryAdmin = "directory-admin@example.com"
+
 // CallOptions returns grpc.CallOptions for the requested user.
 type CallOptions func(userID string) []grpc.CallOption
 
@@ -57,7 +62,10 @@ var AllTests = []NamedTestFn{
 	{Name: "TestListHistory", Fn: TestListHistory},
 	{Name: "TestBatchUpdate", Fn: TestBatchUpdate},
 	{Name: "TestBatchCreate", Fn: TestBatchCreate},
+	{Name: "TestReceipts", Fn: TestReceipts},
 	// Monitor Tests
 	{Name: "TestMonitor", Fn: TestMonitor},
 	{Name: "TestBatchListUserRevisions", Fn: TestBatchListUserRevisions},
+	// Sequencer Tests
+	{Name: "TestRebuildMap", Fn: TestRebuildMap},
 }
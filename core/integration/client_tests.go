@@ -113,7 +113,7 @@ func TestBatchCreate(ctx context.Context, env *Env, t *testing.T) {
 
 			cctx, cancel := context.WithTimeout(ctx, env.Timeout)
 			defer cancel()
-			if err := env.Client.BatchCreateUser(cctx, users, signers1); err != nil {
+			if err := env.Client.BatchCreateUser(cctx, users, signers1, env.CallOpts(DirectoryAdmin)...); err != nil {
 				t.Fatalf("BatchCreateUser(): %v", err)
 			}
 		})
@@ -152,7 +152,7 @@ func TestBatchUpdate(ctx context.Context, env *Env, t *testing.T) {
 			if err != nil {
 				t.Fatalf("BatchCreateMutation(): %v", err)
 			}
-			if err := env.Client.BatchQueueUserUpdate(cctx, mutations, signers1); err != nil {
+			if _, err := env.Client.BatchQueueUserUpdate(cctx, mutations, signers1, env.CallOpts(DirectoryAdmin)...); err != nil {
 				t.Fatalf("BatchQueueUserUpdate(): %v", err)
 			}
 		})
@@ -268,7 +268,7 @@ func TestEmptyGetAndUpdate(ctx context.Context, env *Env, t *testing.T) {
 				if err != nil {
 					t.Fatalf("CreateMutation(%v): %v", tc.userID, err)
 				}
-				if err := env.Client.QueueMutation(cctx, m, tc.signers, tc.opts...); err != nil {
+				if _, err := env.Client.QueueMutation(cctx, m, tc.signers, tc.opts...); err != nil {
 					t.Fatalf("QueueMutation(%v): %v", tc.userID, err)
 				}
 
@@ -362,7 +362,7 @@ func (env *Env) setupHistory(ctx context.Context, directory *pb.Directory, userI
 			if err != nil {
 				return fmt.Errorf("client.CreateMutation(%v): %v", userID, err)
 			}
-			if err := env.Client.QueueMutation(ctx, m, signers, opts...); err != nil {
+			if _, err := env.Client.QueueMutation(ctx, m, signers, opts...); err != nil {
 				return fmt.Errorf("sequencer.QueueMutation(): %v", err)
 			}
 			if _, err := env.Sequencer.RunBatch(ctx, &spb.RunBatchRequest{
@@ -466,7 +466,7 @@ func (env *Env) setupHistoryMultipleUsers(ctx context.Context, directory *pb.Dir
 		if err != nil {
 			return fmt.Errorf("client.CreateMutation(%v): %v", userIDs[i], err)
 		}
-		if err := env.Client.QueueMutation(ctx, m, signers, env.CallOpts(userIDs[i])...); err != nil {
+		if _, err := env.Client.QueueMutation(ctx, m, signers, env.CallOpts(userIDs[i])...); err != nil {
 			return fmt.Errorf("sequencer.QueueMutation(): %v", err)
 		}
 		if _, err := env.Sequencer.RunBatch(ctx, &spb.RunBatchRequest{
@@ -102,7 +102,7 @@ func TestMonitor(ctx context.Context, env *Env, t *testing.T) {
 			if err != nil {
 				t.Fatalf("CreateMutation(%v): %v", u.UserId, err)
 			}
-			if err := env.Client.QueueMutation(ctx, m, e.signers,
+			if _, err := env.Client.QueueMutation(ctx, m, e.signers,
 				env.CallOpts(u.UserId)...); err != nil {
 				t.Errorf("QueueMutation(): %v", err)
 			}
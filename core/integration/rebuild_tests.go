@@ -0,0 +1,126 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/keytransparency/core/testutil"
+
+	tpb "github.com/google/keytransparency/core/api/type/type_go_proto"
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
+)
+
+// TestRebuildMap verifies that replaying the revisions of a directory into a
+// new map reproduces the map roots that were published.
+func TestRebuildMap(ctx context.Context, env *Env, t *testing.T) {
+	signers := testutil.SignKeysetsFromPEMs(testPrivKey1)
+	authorizedKeys := testutil.VerifyKeysetFromPEMs(testPubKey1).Keyset()
+
+	// Publish revisions that create users, update one of them, and are empty.
+	var revisions int64
+	for _, userIDs := range [][]string{
+		{"alice"},
+		genUserIDs(10),
+		{"alice"},
+		nil,
+	} {
+		cctx, cancel := context.WithTimeout(ctx, env.Timeout)
+		defer cancel()
+		users := make([]*tpb.User, 0, len(userIDs))
+		for _, userID := range userIDs {
+			users = append(users, &tpb.User{
+				UserId:         userID,
+				PublicKeyData:  []byte(userID),
+				AuthorizedKeys: authorizedKeys,
+			})
+		}
+		mutations, err := env.Client.BatchCreateMutation(cctx, users)
+		if err != nil {
+			t.Fatalf("BatchCreateMutation(): %v", err)
+		}
+		if _, err := env.Client.BatchQueueUserUpdate(cctx, mutations, signers, env.CallOpts(DirectoryAdmin)...); err != nil {
+			t.Fatalf("BatchQueueUserUpdate(): %v", err)
+		}
+		if _, err := env.Sequencer.RunBatch(cctx, &spb.RunBatchRequest{
+			DirectoryId: env.Directory.DirectoryId,
+			MinBatch:    0,
+			MaxBatch:    100,
+			Block:       true,
+		}); err != nil {
+			t.Fatalf("RunBatch(): %v", err)
+		}
+		revisions++
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, env.Timeout)
+	defer cancel()
+	started, err := env.Admin.RebuildMap(cctx, &pb.RebuildMapRequest{DirectoryId: env.Directory.DirectoryId})
+	if err != nil {
+		t.Fatalf("RebuildMap(): %v", err)
+	}
+	if started.MapId == 0 || started.MapId == env.Directory.Map.TreeId {
+		t.Errorf("RebuildMap(): map %v, want a new map", started.MapId)
+	}
+	if got, want := started.Target, revisions; got != want {
+		t.Errorf("RebuildMap(): target %v, want %v", got, want)
+	}
+	waitForRebuild(cctx, env, t, started.MapId, revisions)
+
+	// Resuming a finished rebuild checks its latest revision again.
+	if _, err := env.Admin.RebuildMap(cctx, &pb.RebuildMapRequest{
+		DirectoryId: env.Directory.DirectoryId,
+		MapId:       started.MapId,
+	}); err != nil {
+		t.Fatalf("RebuildMap(map_id: %v): %v", started.MapId, err)
+	}
+	waitForRebuild(cctx, env, t, started.MapId, revisions)
+}
+
+// waitForRebuild polls the rebuild of mapID until it stops, and verifies that
+// it replayed every revision up to target.
+func waitForRebuild(ctx context.Context, env *Env, t *testing.T, mapID, target int64) {
+	t.Helper()
+	for {
+		r, err := env.Admin.GetMapRebuild(ctx, &pb.GetMapRebuildRequest{
+			DirectoryId: env.Directory.DirectoryId,
+			MapId:       mapID,
+		})
+		if err != nil {
+			t.Fatalf("GetMapRebuild(): %v", err)
+		}
+		if !r.Done {
+			select {
+			case <-ctx.Done():
+				t.Fatalf("GetMapRebuild(): %v after revision %v", ctx.Err(), r.Revision)
+			case <-time.After(10 * time.Millisecond):
+			}
+			continue
+		}
+		if r.Error != "" {
+			t.Errorf("GetMapRebuild(): failed: %v", r.Error)
+		}
+		if r.Divergence != nil {
+			t.Errorf("GetMapRebuild(): diverged at %v", r.Divergence)
+		}
+		if got, want := r.Revision, target; got != want {
+			t.Errorf("GetMapRebuild(): rebuilt revision %v, want %v", got, want)
+		}
+		return
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/keytransparency/core/client"
+	"github.com/google/keytransparency/core/testutil"
+
+	tpb "github.com/google/keytransparency/core/api/type/type_go_proto"
+	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
+)
+
+// TestReceipts verifies that the receipt for a queued mutation is pending
+// until the mutation is applied, and then names the revision that applied it.
+func TestReceipts(ctx context.Context, env *Env, t *testing.T) {
+	userID := "receipt-user"
+	signers := testutil.SignKeysetsFromPEMs(testPrivKey1)
+	authorizedKeys := testutil.VerifyKeysetFromPEMs(testPubKey1).Keyset()
+	cctx, cancel := context.WithTimeout(ctx, env.Timeout)
+	defer cancel()
+
+	_, latest, err := env.Client.VerifiedGetLatestRevision(cctx)
+	if err != nil {
+		t.Fatalf("VerifiedGetLatestRevision(): %v", err)
+	}
+	m, err := env.Client.CreateMutation(cctx, &tpb.User{
+		UserId:         userID,
+		PublicKeyData:  []byte("receipt-key"),
+		AuthorizedKeys: authorizedKeys,
+	})
+	if err != nil {
+		t.Fatalf("CreateMutation(): %v", err)
+	}
+	receipt, err := env.Client.QueueMutation(cctx, m, signers, env.CallOpts(userID)...)
+	if err != nil {
+		t.Fatalf("QueueMutation(): %v", err)
+	}
+	if receipt == nil {
+		t.Skip("The key server does not issue receipts")
+	}
+
+	start := int64(latest.Revision) + 1
+	if _, err := env.Client.CheckReceipt(cctx, receipt, start); err != client.ErrReceiptPending {
+		t.Errorf("CheckReceipt() before sequencing: %v, want %v", err, client.ErrReceiptPending)
+	}
+	if _, err := env.Sequencer.RunBatch(cctx, &spb.RunBatchRequest{
+		DirectoryId: env.Directory.DirectoryId,
+		MinBatch:    1,
+		MaxBatch:    100,
+		Block:       true,
+	}); err != nil {
+		t.Fatalf("RunBatch(): %v", err)
+	}
+	rev, err := env.Client.CheckReceipt(cctx, receipt, start)
+	if err != nil {
+		t.Fatalf("CheckReceipt(): %v", err)
+	}
+	if rev != start {
+		t.Errorf("CheckReceipt(): revision %v, want %v", rev, start)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyserver
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/keytransparency/core/directory"
+)
+
+var (
+	// How long the queue lag of a directory is reused before it is measured again.
+	queueLagCacheTTL = 5 * time.Second
+	// Bounds on the retry delay suggested to rejected clients.
+	minRetryDelay = 1 * time.Second
+	maxRetryDelay = 1 * time.Minute
+)
+
+// lagEntry is a cached queue lag measurement.
+type lagEntry struct {
+	lag      time.Duration
+	measured time.Time
+}
+
+// lagCache holds the most recent queue lag of each directory.
+type lagCache struct {
+	mu      sync.Mutex
+	entries map[string]lagEntry
+}
+
+func (c *lagCache) get(directoryID string, now time.Time) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[directoryID]
+	if !ok || now.Sub(e.measured) > queueLagCacheTTL {
+		return 0, false
+	}
+	return e.lag, true
+}
+
+func (c *lagCache) set(directoryID string, lag time.Duration, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]lagEntry)
+	}
+	c.entries[directoryID] = lagEntry{lag: lag, measured: now}
+}
+
+// checkQueueLag returns ResourceExhausted if the sequencer has fallen more
+// than s.MaxQueueLag behind the writes to d. The error carries a RetryInfo
+// detail with the suggested delay before retrying.
+// Paused directories are not checked: their queue grows by design until
+// sequencing resumes, and rejecting writes would not help it catch up.
+func (s *Server) checkQueueLag(ctx context.Context, d *directory.Directory) error {
+	if s.MaxQueueLag <= 0 || d.Paused {
+		return nil
+	}
+	now := time.Now()
+	lag, ok := s.lags.get(d.DirectoryID, now)
+	if !ok {
+		var err error
+		lag, err = s.queueLag(ctx, d, now)
+		if err != nil {
+			// Don't reject writes because the lag cannot be measured.
+			glog.Warningf("queueLag(%v): %v", d.DirectoryID, err)
+			return nil
+		}
+		s.lags.set(d.DirectoryID, lag, now)
+		queueLag.Set(lag.Seconds(), d.DirectoryID)
+	}
+	if lag <= s.MaxQueueLag {
+		return nil
+	}
+
+	writesRejected.Inc(d.DirectoryID)
+	st := status.Newf(codes.ResourceExhausted,
+		"Directory %v is %v behind, please retry later", d.DirectoryID, lag.Round(time.Second))
+	stWithInfo, err := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: ptypes.DurationProto(retryDelay(lag - s.MaxQueueLag)),
+	})
+	if err != nil {
+		glog.Errorf("WithDetails(): %v", err)
+		return st.Err()
+	}
+	return stWithInfo.Err()
+}
+
+// retryDelay clamps d to [minRetryDelay, maxRetryDelay].
+func retryDelay(d time.Duration) time.Duration {
+	if d < minRetryDelay {
+		return minRetryDelay
+	}
+	if d > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return d
+}
+
+// queueLag returns the age of the oldest mutation in any input log of d that
+// has been written but not yet applied in the latest published revision.
+// Measuring the age of pending items, rather than the distance between the
+// written and applied watermarks, avoids reporting a lag after idle periods.
+func (s *Server) queueLag(ctx context.Context, d *directory.Directory, now time.Time) (time.Duration, error) {
+	// The applied watermark of each log is the end of the batch of the latest revision.
+	applied := make(map[int64]int64)
+	sth, err := s.latestLogRoot(ctx, d)
+	if err != nil {
+		return 0, err
+	}
+	revision, err := mapRevisionFor(sth)
+	if err != nil {
+		return 0, err
+	}
+	// Revision 0 is the empty map and has no batch.
+	if revision > 0 {
+		meta, err := s.batches.ReadBatch(ctx, d.DirectoryID, revision)
+		if err != nil {
+			return 0, err
+		}
+		for _, source := range meta.GetSources() {
+			if applied[source.LogId] < source.HighestExclusive {
+				applied[source.LogId] = source.HighestExclusive
+			}
+		}
+	}
+
+	logIDs, err := s.logs.ListLogs(ctx, d.DirectoryID, false /* writable */)
+	if err != nil {
+		return 0, err
+	}
+	var lag time.Duration
+	for _, logID := range logIDs {
+		msgs, err := s.logs.ReadLog(ctx, d.DirectoryID, logID, applied[logID], math.MaxInt64, 1)
+		if err != nil {
+			return 0, err
+		}
+		if len(msgs) == 0 {
+			continue
+		}
+		if age := now.Sub(time.Unix(0, msgs[0].ID)); age > lag {
+			lag = age
+		}
+	}
+	return lag, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyserver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian/monitoring"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/keytransparency/core/mutator"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	tpb "github.com/google/trillian"
+)
+
+// queuedLogs is a map of logID to the sorted timestamps of its queued items.
+type queuedLogs map[int64][]int64
+
+func (q queuedLogs) Send(ctx context.Context, dirID string, mutation ...*pb.EntryUpdate) (*WriteWatermark, error) {
+	return nil, errors.New("unimplemented")
+}
+
+func (q queuedLogs) ReadLog(ctx context.Context, dirID string,
+	logID, low, high int64, batchSize int32) ([]*mutator.LogMessage, error) {
+	var msgs []*mutator.LogMessage
+	for _, ts := range q[logID] {
+		if ts >= low && ts < high && int32(len(msgs)) < batchSize {
+			msgs = append(msgs, &mutator.LogMessage{LogID: logID, ID: ts})
+		}
+	}
+	return msgs, nil
+}
+
+func (q queuedLogs) ListLogs(ctx context.Context, dirID string, writable bool) ([]int64, error) {
+	logIDs := make([]int64, 0, len(q))
+	for logID := range q {
+		logIDs = append(logIDs, logID)
+	}
+	sort.Slice(logIDs, func(a, b int) bool { return logIDs[a] < logIDs[b] })
+	return logIDs, nil
+}
+
+func TestCheckQueueLag(t *testing.T) {
+	initMetrics.Do(func() { createMetrics(monitoring.InertMetricFactory{}) })
+	ctx := context.Background()
+	now := time.Now()
+	old := now.Add(-time.Hour).UnixNano()
+	recent := now.Add(-time.Second).UnixNano()
+
+	for _, tc := range []struct {
+		desc      string
+		maxLag    time.Duration
+		paused    bool
+		treeSize  int64
+		logs      queuedLogs
+		batches   batchStorage
+		wantCode  codes.Code
+		wantDelay time.Duration
+	}{
+		{desc: "disabled", logs: queuedLogs{1: {old}}},
+		{desc: "empty queue", maxLag: time.Minute, treeSize: 1, logs: queuedLogs{1: {}}},
+		{desc: "recent writes", maxLag: time.Minute, treeSize: 1, logs: queuedLogs{1: {recent}}},
+		{desc: "old write before first revision", maxLag: time.Minute, treeSize: 1,
+			logs: queuedLogs{1: {old, recent}}, wantCode: codes.ResourceExhausted, wantDelay: maxRetryDelay},
+		{desc: "old write applied", maxLag: time.Minute, treeSize: 2,
+			logs:    queuedLogs{1: {old, recent}},
+			batches: batchStorage{1: SourceList{{LogId: 1, HighestExclusive: old + 1}}}},
+		{desc: "old write in unapplied log", maxLag: time.Minute, treeSize: 2,
+			logs:     queuedLogs{1: {old, recent}, 2: {old}},
+			batches:  batchStorage{1: SourceList{{LogId: 1, HighestExclusive: old + 1}}},
+			wantCode: codes.ResourceExhausted, wantDelay: maxRetryDelay},
+		{desc: "slightly behind", maxLag: time.Hour, treeSize: 1,
+			logs: queuedLogs{1: {old}}, wantCode: codes.ResourceExhausted, wantDelay: minRetryDelay},
+		{desc: "paused", maxLag: time.Minute, paused: true, treeSize: 1, logs: queuedLogs{1: {old}}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			e, err := newMiniEnv(ctx, t)
+			if err != nil {
+				t.Fatalf("newMiniEnv(): %v", err)
+			}
+			defer e.Close()
+			e.srv.logs = tc.logs
+			e.srv.batches = tc.batches
+			e.srv.MaxQueueLag = tc.maxLag
+			if tc.maxLag > 0 && !tc.paused {
+				// Subsequent checks are served from the cache.
+				e.s.Log.EXPECT().GetLatestSignedLogRoot(gomock.Any(), gomock.Any()).
+					Return(&tpb.GetLatestSignedLogRootResponse{
+						SignedLogRoot: &tpb.SignedLogRoot{TreeSize: tc.treeSize},
+					}, nil).Times(1)
+			}
+			d, err := e.srv.directories.Read(ctx, directoryID, false)
+			if err != nil {
+				t.Fatalf("directories.Read(): %v", err)
+			}
+			d.Paused = tc.paused
+
+			for i := 0; i < 2; i++ {
+				err := e.srv.checkQueueLag(ctx, d)
+				if got, want := status.Code(err), tc.wantCode; got != want {
+					t.Fatalf("checkQueueLag(): %v, want %v", err, want)
+				}
+				if err == nil {
+					continue
+				}
+				var delay time.Duration
+				for _, detail := range status.Convert(err).Details() {
+					if info, ok := detail.(*errdetails.RetryInfo); ok {
+						if delay, err = ptypes.Duration(info.RetryDelay); err != nil {
+							t.Fatalf("ptypes.Duration(): %v", err)
+						}
+					}
+				}
+				if got, want := delay, tc.wantDelay; got != want {
+					t.Errorf("checkQueueLag(): retry delay %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
@@ -19,11 +19,11 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
-	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/trillian/monitoring"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -36,6 +36,7 @@ import (
 	rtpb "github.com/google/keytransparency/core/keyserver/readtoken_go_proto"
 	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
 	tpb "github.com/google/trillian"
+	tcrypto "github.com/google/trillian/crypto"
 )
 
 const (
@@ -46,6 +47,8 @@ const (
 var (
 	initMetrics      sync.Once
 	watermarkWritten monitoring.Gauge
+	queueLag         monitoring.Gauge
+	writesRejected   monitoring.Counter
 )
 
 func createMetrics(mf monitoring.MetricFactory) {
@@ -53,6 +56,14 @@ func createMetrics(mf monitoring.MetricFactory) {
 		"watermark_written",
 		"High watermark of each input log that has been written",
 		directoryIDLabel, logIDLabel)
+	queueLag = mf.NewGauge(
+		"queue_lag_seconds",
+		"Age of the oldest mutation that has not been applied to the map",
+		directoryIDLabel)
+	writesRejected = mf.NewCounter(
+		"writes_rejected",
+		"Number of write requests rejected because the queue lag is too large",
+		directoryIDLabel)
 }
 
 // WriteWatermark is the metadata that Send creates.
@@ -68,6 +79,8 @@ type MutationLogs interface {
 	// ReadLog returns the messages in the (low, high] range stored in the specified log.
 	ReadLog(ctx context.Context, directoryID string, logID, low, high int64,
 		batchSize int32) ([]*mutator.LogMessage, error)
+	// ListLogs returns the logIDs of a directory, optionally only the writable ones.
+	ListLogs(ctx context.Context, directoryID string, writable bool) ([]int64, error)
 }
 
 // BatchReader reads batch definitions.
@@ -76,6 +89,12 @@ type BatchReader interface {
 	ReadBatch(ctx context.Context, directoryID string, rev int64) (*spb.MapMetadata, error)
 }
 
+// RejectionReader reads log messages that could not be applied to the map.
+type RejectionReader interface {
+	// ReadRejections returns up to limit of the most recent rejections for index.
+	ReadRejections(ctx context.Context, directoryID string, index []byte, limit int32) ([]*mutator.Rejection, error)
+}
+
 // indexFunc computes an index and proof for directory/user
 type indexFunc func(ctx context.Context, d *directory.Directory, userID string) ([32]byte, []byte, error)
 
@@ -83,30 +102,42 @@ type indexFunc func(ctx context.Context, d *directory.Directory, userID string)
 type Server struct {
 	tlog        tpb.TrillianLogClient
 	tmap        tpb.TrillianMapClient
-	mutate      mutator.ReduceMutationFn
 	directories directory.Storage
 	logs        MutationLogs
 	batches     BatchReader
+	rejections  RejectionReader
 	indexFunc   indexFunc
+	lags        lagCache
+
+	// MaxQueueLag is the largest age of an unapplied mutation at which writes
+	// are still accepted. Writes are rejected with ResourceExhausted beyond it.
+	// Zero disables the check.
+	MaxQueueLag time.Duration
+	// ReceiptSigner signs receipts for queued mutations to directories whose
+	// receipt key is its public key.
+	ReceiptSigner *tcrypto.Signer
+	// ReceiptDeadline is the time after mutations are queued by which their
+	// receipts promise they will be applied to the map.
+	ReceiptDeadline time.Duration
 }
 
 // New creates a new instance of the key server.
 func New(tlog tpb.TrillianLogClient,
 	tmap tpb.TrillianMapClient,
-	mutate mutator.ReduceMutationFn,
 	directories directory.Storage,
 	logs MutationLogs,
 	batches BatchReader,
+	rejections RejectionReader,
 	metricsFactory monitoring.MetricFactory,
 ) *Server {
 	initMetrics.Do(func() { createMetrics(metricsFactory) })
 	return &Server{
 		tlog:        tlog,
 		tmap:        tmap,
-		mutate:      mutate,
 		directories: directories,
 		logs:        logs,
 		batches:     batches,
+		rejections:  rejections,
 		indexFunc:   indexFromVRF,
 	}
 }
@@ -535,7 +566,7 @@ func (s *Server) BatchListUserRevisions(ctx context.Context, in *pb.BatchListUse
 }
 
 // QueueEntryUpdate updates a user's profile. If the user does not exist, a new profile will be created.
-func (s *Server) QueueEntryUpdate(ctx context.Context, in *pb.UpdateEntryRequest) (*empty.Empty, error) {
+func (s *Server) QueueEntryUpdate(ctx context.Context, in *pb.UpdateEntryRequest) (*pb.SignedReceipt, error) {
 	return s.BatchQueueUserUpdate(ctx, &pb.BatchQueueUserUpdateRequest{
 		DirectoryId: in.DirectoryId,
 		Updates:     []*pb.EntryUpdate{in.EntryUpdate},
@@ -543,7 +574,7 @@ func (s *Server) QueueEntryUpdate(ctx context.Context, in *pb.UpdateEntryRequest
 }
 
 // BatchQueueUserUpdate updates a user's profile. If the user does not exist, a new profile will be created.
-func (s *Server) BatchQueueUserUpdate(ctx context.Context, in *pb.BatchQueueUserUpdateRequest) (*empty.Empty, error) {
+func (s *Server) BatchQueueUserUpdate(ctx context.Context, in *pb.BatchQueueUserUpdateRequest) (*pb.SignedReceipt, error) {
 	if in.DirectoryId == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "Please specify a directory_id")
 	}
@@ -553,6 +584,19 @@ func (s *Server) BatchQueueUserUpdate(ctx context.Context, in *pb.BatchQueueUser
 		glog.Errorf("adminstorage.Read(%v): %v", in.DirectoryId, err)
 		return nil, status.Errorf(codes.Internal, "Cannot fetch directory info")
 	}
+	if _, err := mutator.Lookup(directory.Mutator); err != nil {
+		glog.Errorf("BatchQueueUserUpdate(%v): %v", in.DirectoryId, err)
+		return nil, status.Errorf(codes.FailedPrecondition, "Directory %v does not accept mutations", in.DirectoryId)
+	}
+	if err := s.checkQueueLag(ctx, directory); err != nil {
+		return nil, err
+	}
+	// Check for a receipt signer before writing, so that a mutation is not
+	// queued without the receipt the client expects.
+	receiptSigner, err := s.receiptSigner(directory)
+	if err != nil {
+		return nil, err
+	}
 	vrfPriv, err := p256.NewFromWrappedKey(ctx, directory.VRFPriv)
 	if err != nil {
 		return nil, err
@@ -581,7 +625,44 @@ func (s *Server) BatchQueueUserUpdate(ctx context.Context, in *pb.BatchQueueUser
 		watermarkWritten.Set(float64(wm.Watermark), directory.DirectoryID, fmt.Sprintf("%v", wm.LogID))
 	}
 
-	return &empty.Empty{}, nil
+	return s.receipt(receiptSigner, directory.DirectoryID, in.Updates, wm)
+}
+
+// ListUserRejections returns the most recent mutations for a user that could
+// not be applied to the map.
+func (s *Server) ListUserRejections(ctx context.Context, in *pb.ListUserRejectionsRequest) (
+	*pb.ListUserRejectionsResponse, error) {
+	if err := validateListUserRejectionsRequest(in); err != nil {
+		glog.Errorf("validateListUserRejectionsRequest(%v): %v", in, err)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+	directory, err := s.directories.Read(ctx, in.DirectoryId, false)
+	if err != nil {
+		glog.Errorf("adminstorage.Read(%v): %v", in.DirectoryId, err)
+		return nil, status.Errorf(codes.Internal, "Cannot fetch directory info")
+	}
+	index, _, err := s.indexFunc(ctx, directory, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	rejections, err := s.rejections.ReadRejections(ctx, directory.DirectoryID, index[:], in.PageSize)
+	if err != nil {
+		glog.Errorf("ReadRejections(%v): %v", in.DirectoryId, err)
+		return nil, status.Errorf(codes.Internal, "Reading rejections failed")
+	}
+	resp := &pb.ListUserRejectionsResponse{
+		Rejections: make([]*pb.RejectedMutation, 0, len(rejections)),
+	}
+	for _, r := range rejections {
+		resp.Rejections = append(resp.Rejections, &pb.RejectedMutation{
+			LogId:          r.LogID,
+			QueueTimestamp: r.ID,
+			LocalId:        r.LocalID,
+			Revision:       r.Revision,
+			Reason:         r.Reason,
+		})
+	}
+	return resp, nil
 }
 
 // GetDirectory returns all info tied to the specified directory.
@@ -610,6 +691,8 @@ func (s *Server) GetDirectory(ctx context.Context, in *pb.GetDirectoryRequest) (
 		Vrf:         directory.VRF,
 		MinInterval: ptypes.DurationProto(directory.MinInterval),
 		MaxInterval: ptypes.DurationProto(directory.MaxInterval),
+		Mutator:     directory.Mutator,
+		ReceiptKey:  directory.ReceiptKey,
 	}, nil
 }
 
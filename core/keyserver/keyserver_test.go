@@ -21,8 +21,11 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/keytransparency/core/directory"
 	"github.com/google/keytransparency/core/fake"
+	"github.com/google/keytransparency/core/mutator"
 	"github.com/google/trillian/testonly"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -172,3 +175,62 @@ func TestLatestRevision(t *testing.T) {
 	}
 
 }
+
+type fakeRejections map[string][]*mutator.Rejection // Map of index to rejections.
+
+func (f fakeRejections) ReadRejections(_ context.Context, _ string, index []byte, limit int32) ([]*mutator.Rejection, error) {
+	r := f[string(index)]
+	if int32(len(r)) > limit {
+		r = r[:limit]
+	}
+	return r, nil
+}
+
+func TestListUserRejections(t *testing.T) {
+	ctx := context.Background()
+	e, err := newMiniEnv(ctx, t)
+	if err != nil {
+		t.Fatalf("newMiniEnv(): %v", err)
+	}
+	defer e.Close()
+	e.srv.rejections = fakeRejections{
+		string(make([]byte, 32)): {
+			{LogID: 1, ID: 20, Revision: 2, Reason: mutator.ErrPreviousHash.Error()},
+			{LogID: 2, ID: 10, LocalID: 3, Revision: 1, Reason: mutator.ErrUnauthorized.Error()},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		req      *pb.ListUserRejectionsRequest
+		wantCode codes.Code
+		want     []*pb.RejectedMutation
+	}{
+		{desc: "no directory", req: &pb.ListUserRejectionsRequest{UserId: "alice"}, wantCode: codes.InvalidArgument},
+		{desc: "no user", req: &pb.ListUserRejectionsRequest{DirectoryId: directoryID}, wantCode: codes.InvalidArgument},
+		{desc: "invalid page size", req: &pb.ListUserRejectionsRequest{DirectoryId: directoryID, UserId: "alice", PageSize: -1},
+			wantCode: codes.InvalidArgument},
+		{desc: "all", req: &pb.ListUserRejectionsRequest{DirectoryId: directoryID, UserId: "alice"},
+			want: []*pb.RejectedMutation{
+				{LogId: 1, QueueTimestamp: 20, Revision: 2, Reason: mutator.ErrPreviousHash.Error()},
+				{LogId: 2, QueueTimestamp: 10, LocalId: 3, Revision: 1, Reason: mutator.ErrUnauthorized.Error()},
+			}},
+		{desc: "page", req: &pb.ListUserRejectionsRequest{DirectoryId: directoryID, UserId: "alice", PageSize: 1},
+			want: []*pb.RejectedMutation{
+				{LogId: 1, QueueTimestamp: 20, Revision: 2, Reason: mutator.ErrPreviousHash.Error()},
+			}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := e.srv.ListUserRejections(ctx, tc.req)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("ListUserRejections(): %v, want %v", err, want)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := resp.GetRejections(), tc.want; !cmp.Equal(got, want, cmp.Comparer(proto.Equal)) {
+				t.Errorf("ListUserRejections(): %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -69,25 +69,28 @@ func (s SourceList) First() *rtpb.ReadToken {
 	}
 }
 
-// Next returns the next read token. Returns an empty struct when the read is finished.
+// Next returns the next read token, and whether the read is finished, in which
+// case the token is an empty struct.
 // lastRow is the (batchSize)th row from the last read, or nil if fewer than
 // batchSize + 1 rows were returned.
-func (s SourceList) Next(rt *rtpb.ReadToken, lastRow *mutator.LogMessage) *rtpb.ReadToken {
+// The empty struct is also the first token of a source list that starts at 0,
+// so callers must rely on done rather than compare tokens to it.
+func (s SourceList) Next(rt *rtpb.ReadToken, lastRow *mutator.LogMessage) (next *rtpb.ReadToken, done bool) {
 	if lastRow != nil {
 		// There are more items in this source slice.
 		return &rtpb.ReadToken{
 			SliceIndex:   rt.SliceIndex,
 			LowWatermark: lastRow.ID,
-		}
+		}, false
 	}
 
 	// Advance to the next slice.
 	if rt.SliceIndex >= int64(len(s))-1 {
 		// There are no more source slices to iterate over.
-		return &rtpb.ReadToken{} // Encodes to ""
+		return &rtpb.ReadToken{}, true // Encodes to ""
 	}
 	return &rtpb.ReadToken{
 		SliceIndex:   rt.SliceIndex + 1,
 		LowWatermark: s[rt.SliceIndex+1].LowestInclusive,
-	}
+	}, false
 }
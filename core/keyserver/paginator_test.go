@@ -99,6 +99,7 @@ func TestNext(t *testing.T) {
 		rt      *rtpb.ReadToken
 		lastRow *mutator.LogMessage
 		want    *rtpb.ReadToken
+		done    bool
 	}{
 		{
 			desc:    "first page",
@@ -120,6 +121,14 @@ func TestNext(t *testing.T) {
 			rt:      &rtpb.ReadToken{SliceIndex: 1},
 			lastRow: nil,
 			want:    &rtpb.ReadToken{},
+			done:    true,
+		},
+		{
+			desc:    "more in first source starting at 0",
+			s:       SourceList{{LogId: 1, LowestInclusive: 0, HighestExclusive: 5}},
+			rt:      &rtpb.ReadToken{},
+			lastRow: &mutator.LogMessage{ID: 3},
+			want:    &rtpb.ReadToken{LowWatermark: 3},
 		},
 		{
 			desc:    "empty",
@@ -127,12 +136,13 @@ func TestNext(t *testing.T) {
 			rt:      &rtpb.ReadToken{SliceIndex: 1, LowWatermark: 2},
 			lastRow: nil,
 			want:    &rtpb.ReadToken{},
+			done:    true,
 		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
-			got := tc.s.Next(tc.rt, tc.lastRow)
-			if !proto.Equal(got, tc.want) {
-				t.Errorf("Next(): %v, want %v", got, tc.want)
+			got, done := tc.s.Next(tc.rt, tc.lastRow)
+			if !proto.Equal(got, tc.want) || done != tc.done {
+				t.Errorf("Next(): %v, %v, want %v, %v", got, done, tc.want, tc.done)
 			}
 		})
 	}
@@ -0,0 +1,97 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyserver
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian/crypto/keys/der"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/keytransparency/core/directory"
+	"github.com/google/keytransparency/core/mutator/entry"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+// receiptSigner returns the signer for receipts of directory d, or nil if d
+// does not issue receipts. It returns an error if d has a receipt key that
+// ReceiptSigner does not match, because clients reject unsigned receipts from
+// such a directory.
+func (s *Server) receiptSigner(d *directory.Directory) (*tcrypto.Signer, error) {
+	if d.ReceiptKey == nil {
+		return nil, nil
+	}
+	if s.ReceiptSigner == nil {
+		glog.Errorf("Directory %v has a receipt key, but this server has no receipt signer", d.DirectoryID)
+		return nil, status.Errorf(codes.FailedPrecondition, "Receipts are unavailable")
+	}
+	key, err := der.ToPublicProto(s.ReceiptSigner.Public())
+	if err != nil {
+		glog.Errorf("der.ToPublicProto(): %v", err)
+		return nil, status.Errorf(codes.Internal, "Invalid receipt key")
+	}
+	if !bytes.Equal(key.GetDer(), d.ReceiptKey.GetDer()) {
+		glog.Errorf("Directory %v has a different receipt key than this server's receipt signer", d.DirectoryID)
+		return nil, status.Errorf(codes.FailedPrecondition, "Receipts are unavailable")
+	}
+	return s.ReceiptSigner, nil
+}
+
+// receipt returns a signed promise that updates, which were written to the
+// input log at wm, will be applied to the map of directoryID before the
+// receipt deadline. receipt returns an empty receipt if signer is nil.
+func (s *Server) receipt(signer *tcrypto.Signer, directoryID string, updates []*pb.EntryUpdate,
+	wm *WriteWatermark) (*pb.SignedReceipt, error) {
+	if signer == nil || wm == nil {
+		return &pb.SignedReceipt{}, nil
+	}
+	hashes := make([][]byte, 0, len(updates))
+	for _, u := range updates {
+		h, err := entry.MutationHash(u.GetMutation())
+		if err != nil {
+			glog.Errorf("MutationHash(): %v", err)
+			return nil, status.Errorf(codes.Internal, "Receipt creation failed")
+		}
+		hashes = append(hashes, h)
+	}
+	deadline, err := ptypes.TimestampProto(time.Unix(0, wm.Watermark).Add(s.ReceiptDeadline))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Invalid receipt deadline: %v", err)
+	}
+	r, err := proto.Marshal(&pb.Receipt{
+		DirectoryId:    directoryID,
+		MutationHashes: hashes,
+		LogId:          wm.LogID,
+		Watermark:      wm.Watermark,
+		Deadline:       deadline,
+	})
+	if err != nil {
+		glog.Errorf("proto.Marshal(receipt): %v", err)
+		return nil, status.Errorf(codes.Internal, "Receipt creation failed")
+	}
+	sig, err := signer.Sign(r)
+	if err != nil {
+		glog.Errorf("ReceiptSigner.Sign(): %v", err)
+		return nil, status.Errorf(codes.Internal, "Receipt signing failed")
+	}
+	return &pb.SignedReceipt{Receipt: r, Signature: sig}, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyserver
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian/crypto/keys/der"
+	"github.com/google/trillian/crypto/keyspb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/keytransparency/core/directory"
+	"github.com/google/keytransparency/core/mutator/entry"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+func TestReceipt(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	updates := []*pb.EntryUpdate{
+		{Mutation: &pb.SignedEntry{Entry: []byte("a")}},
+		{Mutation: &pb.SignedEntry{Entry: []byte("b")}},
+	}
+	queued := time.Unix(1000, 0)
+	wm := &WriteWatermark{LogID: 2, Watermark: queued.UnixNano()}
+
+	// Without a signer, receipts are empty.
+	s := &Server{ReceiptDeadline: time.Hour}
+	signed, err := s.receipt(nil, directoryID, updates, wm)
+	if err != nil {
+		t.Fatalf("receipt(): %v", err)
+	}
+	if !proto.Equal(signed, &pb.SignedReceipt{}) {
+		t.Errorf("receipt(): %v, want empty receipt", signed)
+	}
+
+	signed, err = s.receipt(tcrypto.NewSHA256Signer(key), directoryID, updates, wm)
+	if err != nil {
+		t.Fatalf("receipt(): %v", err)
+	}
+	if err := tcrypto.Verify(key.Public(), crypto.SHA256, signed.Receipt, signed.Signature); err != nil {
+		t.Fatalf("Verify(): %v", err)
+	}
+
+	var got pb.Receipt
+	if err := proto.Unmarshal(signed.Receipt, &got); err != nil {
+		t.Fatalf("Unmarshal(): %v", err)
+	}
+	deadline, err := ptypes.TimestampProto(queued.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.Receipt{
+		DirectoryId: directoryID,
+		LogId:       2,
+		Watermark:   queued.UnixNano(),
+		Deadline:    deadline,
+	}
+	for _, u := range updates {
+		h, err := entry.MutationHash(u.Mutation)
+		if err != nil {
+			t.Fatalf("MutationHash(): %v", err)
+		}
+		want.MutationHashes = append(want.MutationHashes, h)
+	}
+	if !proto.Equal(&got, want) {
+		t.Errorf("receipt(): %v, want %v", &got, want)
+	}
+}
+
+func TestReceiptSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	pubProto, err := der.ToPublicProto(key.Public())
+	if err != nil {
+		t.Fatalf("ToPublicProto(): %v", err)
+	}
+	signer := tcrypto.NewSHA256Signer(key)
+
+	for _, tc := range []struct {
+		desc       string
+		signer     *tcrypto.Signer
+		receiptKey *keyspb.PublicKey
+		wantSigner bool
+		wantCode   codes.Code
+	}{
+		{desc: "no receipts"},
+		{desc: "no receipts, signer", signer: signer},
+		{desc: "matching key", signer: signer, receiptKey: pubProto, wantSigner: true},
+		{desc: "no signer", receiptKey: pubProto, wantCode: codes.FailedPrecondition},
+		{desc: "other key", signer: tcrypto.NewSHA256Signer(other), receiptKey: pubProto,
+			wantCode: codes.FailedPrecondition},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := &Server{ReceiptSigner: tc.signer}
+			got, err := s.receiptSigner(&directory.Directory{DirectoryID: directoryID, ReceiptKey: tc.receiptKey})
+			if status.Code(err) != tc.wantCode {
+				t.Fatalf("receiptSigner(): %v, want %v", err, tc.wantCode)
+			}
+			if (got != nil) != tc.wantSigner {
+				t.Errorf("receiptSigner(): %v, want signer: %v", got, tc.wantSigner)
+			}
+		})
+	}
+}
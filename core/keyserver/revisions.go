@@ -16,6 +16,7 @@ package keyserver
 
 import (
 	"context"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/proto"
@@ -26,6 +27,7 @@ import (
 	"github.com/google/keytransparency/core/mutator"
 
 	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	rtpb "github.com/google/keytransparency/core/keyserver/readtoken_go_proto"
 	tpb "github.com/google/trillian"
 )
 
@@ -34,6 +36,8 @@ var (
 	defaultPageSize = int32(16) //32KB
 	// Maximum allowed requested page size to prevent DOS.
 	maxPageSize = int32(2048) // 8MB
+	// How often GetRevisionStream checks the log for newly published revisions.
+	revisionPollPeriod = 1 * time.Second
 )
 
 // GetLatestRevision returns the latest revision. The current revision tracks the SignedLogRoot.
@@ -112,9 +116,52 @@ func (s *Server) getRevisionByRevision(ctx context.Context, d *directory.Directo
 	}, nil
 }
 
-// GetRevisionStream is a streaming API similar to ListMutations.
-func (*Server) GetRevisionStream(in *pb.GetRevisionRequest, stream pb.KeyTransparency_GetRevisionStreamServer) error {
-	return status.Error(codes.Unimplemented, "GetRevisionStream is unimplemented")
+// GetRevisionStream sends in.Revision and every subsequent revision as soon as
+// it is published. Each revision is sent with proofs relative to the log root
+// at in.LastVerifiedTreeSize. The stream ends when the client cancels it.
+func (s *Server) GetRevisionStream(in *pb.GetRevisionRequest, stream pb.KeyTransparency_GetRevisionStreamServer) error {
+	if err := validateGetRevisionRequest(in); err != nil {
+		glog.Errorf("validateGetRevisionRequest(%v): %v", in, err)
+		return status.Error(codes.InvalidArgument, "Invalid request")
+	}
+	ctx := stream.Context()
+
+	// Lookup log and map info.
+	d, err := s.directories.Read(ctx, in.DirectoryId, false)
+	if err != nil {
+		glog.Errorf("GetRevisionStream(): adminstorage.Read(%v): %v", in.DirectoryId, err)
+		return status.Errorf(codes.Internal, "Cannot fetch directory info")
+	}
+
+	ticker := time.NewTicker(revisionPollPeriod)
+	defer ticker.Stop()
+	for rev := in.GetRevision(); ; {
+		logRoot, logConsistency, err := s.latestLogRootProof(ctx, d, in.GetLastVerifiedTreeSize())
+		if err != nil {
+			return err
+		}
+		// The revision of the map is its index in the log.
+		for ; rev < logRoot.GetTreeSize(); rev++ {
+			revision, err := s.getRevisionByRevision(ctx, d, logRoot, logConsistency, rev)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(revision); err != nil {
+				return err
+			}
+		}
+
+		// Stop before polling again if the client went away while the
+		// ticker was also ready.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
 }
 
 // ListMutations returns the mutations that created an revision.
@@ -137,21 +184,86 @@ func (s *Server) ListMutations(ctx context.Context, in *pb.ListMutationsRequest)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Failed parsing page_token: %v: %v", in.PageToken, err)
 	}
+	mutations, next, done, err := s.readMutations(ctx, d, meta.Sources, rt, in.PageSize, in.Revision)
+	if err != nil {
+		return nil, err
+	}
+	var nextToken string
+	if !done {
+		nextToken, err = EncodeToken(next)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed creating next token: %v", err)
+		}
+	}
+	return &pb.ListMutationsResponse{
+		Mutations:     mutations,
+		NextPageToken: nextToken,
+	}, nil
+}
 
-	// Read PageSize + 1 messages from the log to see if there is another page.
-	high := meta.Sources[rt.SliceIndex].HighestExclusive
-	logID := meta.Sources[rt.SliceIndex].LogId
-	msgs, err := s.logs.ReadLog(ctx, d.DirectoryID, logID, rt.LowWatermark, high, in.PageSize+1)
+// ListMutationsStream sends the mutations that created a revision, along with
+// their inclusion proofs, as they are read from each source in the batch.
+func (s *Server) ListMutationsStream(in *pb.ListMutationsRequest, stream pb.KeyTransparency_ListMutationsStreamServer) error {
+	if err := validateListMutationsRequest(in); err != nil {
+		glog.Errorf("validateListMutationsRequest(%v): %v", in, err)
+		return status.Error(codes.InvalidArgument, "Invalid request")
+	}
+	ctx := stream.Context()
+	// Lookup log and map info.
+	d, err := s.directories.Read(ctx, in.DirectoryId, false)
+	if err != nil {
+		glog.Errorf("ListMutationsStream(): adminstorage.Read(%v): %v", in.DirectoryId, err)
+		return status.Errorf(codes.Internal, "Cannot fetch directory info")
+	}
+	meta, err := s.batches.ReadBatch(ctx, in.DirectoryId, in.Revision)
+	if err != nil {
+		return status.Errorf(codes.Internal, "ReadBatch(%v, %v): %v", in.DirectoryId, in.Revision, err)
+	}
+
+	// Read in.PageSize messages at a time until all sources are exhausted.
+	sources := SourceList(meta.Sources)
+	for rt, done := sources.First(), false; !done; {
+		var mutations []*pb.MutationProof
+		mutations, rt, done, err = s.readMutations(ctx, d, sources, rt, in.PageSize, in.Revision)
+		if err != nil {
+			return err
+		}
+		for _, m := range mutations {
+			if err := stream.Send(m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readMutations reads up to pageSize mutations from the source slice at rt
+// and attaches the inclusion proofs of their indexes at revision-1.
+// readMutations returns the read token for the next page, and whether all
+// sources have been read.
+func (s *Server) readMutations(ctx context.Context, d *directory.Directory, sources SourceList,
+	rt *rtpb.ReadToken, pageSize int32, revision int64) ([]*pb.MutationProof, *rtpb.ReadToken, bool, error) {
+	if len(sources) == 0 {
+		return nil, &rtpb.ReadToken{}, true, nil
+	}
+	if rt.SliceIndex < 0 || rt.SliceIndex >= int64(len(sources)) {
+		return nil, nil, false, status.Errorf(codes.InvalidArgument, "Invalid page_token slice index: %v", rt.SliceIndex)
+	}
+
+	// Read pageSize + 1 messages from the log to see if there is another page.
+	high := sources[rt.SliceIndex].HighestExclusive
+	logID := sources[rt.SliceIndex].LogId
+	msgs, err := s.logs.ReadLog(ctx, d.DirectoryID, logID, rt.LowWatermark, high, pageSize+1)
 	if err != nil {
-		glog.Errorf("ListMutations(): ReadLog(%v, log: %v/(%v, %v], batchSize: %v): %v",
-			d.DirectoryID, logID, rt.LowWatermark, high, in.PageSize, err)
-		return nil, status.Error(codes.Internal, "Reading mutations range failed")
+		glog.Errorf("readMutations(): ReadLog(%v, log: %v/(%v, %v], batchSize: %v): %v",
+			d.DirectoryID, logID, rt.LowWatermark, high, pageSize, err)
+		return nil, nil, false, status.Error(codes.Internal, "Reading mutations range failed")
 	}
-	moreInLogID := len(msgs) == int(in.PageSize+1)
+	moreInLogID := len(msgs) == int(pageSize+1)
 	var lastRow *mutator.LogMessage
 	if moreInLogID {
-		lastRow = msgs[in.PageSize] // Next start is the last row of this batch.
-		msgs = msgs[0:in.PageSize]  // Only return PageSize messages.
+		lastRow = msgs[pageSize] // Next start is the last row of this batch.
+		msgs = msgs[0:pageSize]  // Only return pageSize messages.
 	}
 
 	// For each msg, attach the leaf value from the previous map revision.
@@ -159,34 +271,27 @@ func (s *Server) ListMutations(ctx context.Context, in *pb.ListMutationsRequest)
 	indexes := make([][]byte, 0, len(msgs))
 	mutations := make([]*pb.MutationProof, 0, len(msgs))
 	for _, m := range msgs {
-		mutations = append(mutations, &pb.MutationProof{Mutation: m.Mutation})
+		mutations = append(mutations, &pb.MutationProof{
+			Mutation:       m.Mutation,
+			LogId:          logID,
+			QueueTimestamp: m.ID,
+			LocalId:        m.LocalID,
+		})
 		var entry pb.Entry
 		if err := proto.Unmarshal(m.Mutation.Entry, &entry); err != nil {
-			return nil, status.Errorf(codes.DataLoss, "could not unmarshal entry")
+			return nil, nil, false, status.Errorf(codes.DataLoss, "could not unmarshal entry")
 		}
 		indexes = append(indexes, entry.GetIndex())
 	}
-	proofs, err := s.inclusionProofs(ctx, d, indexes, in.Revision-1)
+	proofs, err := s.inclusionProofs(ctx, d, indexes, revision-1)
 	if err != nil {
-		return nil, err
+		return nil, nil, false, err
 	}
 	for i, p := range proofs {
 		mutations[i].LeafProof = p
 	}
-	nextToken, err := EncodeToken(SourceList(meta.Sources).Next(rt, lastRow))
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed creating next token: %v", err)
-
-	}
-	return &pb.ListMutationsResponse{
-		Mutations:     mutations,
-		NextPageToken: nextToken,
-	}, nil
-}
-
-// ListMutationsStream is a streaming list of mutations in a specific revision.
-func (*Server) ListMutationsStream(in *pb.ListMutationsRequest, stream pb.KeyTransparency_ListMutationsStreamServer) error {
-	return status.Error(codes.Unimplemented, "ListMutationStream is unimplemented")
+	next, done := sources.Next(rt, lastRow)
+	return mutations, next, done, nil
 }
 
 // logInclusion returns the inclusion proof for a map revision in the log of map roots.
@@ -23,6 +23,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -53,11 +54,79 @@ func genIndexes(start, end int64) [][]byte {
 	return indexes
 }
 
+// fakeRevisionStream collects the revisions it is sent and cancels its
+// context once it has received max revisions.
+type fakeRevisionStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	cancel    context.CancelFunc
+	max       int
+	revisions []*pb.Revision
+}
+
+func (f *fakeRevisionStream) Context() context.Context { return f.ctx }
+
+func (f *fakeRevisionStream) Send(r *pb.Revision) error {
+	f.revisions = append(f.revisions, r)
+	if len(f.revisions) >= f.max {
+		f.cancel()
+	}
+	return nil
+}
+
 func TestGetRevisionStream(t *testing.T) {
-	srv := &Server{}
-	err := srv.GetRevisionStream(nil, nil)
-	if got, want := status.Code(err), codes.Unimplemented; got != want {
-		t.Errorf("GetMutationsStream(_, _): %v, want %v", got, want)
+	defer func(p time.Duration) { revisionPollPeriod = p }(revisionPollPeriod)
+	revisionPollPeriod = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	e, err := newMiniEnv(ctx, t)
+	if err != nil {
+		t.Fatalf("newMiniEnv(): %v", err)
+	}
+	defer e.Close()
+
+	// Revisions 1 and 2 are available at first, 3 and 4 are published later.
+	gomock.InOrder(
+		e.s.Log.EXPECT().GetLatestSignedLogRoot(gomock.Any(), gomock.Any()).
+			Return(&tpb.GetLatestSignedLogRootResponse{SignedLogRoot: &tpb.SignedLogRoot{TreeSize: 3}}, nil),
+		e.s.Log.EXPECT().GetLatestSignedLogRoot(gomock.Any(), gomock.Any()).
+			Return(&tpb.GetLatestSignedLogRootResponse{SignedLogRoot: &tpb.SignedLogRoot{TreeSize: 3}}, nil),
+		e.s.Log.EXPECT().GetLatestSignedLogRoot(gomock.Any(), gomock.Any()).
+			Return(&tpb.GetLatestSignedLogRootResponse{SignedLogRoot: &tpb.SignedLogRoot{TreeSize: 5}}, nil),
+	)
+	e.s.Log.EXPECT().GetConsistencyProof(gomock.Any(), gomock.Any()).
+		Return(&tpb.GetConsistencyProofResponse{}, nil).Times(3)
+	e.s.Log.EXPECT().GetInclusionProof(gomock.Any(), gomock.Any()).
+		Return(&tpb.GetInclusionProofResponse{}, nil).Times(4)
+	e.s.Map.EXPECT().GetSignedMapRootByRevision(gomock.Any(), gomock.Any()).Times(4).
+		DoAndReturn(func(_ context.Context, in *tpb.GetSignedMapRootByRevisionRequest, _ ...grpc.CallOption) (
+			*tpb.GetSignedMapRootResponse, error) {
+			return &tpb.GetSignedMapRootResponse{
+				MapRoot: &tpb.SignedMapRoot{MapRoot: []byte{byte(in.Revision)}},
+			}, nil
+		})
+
+	sctx, scancel := context.WithCancel(ctx)
+	stream := &fakeRevisionStream{ctx: sctx, cancel: scancel, max: 4}
+	err = e.srv.GetRevisionStream(&pb.GetRevisionRequest{
+		DirectoryId:          directoryID,
+		Revision:             1,
+		LastVerifiedTreeSize: 1,
+	}, stream)
+	if got, want := err, context.Canceled; got != want {
+		t.Errorf("GetRevisionStream(): %v, want %v", got, want)
+	}
+	if got, want := len(stream.revisions), 4; got != want {
+		t.Fatalf("GetRevisionStream(): sent %v revisions, want %v", got, want)
+	}
+	for i, r := range stream.revisions {
+		if got, want := int64(r.GetMapRoot().GetMapRoot().GetMapRoot()[0]), int64(i+1); got != want {
+			t.Errorf("GetRevisionStream(): revision[%v]: %v, want %v", i, got, want)
+		}
+	}
+
+	if err := e.srv.GetRevisionStream(&pb.GetRevisionRequest{Revision: -1}, stream); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetRevisionStream(Revision: -1): %v, want %v", err, codes.InvalidArgument)
 	}
 }
 
@@ -89,6 +158,14 @@ func (m *mutations) ReadLog(ctx context.Context, dirID string,
 	return logShard[low : low+count], nil
 }
 
+func (m *mutations) ListLogs(ctx context.Context, dirID string, writable bool) ([]int64, error) {
+	logIDs := make([]int64, 0, len(*m))
+	for logID := range *m {
+		logIDs = append(logIDs, logID)
+	}
+	return logIDs, nil
+}
+
 func MustEncodeToken(t *testing.T, low int64) string {
 	t.Helper()
 	rt := &rtpb.ReadToken{
@@ -188,6 +265,9 @@ func TestListMutations(t *testing.T) {
 				if got, want := mut.Mutation, mtns[i].Mutation; !proto.Equal(got, want) {
 					t.Errorf("resp.Mutations[i].Update:%v, want %v", got, want)
 				}
+				if got, want := mut.QueueTimestamp, mtns[i].ID; got != want {
+					t.Errorf("resp.Mutations[i].QueueTimestamp:%v, want %v", got, want)
+				}
 			}
 
 			var npt rtpb.ReadToken
@@ -200,3 +280,104 @@ func TestListMutations(t *testing.T) {
 		})
 	}
 }
+
+type fakeMutationStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	mutations []*pb.MutationProof
+}
+
+func (f *fakeMutationStream) Context() context.Context { return f.ctx }
+
+func (f *fakeMutationStream) Send(m *pb.MutationProof) error {
+	f.mutations = append(f.mutations, m)
+	return nil
+}
+
+func TestListMutationsStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	fakeBatches := batchStorage{
+		1: SourceList{
+			{LogId: 0, LowestInclusive: 2, HighestExclusive: 7},
+			{LogId: 1, LowestInclusive: 0, HighestExclusive: 3},
+		},
+		2: SourceList{},
+		// The first token of a batch whose first source starts at 0 is the
+		// empty ReadToken.
+		3: SourceList{
+			{LogId: 1, LowestInclusive: 0, HighestExclusive: 3},
+			{LogId: 0, LowestInclusive: 2, HighestExclusive: 4},
+		},
+	}
+	fakeLogs := make(mutations)
+	for logID, size := range map[int64]int64{0: 7, 1: 3} {
+		for i := int64(0); i < size; i++ {
+			fakeLogs[logID] = append(fakeLogs[logID], &mutator.LogMessage{
+				ID: i,
+				Mutation: &pb.SignedEntry{
+					Entry: mustMarshal(t, &pb.Entry{Index: []byte(fmt.Sprintf("key_%v_%v", logID, i))}),
+				},
+			})
+		}
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		revision int64
+		pageSize int32
+		want     []*pb.MutationProof // Only LogId and QueueTimestamp are compared.
+	}{
+		{desc: "pages", revision: 1, pageSize: 2, want: []*pb.MutationProof{
+			{LogId: 0, QueueTimestamp: 2}, {LogId: 0, QueueTimestamp: 3}, {LogId: 0, QueueTimestamp: 4},
+			{LogId: 0, QueueTimestamp: 5}, {LogId: 0, QueueTimestamp: 6},
+			{LogId: 1, QueueTimestamp: 0}, {LogId: 1, QueueTimestamp: 1}, {LogId: 1, QueueTimestamp: 2},
+		}},
+		{desc: "one page", revision: 1, pageSize: 10, want: []*pb.MutationProof{
+			{LogId: 0, QueueTimestamp: 2}, {LogId: 0, QueueTimestamp: 3}, {LogId: 0, QueueTimestamp: 4},
+			{LogId: 0, QueueTimestamp: 5}, {LogId: 0, QueueTimestamp: 6},
+			{LogId: 1, QueueTimestamp: 0}, {LogId: 1, QueueTimestamp: 1}, {LogId: 1, QueueTimestamp: 2},
+		}},
+		{desc: "empty", revision: 2},
+		{desc: "first source starts at 0", revision: 3, pageSize: 2, want: []*pb.MutationProof{
+			{LogId: 1, QueueTimestamp: 0}, {LogId: 1, QueueTimestamp: 1}, {LogId: 1, QueueTimestamp: 2},
+			{LogId: 0, QueueTimestamp: 2}, {LogId: 0, QueueTimestamp: 3},
+		}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			e, err := newMiniEnv(ctx, t)
+			if err != nil {
+				t.Fatalf("newMiniEnv(): %v", err)
+			}
+			defer e.Close()
+			e.srv.logs = &fakeLogs
+			e.srv.batches = fakeBatches
+			e.s.Map.EXPECT().GetLeavesByRevision(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(_ context.Context, in *tpb.GetMapLeavesByRevisionRequest, _ ...grpc.CallOption) (
+					*tpb.GetMapLeavesResponse, error) {
+					return &tpb.GetMapLeavesResponse{MapLeafInclusion: genInclusions(0, int64(len(in.Index)))}, nil
+				})
+
+			stream := &fakeMutationStream{ctx: ctx}
+			if err := e.srv.ListMutationsStream(&pb.ListMutationsRequest{
+				DirectoryId: directoryID,
+				Revision:    tc.revision,
+				PageSize:    tc.pageSize,
+			}, stream); err != nil {
+				t.Fatalf("ListMutationsStream(): %v", err)
+			}
+			if got, want := len(stream.mutations), len(tc.want); got != want {
+				t.Fatalf("ListMutationsStream(): sent %v mutations, want %v", got, want)
+			}
+			for i, m := range stream.mutations {
+				if m.LogId != tc.want[i].LogId || m.QueueTimestamp != tc.want[i].QueueTimestamp {
+					t.Errorf("mutations[%v]: log %v, timestamp %v, want log %v, timestamp %v",
+						i, m.LogId, m.QueueTimestamp, tc.want[i].LogId, tc.want[i].QueueTimestamp)
+				}
+				if m.LeafProof == nil {
+					t.Errorf("mutations[%v].LeafProof: nil, want inclusion proof", i)
+				}
+			}
+		})
+	}
+}
@@ -160,6 +160,24 @@ func validateGetRevisionRequest(in *pb.GetRevisionRequest) error {
 	return nil
 }
 
+func validateListUserRejectionsRequest(in *pb.ListUserRejectionsRequest) error {
+	if in.DirectoryId == "" {
+		return errors.New("missing directory_id")
+	}
+	if in.UserId == "" {
+		return errors.New("missing user_id")
+	}
+	switch {
+	case in.PageSize < 0:
+		return ErrInvalidPageSize
+	case in.PageSize == 0:
+		in.PageSize = defaultPageSize
+	case in.PageSize > maxPageSize:
+		in.PageSize = maxPageSize
+	}
+	return nil
+}
+
 func validateListMutationsRequest(in *pb.ListMutationsRequest) error {
 	if in.Revision < 1 {
 		return ErrInvalidStart
@@ -17,10 +17,12 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/keytransparency/core/client"
 	"github.com/google/keytransparency/core/monitorstorage"
+	"github.com/google/keytransparency/core/mutator"
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/types"
@@ -40,6 +42,10 @@ type Monitor struct {
 	mapVerifier *tclient.MapVerifier
 	signer      *tcrypto.Signer
 	store       monitorstorage.Interface
+	mutate      mutator.ReduceMutationFn
+
+	receiptsMu sync.Mutex
+	receipts   []*client.ReceiptChecker
 }
 
 // NewFromDirectory produces a new monitor from a Directory object.
@@ -60,8 +66,12 @@ func NewFromDirectory(cli pb.KeyTransparencyClient,
 	if err != nil {
 		return nil, fmt.Errorf("could not create kt client: %v", err)
 	}
+	mut, err := mutator.Lookup(config.GetMutator())
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve mutator: %v", err)
+	}
 
-	return New(ktClient, logVerifier, mapVerifier, signer, store)
+	return New(ktClient, logVerifier, mapVerifier, signer, store, mut.Reduce)
 }
 
 // New creates a new instance of the monitor.
@@ -69,13 +79,15 @@ func New(cli *client.Client,
 	logVerifier *tclient.LogVerifier,
 	mapVerifier *tclient.MapVerifier,
 	signer *tcrypto.Signer,
-	store monitorstorage.Interface) (*Monitor, error) {
+	store monitorstorage.Interface,
+	mutate mutator.ReduceMutationFn) (*Monitor, error) {
 	return &Monitor{
 		cli:         cli,
 		logVerifier: logVerifier,
 		mapVerifier: mapVerifier,
 		signer:      signer,
 		store:       store,
+		mutate:      mutate,
 	}, nil
 }
 
@@ -151,6 +163,9 @@ func (m *Monitor) ProcessLoop(ctx context.Context, directoryID string, trusted t
 				return err
 			}
 		}
+		// A missed receipt is reported with the revision that missed it, but
+		// does not make the revision itself invalid.
+		errList = append(errList, m.checkReceipts(mapRootB, mutations)...)
 
 		// Save result.
 		if err := m.store.Set(int64(mapRootB.Revision), &monitorstorage.Result{
@@ -0,0 +1,81 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/trillian/types"
+
+	"github.com/google/keytransparency/core/client"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+)
+
+// MaxPendingReceipts is the maximum number of receipts the monitor checks at
+// the same time.
+const MaxPendingReceipts = 10000
+
+// ErrTooManyReceipts occurs when MaxPendingReceipts receipts are pending.
+var ErrTooManyReceipts = errors.New("too many pending receipts")
+
+// AddReceipt verifies a receipt for queued mutations and checks that its
+// mutations are applied by its deadline in the revisions processed from now on.
+func (m *Monitor) AddReceipt(signed *pb.SignedReceipt) error {
+	r, err := m.cli.VerifyReceipt(signed)
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return errors.New("directory does not issue receipts")
+	}
+	if r.DirectoryId != m.cli.DirectoryID {
+		return fmt.Errorf("receipt for directory %v, want %v", r.DirectoryId, m.cli.DirectoryID)
+	}
+	rc, err := client.NewReceiptChecker(r)
+	if err != nil {
+		return err
+	}
+	m.receiptsMu.Lock()
+	defer m.receiptsMu.Unlock()
+	if len(m.receipts) >= MaxPendingReceipts {
+		return ErrTooManyReceipts
+	}
+	m.receipts = append(m.receipts, rc)
+	return nil
+}
+
+// checkReceipts processes the mutations of the revision with mapRoot for all
+// pending receipts, and returns an error for each receipt whose deadline was
+// missed. Receipts that are satisfied or missed are no longer checked.
+func (m *Monitor) checkReceipts(mapRoot *types.MapRootV1, mutations []*pb.MutationProof) []error {
+	m.receiptsMu.Lock()
+	defer m.receiptsMu.Unlock()
+	var errs []error
+	pending := m.receipts[:0]
+	for _, rc := range m.receipts {
+		switch err := rc.Process(mapRoot, mutations); err {
+		case nil:
+		case client.ErrReceiptPending:
+			pending = append(pending, rc)
+		default:
+			r := rc.Receipt()
+			errs = append(errs, fmt.Errorf("receipt for log %v watermark %v: %v", r.GetLogId(), r.GetWatermark(), err))
+		}
+	}
+	m.receipts = pending
+	return errs
+}
@@ -21,9 +21,11 @@ import (
 	"bytes"
 	"errors"
 	"math/big"
+	"sort"
 
 	"github.com/golang/glog"
-	"github.com/google/keytransparency/core/mutator/entry"
+	"github.com/google/keytransparency/core/mutator"
+	"github.com/google/keytransparency/core/sequencer/mapper"
 	"github.com/google/trillian"
 	"github.com/google/trillian/merkle"
 	"github.com/google/trillian/storage"
@@ -92,48 +94,34 @@ func (e *ErrList) Proto() []*statuspb.Status {
 func (m *Monitor) verifyMutations(muts []*pb.MutationProof, oldRoot *trillian.SignedMapRoot, expectedNewRoot *types.MapRootV1) []error {
 	errs := ErrList{}
 	oldProofNodes := make(map[string][]byte)
-	newLeaves := make([]merkle.HStar2LeafHash, 0, len(muts))
 	glog.Infof("verifyMutations() called with %v mutations.", len(muts))
 
-	for _, mut := range muts {
-		oldLeaf, err := entry.FromLeafValue(mut.GetLeafProof().GetLeaf().GetLeafValue())
-		if err != nil {
-			errs.AppendStatus(status.Newf(codes.DataLoss, "could not decode leaf: %v", err).WithDetails(mut.GetLeafProof().GetLeaf()))
-		}
-
+	// Apply mutations in the same order the sequencer does.
+	sorted := make([]*pb.MutationProof, len(muts))
+	copy(sorted, muts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return logMessage(sorted[i]).Less(logMessage(sorted[j]))
+	})
+
+	// Group mutations by index.
+	indexes := make([][]byte, 0, len(sorted))
+	oldLeaves := make(map[string]*trillian.MapLeaf)
+	updates := make(map[string][]*pb.EntryUpdate)
+	for _, mut := range sorted {
 		// verify that the provided leaf’s inclusion proof goes to revision e-1:
 		index := mut.GetLeafProof().GetLeaf().GetIndex()
 		if err := m.mapVerifier.VerifyMapLeafInclusion(oldRoot, mut.GetLeafProof()); err != nil {
 			glog.Infof("VerifyMapInclusionProof(%x): %v", index, err)
 			errs.AppendStatus(status.Newf(codes.DataLoss, "invalid  map inclusion proof: %v", err).WithDetails(mut.GetLeafProof()))
 		}
-
-		// compute the new leaf
-		newValue, err := entry.MutateFn(oldLeaf, mut.GetMutation())
-		if err != nil {
-			glog.Infof("Mutation did not verify: %v", err)
-			errs.AppendStatus(status.Newf(codes.DataLoss, "invalid mutation: %v", err).WithDetails(mut.GetMutation()))
-		}
-		leafNodeID := storage.NewNodeIDFromPrefixSuffix(index, storage.Suffix{}, m.mapVerifier.Hasher.BitLen())
-		leaf, err := entry.ToLeafValue(newValue)
-		if err != nil {
-			glog.Infof("Failed to serialize: %v", err)
-			errs.AppendStatus(status.Newf(codes.DataLoss, "failed to serialize: %v", err).WithDetails(newValue))
-		}
-
-		// BUG(gdbelvin): Proto serializations are not idempotent.
-		// - Upgrade the hasher to use ObjectHash.
-		// - Use deep compare between the tree and the computed value.
-		leafHash, err := m.mapVerifier.Hasher.HashLeaf(m.mapVerifier.MapID, index, leaf)
-		if err != nil {
-			errs.appendErr(err)
+		if _, ok := oldLeaves[string(index)]; !ok {
+			indexes = append(indexes, index)
+			oldLeaves[string(index)] = mut.GetLeafProof().GetLeaf()
 		}
-		newLeaves = append(newLeaves, merkle.HStar2LeafHash{
-			Index:    leafNodeID.BigInt(),
-			LeafHash: leafHash,
-		})
+		updates[string(index)] = append(updates[string(index)], &pb.EntryUpdate{Mutation: mut.GetMutation()})
 
 		// store the proof hashes locally to recompute the tree below:
+		leafNodeID := storage.NewNodeIDFromPrefixSuffix(index, storage.Suffix{}, m.mapVerifier.Hasher.BitLen())
 		sibIDs := leafNodeID.Siblings()
 		proofs := mut.GetLeafProof().GetInclusion()
 		for level, sibID := range sibIDs {
@@ -153,6 +141,50 @@ func (m *Monitor) verifyMutations(muts []*pb.MutationProof, oldRoot *trillian.Si
 		}
 	}
 
+	// compute the new leaves
+	newLeaves := make([]merkle.HStar2LeafHash, 0, len(indexes))
+	appendLeaf := func(index, value []byte) {
+		// BUG(gdbelvin): Proto serializations are not idempotent.
+		// - Upgrade the hasher to use ObjectHash.
+		// - Use deep compare between the tree and the computed value.
+		leafHash, err := m.mapVerifier.Hasher.HashLeaf(m.mapVerifier.MapID, index, value)
+		if err != nil {
+			errs.appendErr(err)
+		}
+		leafNodeID := storage.NewNodeIDFromPrefixSuffix(index, storage.Suffix{}, m.mapVerifier.Hasher.BitLen())
+		newLeaves = append(newLeaves, merkle.HStar2LeafHash{
+			Index:    leafNodeID.BigInt(),
+			LeafHash: leafHash,
+		})
+	}
+	for _, index := range indexes {
+		oldLeaf := oldLeaves[string(index)]
+		emitted := false
+		if err := mapper.ReduceFn(m.mutate, index, []*trillian.MapLeaf{oldLeaf}, updates[string(index)],
+			func(leaf *trillian.MapLeaf) {
+				emitted = true
+				appendLeaf(index, leaf.GetLeafValue())
+			},
+			func(*pb.EntryUpdate, error) {}, // Invalid mutations are skipped by the sequencer too.
+		); err != nil {
+			errs.AppendStatus(status.Newf(codes.DataLoss, "could not decode leaf: %v", err).WithDetails(oldLeaf))
+			continue
+		}
+		// When every mutation for an index is invalid the sequencer leaves
+		// the leaf untouched, so the old leaf is still part of the new root.
+		if !emitted && len(oldLeaf.GetLeafValue()) > 0 {
+			appendLeaf(index, oldLeaf.GetLeafValue())
+		}
+	}
+
+	// A revision that changes no leaves must keep the previous root.
+	if len(newLeaves) == 0 {
+		if err := m.validateUnchangedRoot(expectedNewRoot, oldRoot); err != nil {
+			errs.appendErr(err)
+		}
+		return errs
+	}
+
 	if err := m.validateMapRoot(expectedNewRoot, newLeaves, oldProofNodes); err != nil {
 		errs.appendErr(err)
 	}
@@ -160,6 +192,27 @@ func (m *Monitor) verifyMutations(muts []*pb.MutationProof, oldRoot *trillian.Si
 	return errs
 }
 
+// logMessage returns the fields of mut that determine the order in which it is applied.
+func logMessage(mut *pb.MutationProof) *mutator.LogMessage {
+	return &mutator.LogMessage{
+		LogID:   mut.GetLogId(),
+		ID:      mut.GetQueueTimestamp(),
+		LocalID: mut.GetLocalId(),
+	}
+}
+
+func (m *Monitor) validateUnchangedRoot(newRoot *types.MapRootV1, oldRoot *trillian.SignedMapRoot) error {
+	var old types.MapRootV1
+	if err := old.UnmarshalBinary(oldRoot.GetMapRoot()); err != nil {
+		glog.Errorf("UnmarshalBinary(old map root): %v", err)
+		return ErrNotMatchingMapRoot
+	}
+	if !bytes.Equal(old.RootHash, newRoot.RootHash) {
+		return ErrNotMatchingMapRoot
+	}
+	return nil
+}
+
 func (m *Monitor) validateMapRoot(newRoot *types.MapRootV1, mutatedLeaves []merkle.HStar2LeafHash, oldProofNodes map[string][]byte) error {
 	// compute the new root using local intermediate hashes from revision e
 	// (above proof hashes):
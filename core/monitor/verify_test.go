@@ -0,0 +1,111 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/google/keytransparency/core/mutator/entry"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/coniks"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/types"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	tclient "github.com/google/trillian/client"
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+const mapID = 1
+
+func rejectAll(*pb.SignedEntry, *pb.SignedEntry) (*pb.SignedEntry, error) {
+	return nil, errors.New("invalid mutation")
+}
+
+func TestVerifyMutationsUnchangedRoot(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	signer := tcrypto.NewSigner(0, key, crypto.SHA256)
+	m := &Monitor{
+		mapVerifier: &tclient.MapVerifier{
+			MapID:   mapID,
+			Hasher:  coniks.Default,
+			PubKey:  key.Public(),
+			SigHash: crypto.SHA256,
+		},
+		mutate: rejectAll,
+	}
+
+	// Build a map holding a single leaf.
+	index := sha256.Sum256([]byte("alice"))
+	leafValue, err := entry.ToLeafValue(&pb.SignedEntry{Entry: []byte("old")})
+	if err != nil {
+		t.Fatalf("ToLeafValue(): %v", err)
+	}
+	leafHash, err := coniks.Default.HashLeaf(mapID, index[:], leafValue)
+	if err != nil {
+		t.Fatalf("HashLeaf(): %v", err)
+	}
+	hs2 := merkle.NewHStar2(mapID, coniks.Default)
+	rootHash, err := hs2.HStar2Root(coniks.Default.BitLen(), []merkle.HStar2LeafHash{{
+		Index:    storage.NewNodeIDFromHash(index[:]).BigInt(),
+		LeafHash: leafHash,
+	}})
+	if err != nil {
+		t.Fatalf("HStar2Root(): %v", err)
+	}
+	oldRoot, err := signer.SignMapRoot(&types.MapRootV1{RootHash: rootHash, Revision: 1})
+	if err != nil {
+		t.Fatalf("SignMapRoot(): %v", err)
+	}
+	invalid := &pb.MutationProof{
+		Mutation: &pb.SignedEntry{Entry: []byte("new")},
+		LeafProof: &trillian.MapLeafInclusion{
+			Leaf:      &trillian.MapLeaf{Index: index[:], LeafValue: leafValue},
+			Inclusion: make([][]byte, coniks.Default.BitLen()),
+		},
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		muts     []*pb.MutationProof
+		rootHash []byte
+		wantErr  error
+	}{
+		{desc: "empty revision", rootHash: rootHash},
+		{desc: "empty revision, changed root", rootHash: []byte("changed"), wantErr: ErrNotMatchingMapRoot},
+		{desc: "all invalid", muts: []*pb.MutationProof{invalid}, rootHash: rootHash},
+		{desc: "all invalid, changed root", muts: []*pb.MutationProof{invalid}, rootHash: []byte("changed"), wantErr: ErrNotMatchingMapRoot},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			errs := m.verifyMutations(tc.muts, oldRoot, &types.MapRootV1{RootHash: tc.rootHash, Revision: 2})
+			switch {
+			case tc.wantErr == nil && len(errs) != 0:
+				t.Errorf("verifyMutations(): %v, want no errors", errs)
+			case tc.wantErr != nil && (len(errs) != 1 || errs[0] != tc.wantErr):
+				t.Errorf("verifyMutations(): %v, want %v", errs, tc.wantErr)
+			}
+		})
+	}
+}
@@ -20,16 +20,49 @@ package monitorserver
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/google/keytransparency/core/fake"
+	"github.com/google/keytransparency/core/monitor"
+
+	pb "github.com/google/keytransparency/core/api/monitor/v1/monitor_go_proto"
+	ktpb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
 )
 
 func TestGetSignedMapRoot(t *testing.T) {
 	ctx := context.Background()
-	srv := New(fake.NewMonitorStorage())
+	srv := New(fake.NewMonitorStorage(), nil)
 	_, err := srv.GetState(ctx, nil)
 	if got, want := err, ErrNothingProcessed; got != want {
 		t.Errorf("GetSignedMapRoot(_, _): %v, want %v", got, want)
 	}
 }
+
+type fakeReceipts struct{ err error }
+
+func (f fakeReceipts) AddReceipt(*ktpb.SignedReceipt) error { return f.err }
+
+func TestAddReceipt(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc string
+		err  error
+		want codes.Code
+	}{
+		{desc: "accepted", want: codes.OK},
+		{desc: "invalid", err: errors.New("receipt signature"), want: codes.InvalidArgument},
+		{desc: "too many", err: monitor.ErrTooManyReceipts, want: codes.ResourceExhausted},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			srv := New(fake.NewMonitorStorage(), fakeReceipts{err: tc.err})
+			_, err := srv.AddReceipt(ctx, &pb.AddReceiptRequest{Receipt: &ktpb.SignedReceipt{}})
+			if got := status.Code(err); got != tc.want {
+				t.Errorf("AddReceipt(): %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
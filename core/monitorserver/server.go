@@ -29,10 +29,12 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/keytransparency/core/monitor"
 	"github.com/google/keytransparency/core/monitorstorage"
 
 	pb "github.com/google/keytransparency/core/api/monitor/v1/monitor_go_proto"
+	ktpb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
 )
 
 var (
@@ -41,16 +43,24 @@ var (
 	ErrNothingProcessed = errors.New("did not process any mutations yet")
 )
 
+// ReceiptAdder checks that the mutations of receipts are applied.
+type ReceiptAdder interface {
+	// AddReceipt verifies a receipt and checks its mutations from now on.
+	AddReceipt(signed *ktpb.SignedReceipt) error
+}
+
 // Server holds internal state for the monitor server. It serves monitoring
 // responses via a grpc and HTTP API.
 type Server struct {
-	storage monitorstorage.Interface
+	storage  monitorstorage.Interface
+	receipts ReceiptAdder
 }
 
 // New creates a new instance of the monitor server.
-func New(storage monitorstorage.Interface) *Server {
+func New(storage monitorstorage.Interface, receipts ReceiptAdder) *Server {
 	return &Server{
-		storage: storage,
+		storage:  storage,
+		receipts: receipts,
 	}
 }
 
@@ -81,6 +91,19 @@ func (s *Server) GetStateByRevision(ctx context.Context, in *pb.GetStateRequest)
 	return s.getResponseByRevision(in.GetRevision())
 }
 
+// AddReceipt verifies a receipt and checks that its mutations are applied by
+// its deadline.
+func (s *Server) AddReceipt(ctx context.Context, in *pb.AddReceiptRequest) (*empty.Empty, error) {
+	switch err := s.receipts.AddReceipt(in.GetReceipt()); err {
+	case nil:
+		return &empty.Empty{}, nil
+	case monitor.ErrTooManyReceipts:
+		return nil, status.Errorf(codes.ResourceExhausted, "%v", err)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid receipt: %v", err)
+	}
+}
+
 func (s *Server) getResponseByRevision(revision int64) (*pb.State, error) {
 	r, err := s.storage.Get(revision)
 	if err == monitorstorage.ErrNotFound {
@@ -16,6 +16,8 @@
 package entry
 
 import (
+	"crypto/sha256"
+
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/proto"
 
@@ -42,3 +44,14 @@ func FromLeafValue(value []byte) (*pb.SignedEntry, error) {
 func ToLeafValue(update *pb.SignedEntry) ([]byte, error) {
 	return proto.Marshal(update)
 }
+
+// MutationHash returns the SHA256 hash of a serialized mutation. Receipts for
+// queued mutations identify them by this hash.
+func MutationHash(m *pb.SignedEntry) ([]byte, error) {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(b)
+	return h[:], nil
+}
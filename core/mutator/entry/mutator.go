@@ -30,6 +30,13 @@ import (
 	tinkpb "github.com/google/tink/proto/tink_go_proto"
 )
 
+func init() {
+	mutator.Register(mutator.DefaultName, &mutator.Mutator{
+		MapLogItem: MapLogItemFn,
+		Reduce:     MutateFn,
+	})
+}
+
 // MapLogItemFn maps elements from *mutator.LogMessage to KV<index, *pb.EntryUpdate>.
 func MapLogItemFn(m *mutator.LogMessage, emit func(index []byte, mutation *pb.EntryUpdate)) error {
 	var entry pb.Entry
@@ -54,8 +54,37 @@ type MapLogItemFn func(logItem *LogMessage, emit func(index []byte, mutation *pb
 
 // LogMessage represents a change to a user, and associated data.
 type LogMessage struct {
+	LogID     int64
 	ID        int64
 	LocalID   int64
 	Mutation  *pb.SignedEntry
 	ExtraData *pb.Committed
+	// UserID is the user the mutation was queued for.
+	UserID string
+}
+
+// Less reports whether m is applied to the map before o.
+// Log messages are applied in order of ID (queue timestamp), then LocalID, then LogID.
+func (m *LogMessage) Less(o *LogMessage) bool {
+	if m.ID != o.ID {
+		return m.ID < o.ID
+	}
+	if m.LocalID != o.LocalID {
+		return m.LocalID < o.LocalID
+	}
+	return m.LogID < o.LogID
+}
+
+// Rejection describes a log message that could not be applied to the map.
+type Rejection struct {
+	// LogID, ID, and LocalID identify the rejected log message.
+	LogID   int64
+	ID      int64
+	LocalID int64
+	// Index is the map index the log message was for.
+	Index []byte
+	// Revision is the map revision the log message was rejected from.
+	Revision int64
+	// Reason is the error returned by the mutation function.
+	Reason string
 }
@@ -0,0 +1,84 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultName is the name of the mutator used by directories that do not
+// specify one. It is registered by the entry package.
+const DefaultName = "entry"
+
+// Mutator is a named set of functions that define how a directory's log
+// messages change its map.
+type Mutator struct {
+	// MapLogItem converts log messages into index and mutation pairs.
+	MapLogItem MapLogItemFn
+	// Reduce applies a mutation to the existing value of a map leaf.
+	Reduce ReduceMutationFn
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Mutator)
+)
+
+// Register makes a mutator available under name.
+// Register is intended to be called from init functions and panics if name
+// is empty or already registered, or if m is incomplete.
+func Register(name string, m *Mutator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == "" {
+		panic("mutator: Register called with an empty name")
+	}
+	if m == nil || m.MapLogItem == nil || m.Reduce == nil {
+		panic(fmt.Sprintf("mutator: Register(%q) called with an incomplete mutator", name))
+	}
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("mutator: Register called twice for %q", name))
+	}
+	registry[name] = m
+}
+
+// Lookup returns the mutator registered under name.
+// The empty name refers to DefaultName.
+func Lookup(name string) (*Mutator, error) {
+	if name == "" {
+		name = DefaultName
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	m, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("mutator: unknown mutator %q", name)
+	}
+	return m, nil
+}
+
+// Names returns the sorted names of all registered mutators.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutator
+
+import (
+	"testing"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+)
+
+func appendOnly(oldValue, mutation *pb.SignedEntry) (*pb.SignedEntry, error) {
+	if oldValue != nil {
+		return nil, ErrReplay
+	}
+	return mutation, nil
+}
+
+func mapLogItem(m *LogMessage, emit func(index []byte, mutation *pb.EntryUpdate)) error {
+	emit(m.Mutation.GetEntry(), &pb.EntryUpdate{Mutation: m.Mutation})
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	Register(DefaultName, &Mutator{MapLogItem: mapLogItem, Reduce: appendOnly})
+	Register("appendonly", &Mutator{MapLogItem: mapLogItem, Reduce: appendOnly})
+
+	for _, tc := range []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: ""},
+		{name: DefaultName},
+		{name: "appendonly"},
+		{name: "unknown", wantErr: true},
+	} {
+		m, err := Lookup(tc.name)
+		if got, want := err != nil, tc.wantErr; got != want {
+			t.Errorf("Lookup(%q): %v, wantErr %v", tc.name, err, want)
+		}
+		if err == nil && m.Reduce == nil {
+			t.Errorf("Lookup(%q).Reduce: nil", tc.name)
+		}
+	}
+
+	for _, tc := range []struct {
+		desc string
+		name string
+		m    *Mutator
+	}{
+		{desc: "duplicate", name: "appendonly", m: &Mutator{MapLogItem: mapLogItem, Reduce: appendOnly}},
+		{desc: "empty name", m: &Mutator{MapLogItem: mapLogItem, Reduce: appendOnly}},
+		{desc: "incomplete", name: "incomplete", m: &Mutator{Reduce: appendOnly}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic", tc.name)
+				}
+			}()
+			Register(tc.name, tc.m)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sequencer
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/keytransparency/core/mutator"
+	"github.com/google/keytransparency/core/mutator/entry"
+
+	ktpb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+)
+
+// maxEventRevisions bounds the number of revisions whose events are sent
+// after each call to PublishRevisions, so that catching up after an outage
+// does not hold up sequencing.
+const maxEventRevisions = 100
+
+// eventQueueSize bounds the number of directories waiting for RunEvents to
+// send their events.
+const eventQueueSize = 1000
+
+// Event describes a change to a user's entry in a published revision.
+type Event struct {
+	DirectoryID string `json:"directory_id"`
+	Revision    int64  `json:"revision"`
+	UserID      string `json:"user_id"`
+	Index       []byte `json:"index"`
+	// OldCommitment is empty if the user did not exist before Revision.
+	OldCommitment []byte `json:"old_commitment,omitempty"`
+	NewCommitment []byte `json:"new_commitment"`
+}
+
+// EventSink delivers events to downstream systems.
+type EventSink interface {
+	// Send delivers the events of one revision. Send must only return nil
+	// once the events have been accepted. Events are sent again if Send
+	// fails, or if the sequencer restarts before recording their delivery,
+	// so receivers should deduplicate by directory, revision and index.
+	Send(ctx context.Context, events []*Event) error
+}
+
+// EventCursors stores how far events have been delivered for each directory.
+type EventCursors interface {
+	// ReadEventCursor returns the highest revision whose events have been
+	// delivered, or 0 if none have.
+	ReadEventCursor(ctx context.Context, directoryID string) (int64, error)
+	// WriteEventCursor records that the events up to and including revision
+	// have been delivered.
+	WriteEventCursor(ctx context.Context, directoryID string, revision int64) error
+}
+
+// queueEvents schedules the events of directoryID up to and including latest
+// to be sent by RunEvents, without waiting for them to be delivered. If the
+// queue is full the directory is skipped; its events are sent after a later
+// call, since delivery always resumes from the event cursor.
+func (s *Server) queueEvents(directoryID string, latest int64) {
+	s.eventsMu.Lock()
+	defer s.eventsMu.Unlock()
+	if queued, ok := s.eventsPending[directoryID]; ok {
+		if latest > queued {
+			s.eventsPending[directoryID] = latest
+		}
+		return
+	}
+	select {
+	case s.eventQueue <- directoryID:
+		s.eventsPending[directoryID] = latest
+	default:
+		glog.Warningf("Event queue full, deferring events of directory %v", directoryID)
+	}
+}
+
+// RunEvents sends the events queued by PublishRevisions until ctx is done.
+func (s *Server) RunEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case directoryID := <-s.eventQueue:
+			s.eventsMu.Lock()
+			latest := s.eventsPending[directoryID]
+			delete(s.eventsPending, directoryID)
+			s.eventsMu.Unlock()
+
+			// Events that fail to send are retried after the next revision
+			// is published.
+			mapClient, err := s.trillian.MapClient(ctx, directoryID)
+			if err != nil {
+				glog.Errorf("MapClient(%v): %v", directoryID, err)
+				continue
+			}
+			if err := s.sendEvents(ctx, directoryID, mapClient, latest); err != nil {
+				glog.Errorf("sendEvents(%v): %v", directoryID, err)
+			}
+		}
+	}
+}
+
+// sendEvents sends the events of revisions of directoryID after the event
+// cursor, up to and including latest, and advances the cursor after each
+// revision is delivered.
+func (s *Server) sendEvents(ctx context.Context, directoryID string, mapClient trillianMap, latest int64) error {
+	cursor, err := s.EventCursors.ReadEventCursor(ctx, directoryID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "ReadEventCursor(%v): %v", directoryID, err)
+	}
+	if cursor >= latest {
+		return nil
+	}
+	mut, err := s.mutatorFor(ctx, directoryID)
+	if err != nil {
+		return err
+	}
+	for rev := cursor + 1; rev <= latest && rev <= cursor+maxEventRevisions; rev++ {
+		events, err := s.revisionEvents(ctx, directoryID, rev, mut, mapClient)
+		if err != nil {
+			return err
+		}
+		if len(events) > 0 {
+			if err := s.EventSink.Send(ctx, events); err != nil {
+				return status.Errorf(codes.Unavailable, "EventSink.Send(rev: %v): %v", rev, err)
+			}
+			eventsSent.Add(float64(len(events)), directoryID)
+		}
+		if err := s.EventCursors.WriteEventCursor(ctx, directoryID, rev); err != nil {
+			return status.Errorf(codes.Internal, "WriteEventCursor(%v, %v): %v", directoryID, rev, err)
+		}
+	}
+	return nil
+}
+
+// revisionEvents returns an event for each map leaf that changed in rev.
+func (s *Server) revisionEvents(ctx context.Context, directoryID string, rev int64,
+	mut *mutator.Mutator, mapClient trillianMap) ([]*Event, error) {
+	meta, err := s.batcher.ReadBatch(ctx, directoryID, rev)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ReadBatch(%v, %v): %v", directoryID, rev, err)
+	}
+	msgs, err := s.readMessages(ctx, directoryID, meta, s.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the indexes that were mutated and the users they belong to.
+	userIDs := make(map[string]string)
+	indexes := make([][]byte, 0, len(msgs))
+	for _, m := range msgs {
+		if err := mut.MapLogItem(m, func(index []byte, _ *ktpb.EntryUpdate) {
+			if _, ok := userIDs[string(index)]; !ok {
+				indexes = append(indexes, index)
+			}
+			userIDs[string(index)] = m.UserID
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+
+	oldLeaves, err := mapClient.GetAndVerifyMapLeavesByRevision(ctx, rev-1, indexes)
+	if err != nil {
+		return nil, err
+	}
+	oldValues := make(map[string][]byte, len(oldLeaves))
+	for _, l := range oldLeaves {
+		oldValues[string(l.Index)] = l.LeafValue
+	}
+	newLeaves, err := mapClient.GetAndVerifyMapLeavesByRevision(ctx, rev, indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*Event, 0, len(newLeaves))
+	for _, l := range newLeaves {
+		oldValue := oldValues[string(l.Index)]
+		if bytes.Equal(oldValue, l.LeafValue) {
+			continue // Every mutation for this index was rejected.
+		}
+		oldCommitment, err := commitment(oldValue)
+		if err != nil {
+			return nil, err
+		}
+		newCommitment, err := commitment(l.LeafValue)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &Event{
+			DirectoryID:   directoryID,
+			Revision:      rev,
+			UserID:        userIDs[string(l.Index)],
+			Index:         l.Index,
+			OldCommitment: oldCommitment,
+			NewCommitment: newCommitment,
+		})
+	}
+	return events, nil
+}
+
+// commitment returns the commitment in a map leaf value, or nil if the leaf is empty.
+func commitment(leafValue []byte) ([]byte, error) {
+	signed, err := entry.FromLeafValue(leafValue)
+	if err != nil || signed == nil {
+		return nil, err
+	}
+	var e ktpb.Entry
+	if err := proto.Unmarshal(signed.GetEntry(), &e); err != nil {
+		return nil, status.Errorf(codes.Internal, "proto.Unmarshal(entry): %v", err)
+	}
+	return e.GetCommitment(), nil
+}
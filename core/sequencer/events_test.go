@@ -0,0 +1,235 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/trillian/monitoring"
+
+	"github.com/google/keytransparency/core/directory"
+	"github.com/google/keytransparency/core/mutator"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
+	tpb "github.com/google/trillian"
+)
+
+type fakeDirectories struct {
+	directory.Storage
+}
+
+func (fakeDirectories) Read(_ context.Context, directoryID string, _ bool) (*directory.Directory, error) {
+	return &directory.Directory{DirectoryID: directoryID}, nil
+}
+
+// oneMessageBatcher defines revision rev as log message rev-1 of log 0.
+type oneMessageBatcher struct{ fakeBatcher }
+
+func (oneMessageBatcher) ReadBatch(_ context.Context, _ string, rev int64) (*spb.MapMetadata, error) {
+	return &spb.MapMetadata{Sources: []*spb.MapMetadata_SourceSlice{
+		{LogId: 0, LowestInclusive: rev - 1, HighestExclusive: rev},
+	}}, nil
+}
+
+// revisionMap stores the leaf values of each revision by index.
+type revisionMap struct {
+	MapClient
+	revs map[int64]map[string][]byte
+}
+
+func (m *revisionMap) GetAndVerifyMapLeavesByRevision(_ context.Context, rev int64, indexes [][]byte) ([]*tpb.MapLeaf, error) {
+	leaves := make([]*tpb.MapLeaf, 0, len(indexes))
+	for _, index := range indexes {
+		leaves = append(leaves, &tpb.MapLeaf{Index: index, LeafValue: m.revs[rev][string(index)]})
+	}
+	return leaves, nil
+}
+
+// flakySink fails the first failures calls to Send.
+type flakySink struct {
+	failures int
+	events   []*Event
+}
+
+func (s *flakySink) Send(_ context.Context, events []*Event) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+type fakeCursors map[string]int64
+
+func (c fakeCursors) ReadEventCursor(_ context.Context, directoryID string) (int64, error) {
+	return c[directoryID], nil
+}
+
+func (c fakeCursors) WriteEventCursor(_ context.Context, directoryID string, rev int64) error {
+	c[directoryID] = rev
+	return nil
+}
+
+func queued(t *testing.T, userID string, index []byte) mutator.LogMessage {
+	t.Helper()
+	e, err := proto.Marshal(&pb.Entry{Index: index})
+	if err != nil {
+		t.Fatalf("proto.Marshal(): %v", err)
+	}
+	return mutator.LogMessage{UserID: userID, Mutation: &pb.SignedEntry{Entry: e}}
+}
+
+func leafValue(t *testing.T, index, commitment []byte) []byte {
+	t.Helper()
+	e, err := proto.Marshal(&pb.Entry{Index: index, Commitment: commitment})
+	if err != nil {
+		t.Fatalf("proto.Marshal(): %v", err)
+	}
+	v, err := proto.Marshal(&pb.SignedEntry{Entry: e})
+	if err != nil {
+		t.Fatalf("proto.Marshal(): %v", err)
+	}
+	return v
+}
+
+func TestSendEvents(t *testing.T) {
+	ctx := context.Background()
+	initMetrics.Do(func() { createMetrics(monitoring.InertMetricFactory{}) })
+	alice, bob := []byte("alice"), []byte("bob")
+	a1, a2 := leafValue(t, alice, []byte("a1")), leafValue(t, alice, []byte("a2"))
+	b1 := leafValue(t, bob, []byte("b1"))
+
+	sink := &flakySink{failures: 1}
+	cursors := fakeCursors{}
+	s := &Server{
+		directories: fakeDirectories{},
+		batcher:     &oneMessageBatcher{},
+		logs: fakeLogs{0: {
+			queued(t, "alice", alice), // Creates alice.
+			queued(t, "alice", alice), // Is rejected.
+			queued(t, "bob", bob),     // Creates bob.
+			queued(t, "alice", alice), // Updates alice.
+		}},
+		BatchSize:    10,
+		EventSink:    sink,
+		EventCursors: cursors,
+	}
+	mapClient := &revisionMap{revs: map[int64]map[string][]byte{
+		1: {"alice": a1},
+		2: {"alice": a1},
+		3: {"alice": a1, "bob": b1},
+		4: {"alice": a2, "bob": b1},
+	}}
+
+	for _, tc := range []struct {
+		desc       string
+		latest     int64
+		wantErr    bool
+		wantCursor int64
+		want       []*Event
+	}{
+		{desc: "sink unavailable", latest: 2, wantErr: true, wantCursor: 0},
+		{desc: "retried", latest: 2, wantCursor: 2, want: []*Event{
+			{DirectoryID: directoryID, Revision: 1, UserID: "alice", Index: alice, NewCommitment: []byte("a1")},
+		}},
+		{desc: "caught up", latest: 2, wantCursor: 2},
+		{desc: "more revisions", latest: 4, wantCursor: 4, want: []*Event{
+			{DirectoryID: directoryID, Revision: 3, UserID: "bob", Index: bob, NewCommitment: []byte("b1")},
+			{DirectoryID: directoryID, Revision: 4, UserID: "alice", Index: alice,
+				OldCommitment: []byte("a1"), NewCommitment: []byte("a2")},
+		}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			sink.events = nil
+			err := s.sendEvents(ctx, directoryID, mapClient, tc.latest)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("sendEvents(): %v, wantErr %v", err, tc.wantErr)
+			}
+			if got := cursors[directoryID]; got != tc.wantCursor {
+				t.Errorf("cursor: %v, want %v", got, tc.wantCursor)
+			}
+			if !cmp.Equal(sink.events, tc.want) {
+				t.Errorf("sendEvents(): sent %v, want %v", sink.events, tc.want)
+			}
+		})
+	}
+}
+
+func TestQueueEvents(t *testing.T) {
+	s := &Server{
+		eventsPending: make(map[string]int64),
+		eventQueue:    make(chan string, 1),
+	}
+	s.queueEvents("a", 1)
+	s.queueEvents("a", 3) // Coalesced with the queued request.
+	s.queueEvents("a", 2)
+	s.queueEvents("b", 2) // Deferred, the queue is full.
+
+	if got, want := s.eventsPending, map[string]int64{"a": 3}; !cmp.Equal(got, want) {
+		t.Errorf("eventsPending: %v, want %v", got, want)
+	}
+	if got, want := len(s.eventQueue), 1; got != want {
+		t.Errorf("len(eventQueue): %v, want %v", got, want)
+	}
+}
+
+// chanSink passes the events it is sent to a channel.
+type chanSink chan []*Event
+
+func (c chanSink) Send(_ context.Context, events []*Event) error {
+	c <- events
+	return nil
+}
+
+func TestRunEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initMetrics.Do(func() { createMetrics(monitoring.InertMetricFactory{}) })
+	alice := []byte("alice")
+
+	sink := make(chanSink)
+	s := &Server{
+		directories: fakeDirectories{},
+		batcher:     &oneMessageBatcher{},
+		logs:        fakeLogs{0: {queued(t, "alice", alice)}},
+		trillian: &fakeTrillianFactory{tmap: &revisionMap{revs: map[int64]map[string][]byte{
+			1: {"alice": leafValue(t, alice, []byte("a1"))},
+		}}},
+		BatchSize:     10,
+		EventSink:     sink,
+		EventCursors:  fakeCursors{},
+		eventsPending: make(map[string]int64),
+		eventQueue:    make(chan string, eventQueueSize),
+	}
+	go s.RunEvents(ctx)
+	s.queueEvents(directoryID, 1)
+
+	select {
+	case got := <-sink:
+		want := []*Event{{DirectoryID: directoryID, Revision: 1, UserID: "alice", Index: alice, NewCommitment: []byte("a1")}}
+		if !cmp.Equal(got, want) {
+			t.Errorf("RunEvents(): sent %v, want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunEvents(): no events sent")
+	}
+}
@@ -46,10 +46,15 @@ func MapUpdateFn(msg *pb.EntryUpdate) (*IndexedUpdate, error) {
 	}, nil
 }
 
-// ReduceFn decides which of multiple updates can be applied in this revision.
+// ReduceFn applies each of msgs to the leaf at index in turn, starting from the
+// value in leaves. msgs must be in canonical order, as defined by mutator.LogMessage.Less.
+// Invalid mutations are skipped, and subsequent mutations are applied to the
+// result of the last valid one. Invalid mutations are passed to emitErr.
+// Nothing is emitted if no mutation is valid.
 // TODO(gbelvin): Move to mutator interface.
 func ReduceFn(mutatorFn mutator.ReduceMutationFn,
-	index []byte, leaves []*tpb.MapLeaf, msgs []*pb.EntryUpdate, emit func(*tpb.MapLeaf)) error {
+	index []byte, leaves []*tpb.MapLeaf, msgs []*pb.EntryUpdate,
+	emit func(*tpb.MapLeaf), emitErr func(*pb.EntryUpdate, error)) error {
 	if got := len(leaves); got > 1 {
 		return fmt.Errorf("expected 0 or 1 map leaf for index %x, got %v", index, got)
 	}
@@ -66,23 +71,33 @@ func ReduceFn(mutatorFn mutator.ReduceMutationFn,
 		return fmt.Errorf("no msgs for index %x", index)
 	}
 
-	// TODO(gbelvin): Choose the mutation deterministically, regardless of the messages order.
-	// (optional): Select the mutation based on it's correctness.
-	msg := msgs[0]
-	newValue, err := mutatorFn(oldValue, msg.Mutation)
-	if err != nil {
-		glog.Warningf("Mutate(): %v", err)
-		return nil // A bad mutation should not make the whole batch to fail.
+	newValue := oldValue
+	var last *pb.EntryUpdate // The last mutation that was successfully applied.
+	for _, msg := range msgs {
+		value, err := mutatorFn(newValue, msg.Mutation)
+		if err != nil {
+			glog.Warningf("Mutate(): %v", err)
+			emitErr(msg, err)
+			continue // A bad mutation should not make the whole batch to fail.
+		}
+		newValue, last = value, msg
+	}
+	if last == nil {
+		return nil // No valid mutations for this index.
 	}
+
 	leafValue, err := entry.ToLeafValue(newValue)
 	if err != nil {
 		glog.Warningf("ToLeafValue(): %v", err)
 		return nil // A bad mutation should not cause the entire pipeline to fail.
 	}
-	extraData, err := proto.Marshal(msg.Committed)
-	if err != nil {
-		glog.Warningf("proto.Marshal(): %v", err)
-		return nil
+	var extraData []byte
+	if last.Committed != nil {
+		extraData, err = proto.Marshal(last.Committed)
+		if err != nil {
+			glog.Warningf("proto.Marshal(): %v", err)
+			return nil
+		}
 	}
 	emit(&tpb.MapLeaf{Index: index, LeafValue: leafValue, ExtraData: extraData})
 	return nil
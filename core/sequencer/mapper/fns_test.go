@@ -0,0 +1,98 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapper
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/keytransparency/core/mutator"
+	"github.com/google/keytransparency/core/mutator/entry"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	tpb "github.com/google/trillian"
+)
+
+// fakeMutate accepts mutations whose first signature equals the entry of the
+// existing value.
+func fakeMutate(oldValue, mutation *pb.SignedEntry) (*pb.SignedEntry, error) {
+	if !bytes.Equal(mutation.GetSignatures()[0], oldValue.GetEntry()) {
+		return nil, mutator.ErrPreviousHash
+	}
+	return mutation, nil
+}
+
+// update returns an EntryUpdate that changes the entry from prev to next.
+func update(prev, next string) *pb.EntryUpdate {
+	return &pb.EntryUpdate{
+		Mutation:  &pb.SignedEntry{Entry: []byte(next), Signatures: [][]byte{[]byte(prev)}},
+		Committed: &pb.Committed{Data: []byte(next)},
+	}
+}
+
+func TestReduceFn(t *testing.T) {
+	index := []byte("index")
+	existing, err := entry.ToLeafValue(&pb.SignedEntry{Entry: []byte("a")})
+	if err != nil {
+		t.Fatalf("ToLeafValue(): %v", err)
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		leaves   []*tpb.MapLeaf
+		msgs     []*pb.EntryUpdate
+		want     string // Empty if nothing should be emitted.
+		rejected int
+	}{
+		{desc: "first", msgs: []*pb.EntryUpdate{update("", "a")}, want: "a"},
+		{desc: "chain", msgs: []*pb.EntryUpdate{update("", "a"), update("a", "b")}, want: "b"},
+		{desc: "skip invalid", msgs: []*pb.EntryUpdate{update("x", "y"), update("", "a"), update("a", "b")}, want: "b", rejected: 1},
+		{desc: "out of order", msgs: []*pb.EntryUpdate{update("a", "b"), update("", "a")}, want: "a", rejected: 1},
+		{desc: "conflict", msgs: []*pb.EntryUpdate{update("", "a"), update("", "b")}, want: "a", rejected: 1},
+		{desc: "existing leaf", leaves: []*tpb.MapLeaf{{Index: index, LeafValue: existing}},
+			msgs: []*pb.EntryUpdate{update("a", "b"), update("b", "c")}, want: "c"},
+		{desc: "all invalid", leaves: []*tpb.MapLeaf{{Index: index, LeafValue: existing}},
+			msgs: []*pb.EntryUpdate{update("", "b")}, rejected: 1},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var got []*tpb.MapLeaf
+			var rejected int
+			if err := ReduceFn(fakeMutate, index, tc.leaves, tc.msgs,
+				func(l *tpb.MapLeaf) { got = append(got, l) },
+				func(*pb.EntryUpdate, error) { rejected++ }); err != nil {
+				t.Fatalf("ReduceFn(): %v", err)
+			}
+			if rejected != tc.rejected {
+				t.Errorf("ReduceFn(): rejected %v mutations, want %v", rejected, tc.rejected)
+			}
+			if tc.want == "" {
+				if len(got) != 0 {
+					t.Fatalf("ReduceFn(): emitted %v, want nothing", got)
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("ReduceFn(): emitted %v leaves, want 1", len(got))
+			}
+			value, err := entry.FromLeafValue(got[0].LeafValue)
+			if err != nil {
+				t.Fatalf("FromLeafValue(): %v", err)
+			}
+			if got, want := string(value.GetEntry()), tc.want; got != want {
+				t.Errorf("ReduceFn(): entry %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sequencer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/keytransparency/core/mutator"
+
+	ktpb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
+	tpb "github.com/google/trillian"
+)
+
+// rebuildStratumDepth is the depth of the subtrees whose roots
+// rootCalculator keeps in memory. It bounds the calculator to fewer than
+// 2^(rebuildStratumDepth+1) cached nodes, in addition to the leaf indexes it
+// records. It must be a multiple of 8.
+const rebuildStratumDepth = 16
+
+// rootCalculator computes the root hashes that a map with a given tree ID
+// would have after successive sets of leaves are written to it.
+//
+// Map hash strategies such as CONIKS include the tree ID in every node, so the
+// root hashes of a rebuilt map differ from those of the original map even if
+// their contents are identical. rootCalculator recomputes the roots for the
+// original tree ID so that they can be compared with the published roots.
+//
+// Only the nodes above depth stratumDepth are kept in memory, along with the
+// indexes of the leaves that have been written, so memory grows with the
+// number of distinct indexes in the map rather than with the size of their
+// values. The root of each subtree at stratumDepth that a revision modifies
+// is recomputed from the leaves that the rebuilt map holds at that revision,
+// so the roots also verify what the rebuilt map stores.
+type rootCalculator struct {
+	treeID       int64
+	hasher       hashers.MapHasher
+	hs2          merkle.HStar2
+	stratumDepth int
+	// top holds the nodes at stratumDepth and above.
+	top map[string][]byte
+	// indexes holds the written leaf indexes by subtree prefix.
+	indexes map[string][][]byte
+	// written holds the written leaf indexes.
+	written map[string]bool
+}
+
+func newRootCalculator(tree *tpb.Tree) (*rootCalculator, error) {
+	hasher, err := hashers.NewMapHasher(tree.GetHashStrategy())
+	if err != nil {
+		return nil, err
+	}
+	return &rootCalculator{
+		treeID:       tree.GetTreeId(),
+		hasher:       hasher,
+		hs2:          merkle.NewHStar2(tree.GetTreeId(), hasher),
+		stratumDepth: rebuildStratumDepth,
+		top:          make(map[string][]byte),
+		indexes:      make(map[string][][]byte),
+		written:      make(map[string]bool),
+	}, nil
+}
+
+func nodeKey(depth int, index *big.Int) string {
+	return fmt.Sprintf("%d/%x", depth, index.Bytes())
+}
+
+// add records that index has been written and returns its subtree prefix.
+func (c *rootCalculator) add(index []byte) string {
+	prefix := string(index[:c.stratumDepth/8])
+	if c.written[string(index)] {
+		return prefix
+	}
+	c.written[string(index)] = true
+	c.indexes[prefix] = append(c.indexes[prefix], index)
+	return prefix
+}
+
+// update returns the root hash of the map at rev, after the leaves at
+// indexes were written to it. Leaves are read from the rebuilt map m.
+func (c *rootCalculator) update(ctx context.Context, m trillianMap, rev int64, indexes [][]byte) ([]byte, error) {
+	modified := make(map[string]bool)
+	for _, index := range indexes {
+		modified[c.add(index)] = true
+	}
+	subtrees := make([]merkle.HStar2LeafHash, 0, len(modified))
+	for prefix := range modified {
+		root, err := c.subtreeRoot(ctx, m, rev, []byte(prefix))
+		if err != nil {
+			return nil, err
+		}
+		offset := make([]byte, c.hasher.Size())
+		copy(offset, prefix)
+		index := new(big.Int).SetBytes(offset)
+		c.top[nodeKey(c.stratumDepth, index)] = root
+		subtrees = append(subtrees, merkle.HStar2LeafHash{Index: index, LeafHash: root})
+	}
+	return c.hs2.HStar2Nodes([]byte{}, c.stratumDepth, subtrees,
+		func(depth int, index *big.Int) ([]byte, error) {
+			return c.top[nodeKey(depth, index)], nil
+		},
+		func(depth int, index *big.Int, hash []byte) error {
+			c.top[nodeKey(depth, index)] = hash
+			return nil
+		})
+}
+
+// subtreeRoot computes the root of the subtree below prefix at rev from the
+// leaves that the rebuilt map m holds.
+func (c *rootCalculator) subtreeRoot(ctx context.Context, m trillianMap, rev int64, prefix []byte) ([]byte, error) {
+	leaves, err := m.GetAndVerifyMapLeavesByRevision(ctx, rev, c.indexes[string(prefix)])
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GetAndVerifyMapLeavesByRevision(%v): %v", rev, err)
+	}
+	values := make([]merkle.HStar2LeafHash, 0, len(leaves))
+	for _, l := range leaves {
+		if len(l.LeafValue) == 0 {
+			continue // Not set at rev.
+		}
+		leafHash, err := c.hasher.HashLeaf(c.treeID, l.Index, l.LeafValue)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, merkle.HStar2LeafHash{Index: new(big.Int).SetBytes(l.Index), LeafHash: leafHash})
+	}
+	return c.hs2.HStar2Nodes(prefix, c.hasher.BitLen()-c.stratumDepth, values, nil, nil)
+}
+
+// StartRebuild starts replaying every published revision of directoryID into
+// a map in the background, and returns its initial progress. If mapID is 0, a
+// new map is created. Otherwise the rebuild resumes at the latest revision of
+// map mapID, which an earlier call must have created. The root hash and
+// metadata of each revision are compared with the map root published in the
+// log of map roots, and the rebuild stops at the first revision that does not
+// match. Revisions whose mutations have been pruned from the queue cannot be
+// rebuilt and are reported as diverging.
+//
+// The directory keeps using its original map. Swapping in the new map is left
+// to the operator.
+func (s *Server) StartRebuild(ctx context.Context, directoryID string, mapID int64) (*ktpb.MapRebuild, error) {
+	d, err := s.directories.Read(ctx, directoryID, false)
+	if err != nil {
+		glog.Errorf("directories.Read(%v): %v", directoryID, err)
+		return nil, status.Errorf(codes.Internal, "Cannot fetch directory info")
+	}
+	mut, err := mutator.Lookup(d.Mutator)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "directory %v: %v", directoryID, err)
+	}
+	calc, err := newRootCalculator(d.Map)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "directory %v: %v", directoryID, err)
+	}
+	logClient, err := s.trillian.LogClient(ctx, directoryID)
+	if err != nil {
+		return nil, err
+	}
+	logRoot, err := logClient.UpdateRoot(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "UpdateRoot(): %v", err)
+	}
+	if logRoot.TreeSize == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "directory %v has no published revisions", directoryID)
+	}
+
+	var tree *tpb.Tree
+	var mapClient trillianMap
+	var start int64
+	if mapID == 0 {
+		if tree, mapClient, err = s.trillian.NewMap(ctx, directoryID); err != nil {
+			return nil, err
+		}
+	} else {
+		if tree, mapClient, err = s.trillian.OpenMap(ctx, directoryID, mapID); err != nil {
+			return nil, err
+		}
+		// The latest revision may have been written but not checked.
+		_, root, err := mapClient.GetAndVerifyLatestMapRoot(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "GetAndVerifyLatestMapRoot(): %v", err)
+		}
+		start = int64(root.Revision)
+	}
+
+	r := &ktpb.MapRebuild{
+		DirectoryId: directoryID,
+		MapId:       tree.TreeId,
+		Revision:    start - 1,
+		Target:      int64(logRoot.TreeSize) - 1,
+	}
+	// The rebuild outlives the request that started it, until it is
+	// canceled with CancelRebuild.
+	rctx, cancel := context.WithCancel(context.Background())
+	s.rebuildMu.Lock()
+	if running, ok := s.rebuilds[r.MapId]; ok && !running.Done {
+		s.rebuildMu.Unlock()
+		cancel()
+		return nil, status.Errorf(codes.FailedPrecondition, "map %v is already being rebuilt", r.MapId)
+	}
+	if s.rebuilds == nil {
+		s.rebuilds = make(map[int64]*ktpb.MapRebuild)
+		s.rebuildCancels = make(map[int64]context.CancelFunc)
+	}
+	s.rebuilds[r.MapId] = r
+	s.rebuildCancels[r.MapId] = cancel
+	resp := proto.Clone(r).(*ktpb.MapRebuild)
+	s.rebuildMu.Unlock()
+
+	glog.Infof("RebuildMap(%v): replaying revisions %v to %v into map %v", directoryID, start, r.Target, r.MapId)
+	go func() {
+		div, err := s.rebuild(rctx, r.MapId, directoryID, mut, calc, logClient, mapClient, start, resp.Target)
+		s.rebuildMu.Lock()
+		defer s.rebuildMu.Unlock()
+		cancel()
+		delete(s.rebuildCancels, r.MapId)
+		r.Done = true
+		r.Divergence = div
+		if rctx.Err() == context.Canceled {
+			glog.Infof("RebuildMap(%v): map %v canceled after revision %v", directoryID, r.MapId, r.Revision)
+			r.Error = "canceled"
+			return
+		}
+		if err != nil {
+			glog.Errorf("RebuildMap(%v): map %v: %v", directoryID, r.MapId, err)
+			r.Error = err.Error()
+			return
+		}
+		glog.Infof("RebuildMap(%v): replayed revisions %v to %v into map %v", directoryID, start, r.Revision, r.MapId)
+	}()
+	return resp, nil
+}
+
+// RebuildStatus returns the progress of the rebuild of directoryID into mapID.
+// Only rebuilds started since this server started are known.
+func (s *Server) RebuildStatus(directoryID string, mapID int64) (*ktpb.MapRebuild, error) {
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+	r, ok := s.rebuilds[mapID]
+	if !ok || r.DirectoryId != directoryID {
+		return nil, status.Errorf(codes.NotFound, "no rebuild of map %v for directory %v is known", mapID, directoryID)
+	}
+	return proto.Clone(r).(*ktpb.MapRebuild), nil
+}
+
+// CancelRebuild stops the rebuild of directoryID into mapID, and returns its
+// progress. The rebuild stops after the revision it is replaying, and is
+// reported as done once it has stopped.
+func (s *Server) CancelRebuild(directoryID string, mapID int64) (*ktpb.MapRebuild, error) {
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+	r, ok := s.rebuilds[mapID]
+	if !ok || r.DirectoryId != directoryID {
+		return nil, status.Errorf(codes.NotFound, "no rebuild of map %v for directory %v is known", mapID, directoryID)
+	}
+	if cancel, ok := s.rebuildCancels[mapID]; ok {
+		cancel()
+	}
+	return proto.Clone(r).(*ktpb.MapRebuild), nil
+}
+
+// rebuild replays revisions start through target of directoryID into
+// mapClient, recording progress in s.rebuilds[mapID]. It returns a Divergence
+// if a revision does not match the log of map roots.
+func (s *Server) rebuild(ctx context.Context, mapID int64, directoryID string, mut *mutator.Mutator,
+	calc *rootCalculator, logClient trillianLog, mapClient trillianMap, start, target int64) (*ktpb.Divergence, error) {
+	// Recompute the root of the revision to start from.
+	var indexes [][]byte
+	for rev := int64(1); rev <= start; rev++ {
+		meta, err := s.batcher.ReadBatch(ctx, directoryID, rev)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "ReadBatch(%v, %v): %v", directoryID, rev, err)
+		}
+		msgs, err := s.readMessages(ctx, directoryID, meta, s.BatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			if err := mut.MapLogItem(m, func(index []byte, _ *ktpb.EntryUpdate) {
+				indexes = append(indexes, index)
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	meta := &spb.MapMetadata{}
+	if start > 0 {
+		var err error
+		if meta, err = s.batcher.ReadBatch(ctx, directoryID, start); err != nil {
+			return nil, status.Errorf(codes.Internal, "ReadBatch(%v, %v): %v", directoryID, start, err)
+		}
+	}
+	rootHash, err := calc.update(ctx, mapClient, start, indexes)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "root of revision %v: %v", start, err)
+	}
+	if div, err := s.checkRevision(ctx, logClient, start, rootHash, meta); err != nil || div != nil {
+		return div, err
+	}
+	s.rebuilt(mapID, start)
+
+	for rev := start + 1; rev <= target; rev++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		meta, err := s.batcher.ReadBatch(ctx, directoryID, rev)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "ReadBatch(%v, %v): %v", directoryID, rev, err)
+		}
+		r, err := s.computeRevision(ctx, directoryID, rev, meta, mut, mapClient)
+		if err != nil {
+			return nil, err
+		}
+		metadata, err := proto.Marshal(meta)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := mapClient.SetLeavesAtRevision(ctx, rev, r.leaves, metadata); err != nil {
+			return nil, status.Errorf(codes.Internal, "SetLeavesAtRevision(%v): %v", rev, err)
+		}
+
+		indexes := make([][]byte, 0, len(r.leaves))
+		for _, l := range r.leaves {
+			indexes = append(indexes, l.Index)
+		}
+		rootHash, err := calc.update(ctx, mapClient, rev, indexes)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "root of revision %v: %v", rev, err)
+		}
+		if div, err := s.checkRevision(ctx, logClient, rev, rootHash, meta); err != nil || div != nil {
+			return div, err
+		}
+		s.rebuilt(mapID, rev)
+	}
+	return nil, nil
+}
+
+// rebuilt records that rev of mapID matches the log of map roots.
+func (s *Server) rebuilt(mapID, rev int64) {
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+	s.rebuilds[mapID].Revision = rev
+}
+
+// checkRevision compares a rebuilt revision with the map root published at
+// rev in the log of map roots. It returns a Divergence if they differ.
+func (s *Server) checkRevision(ctx context.Context, logClient trillianLog, rev int64,
+	rootHash []byte, meta *spb.MapMetadata) (*ktpb.Divergence, error) {
+	leaves, err := logClient.ListByIndex(ctx, rev, 1)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListByIndex(%v): %v", rev, err)
+	}
+	if len(leaves) != 1 {
+		return nil, status.Errorf(codes.Internal, "ListByIndex(%v): %v leaves, want 1", rev, len(leaves))
+	}
+	var published types.MapRootV1
+	if err := published.UnmarshalBinary(leaves[0].LeafValue); err != nil {
+		return nil, status.Errorf(codes.Internal, "MapRootV1.UnmarshalBinary(): %v", err)
+	}
+	var publishedMeta spb.MapMetadata
+	if err := proto.Unmarshal(published.Metadata, &publishedMeta); err != nil {
+		return nil, status.Errorf(codes.Internal, "proto.Unmarshal(metadata): %v", err)
+	}
+
+	div := &ktpb.Divergence{
+		Revision:          rev,
+		PublishedRootHash: published.RootHash,
+		RebuiltRootHash:   rootHash,
+	}
+	switch {
+	case int64(published.Revision) != rev:
+		div.Reason = fmt.Sprintf("log of map roots has revision %v at index %v", published.Revision, rev)
+	case !proto.Equal(&publishedMeta, meta):
+		div.Reason = fmt.Sprintf("batch definition %v does not match published metadata %v", meta, &publishedMeta)
+	case !bytes.Equal(published.RootHash, rootHash):
+		div.Reason = "root hash does not match"
+	default:
+		return nil, nil
+	}
+	glog.Warningf("RebuildMap(): revision %v diverges: %v", rev, div.Reason)
+	return div, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sequencer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/hashers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ktpb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	tpb "github.com/google/trillian"
+	_ "github.com/google/trillian/merkle/coniks" // Register hasher
+)
+
+func leaf(i int, value string) *tpb.MapLeaf {
+	index := sha256.Sum256([]byte(fmt.Sprintf("user%d", i)))
+	return &tpb.MapLeaf{Index: index[:], LeafValue: []byte(value)}
+}
+
+func TestRootCalculator(t *testing.T) {
+	ctx := context.Background()
+	tree := &tpb.Tree{TreeId: 3, HashStrategy: tpb.HashStrategy_CONIKS_SHA256}
+	hasher, err := hashers.NewMapHasher(tree.HashStrategy)
+	if err != nil {
+		t.Fatalf("NewMapHasher(): %v", err)
+	}
+	revs := []struct {
+		desc   string
+		leaves []*tpb.MapLeaf
+	}{
+		{desc: "empty"},
+		{desc: "one", leaves: []*tpb.MapLeaf{leaf(0, "a")}},
+		{desc: "more", leaves: []*tpb.MapLeaf{leaf(1, "b"), leaf(2, "c"), leaf(3, "d")}},
+		{desc: "overwrite", leaves: []*tpb.MapLeaf{leaf(0, "e"), leaf(4, "f")}},
+		{desc: "no changes"},
+	}
+	// The rebuilt map holds the leaves of every revision.
+	m := &revisionMap{revs: make(map[int64]map[string][]byte)}
+	state := make(map[string][]byte)
+	for rev, tc := range revs {
+		for _, l := range tc.leaves {
+			state[string(l.Index)] = l.LeafValue
+		}
+		m.revs[int64(rev)] = make(map[string][]byte)
+		for index, value := range state {
+			m.revs[int64(rev)][index] = value
+		}
+	}
+
+	// Subtrees are shared by every leaf at depth 0 and by none at depth 16.
+	for _, depth := range []int{0, 8, 16} {
+		calc, err := newRootCalculator(tree)
+		if err != nil {
+			t.Fatalf("newRootCalculator(): %v", err)
+		}
+		calc.stratumDepth = depth
+		for rev, tc := range revs {
+			t.Run(fmt.Sprintf("depth %v/%v", depth, tc.desc), func(t *testing.T) {
+				indexes := make([][]byte, 0, len(tc.leaves))
+				for _, l := range tc.leaves {
+					indexes = append(indexes, l.Index)
+				}
+				got, err := calc.update(ctx, m, int64(rev), indexes)
+				if err != nil {
+					t.Fatalf("update(): %v", err)
+				}
+
+				// Compute the expected root from scratch.
+				values := make([]merkle.HStar2LeafHash, 0, len(m.revs[int64(rev)]))
+				for index, value := range m.revs[int64(rev)] {
+					leafHash, err := hasher.HashLeaf(tree.TreeId, []byte(index), value)
+					if err != nil {
+						t.Fatalf("HashLeaf(): %v", err)
+					}
+					values = append(values, merkle.HStar2LeafHash{
+						Index:    new(big.Int).SetBytes([]byte(index)),
+						LeafHash: leafHash,
+					})
+				}
+				hs2 := merkle.NewHStar2(tree.TreeId, hasher)
+				want, err := hs2.HStar2Root(hasher.BitLen(), values)
+				if err != nil {
+					t.Fatalf("HStar2Root(): %v", err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("update(): %x, want %x", got, want)
+				}
+			})
+		}
+		if got, max := len(calc.top), 1<<uint(depth+1); got >= max {
+			t.Errorf("depth %v: %v nodes cached, want < %v", depth, got, max)
+		}
+	}
+
+	// The same leaves produce a different root for another tree ID.
+	last := int64(len(revs) - 1)
+	roots := make(map[int64][]byte)
+	for _, treeID := range []int64{3, 4} {
+		calc, err := newRootCalculator(&tpb.Tree{TreeId: treeID, HashStrategy: tree.HashStrategy})
+		if err != nil {
+			t.Fatalf("newRootCalculator(): %v", err)
+		}
+		indexes := make([][]byte, 0, len(state))
+		for index := range state {
+			indexes = append(indexes, []byte(index))
+		}
+		if roots[treeID], err = calc.update(ctx, m, last, indexes); err != nil {
+			t.Fatalf("update(): %v", err)
+		}
+	}
+	if bytes.Equal(roots[3], roots[4]) {
+		t.Errorf("update(): roots for tree 3 and 4 are both %x", roots[3])
+	}
+}
+
+func TestCancelRebuild(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &Server{
+		rebuilds:       map[int64]*ktpb.MapRebuild{5: {DirectoryId: "dir", MapId: 5}},
+		rebuildCancels: map[int64]context.CancelFunc{5: cancel},
+	}
+	for _, tc := range []struct {
+		desc        string
+		directoryID string
+		mapID       int64
+		wantCode    codes.Code
+	}{
+		{desc: "unknown map", directoryID: "dir", mapID: 6, wantCode: codes.NotFound},
+		{desc: "other directory", directoryID: "other", mapID: 5, wantCode: codes.NotFound},
+		{desc: "running", directoryID: "dir", mapID: 5},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := s.CancelRebuild(tc.directoryID, tc.mapID)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Errorf("CancelRebuild(): %v, want %v", err, tc.wantCode)
+			}
+		})
+	}
+	if ctx.Err() != context.Canceled {
+		t.Errorf("rebuild context: %v, want %v", ctx.Err(), context.Canceled)
+	}
+}
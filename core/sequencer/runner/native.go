@@ -16,6 +16,9 @@
 package runner
 
 import (
+	"bytes"
+	"sort"
+
 	"github.com/golang/glog"
 
 	"github.com/google/keytransparency/core/mutator"
@@ -27,8 +30,10 @@ import (
 
 // ApplyMutations takes the set of mutations and applies them to given leaves.
 // Returns a list of map leaves that should be updated.
+// Mutations that could not be applied are passed to emitErr.
 func ApplyMutations(mutatorFunc mutator.ReduceMutationFn,
-	msgs []*pb.EntryUpdate, leaves []*tpb.MapLeaf) ([]*tpb.MapLeaf, error) {
+	msgs []*pb.EntryUpdate, leaves []*tpb.MapLeaf,
+	emitErr func(index []byte, msg *pb.EntryUpdate, err error)) ([]*tpb.MapLeaf, error) {
 	// Index the updates.
 	indexedUpdates, err := DoMapUpdateFn(mapper.MapUpdateFn, msgs)
 	if err != nil {
@@ -39,8 +44,10 @@ func ApplyMutations(mutatorFunc mutator.ReduceMutationFn,
 
 	ret := make([]*tpb.MapLeaf, 0, len(joined))
 	for _, j := range joined {
+		index := j.Index
 		if err := mapper.ReduceFn(mutatorFunc, j.Index, j.Leaves, j.Msgs,
-			func(l *tpb.MapLeaf) { ret = append(ret, l) }); err != nil {
+			func(l *tpb.MapLeaf) { ret = append(ret, l) },
+			func(msg *pb.EntryUpdate, err error) { emitErr(index, msg, err) }); err != nil {
 			return nil, err
 		}
 	}
@@ -56,6 +63,7 @@ type Joined struct {
 }
 
 // Join pairs up MapLeaves and IndexedUpdates by index.
+// Rows are sorted by index, and Msgs retain their order from msgs.
 func Join(leaves []*tpb.MapLeaf, msgs []*mapper.IndexedUpdate) []*Joined {
 	joinMap := make(map[string]*Joined)
 	for _, l := range leaves {
@@ -78,6 +86,7 @@ func Join(leaves []*tpb.MapLeaf, msgs []*mapper.IndexedUpdate) []*Joined {
 	for _, r := range joinMap {
 		ret = append(ret, r)
 	}
+	sort.Slice(ret, func(i, j int) bool { return bytes.Compare(ret[i].Index, ret[j].Index) < 0 })
 	return ret
 }
 
@@ -42,6 +42,18 @@ func TestJoin(t *testing.T) {
 				Msgs:   []*pb.EntryUpdate{{}},
 			}},
 		},
+		{
+			desc: "sorted",
+			msgs: []*mapper.IndexedUpdate{
+				{Index: []byte("B"), Update: &pb.EntryUpdate{UserId: "1"}},
+				{Index: []byte("A"), Update: &pb.EntryUpdate{UserId: "2"}},
+				{Index: []byte("B"), Update: &pb.EntryUpdate{UserId: "3"}},
+			},
+			want: []*Joined{
+				{Index: []byte("A"), Msgs: []*pb.EntryUpdate{{UserId: "2"}}},
+				{Index: []byte("B"), Msgs: []*pb.EntryUpdate{{UserId: "1"}, {UserId: "3"}}},
+			},
+		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 			got := Join(tc.leaves, tc.msgs)
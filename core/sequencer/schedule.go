@@ -0,0 +1,212 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sequencer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/google/keytransparency/core/directory"
+
+	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
+)
+
+// defaultRevisionInterval is used to weigh the time since the last revision
+// of directories that do not have a MaxInterval.
+const defaultRevisionInterval = time.Minute
+
+// DefaultBatchTimeout is the deadline for sequencing a single directory when
+// none is configured.
+const DefaultBatchTimeout = 5 * time.Minute
+
+// queueStatusMaxAge is how long a directory's queue status is reused for
+// scheduling before it is fetched again.
+const queueStatusMaxAge = 10 * time.Second
+
+// batchJob is a pending RunBatch call for one directory.
+type batchJob struct {
+	ctx      context.Context // Mastership context for the directory.
+	dir      *directory.Directory
+	req      *spb.RunBatchRequest
+	priority float64
+}
+
+// queueStatus is a cached QueueStatus response.
+type queueStatus struct {
+	resp    *spb.QueueStatusResponse
+	fetched time.Time
+}
+
+// queueStatus returns the queue status of dirID, fetching it at most once per
+// queueStatusMaxAge.
+func (s *Sequencer) queueStatus(ctx context.Context, dirID string) (*spb.QueueStatusResponse, error) {
+	s.runMu.Lock()
+	cached, ok := s.status[dirID]
+	s.runMu.Unlock()
+	if ok && time.Since(cached.fetched) < queueStatusMaxAge {
+		return cached.resp, nil
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	resp, err := s.sequencerClient.QueueStatus(ctx, &spb.QueueStatusRequest{
+		DirectoryId: dirID,
+		MaxCount:    s.batchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	queuePending.Set(float64(resp.PendingMutations), dirID)
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	if s.status == nil {
+		s.status = make(map[string]queueStatus)
+	}
+	s.status[dirID] = queueStatus{resp: resp, fetched: time.Now()}
+	return resp, nil
+}
+
+// priority returns the scheduling priority of d. Directories with a deeper
+// queue, or that have gone longer without a revision, are run first.
+func (s *Sequencer) priority(ctx context.Context, d *directory.Directory) float64 {
+	resp, err := s.queueStatus(ctx, d.DirectoryID)
+	if err != nil {
+		// Still run the directory, RunBatch will report the underlying problem.
+		glog.Warningf("QueueStatus(%v): %v", d.DirectoryID, err)
+		return 0
+	}
+	lastRevision, err := ptypes.Timestamp(resp.LastRevision)
+	if err != nil {
+		return 0
+	}
+	return batchPriority(resp.PendingMutations+resp.OutstandingRevisions*int64(s.batchSize),
+		s.batchSize, time.Since(lastRevision), d.MaxInterval)
+}
+
+// batchPriority combines queue depth and time since the last revision. Each
+// is normalized so that 1 means a revision is due: pending reaches batchSize,
+// or the time since the last revision reaches maxInterval.
+func batchPriority(pending int64, batchSize int32, sinceRevision, maxInterval time.Duration) float64 {
+	if maxInterval <= 0 {
+		maxInterval = defaultRevisionInterval
+	}
+	var depth float64
+	if batchSize > 0 {
+		depth = float64(pending) / float64(batchSize)
+	}
+	return depth + sinceRevision.Seconds()/maxInterval.Seconds()
+}
+
+// runJobs starts jobs in order of decreasing priority, with at most
+// s.concurrency running at once. Each directory runs in its own goroutine and
+// runJobs does not wait for them: a directory whose previous batch is still
+// running is skipped, and jobs that find no free slot are left for the next
+// call. The returned WaitGroup is done once every started job has returned.
+func (s *Sequencer) runJobs(jobs []*batchJob) *sync.WaitGroup {
+	idle := make([]*batchJob, 0, len(jobs))
+	s.runMu.Lock()
+	for _, job := range jobs {
+		if !s.running[job.req.DirectoryId] {
+			idle = append(idle, job)
+		}
+	}
+	s.runMu.Unlock()
+
+	s.forEach(idle, func(job *batchJob) { job.priority = s.priority(job.ctx, job.dir) })
+	sort.SliceStable(idle, func(i, j int) bool { return idle[i].priority > idle[j].priority })
+
+	concurrency := s.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var wg sync.WaitGroup
+	start := time.Now()
+	for _, job := range idle {
+		dirID := job.req.DirectoryId
+		s.runMu.Lock()
+		if len(s.running) >= concurrency {
+			s.runMu.Unlock()
+			break
+		}
+		if s.running == nil {
+			s.running = make(map[string]bool)
+		}
+		s.running[dirID] = true
+		s.runMu.Unlock()
+
+		wg.Add(1)
+		go func(job *batchJob) {
+			defer wg.Done()
+			s.runJob(job, start)
+
+			s.runMu.Lock()
+			defer s.runMu.Unlock()
+			delete(s.running, dirID)
+			delete(s.status, dirID) // The queue has changed.
+		}(job)
+	}
+	return &wg
+}
+
+// forEach calls f on each job in order, with at most s.concurrency calls
+// running at once, and returns when all calls have returned.
+func (s *Sequencer) forEach(jobs []*batchJob, f func(job *batchJob)) {
+	concurrency := s.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(job *batchJob) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			f(job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// withTimeout returns a context limited to s.timeout, or to
+// DefaultBatchTimeout if it is not set.
+func (s *Sequencer) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := s.timeout
+	if timeout <= 0 {
+		timeout = DefaultBatchTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// runJob calls RunBatch for a single directory with its own deadline.
+func (s *Sequencer) runJob(job *batchJob, scheduled time.Time) {
+	dirID := job.req.DirectoryId
+	ctx, cancel := s.withTimeout(job.ctx)
+	defer cancel()
+	runStart := time.Now()
+	runBatchWait.Observe(runStart.Sub(scheduled).Seconds(), dirID)
+	_, err := s.sequencerClient.RunBatch(ctx, job.req)
+	runBatchLatency.Observe(time.Since(runStart).Seconds(), dirID)
+	if err != nil {
+		glog.Errorf("RunBatch for %v failed: %v", dirID, err)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sequencer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/trillian/monitoring"
+	"google.golang.org/grpc"
+
+	"github.com/google/keytransparency/core/directory"
+
+	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
+)
+
+// fakeSequencerClient reports pending[dirID] mutations in QueueStatus and
+// records the order of RunBatch calls. RunBatch empties the queue of a
+// directory, and blocks until its context is done for directories in slow.
+type fakeSequencerClient struct {
+	spb.KeyTransparencySequencerClient
+	slow map[string]bool
+
+	mu          sync.Mutex
+	pending     map[string]int64
+	statusCalls int
+	started     []string
+	ran         []string
+}
+
+func (c *fakeSequencerClient) QueueStatus(ctx context.Context, in *spb.QueueStatusRequest,
+	opts ...grpc.CallOption) (*spb.QueueStatusResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statusCalls++
+	return &spb.QueueStatusResponse{
+		PendingMutations: c.pending[in.DirectoryId],
+		LastRevision:     ptypes.TimestampNow(),
+	}, nil
+}
+
+func (c *fakeSequencerClient) RunBatch(ctx context.Context, in *spb.RunBatchRequest,
+	opts ...grpc.CallOption) (*empty.Empty, error) {
+	c.mu.Lock()
+	c.started = append(c.started, in.DirectoryId)
+	c.mu.Unlock()
+	if c.slow[in.DirectoryId] {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ran = append(c.ran, in.DirectoryId)
+	c.pending[in.DirectoryId] = 0
+	return &empty.Empty{}, nil
+}
+
+func (c *fakeSequencerClient) ranDirectories() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.ran...)
+}
+
+func newJobs(dirIDs ...string) []*batchJob {
+	jobs := make([]*batchJob, 0, len(dirIDs))
+	for _, dirID := range dirIDs {
+		jobs = append(jobs, &batchJob{
+			ctx: context.Background(),
+			dir: &directory.Directory{DirectoryID: dirID, MaxInterval: time.Hour},
+			req: &spb.RunBatchRequest{DirectoryId: dirID},
+		})
+	}
+	return jobs
+}
+
+func TestBatchPriority(t *testing.T) {
+	for _, tc := range []struct {
+		desc          string
+		pending       int64
+		sinceRevision time.Duration
+		maxInterval   time.Duration
+		want          float64
+	}{
+		{desc: "idle"},
+		{desc: "full batch", pending: 100, want: 1},
+		{desc: "max interval", sinceRevision: time.Hour, maxInterval: time.Hour, want: 1},
+		{desc: "default interval", sinceRevision: 2 * defaultRevisionInterval, want: 2},
+		{desc: "both", pending: 50, sinceRevision: 30 * time.Minute, maxInterval: time.Hour, want: 1},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := batchPriority(tc.pending, 100, tc.sinceRevision, tc.maxInterval); got != tc.want {
+				t.Errorf("batchPriority(): %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunJobsPriority(t *testing.T) {
+	initMetrics.Do(func() { createMetrics(monitoring.InertMetricFactory{}) })
+	c := &fakeSequencerClient{pending: map[string]int64{"a": 0, "b": 200, "c": 100}}
+	s := &Sequencer{sequencerClient: c, batchSize: 100, concurrency: 1}
+
+	// With a single slot, each call runs the directory with the highest
+	// priority.
+	for i := 0; i < 3; i++ {
+		s.runJobs(newJobs("a", "b", "c")).Wait()
+	}
+	if got, want := c.ran, []string{"b", "c", "a"}; !cmp.Equal(got, want) {
+		t.Errorf("runJobs() ran %v, want %v", got, want)
+	}
+}
+
+func TestRunJobsSlowDirectory(t *testing.T) {
+	initMetrics.Do(func() { createMetrics(monitoring.InertMetricFactory{}) })
+	// The slow directory has the highest priority, so it starts first.
+	c := &fakeSequencerClient{
+		pending: map[string]int64{"slow": 1000},
+		slow:    map[string]bool{"slow": true},
+	}
+	s := &Sequencer{sequencerClient: c, batchSize: 100, concurrency: 2, timeout: time.Minute}
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := func() []*batchJob {
+		jobs := newJobs("slow", "a", "b", "c")
+		for _, job := range jobs {
+			job.ctx = ctx
+		}
+		return jobs
+	}
+
+	// The other directories keep running, one at a time, while the slow one
+	// holds the other slot.
+	slow := s.runJobs(jobs())
+	deadline := time.Now().Add(5 * time.Second)
+	for len(c.ranDirectories()) < 3 && time.Now().Before(deadline) {
+		s.runJobs(jobs()).Wait()
+	}
+	if got, want := len(c.ranDirectories()), 3; got != want {
+		t.Errorf("runJobs() ran %v, want %v directories", c.ranDirectories(), want)
+	}
+	cancel()
+	slow.Wait()
+
+	var slowStarts int
+	for _, dirID := range c.started {
+		if dirID == "slow" {
+			slowStarts++
+		}
+	}
+	if slowStarts != 1 {
+		t.Errorf("RunBatch(slow) started %v times, want 1", slowStarts)
+	}
+}
+
+func TestQueueStatusCache(t *testing.T) {
+	initMetrics.Do(func() { createMetrics(monitoring.InertMetricFactory{}) })
+	ctx := context.Background()
+	c := &fakeSequencerClient{pending: map[string]int64{"a": 1}}
+	s := &Sequencer{sequencerClient: c, batchSize: 100}
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.queueStatus(ctx, "a"); err != nil {
+			t.Fatalf("queueStatus(): %v", err)
+		}
+	}
+	if got, want := c.statusCalls, 1; got != want {
+		t.Errorf("QueueStatus called %v times, want %v", got, want)
+	}
+
+	// Running a batch invalidates the cached status.
+	s.runJobs(newJobs("a")).Wait()
+	if _, err := s.queueStatus(ctx, "a"); err != nil {
+		t.Fatalf("queueStatus(): %v", err)
+	}
+	if got, want := c.statusCalls, 2; got != want {
+		t.Errorf("QueueStatus called %v times, want %v", got, want)
+	}
+}
@@ -18,13 +18,16 @@ package sequencer
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
 
 	"github.com/google/keytransparency/core/directory"
 	"github.com/google/keytransparency/core/sequencer/election"
 
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
 	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
 )
 
@@ -32,21 +35,40 @@ import (
 type Sequencer struct {
 	directories     directory.Storage
 	batchSize       int32
+	maxLatency      time.Duration
+	concurrency     int
+	timeout         time.Duration
 	sequencerClient spb.KeyTransparencySequencerClient
+	adminClient     pb.KeyTransparencyAdminClient
 	tracker         *election.Tracker
+
+	runMu   sync.Mutex
+	running map[string]bool        // Directories with a RunBatch call in flight.
+	status  map[string]queueStatus // Recent QueueStatus responses.
 }
 
 // New creates a new instance of the signer.
+// Up to concurrency directories are sequenced at the same time, and each
+// RunBatch call is canceled after timeout. A zero timeout uses
+// DefaultBatchTimeout.
 func New(
 	sequencerClient spb.KeyTransparencySequencerClient,
+	adminClient pb.KeyTransparencyAdminClient,
 	directories directory.Storage,
 	batchSize int32,
+	maxLatency time.Duration,
+	concurrency int,
+	timeout time.Duration,
 	tracker *election.Tracker,
 ) *Sequencer {
 	return &Sequencer{
 		sequencerClient: sequencerClient,
+		adminClient:     adminClient,
 		directories:     directories,
 		batchSize:       batchSize,
+		maxLatency:      maxLatency,
+		concurrency:     concurrency,
+		timeout:         timeout,
 		tracker:         tracker,
 	}
 }
@@ -99,7 +121,58 @@ func (s *Sequencer) AddDirectory(dirIDs ...string) {
 }
 
 // RunBatchForAllMasterships runs RunBatch on all directires this sequencer is currently master for.
+// Paused directories are skipped. Directories are started concurrently, up to
+// the configured limit, in order of priority. RunBatchForAllMasterships does
+// not wait for them, so a slow directory does not hold up the others.
 func (s *Sequencer) RunBatchForAllMasterships(ctx context.Context) error {
+	// Batches outlive this call, so they hold masterships from a context of
+	// their own that is released once they have all returned. Each batch is
+	// still bounded by s.timeout.
+	bctx, cancel := context.WithCancel(context.Background())
+	masterships, err := s.tracker.Masterships(bctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	var lastErr error
+	jobs := make([]*batchJob, 0, len(masterships))
+	for dirID, whileMaster := range masterships {
+		d, err := s.directories.Read(ctx, dirID, false)
+		if err != nil {
+			lastErr = err
+			glog.Errorf("directories.Read(%v): %v", dirID, err)
+			continue
+		}
+		if d.Paused {
+			glog.V(2).Infof("Skipping paused directory %v: %v", dirID, d.PausedReason)
+			continue
+		}
+		jobs = append(jobs, &batchJob{
+			ctx: whileMaster,
+			dir: d,
+			req: &spb.RunBatchRequest{
+				DirectoryId: dirID,
+				MinBatch:    1,
+				MaxBatch:    s.batchSize,
+				MaxLatency:  ptypes.DurationProto(s.maxLatency),
+				MaxInterval: ptypes.DurationProto(d.MaxInterval),
+			},
+		})
+	}
+
+	started := s.runJobs(jobs)
+	go func() {
+		started.Wait()
+		cancel()
+	}()
+	return lastErr
+}
+
+// PruneQueueForAllMasterships deletes mutations that are older than retention
+// and have been published in a map revision from the queues of all directories
+// this sequencer is currently master for. Paused directories are skipped.
+func (s *Sequencer) PruneQueueForAllMasterships(ctx context.Context, retention time.Duration) error {
 	cctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	masterships, err := s.tracker.Masterships(cctx)
@@ -109,15 +182,25 @@ func (s *Sequencer) RunBatchForAllMasterships(ctx context.Context) error {
 
 	var lastErr error
 	for dirID, whileMaster := range masterships {
-		req := &spb.RunBatchRequest{
-			DirectoryId: dirID,
-			MinBatch:    1,
-			MaxBatch:    s.batchSize,
+		d, err := s.directories.Read(whileMaster, dirID, false)
+		if err != nil {
+			lastErr = err
+			glog.Errorf("directories.Read(%v): %v", dirID, err)
+			continue
 		}
-		if _, err := s.sequencerClient.RunBatch(whileMaster, req); err != nil {
+		if d.Paused {
+			continue
+		}
+		resp, err := s.adminClient.PruneQueue(whileMaster, &pb.PruneQueueRequest{
+			DirectoryId: dirID,
+			Retention:   ptypes.DurationProto(retention),
+		})
+		if err != nil {
 			lastErr = err
-			glog.Errorf("RunBatch for %v failed: %v", dirID, err)
+			glog.Errorf("PruneQueue for %v failed: %v", dirID, err)
+			continue
 		}
+		queueRowsPruned.Add(float64(resp.Pruned), dirID)
 	}
 
 	return lastErr
@@ -12,7 +12,9 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	grpc "google.golang.org/grpc"
 	math "math"
 )
@@ -138,17 +140,22 @@ type RunBatchRequest struct {
 	// directory_id is the directory to run for.
 	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
 	// min_batch is the minimum number of items in a batch.
-	// If less than min_batch items are available, nothing happens.
-	// TODO(#1047): Replace with timeout so items in the log get processed
-	// eventually.
+	// If less than min_batch items are available, nothing happens unless
+	// max_latency or max_interval have elapsed.
 	MinBatch int32 `protobuf:"varint,2,opt,name=min_batch,json=minBatch,proto3" json:"min_batch,omitempty"`
 	// max_batch is the maximum number of items in a batch.
 	MaxBatch int32 `protobuf:"varint,3,opt,name=max_batch,json=maxBatch,proto3" json:"max_batch,omitempty"`
 	// block until a Signed Log Root has been published which encompases all map roots.
-	Block                bool     `protobuf:"varint,4,opt,name=block,proto3" json:"block,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Block bool `protobuf:"varint,4,opt,name=block,proto3" json:"block,omitempty"`
+	// max_latency is the maximum time an item may wait in the queue before a
+	// revision is defined, regardless of min_batch.
+	MaxLatency *duration.Duration `protobuf:"bytes,5,opt,name=max_latency,json=maxLatency,proto3" json:"max_latency,omitempty"`
+	// max_interval is the maximum time between map revisions. Empty revisions
+	// are defined once max_interval has elapsed since the last revision.
+	MaxInterval          *duration.Duration `protobuf:"bytes,6,opt,name=max_interval,json=maxInterval,proto3" json:"max_interval,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *RunBatchRequest) Reset()         { *m = RunBatchRequest{} }
@@ -204,20 +211,40 @@ func (m *RunBatchRequest) GetBlock() bool {
 	return false
 }
 
+func (m *RunBatchRequest) GetMaxLatency() *duration.Duration {
+	if m != nil {
+		return m.MaxLatency
+	}
+	return nil
+}
+
+func (m *RunBatchRequest) GetMaxInterval() *duration.Duration {
+	if m != nil {
+		return m.MaxInterval
+	}
+	return nil
+}
+
 // DefineRevisionRequest contains information needed to define a new revision.
 type DefineRevisionsRequest struct {
 	// directory_id is the directory to examine the outstanding mutations for.
 	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
 	// min_batch is the minimum number of items in a batch.
-	// If less than min_batch items are available, nothing happens.
-	// TODO(#1047): Replace with timeout so items in the log get processed
-	// eventually.
+	// If less than min_batch items are available, nothing happens unless
+	// max_latency or max_interval have elapsed.
 	MinBatch int32 `protobuf:"varint,2,opt,name=min_batch,json=minBatch,proto3" json:"min_batch,omitempty"`
 	// max_batch is the maximum number of items in a batch.
-	MaxBatch             int32    `protobuf:"varint,3,opt,name=max_batch,json=maxBatch,proto3" json:"max_batch,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	MaxBatch int32 `protobuf:"varint,3,opt,name=max_batch,json=maxBatch,proto3" json:"max_batch,omitempty"`
+	// max_latency is the maximum time an item may wait in the queue before a
+	// revision is defined, regardless of min_batch. Zero disables this check.
+	MaxLatency *duration.Duration `protobuf:"bytes,4,opt,name=max_latency,json=maxLatency,proto3" json:"max_latency,omitempty"`
+	// max_interval is the maximum time between map revisions. Once max_interval
+	// has elapsed since the last revision, a revision is defined even if it
+	// contains no items. Zero disables this check.
+	MaxInterval          *duration.Duration `protobuf:"bytes,5,opt,name=max_interval,json=maxInterval,proto3" json:"max_interval,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *DefineRevisionsRequest) Reset()         { *m = DefineRevisionsRequest{} }
@@ -266,6 +293,20 @@ func (m *DefineRevisionsRequest) GetMaxBatch() int32 {
 	return 0
 }
 
+func (m *DefineRevisionsRequest) GetMaxLatency() *duration.Duration {
+	if m != nil {
+		return m.MaxLatency
+	}
+	return nil
+}
+
+func (m *DefineRevisionsRequest) GetMaxInterval() *duration.Duration {
+	if m != nil {
+		return m.MaxInterval
+	}
+	return nil
+}
+
 // DefineRevisionResponse contains information about freshly defined revisions.
 type DefineRevisionsResponse struct {
 	// outsanding_revisions a list of all the defined revisions which are not yet applied.
@@ -514,6 +555,116 @@ func (m *PublishRevisionsResponse) GetRevisions() []int64 {
 	return nil
 }
 
+// QueueStatusRequest asks for the amount of outstanding work for a directory.
+type QueueStatusRequest struct {
+	DirectoryId string `protobuf:"bytes,1,opt,name=directory_id,json=directoryId,proto3" json:"directory_id,omitempty"`
+	// max_count limits the number of pending mutations counted in each log.
+	MaxCount             int32    `protobuf:"varint,2,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueueStatusRequest) Reset()         { *m = QueueStatusRequest{} }
+func (m *QueueStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*QueueStatusRequest) ProtoMessage()    {}
+func (*QueueStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0a5d61b2e27141ee, []int{8}
+}
+
+func (m *QueueStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueueStatusRequest.Unmarshal(m, b)
+}
+func (m *QueueStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueueStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *QueueStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueueStatusRequest.Merge(m, src)
+}
+func (m *QueueStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_QueueStatusRequest.Size(m)
+}
+func (m *QueueStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueueStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueueStatusRequest proto.InternalMessageInfo
+
+func (m *QueueStatusRequest) GetDirectoryId() string {
+	if m != nil {
+		return m.DirectoryId
+	}
+	return ""
+}
+
+func (m *QueueStatusRequest) GetMaxCount() int32 {
+	if m != nil {
+		return m.MaxCount
+	}
+	return 0
+}
+
+// QueueStatusResponse describes the outstanding work for a directory.
+type QueueStatusResponse struct {
+	// pending_mutations is the number of queued mutations that are not yet part
+	// of a defined revision, up to max_count per log.
+	PendingMutations int64 `protobuf:"varint,1,opt,name=pending_mutations,json=pendingMutations,proto3" json:"pending_mutations,omitempty"`
+	// outstanding_revisions is the number of defined revisions that have not
+	// been applied to the map.
+	OutstandingRevisions int64 `protobuf:"varint,2,opt,name=outstanding_revisions,json=outstandingRevisions,proto3" json:"outstanding_revisions,omitempty"`
+	// last_revision is the time the latest map revision was created.
+	LastRevision         *timestamp.Timestamp `protobuf:"bytes,3,opt,name=last_revision,json=lastRevision,proto3" json:"last_revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *QueueStatusResponse) Reset()         { *m = QueueStatusResponse{} }
+func (m *QueueStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*QueueStatusResponse) ProtoMessage()    {}
+func (*QueueStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0a5d61b2e27141ee, []int{9}
+}
+
+func (m *QueueStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueueStatusResponse.Unmarshal(m, b)
+}
+func (m *QueueStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueueStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *QueueStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueueStatusResponse.Merge(m, src)
+}
+func (m *QueueStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_QueueStatusResponse.Size(m)
+}
+func (m *QueueStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueueStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueueStatusResponse proto.InternalMessageInfo
+
+func (m *QueueStatusResponse) GetPendingMutations() int64 {
+	if m != nil {
+		return m.PendingMutations
+	}
+	return 0
+}
+
+func (m *QueueStatusResponse) GetOutstandingRevisions() int64 {
+	if m != nil {
+		return m.OutstandingRevisions
+	}
+	return 0
+}
+
+func (m *QueueStatusResponse) GetLastRevision() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastRevision
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*MapMetadata)(nil), "google.keytransparency.sequencer.MapMetadata")
 	proto.RegisterType((*MapMetadata_SourceSlice)(nil), "google.keytransparency.sequencer.MapMetadata.SourceSlice")
@@ -524,50 +675,63 @@ func init() {
 	proto.RegisterType((*ApplyRevisionResponse)(nil), "google.keytransparency.sequencer.ApplyRevisionResponse")
 	proto.RegisterType((*PublishRevisionsRequest)(nil), "google.keytransparency.sequencer.PublishRevisionsRequest")
 	proto.RegisterType((*PublishRevisionsResponse)(nil), "google.keytransparency.sequencer.PublishRevisionsResponse")
+	proto.RegisterType((*QueueStatusRequest)(nil), "google.keytransparency.sequencer.QueueStatusRequest")
+	proto.RegisterType((*QueueStatusResponse)(nil), "google.keytransparency.sequencer.QueueStatusResponse")
 }
 
 func init() { proto.RegisterFile("sequencer_api.proto", fileDescriptor_0a5d61b2e27141ee) }
 
 var fileDescriptor_0a5d61b2e27141ee = []byte{
-	// 595 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x54, 0x4f, 0x6f, 0xd3, 0x4e,
-	0x14, 0x94, 0xe3, 0xa4, 0xbf, 0xe4, 0xe5, 0x87, 0x12, 0x96, 0xa4, 0xb5, 0xdc, 0x22, 0x05, 0x9f,
-	0x82, 0x90, 0x1c, 0xd1, 0x4a, 0xd0, 0x72, 0xa3, 0x90, 0x43, 0x80, 0x22, 0xe4, 0xd0, 0x0b, 0x17,
-	0x6b, 0x63, 0x6f, 0x9d, 0x55, 0x6d, 0xaf, 0xf1, 0xae, 0x43, 0x2c, 0x71, 0xe0, 0x80, 0x90, 0x7a,
-	0xe6, 0x3b, 0xf2, 0x39, 0x90, 0xff, 0x26, 0xb8, 0x45, 0xa1, 0x41, 0xe2, 0x94, 0xec, 0xbc, 0x99,
-	0xd9, 0xb7, 0x7e, 0xb3, 0x0b, 0xf7, 0x38, 0xf9, 0x18, 0x11, 0xdf, 0x22, 0xa1, 0x89, 0x03, 0xaa,
-	0x07, 0x21, 0x13, 0x0c, 0x0d, 0x1c, 0xc6, 0x1c, 0x97, 0xe8, 0x97, 0x24, 0x16, 0x21, 0xf6, 0x79,
-	0x80, 0x43, 0xe2, 0x5b, 0xb1, 0x5e, 0x72, 0xd5, 0xfd, 0x8c, 0x31, 0x4a, 0xf9, 0xb3, 0xe8, 0x62,
-	0x44, 0xbc, 0x40, 0xc4, 0x99, 0x5c, 0xfb, 0x21, 0x41, 0xfb, 0x0c, 0x07, 0x67, 0x44, 0x60, 0x1b,
-	0x0b, 0x8c, 0xa6, 0xf0, 0x1f, 0x67, 0x51, 0x68, 0x11, 0xae, 0xd4, 0x06, 0xf2, 0xb0, 0x7d, 0x78,
-	0xa2, 0x6f, 0xda, 0x40, 0x5f, 0xd3, 0xeb, 0xd3, 0x54, 0x3c, 0x75, 0xa9, 0x45, 0x8c, 0xc2, 0x49,
-	0xfd, 0x0c, 0xed, 0x35, 0x1c, 0x3d, 0x84, 0xae, 0xcb, 0x3e, 0x11, 0x2e, 0x4c, 0xea, 0x5b, 0x6e,
-	0xc4, 0xe9, 0x82, 0x28, 0xd2, 0x40, 0x1a, 0xca, 0x46, 0x27, 0xc3, 0x27, 0x05, 0x8c, 0x1e, 0xc1,
-	0xdd, 0x39, 0x75, 0xe6, 0x09, 0x97, 0x2c, 0x0b, 0x6e, 0x2d, 0xe5, 0x76, 0xf3, 0xc2, 0xb8, 0xc0,
-	0x51, 0x1f, 0x76, 0x5c, 0xe6, 0x98, 0xd4, 0x56, 0xe4, 0x94, 0xd1, 0x70, 0x99, 0x33, 0xb1, 0x5f,
-	0xd5, 0x9b, 0x52, 0xb7, 0xa6, 0x7d, 0x95, 0xa0, 0x63, 0x44, 0xfe, 0x29, 0x16, 0xd6, 0xdc, 0x48,
-	0x5a, 0xe7, 0x02, 0x3d, 0x80, 0xff, 0x6d, 0x1a, 0x12, 0x4b, 0xb0, 0x30, 0x4e, 0x64, 0x49, 0x13,
-	0x2d, 0xa3, 0x5d, 0x62, 0x13, 0x1b, 0xed, 0x43, 0xcb, 0xa3, 0xbe, 0x39, 0x4b, 0x64, 0xe9, 0xc6,
-	0x0d, 0xa3, 0xe9, 0xd1, 0xcc, 0x26, 0x2d, 0xe2, 0x65, 0x5e, 0x94, 0xf3, 0x22, 0x5e, 0x66, 0xc5,
-	0x1e, 0x34, 0x66, 0x2e, 0xb3, 0x2e, 0x95, 0xfa, 0x40, 0x1a, 0x36, 0x8d, 0x6c, 0xa1, 0x45, 0xb0,
-	0xfb, 0x92, 0x5c, 0x50, 0x9f, 0x18, 0x64, 0x41, 0x39, 0x65, 0x3e, 0xff, 0x17, 0xcd, 0x68, 0x6f,
-	0x61, 0xef, 0xda, 0xb6, 0x3c, 0x60, 0x3e, 0x27, 0xe8, 0x08, 0xfa, 0x2c, 0x12, 0x5c, 0x60, 0xdf,
-	0xa6, 0xbe, 0x63, 0x86, 0x05, 0x41, 0x91, 0x06, 0xf2, 0x50, 0x36, 0x7a, 0x6b, 0xc5, 0x52, 0xac,
-	0x9d, 0x43, 0xef, 0x79, 0x10, 0xb8, 0x71, 0x81, 0xdc, 0xe2, 0x10, 0x2a, 0x34, 0x8b, 0x3d, 0xf2,
-	0x49, 0x96, 0x6b, 0xed, 0xbb, 0x04, 0xfd, 0x8a, 0x6f, 0xde, 0xe5, 0xdf, 0x19, 0xa3, 0x03, 0x68,
-	0x79, 0x91, 0xc0, 0x22, 0x3d, 0x58, 0x96, 0x8e, 0x15, 0x80, 0xee, 0x03, 0x78, 0x38, 0x30, 0x5d,
-	0x82, 0x17, 0x84, 0xa7, 0xf3, 0x4a, 0xca, 0x38, 0x78, 0x93, 0x02, 0x9a, 0x01, 0x7b, 0xef, 0xa2,
-	0x99, 0x4b, 0xf9, 0x7c, 0x9b, 0xa1, 0x95, 0x39, 0xa8, 0xad, 0xe7, 0xe0, 0x18, 0x94, 0xeb, 0x9e,
-	0xf9, 0x59, 0x0f, 0xa0, 0x55, 0x9d, 0xc2, 0x0a, 0x38, 0xbc, 0xaa, 0x83, 0xf2, 0x9a, 0xc4, 0xef,
-	0xd7, 0xee, 0xe2, 0xb4, 0xb8, 0x8a, 0xe8, 0x1c, 0x9a, 0x45, 0xc8, 0xd1, 0xe3, 0xcd, 0x37, 0xb7,
-	0x72, 0x21, 0xd4, 0xdd, 0x42, 0x52, 0xbc, 0x15, 0xfa, 0x38, 0x79, 0x2b, 0xd0, 0x37, 0x09, 0x3a,
-	0x95, 0xfc, 0xa0, 0xe3, 0xcd, 0xf6, 0x37, 0x27, 0x5d, 0x3d, 0xd9, 0x42, 0x99, 0x7f, 0x9a, 0x2f,
-	0x12, 0xdc, 0xf9, 0x25, 0x20, 0xe8, 0xc9, 0x66, 0xb3, 0x9b, 0x92, 0xaa, 0x3e, 0xbd, 0xb5, 0x2e,
-	0x6f, 0xe1, 0x4a, 0x82, 0x6e, 0x75, 0x74, 0xe8, 0x0f, 0x8e, 0xf4, 0x9b, 0x08, 0xa9, 0xcf, 0xb6,
-	0x91, 0x66, 0xbd, 0x9c, 0x8e, 0x3f, 0xbc, 0x70, 0xa8, 0x98, 0x47, 0x33, 0xdd, 0x62, 0xde, 0x28,
-	0x7f, 0xe7, 0x2b, 0x3e, 0x23, 0x8b, 0x85, 0x64, 0x54, 0x9a, 0xad, 0xfe, 0x99, 0x0e, 0x33, 0xb3,
-	0x39, 0xef, 0xa4, 0x3f, 0x47, 0x3f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xa4, 0xa4, 0x05, 0xf7, 0x61,
-	0x06, 0x00, 0x00,
+	// 781 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0x4b, 0x6f, 0xeb, 0x44,
+	0x14, 0x96, 0xf3, 0x28, 0xc9, 0x71, 0xaf, 0x9a, 0x3b, 0xb7, 0x0f, 0xe3, 0x16, 0x08, 0x5e, 0x05,
+	0x55, 0x72, 0x44, 0xca, 0xa3, 0xad, 0x90, 0x10, 0x7d, 0x2c, 0x02, 0x2d, 0x02, 0xa7, 0xdd, 0xb0,
+	0xb1, 0x26, 0xce, 0xd4, 0x19, 0xd5, 0xf6, 0x18, 0xcf, 0x38, 0x24, 0x88, 0x05, 0x1b, 0x90, 0x58,
+	0xf3, 0x67, 0xf8, 0x45, 0x6c, 0xf9, 0x07, 0x08, 0xd9, 0x1e, 0x3b, 0xa9, 0xd3, 0x2a, 0xa4, 0x95,
+	0xee, 0x2a, 0x99, 0x73, 0xbe, 0xef, 0x9b, 0x33, 0x67, 0xbe, 0x39, 0x09, 0xbc, 0xe1, 0xe4, 0xc7,
+	0x98, 0x04, 0x0e, 0x89, 0x6c, 0x1c, 0x52, 0x33, 0x8c, 0x98, 0x60, 0xa8, 0xed, 0x32, 0xe6, 0x7a,
+	0xc4, 0xbc, 0x27, 0x33, 0x11, 0xe1, 0x80, 0x87, 0x38, 0x22, 0x81, 0x33, 0x33, 0x0b, 0xac, 0xfe,
+	0x7e, 0x86, 0xe8, 0xa6, 0xf8, 0x61, 0x7c, 0xd7, 0x1d, 0xc5, 0x11, 0x16, 0x94, 0x05, 0x99, 0x82,
+	0xbe, 0x5f, 0xce, 0x13, 0x3f, 0x14, 0x33, 0x99, 0xfc, 0xa0, 0x9c, 0x14, 0xd4, 0x27, 0x5c, 0x60,
+	0x3f, 0xcc, 0x00, 0xc6, 0xdf, 0x0a, 0xa8, 0xd7, 0x38, 0xbc, 0x26, 0x02, 0x8f, 0xb0, 0xc0, 0x68,
+	0x00, 0xef, 0x70, 0x16, 0x47, 0x0e, 0xe1, 0x5a, 0xa5, 0x5d, 0xed, 0xa8, 0xbd, 0x13, 0x73, 0x55,
+	0x85, 0xe6, 0x02, 0xdf, 0x1c, 0xa4, 0xe4, 0x81, 0x47, 0x1d, 0x62, 0xe5, 0x4a, 0xfa, 0x2f, 0xa0,
+	0x2e, 0xc4, 0xd1, 0x47, 0xd0, 0xf2, 0xd8, 0x4f, 0x84, 0x0b, 0x9b, 0x06, 0x8e, 0x17, 0x73, 0x3a,
+	0x21, 0x9a, 0xd2, 0x56, 0x3a, 0x55, 0x6b, 0x2b, 0x8b, 0xf7, 0xf3, 0x30, 0x3a, 0x84, 0xd7, 0x63,
+	0xea, 0x8e, 0x13, 0x2c, 0x99, 0xe6, 0xd8, 0x4a, 0x8a, 0x6d, 0xc9, 0xc4, 0x65, 0x1e, 0x47, 0x3b,
+	0xb0, 0xe1, 0x31, 0xd7, 0xa6, 0x23, 0xad, 0x9a, 0x22, 0xea, 0x1e, 0x73, 0xfb, 0xa3, 0xaf, 0x6b,
+	0x0d, 0xa5, 0x55, 0x31, 0xfe, 0x55, 0x60, 0xcb, 0x8a, 0x83, 0x33, 0x2c, 0x9c, 0xb1, 0x95, 0x94,
+	0xce, 0x05, 0xfa, 0x10, 0x36, 0x47, 0x34, 0x22, 0x8e, 0x60, 0xd1, 0x2c, 0xa1, 0x25, 0x45, 0x34,
+	0x2d, 0xb5, 0x88, 0xf5, 0x47, 0x68, 0x1f, 0x9a, 0x3e, 0x0d, 0xec, 0x61, 0x42, 0x4b, 0x37, 0xae,
+	0x5b, 0x0d, 0x9f, 0x66, 0x32, 0x69, 0x12, 0x4f, 0x65, 0xb2, 0x2a, 0x93, 0x78, 0x9a, 0x25, 0xb7,
+	0xa1, 0x3e, 0xf4, 0x98, 0x73, 0xaf, 0xd5, 0xda, 0x4a, 0xa7, 0x61, 0x65, 0x0b, 0x74, 0x0a, 0x6a,
+	0x42, 0xf1, 0xb0, 0x48, 0x9a, 0xa8, 0xd5, 0xdb, 0x4a, 0x47, 0xed, 0xbd, 0x9b, 0xf7, 0x38, 0xbf,
+	0x26, 0xf3, 0x42, 0xde, 0xb1, 0x05, 0x3e, 0x9e, 0x5e, 0x65, 0x60, 0xf4, 0x05, 0x6c, 0x26, 0x5c,
+	0x1a, 0x08, 0x12, 0x4d, 0xb0, 0xa7, 0x6d, 0xac, 0x22, 0x27, 0x5b, 0xf5, 0x25, 0xda, 0xf8, 0x47,
+	0x81, 0xdd, 0x0b, 0x72, 0x47, 0x03, 0x62, 0x91, 0x09, 0xe5, 0x94, 0x05, 0xfc, 0xad, 0xf4, 0xa1,
+	0x74, 0xe2, 0xda, 0x4b, 0x4e, 0x5c, 0x5f, 0xeb, 0xc4, 0xdf, 0xc2, 0xde, 0xd2, 0x81, 0x79, 0xc8,
+	0x02, 0x4e, 0xd0, 0x11, 0xec, 0xb0, 0x58, 0x70, 0x81, 0x83, 0x11, 0x0d, 0x5c, 0x3b, 0xca, 0x01,
+	0x9a, 0xd2, 0xae, 0x76, 0xaa, 0xd6, 0xf6, 0x42, 0xb2, 0x20, 0x1b, 0xb7, 0xb0, 0xfd, 0x55, 0x18,
+	0x7a, 0xb3, 0x3c, 0xb2, 0x46, 0xfb, 0x74, 0x68, 0xe4, 0x7b, 0x48, 0xfb, 0x16, 0x6b, 0xe3, 0x4f,
+	0x05, 0x76, 0x4a, 0xba, 0xb2, 0xca, 0x97, 0x09, 0xa3, 0x03, 0x68, 0xfa, 0xb1, 0x48, 0x1b, 0xc3,
+	0xe5, 0x93, 0x98, 0x07, 0xd0, 0x7b, 0x00, 0x3e, 0x0e, 0x6d, 0x8f, 0xe0, 0x09, 0xe1, 0x5a, 0x4d,
+	0xa6, 0x71, 0x78, 0x95, 0x06, 0x0c, 0x0b, 0xf6, 0xbe, 0x8b, 0x87, 0x1e, 0xe5, 0xe3, 0xe7, 0xd8,
+	0xa5, 0x30, 0x7f, 0x65, 0xc1, 0xfc, 0xc6, 0x31, 0x68, 0xcb, 0x9a, 0xf2, 0xac, 0x07, 0xd0, 0x2c,
+	0xdf, 0xc2, 0x3c, 0x60, 0xdc, 0x00, 0xfa, 0x3e, 0x26, 0x31, 0x19, 0x08, 0x2c, 0xe2, 0x75, 0x7d,
+	0x8b, 0xa7, 0xb6, 0xc3, 0xe2, 0x40, 0x14, 0xbe, 0xc5, 0xd3, 0xf3, 0x64, 0x6d, 0xfc, 0xa5, 0xc0,
+	0x9b, 0x07, 0xb2, 0xb2, 0x96, 0x43, 0x78, 0x1d, 0x92, 0xcc, 0x19, 0xf3, 0x06, 0x66, 0x13, 0xaa,
+	0x25, 0x13, 0xd7, 0x45, 0x1f, 0x9f, 0xb4, 0x52, 0x76, 0x1d, 0x8f, 0x5a, 0x09, 0x7d, 0x09, 0xaf,
+	0x3c, 0xcc, 0x45, 0x81, 0x4e, 0xaf, 0x47, 0xed, 0xe9, 0x4b, 0xce, 0xbe, 0xc9, 0xe7, 0xb5, 0xb5,
+	0x99, 0x10, 0x72, 0x85, 0xde, 0x6f, 0x75, 0xd0, 0xbe, 0x21, 0xb3, 0x9b, 0x85, 0x89, 0x3c, 0xc8,
+	0x07, 0x32, 0xba, 0x85, 0x46, 0x3e, 0xea, 0xd0, 0xc7, 0xab, 0xe7, 0x77, 0x69, 0x2c, 0xea, 0xbb,
+	0x4b, 0x55, 0x5c, 0x26, 0x3f, 0x29, 0xe8, 0x77, 0x05, 0xb6, 0x4a, 0x0f, 0x0a, 0x1d, 0xaf, 0x96,
+	0x7f, 0x7c, 0xe8, 0xe8, 0x27, 0xcf, 0x60, 0xca, 0xfb, 0xf9, 0x55, 0x81, 0x57, 0x0f, 0x5e, 0x0c,
+	0xfa, 0x6c, 0xb5, 0xd8, 0x63, 0x4f, 0x57, 0xff, 0x7c, 0x6d, 0x9e, 0x2c, 0xe1, 0x0f, 0x05, 0x5a,
+	0x65, 0x2f, 0xa3, 0xff, 0x71, 0xa4, 0x27, 0xde, 0x94, 0x7e, 0xfa, 0x1c, 0xaa, 0xac, 0xe5, 0x67,
+	0x50, 0x17, 0x5c, 0x8c, 0x3e, 0x59, 0x2d, 0xb5, 0xfc, 0x96, 0xf4, 0x4f, 0xd7, 0x64, 0x65, 0x7b,
+	0x9f, 0x5d, 0xfe, 0x70, 0xee, 0x52, 0x31, 0x8e, 0x87, 0xa6, 0xc3, 0xfc, 0xae, 0xfc, 0xb7, 0x51,
+	0x92, 0xe8, 0x3a, 0x2c, 0x22, 0xdd, 0x42, 0x67, 0xfe, 0xcd, 0x76, 0x99, 0x9d, 0x79, 0x6c, 0x23,
+	0xfd, 0x38, 0xfa, 0x6f, 0x00, 0x70, 0x14, 0x06, 0x57, 0x24, 0x09, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -593,6 +757,9 @@ type KeyTransparencySequencerClient interface {
 	// PublishRevisions copies the MapRoots of all known map revisions into the Log
 	// of MapRoots.
 	PublishRevisions(ctx context.Context, in *PublishRevisionsRequest, opts ...grpc.CallOption) (*PublishRevisionsResponse, error)
+	// QueueStatus reports the amount of outstanding work for a directory so
+	// that directories can be scheduled by need.
+	QueueStatus(ctx context.Context, in *QueueStatusRequest, opts ...grpc.CallOption) (*QueueStatusResponse, error)
 }
 
 type keyTransparencySequencerClient struct {
@@ -639,6 +806,15 @@ func (c *keyTransparencySequencerClient) PublishRevisions(ctx context.Context, i
 	return out, nil
 }
 
+func (c *keyTransparencySequencerClient) QueueStatus(ctx context.Context, in *QueueStatusRequest, opts ...grpc.CallOption) (*QueueStatusResponse, error) {
+	out := new(QueueStatusResponse)
+	err := c.cc.Invoke(ctx, "/google.keytransparency.sequencer.KeyTransparencySequencer/QueueStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KeyTransparencySequencerServer is the server API for KeyTransparencySequencer service.
 type KeyTransparencySequencerServer interface {
 	// RunBatch calls DefineRevisions, ApplyRevision, and PublishRevisions successively.
@@ -652,6 +828,9 @@ type KeyTransparencySequencerServer interface {
 	// PublishRevisions copies the MapRoots of all known map revisions into the Log
 	// of MapRoots.
 	PublishRevisions(context.Context, *PublishRevisionsRequest) (*PublishRevisionsResponse, error)
+	// QueueStatus reports the amount of outstanding work for a directory so
+	// that directories can be scheduled by need.
+	QueueStatus(context.Context, *QueueStatusRequest) (*QueueStatusResponse, error)
 }
 
 func RegisterKeyTransparencySequencerServer(s *grpc.Server, srv KeyTransparencySequencerServer) {
@@ -730,6 +909,24 @@ func _KeyTransparencySequencer_PublishRevisions_Handler(srv interface{}, ctx con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KeyTransparencySequencer_QueueStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyTransparencySequencerServer).QueueStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.keytransparency.sequencer.KeyTransparencySequencer/QueueStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyTransparencySequencerServer).QueueStatus(ctx, req.(*QueueStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _KeyTransparencySequencer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "google.keytransparency.sequencer.KeyTransparencySequencer",
 	HandlerType: (*KeyTransparencySequencerServer)(nil),
@@ -750,6 +947,10 @@ var _KeyTransparencySequencer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PublishRevisions",
 			Handler:    _KeyTransparencySequencer_PublishRevisions_Handler,
 		},
+		{
+			MethodName: "QueueStatus",
+			Handler:    _KeyTransparencySequencer_QueueStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "sequencer_api.proto",
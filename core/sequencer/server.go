@@ -19,9 +19,12 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	durpb "github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/trillian/monitoring"
 	"google.golang.org/grpc/codes"
@@ -29,7 +32,6 @@ import (
 
 	"github.com/google/keytransparency/core/directory"
 	"github.com/google/keytransparency/core/mutator"
-	"github.com/google/keytransparency/core/mutator/entry"
 	"github.com/google/keytransparency/core/sequencer/runner"
 
 	ktpb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
@@ -41,6 +43,10 @@ const (
 	directoryIDLabel = "directoryid"
 	logIDLabel       = "logid"
 	reasonLabel      = "reason"
+
+	// maxParallelWatermarks bounds the number of concurrent
+	// LogsReader.HighWatermark queries made by HighWatermarks.
+	maxParallelWatermarks = 16
 )
 
 var (
@@ -52,6 +58,12 @@ var (
 	watermarkDefined monitoring.Gauge
 	watermarkApplied monitoring.Gauge
 	mutationFailures monitoring.Counter
+	revisionsDefined monitoring.Counter
+	queueRowsPruned  monitoring.Counter
+	queuePending     monitoring.Gauge
+	runBatchWait     monitoring.Histogram
+	runBatchLatency  monitoring.Histogram
+	eventsSent       monitoring.Counter
 )
 
 func createMetrics(mf monitoring.MetricFactory) {
@@ -83,6 +95,30 @@ func createMetrics(mf monitoring.MetricFactory) {
 		"mutation_failures",
 		"Number of invalid mutations the signer has processed for directoryid since process start",
 		directoryIDLabel, reasonLabel)
+	revisionsDefined = mf.NewCounter(
+		"revisions_defined",
+		"Number of revisions defined for directoryid since process start, by the condition that triggered them",
+		directoryIDLabel, reasonLabel)
+	queueRowsPruned = mf.NewCounter(
+		"queue_rows_pruned",
+		"Number of published mutations deleted from the queue for directoryid since process start",
+		directoryIDLabel)
+	queuePending = mf.NewGauge(
+		"queue_pending_mutations",
+		"Number of queued mutations not yet in a revision for directoryid, up to the batch size per log",
+		directoryIDLabel)
+	runBatchWait = mf.NewHistogram(
+		"run_batch_wait_seconds",
+		"Time directoryid waited for a free worker before RunBatch started",
+		directoryIDLabel)
+	runBatchLatency = mf.NewHistogram(
+		"run_batch_latency_seconds",
+		"Duration of RunBatch calls for directoryid",
+		directoryIDLabel)
+	eventsSent = mf.NewCounter(
+		"events_sent",
+		"Number of entry change events delivered for directoryid since process start. Duplicates are not removed.",
+		directoryIDLabel)
 }
 
 // Watermarks is a map of watermarks by logID.
@@ -116,13 +152,41 @@ type Batcher interface {
 	HighestRev(ctx context.Context, directoryID string) (int64, error)
 }
 
+// RejectionWriter records log messages that could not be applied to the map.
+type RejectionWriter interface {
+	// WriteRejections saves rejections for directoryID.
+	// Writing the same rejection more than once has no further effect.
+	WriteRejections(ctx context.Context, directoryID string, rejections []*mutator.Rejection) error
+}
+
 // Server implements KeyTransparencySequencerServer.
 type Server struct {
-	batcher   Batcher
-	trillian  trillianFactory
-	logs      LogsReader
-	loopback  spb.KeyTransparencySequencerClient
-	BatchSize int32
+	directories directory.Storage
+	batcher     Batcher
+	trillian    trillianFactory
+	logs        LogsReader
+	rejections  RejectionWriter
+	loopback    spb.KeyTransparencySequencerClient
+	BatchSize   int32
+
+	// EventSink, if set, receives an event for each entry that changes in a
+	// published revision. EventCursors must also be set, and RunEvents must
+	// be running.
+	EventSink    EventSink
+	EventCursors EventCursors
+
+	// eventsPending holds the latest published revision of the directories
+	// in eventQueue.
+	eventsMu      sync.Mutex
+	eventsPending map[string]int64
+	eventQueue    chan string
+
+	// rebuilds holds the progress of the map rebuilds started by StartRebuild
+	// by map ID.
+	rebuildMu sync.Mutex
+	rebuilds  map[int64]*ktpb.MapRebuild
+	// rebuildCancels holds the cancel funcs of running rebuilds by map ID.
+	rebuildCancels map[int64]context.CancelFunc
 }
 
 // NewServer creates a new KeyTransparencySequencerServer.
@@ -130,8 +194,10 @@ func NewServer(
 	directories directory.Storage,
 	tlog tpb.TrillianLogClient,
 	tmap tpb.TrillianMapClient,
+	mapAdmin tpb.TrillianAdminClient,
 	batcher Batcher,
 	logs LogsReader,
+	rejections RejectionWriter,
 	loopback spb.KeyTransparencySequencerClient,
 	metricsFactory monitoring.MetricFactory,
 ) *Server {
@@ -141,11 +207,17 @@ func NewServer(
 			directories: directories,
 			tmap:        tmap,
 			tlog:        tlog,
+			mapAdmin:    mapAdmin,
 		},
-		batcher:   batcher,
-		logs:      logs,
-		loopback:  loopback,
-		BatchSize: 10000,
+		directories: directories,
+		batcher:     batcher,
+		logs:        logs,
+		rejections:  rejections,
+		loopback:    loopback,
+		BatchSize:   10000,
+
+		eventsPending: make(map[string]int64),
+		eventQueue:    make(chan string, eventQueueSize),
 	}
 }
 
@@ -156,10 +228,23 @@ func NewServer(
 // b) apply the batch to the map
 // c) publish existing map roots to a log of SignedMapRoots.
 func (s *Server) RunBatch(ctx context.Context, in *spb.RunBatchRequest) (*empty.Empty, error) {
+	// The scheduler skips paused directories, but callers of this RPC
+	// must not be able to sequence one either.
+	d, err := s.directories.Read(ctx, in.DirectoryId, false)
+	if err != nil {
+		return nil, err
+	}
+	if d.Paused {
+		return nil, status.Errorf(codes.FailedPrecondition, "directory %v is paused: %v", in.DirectoryId, d.PausedReason)
+	}
+
 	defResp, err := s.loopback.DefineRevisions(ctx, &spb.DefineRevisionsRequest{
 		DirectoryId: in.DirectoryId,
 		MinBatch:    in.MinBatch,
-		MaxBatch:    in.MaxBatch})
+		MaxBatch:    in.MaxBatch,
+		MaxLatency:  in.MaxLatency,
+		MaxInterval: in.MaxInterval,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -213,6 +298,15 @@ func (s *Server) DefineRevisions(ctx context.Context,
 		return &spb.DefineRevisionsResponse{OutstandingRevisions: outstanding}, nil
 	}
 
+	maxLatency, err := optionalDuration(in.MaxLatency)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "max_latency: %v", err)
+	}
+	maxInterval, err := optionalDuration(in.MaxInterval)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "max_interval: %v", err)
+	}
+
 	// Query metadata about outstanding log items.
 	var lastMeta spb.MapMetadata
 	if err := proto.Unmarshal(latestMapRoot.Metadata, &lastMeta); err != nil {
@@ -228,10 +322,28 @@ func (s *Server) DefineRevisions(ctx context.Context,
 	// Rate limit the creation of new batches.
 	//
 
-	// TODO(#1057): If time since last map revision > max timeout, define batch.
-	// TODO(#1047): If time since oldest queue item > max latency has elapsed, define batch.
+	var reason string
+	switch {
 	// If count items >= min_batch, define batch.
-	if count >= in.MinBatch {
+	case count >= in.MinBatch:
+		reason = "min_batch"
+	// If time since oldest queue item > max latency has elapsed, define batch.
+	case count > 0 && maxLatency > 0:
+		oldest, err := s.oldestItem(ctx, in.DirectoryId, meta)
+		if err != nil {
+			return nil, err
+		}
+		if time.Since(oldest) >= maxLatency {
+			reason = "max_latency"
+		}
+	}
+	// If time since last map revision > max interval, define batch, even if it is empty.
+	lastRevision := time.Unix(0, int64(latestMapRoot.TimestampNanos))
+	if reason == "" && maxInterval > 0 && time.Since(lastRevision) >= maxInterval {
+		reason = "max_interval"
+	}
+
+	if reason != "" {
 		nextRev := int64(latestMapRoot.Revision) + 1
 		if err := s.batcher.WriteBatchSources(ctx, in.DirectoryId, nextRev, meta); err != nil {
 			return nil, err
@@ -240,15 +352,64 @@ func (s *Server) DefineRevisions(ctx context.Context,
 			watermarkDefined.Set(float64(source.HighestExclusive),
 				in.DirectoryId, fmt.Sprintf("%v", source.LogId))
 		}
+		revisionsDefined.Inc(in.DirectoryId, reason)
 		outstanding = append(outstanding, nextRev)
-
 	}
 	// TODO(#1056): If count items == max_batch, should we define the next batch immediately?
 
 	return &spb.DefineRevisionsResponse{OutstandingRevisions: outstanding}, nil
 }
 
-// readMessages returns the full set of EntryUpdates defined by sources.
+// failureReason returns a metric label for a mutation error.
+func failureReason(err error) string {
+	switch err {
+	case mutator.ErrReplay:
+		return "replay"
+	case mutator.ErrSize:
+		return "size"
+	case mutator.ErrPreviousHash:
+		return "previous_hash"
+	case mutator.ErrInvalidSig:
+		return "invalid_signature"
+	case mutator.ErrUnauthorized:
+		return "unauthorized"
+	default:
+		return "other"
+	}
+}
+
+// optionalDuration converts d into a time.Duration. A nil d is treated as zero.
+func optionalDuration(d *durpb.Duration) (time.Duration, error) {
+	if d == nil {
+		return 0, nil
+	}
+	return ptypes.Duration(d)
+}
+
+// oldestItem returns the queue time of the oldest item in the ranges defined by meta.
+// The primary keys of queued items are their queue timestamps in nanoseconds.
+func (s *Server) oldestItem(ctx context.Context, directoryID string, meta *spb.MapMetadata) (time.Time, error) {
+	oldest := time.Now()
+	for _, source := range meta.Sources {
+		if source.LowestInclusive >= source.HighestExclusive {
+			continue // No items in this log.
+		}
+		msgs, err := s.logs.ReadLog(ctx, directoryID, source.LogId,
+			source.LowestInclusive, source.HighestExclusive, 1)
+		if err != nil {
+			return time.Time{}, status.Errorf(codes.Internal, "ReadLog(): %v", err)
+		}
+		if len(msgs) == 0 {
+			continue
+		}
+		if t := time.Unix(0, msgs[0].ID); t.Before(oldest) {
+			oldest = t
+		}
+	}
+	return oldest, nil
+}
+
+// readMessages returns the full set of EntryUpdates defined by sources, in canonical order.
 // batchSize limits the number of messages to read from a log at one time.
 func (s *Server) readMessages(ctx context.Context, directoryID string, meta *spb.MapMetadata,
 	batchSize int32) ([]*mutator.LogMessage, error) {
@@ -273,10 +434,25 @@ func (s *Server) readMessages(ctx context.Context, directoryID string, meta *spb
 			}
 		}
 	}
-	logEntryCount.Add(float64(len(msgs)), directoryID)
+	// Apply mutations in the same order regardless of how they were read.
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Less(msgs[j]) })
 	return msgs, nil
 }
 
+// mutatorFor returns the mutator that directoryID is configured to use.
+func (s *Server) mutatorFor(ctx context.Context, directoryID string) (*mutator.Mutator, error) {
+	d, err := s.directories.Read(ctx, directoryID, false)
+	if err != nil {
+		glog.Errorf("directories.Read(%v): %v", directoryID, err)
+		return nil, status.Errorf(codes.Internal, "Cannot fetch directory info")
+	}
+	mut, err := mutator.Lookup(d.Mutator)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "directory %v: %v", directoryID, err)
+	}
+	return mut, nil
+}
+
 // ApplyRevision applies the supplied mutations to the current map revision and creates a new revision.
 func (s *Server) ApplyRevision(ctx context.Context, in *spb.ApplyRevisionRequest) (*spb.ApplyRevisionResponse, error) {
 	meta, err := s.batcher.ReadBatch(ctx, in.DirectoryId, in.Revision)
@@ -284,39 +460,31 @@ func (s *Server) ApplyRevision(ctx context.Context, in *spb.ApplyRevisionRequest
 		return nil, status.Errorf(codes.Internal, "ReadBatch(%v, %v): %v", in.DirectoryId, in.Revision, err)
 	}
 	glog.Infof("ApplyRevision(): dir: %v, rev: %v, sources: %v", in.DirectoryId, in.Revision, meta)
-	msgs, err := s.readMessages(ctx, in.DirectoryId, meta, s.BatchSize)
-	if err != nil {
-		return nil, err
-	}
 
 	mapClient, err := s.trillian.MapClient(ctx, in.DirectoryId)
 	if err != nil {
 		return nil, err
 	}
-
-	// Parse mutations using the mutator for this directory.
-	indexes := make([][]byte, 0, len(msgs))
-	mutations := make([]*ktpb.EntryUpdate, 0, len(msgs))
-	for _, m := range msgs {
-		if err := entry.MapLogItemFn(m, func(index []byte, mutation *ktpb.EntryUpdate) {
-			indexes = append(indexes, index)
-			mutations = append(mutations, mutation)
-		}); err != nil {
-			return nil, err
-		}
-
-	}
-
-	leaves, err := mapClient.GetAndVerifyMapLeavesByRevision(ctx, in.Revision-1, indexes)
+	mut, err := s.mutatorFor(ctx, in.DirectoryId)
 	if err != nil {
 		return nil, err
 	}
-
-	// Apply mutations to values.
-	newLeaves, err := runner.ApplyMutations(entry.MutateFn, mutations, leaves)
+	r, err := s.computeRevision(ctx, in.DirectoryId, in.Revision, meta, mut, mapClient)
 	if err != nil {
 		return nil, err
 	}
+	logEntryCount.Add(float64(r.msgs), in.DirectoryId)
+	for _, reason := range r.failures {
+		mutationFailures.Inc(in.DirectoryId, reason)
+	}
+
+	// Record rejections before writing the new revision so that they are
+	// not lost if this method needs to be retried.
+	if len(r.rejections) > 0 {
+		if err := s.rejections.WriteRejections(ctx, in.DirectoryId, r.rejections); err != nil {
+			return nil, status.Errorf(codes.Internal, "WriteRejections(): %v", err)
+		}
+	}
 
 	// Serialize metadata
 	metadata, err := proto.Marshal(meta)
@@ -325,7 +493,7 @@ func (s *Server) ApplyRevision(ctx context.Context, in *spb.ApplyRevisionRequest
 	}
 
 	// Set new leaf values.
-	mapRoot, err := mapClient.SetLeavesAtRevision(ctx, in.Revision, newLeaves, metadata)
+	mapRoot, err := mapClient.SetLeavesAtRevision(ctx, in.Revision, r.leaves, metadata)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "VerifySignedMapRoot(): %v", err)
 	}
@@ -335,18 +503,77 @@ func (s *Server) ApplyRevision(ctx context.Context, in *spb.ApplyRevisionRequest
 		watermarkApplied.Set(float64(s.HighestExclusive),
 			in.DirectoryId, fmt.Sprintf("%v", s.LogId))
 	}
-	mapLeafCount.Add(float64(len(newLeaves)), in.DirectoryId)
+	mapLeafCount.Add(float64(len(r.leaves)), in.DirectoryId)
 	mapRevisionCount.Add(1, in.DirectoryId)
 	glog.Infof("ApplyRevision(): dir: %v, rev: %v, root: %x, mutations: %v, indexes: %v, newleaves: %v",
-		in.DirectoryId, mapRoot.Revision, mapRoot.RootHash, len(msgs), len(indexes), len(newLeaves))
+		in.DirectoryId, mapRoot.Revision, mapRoot.RootHash, r.msgs, r.mutations, len(r.leaves))
 	return &spb.ApplyRevisionResponse{
 		DirectoryId: in.DirectoryId,
 		Revision:    in.Revision,
-		Mutations:   int64(len(mutations)),
-		MapLeaves:   int64(len(newLeaves)),
+		Mutations:   int64(r.mutations),
+		MapLeaves:   int64(len(r.leaves)),
 	}, nil
 }
 
+// revision is the result of applying the mutations of a batch to the map.
+type revision struct {
+	leaves     []*tpb.MapLeaf       // Map leaves that changed.
+	rejections []*mutator.Rejection // Mutations that could not be applied.
+	failures   []string             // Failure reason of each rejection.
+	msgs       int                  // Number of log messages read.
+	mutations  int                  // Number of mutations derived from the messages.
+}
+
+// computeRevision reads the log messages defined by meta and applies them to
+// the leaves of mapClient at rev-1. It does not modify the map.
+func (s *Server) computeRevision(ctx context.Context, directoryID string, rev int64, meta *spb.MapMetadata,
+	mut *mutator.Mutator, mapClient trillianMap) (*revision, error) {
+	msgs, err := s.readMessages(ctx, directoryID, meta, s.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse mutations using the mutator for this directory.
+	indexes := make([][]byte, 0, len(msgs))
+	mutations := make([]*ktpb.EntryUpdate, 0, len(msgs))
+	sources := make(map[*ktpb.EntryUpdate]*mutator.LogMessage, len(msgs))
+	for _, m := range msgs {
+		if err := mut.MapLogItem(m, func(index []byte, mutation *ktpb.EntryUpdate) {
+			indexes = append(indexes, index)
+			mutations = append(mutations, mutation)
+			sources[mutation] = m
+		}); err != nil {
+			return nil, err
+		}
+
+	}
+
+	leaves, err := mapClient.GetAndVerifyMapLeavesByRevision(ctx, rev-1, indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply mutations to values.
+	r := &revision{msgs: len(msgs), mutations: len(mutations)}
+	r.leaves, err = runner.ApplyMutations(mut.Reduce, mutations, leaves,
+		func(index []byte, mutation *ktpb.EntryUpdate, err error) {
+			m := sources[mutation]
+			r.rejections = append(r.rejections, &mutator.Rejection{
+				LogID:    m.LogID,
+				ID:       m.ID,
+				LocalID:  m.LocalID,
+				Index:    index,
+				Revision: rev,
+				Reason:   err.Error(),
+			})
+			r.failures = append(r.failures, failureReason(err))
+		})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 // PublishRevisions copies the MapRoots of all known map revisions into the Log of MapRoots.
 func (s *Server) PublishRevisions(ctx context.Context,
 	in *spb.PublishRevisionsRequest) (*spb.PublishRevisionsResponse, error) {
@@ -391,11 +618,77 @@ func (s *Server) PublishRevisions(ctx context.Context,
 			return nil, status.Errorf(codes.Internal, "WaitForInclusion(): %v", err)
 		}
 	}
+
+	if s.EventSink != nil {
+		s.queueEvents(in.DirectoryId, int64(latestMapRoot.Revision))
+	}
 	return &spb.PublishRevisionsResponse{Revisions: revs}, nil
 }
 
+// QueueStatus returns the number of mutations waiting to be included in a
+// revision, the number of revisions waiting to be applied, and the time of the
+// latest revision.
+func (s *Server) QueueStatus(ctx context.Context, in *spb.QueueStatusRequest) (*spb.QueueStatusResponse, error) {
+	mapClient, err := s.trillian.MapClient(ctx, in.DirectoryId)
+	if err != nil {
+		return nil, err
+	}
+	_, latestMapRoot, err := mapClient.GetAndVerifyLatestMapRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	highestRev, err := s.batcher.HighestRev(ctx, in.DirectoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count items after the last defined revision.
+	meta := &spb.MapMetadata{}
+	if highestRev > int64(latestMapRoot.Revision) {
+		if meta, err = s.batcher.ReadBatch(ctx, in.DirectoryId, highestRev); err != nil {
+			return nil, status.Errorf(codes.Internal, "ReadBatch(%v, %v): %v", in.DirectoryId, highestRev, err)
+		}
+	} else if err := proto.Unmarshal(latestMapRoot.Metadata, meta); err != nil {
+		return nil, err
+	}
+	ends := map[int64]int64{}
+	for _, source := range meta.Sources {
+		if ends[source.LogId] < source.HighestExclusive {
+			ends[source.LogId] = source.HighestExclusive
+		}
+	}
+	logIDs, err := s.logs.ListLogs(ctx, in.DirectoryId, false /* writable */)
+	if err != nil {
+		return nil, err
+	}
+	watermarks, err := s.logWatermarks(ctx, in.DirectoryId, logIDs, ends,
+		func(int64) int32 { return in.MaxCount })
+	if err != nil {
+		return nil, err
+	}
+	var pending int64
+	for _, w := range watermarks {
+		pending += int64(w.count)
+	}
+
+	outstanding := highestRev - int64(latestMapRoot.Revision)
+	if outstanding < 0 {
+		outstanding = 0
+	}
+	lastRevision, err := ptypes.TimestampProto(time.Unix(0, int64(latestMapRoot.TimestampNanos)))
+	if err != nil {
+		return nil, err
+	}
+	return &spb.QueueStatusResponse{
+		PendingMutations:     pending,
+		OutstandingRevisions: outstanding,
+		LastRevision:         lastRevision,
+	}, nil
+}
+
 // HighWatermarks returns the total count across all logs and the highest watermark for each log.
 // batchSize is a limit on the total number of items represented by the returned watermarks.
+// batchSize is shared fairly between the logs, which are queried in parallel.
 // TODO(gbelvin): Block until a minBatchSize has been reached or a timeout has occurred.
 func (s *Server) HighWatermarks(ctx context.Context, directoryID string, lastMeta *spb.MapMetadata,
 	batchSize int32) (int32, *spb.MapMetadata, error) {
@@ -421,18 +714,40 @@ func (s *Server) HighWatermarks(ctx context.Context, directoryID string, lastMet
 	if err != nil {
 		return 0, nil, err
 	}
-	// TODO(gbelvin): Get HighWatermarks in parallel.
+	// Find out how many items each log could contribute to the whole batch,
+	// then divide batchSize fairly between the logs so that a busy log cannot
+	// starve the others.
+	available, err := s.logWatermarks(ctx, directoryID, logIDs, ends,
+		func(int64) int32 { return batchSize })
+	if err != nil {
+		return 0, nil, err
+	}
+	counts := make(map[int64]int32, len(logIDs))
+	for logID, w := range available {
+		counts[logID] = w.count
+	}
+	shares := fairShares(batchSize, logIDs, counts)
+
+	// Logs that have more items than their share need their watermark recomputed.
+	var partial []int64
 	for _, logID := range logIDs {
-		low := ends[logID]
-		count, high, err := s.logs.HighWatermark(ctx, directoryID, logID, low, batchSize)
-		if err != nil {
-			return 0, nil, status.Errorf(codes.Internal,
-				"HighWatermark(%v/%v, start: %v, batch: %v): %v",
-				directoryID, logID, low, batchSize, err)
+		if shares[logID] < available[logID].count {
+			partial = append(partial, logID)
 		}
-		starts[logID], ends[logID] = low, high
-		total += count
-		batchSize -= count
+	}
+	limited, err := s.logWatermarks(ctx, directoryID, partial, ends,
+		func(logID int64) int32 { return shares[logID] })
+	if err != nil {
+		return 0, nil, err
+	}
+	for logID, w := range limited {
+		available[logID] = w
+	}
+
+	for _, logID := range logIDs {
+		w := available[logID]
+		starts[logID], ends[logID] = ends[logID], w.high
+		total += w.count
 	}
 
 	meta := &spb.MapMetadata{}
@@ -449,3 +764,74 @@ func (s *Server) HighWatermarks(ctx context.Context, directoryID string, lastMet
 	})
 	return total, meta, nil
 }
+
+// watermark is the result of LogsReader.HighWatermark.
+type watermark struct {
+	count int32
+	high  int64
+}
+
+// logWatermarks queries the high watermark of each log in logIDs, starting at
+// lows[logID] and limited to batchSize(logID) items. At most
+// maxParallelWatermarks queries are outstanding at any time.
+func (s *Server) logWatermarks(ctx context.Context, directoryID string, logIDs []int64,
+	lows map[int64]int64, batchSize func(logID int64) int32) (map[int64]watermark, error) {
+	results := make([]watermark, len(logIDs))
+	errs := make([]error, len(logIDs))
+	sem := make(chan struct{}, maxParallelWatermarks)
+	var wg sync.WaitGroup
+	for i, logID := range logIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, logID int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			low, limit := lows[logID], batchSize(logID)
+			count, high, err := s.logs.HighWatermark(ctx, directoryID, logID, low, limit)
+			if err != nil {
+				errs[i] = status.Errorf(codes.Internal,
+					"HighWatermark(%v/%v, start: %v, batch: %v): %v",
+					directoryID, logID, low, limit, err)
+				return
+			}
+			results[i] = watermark{count: count, high: high}
+		}(i, logID)
+	}
+	wg.Wait()
+
+	watermarks := make(map[int64]watermark, len(logIDs))
+	for i, logID := range logIDs {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		watermarks[logID] = results[i]
+	}
+	return watermarks, nil
+}
+
+// fairShares divides batchSize between logIDs using max-min fairness: logs
+// with fewer than an equal share of items available get everything they have,
+// and the remainder is split evenly between the rest.
+func fairShares(batchSize int32, logIDs []int64, available map[int64]int32) map[int64]int32 {
+	// Visit the logs with the fewest items first so that their unused share
+	// is passed on to the logs that follow. Ties are broken by logID.
+	sorted := append([]int64(nil), logIDs...)
+	sort.Slice(sorted, func(a, b int) bool {
+		if available[sorted[a]] != available[sorted[b]] {
+			return available[sorted[a]] < available[sorted[b]]
+		}
+		return sorted[a] < sorted[b]
+	})
+
+	shares := make(map[int64]int32, len(sorted))
+	for i, logID := range sorted {
+		remaining := int32(len(sorted) - i)
+		share := (batchSize + remaining - 1) / remaining // Round up.
+		if available[logID] < share {
+			share = available[logID]
+		}
+		shares[logID] = share
+		batchSize -= share
+	}
+	return shares
+}
@@ -18,11 +18,18 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/google/keytransparency/core/directory"
+	"github.com/google/keytransparency/core/fake"
 	"github.com/google/keytransparency/core/mutator"
 
 	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
@@ -38,6 +45,7 @@ func (l fakeLogs) ReadLog(ctx context.Context, directoryID string, logID, low, h
 	refs := make([]*mutator.LogMessage, 0, int(high-low))
 	for i := low; i < high; i++ {
 		l[logID][i].ID = i
+		l[logID][i].LogID = logID
 		refs = append(refs, &l[logID][i])
 	}
 	return refs, nil
@@ -77,6 +85,14 @@ func (t *fakeTrillianFactory) LogClient(_ context.Context, _ string) (trillianLo
 	return t.tlog, nil
 }
 
+func (t *fakeTrillianFactory) NewMap(_ context.Context, _ string) (*tpb.Tree, trillianMap, error) {
+	return &tpb.Tree{}, t.tmap, nil
+}
+
+func (t *fakeTrillianFactory) OpenMap(_ context.Context, _ string, mapID int64) (*tpb.Tree, trillianMap, error) {
+	return &tpb.Tree{TreeId: mapID}, t.tmap, nil
+}
+
 type fakeMap struct {
 	MapClient
 	latestMapRoot *types.MapRootV1
@@ -142,6 +158,123 @@ func TestDefineRevisions(t *testing.T) {
 
 }
 
+func TestQueueStatus(t *testing.T) {
+	ctx := context.Background()
+	mapRev := int64(2)
+	initMetrics.Do(func() { createMetrics(monitoring.InertMetricFactory{}) })
+	metadata, err := proto.Marshal(&spb.MapMetadata{Sources: []*spb.MapMetadata_SourceSlice{
+		{LogId: 0, HighestExclusive: 4},
+		{LogId: 1, HighestExclusive: 5},
+	}})
+	if err != nil {
+		t.Fatalf("proto.Marshal(): %v", err)
+	}
+	lastRevision := time.Unix(1000, 0)
+	s := Server{
+		logs: fakeLogs{
+			0: make([]mutator.LogMessage, 10),
+			1: make([]mutator.LogMessage, 20),
+		},
+		trillian: &fakeTrillianFactory{
+			tmap: &fakeMap{latestMapRoot: &types.MapRootV1{
+				Revision:       uint64(mapRev),
+				Metadata:       metadata,
+				TimestampNanos: uint64(lastRevision.UnixNano()),
+			}},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc            string
+		highestRev      int64
+		maxCount        int32
+		wantPending     int64
+		wantOutstanding int64
+	}{
+		{desc: "applied", highestRev: mapRev, maxCount: 100, wantPending: 6 + 15},
+		{desc: "limited", highestRev: mapRev, maxCount: 5, wantPending: 5 + 5},
+		// fakeBatcher defines revisions with no sources, so every item is pending.
+		{desc: "outstanding", highestRev: mapRev + 2, maxCount: 100, wantPending: 10 + 20, wantOutstanding: 2},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			s.batcher = &fakeBatcher{highestRev: tc.highestRev}
+			got, err := s.QueueStatus(ctx, &spb.QueueStatusRequest{DirectoryId: directoryID, MaxCount: tc.maxCount})
+			if err != nil {
+				t.Fatalf("QueueStatus(): %v", err)
+			}
+			if got.PendingMutations != tc.wantPending || got.OutstandingRevisions != tc.wantOutstanding {
+				t.Errorf("QueueStatus(): pending: %v, outstanding: %v, want %v, %v",
+					got.PendingMutations, got.OutstandingRevisions, tc.wantPending, tc.wantOutstanding)
+			}
+			if ts, err := ptypes.Timestamp(got.LastRevision); err != nil || !ts.Equal(lastRevision) {
+				t.Errorf("QueueStatus().LastRevision: %v, %v, want %v", ts, err, lastRevision)
+			}
+		})
+	}
+}
+
+func TestDefineRevisionsTriggers(t *testing.T) {
+	ctx := context.Background()
+	mapRev := int64(2)
+	initMetrics.Do(func() { createMetrics(monitoring.InertMetricFactory{}) })
+	// Queue timestamps in fakeLogs are their indexes, so all items are very old.
+	logs := fakeLogs{
+		0: make([]mutator.LogMessage, 10),
+		1: make([]mutator.LogMessage, 20),
+	}
+	drained, err := proto.Marshal(&spb.MapMetadata{Sources: []*spb.MapMetadata_SourceSlice{
+		{LogId: 0, HighestExclusive: 10},
+		{LogId: 1, HighestExclusive: 20},
+	}})
+	if err != nil {
+		t.Fatalf("proto.Marshal(): %v", err)
+	}
+	lastRevision := time.Now().Add(-2 * time.Hour)
+
+	for _, tc := range []struct {
+		desc        string
+		metadata    []byte
+		minBatch    int32
+		maxLatency  time.Duration
+		maxInterval time.Duration
+		want        []int64
+	}{
+		{desc: "min batch", minBatch: 1, want: []int64{mapRev + 1}},
+		{desc: "below min batch", minBatch: 100, want: []int64{}},
+		{desc: "max latency", minBatch: 100, maxLatency: time.Hour, want: []int64{mapRev + 1}},
+		{desc: "max latency empty", metadata: drained, minBatch: 100, maxLatency: time.Hour, want: []int64{}},
+		{desc: "max interval", metadata: drained, minBatch: 1, maxInterval: time.Hour, want: []int64{mapRev + 1}},
+		{desc: "within max interval", metadata: drained, minBatch: 1, maxInterval: 3 * time.Hour, want: []int64{}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := Server{
+				logs:    logs,
+				batcher: &fakeBatcher{highestRev: mapRev},
+				trillian: &fakeTrillianFactory{
+					tmap: &fakeMap{latestMapRoot: &types.MapRootV1{
+						Revision:       uint64(mapRev),
+						TimestampNanos: uint64(lastRevision.UnixNano()),
+						Metadata:       tc.metadata,
+					}},
+				},
+			}
+			got, err := s.DefineRevisions(ctx, &spb.DefineRevisionsRequest{
+				DirectoryId: directoryID,
+				MinBatch:    tc.minBatch,
+				MaxBatch:    100,
+				MaxLatency:  ptypes.DurationProto(tc.maxLatency),
+				MaxInterval: ptypes.DurationProto(tc.maxInterval),
+			})
+			if err != nil {
+				t.Fatalf("DefineRevisions(): %v", err)
+			}
+			if !cmp.Equal(got.OutstandingRevisions, tc.want) {
+				t.Errorf("DefineRevisions(): %v, want %v", got.OutstandingRevisions, tc.want)
+			}
+		})
+	}
+}
+
 func TestReadMessages(t *testing.T) {
 	ctx := context.Background()
 	s := Server{logs: fakeLogs{
@@ -200,6 +333,16 @@ func TestHighWatermarks(t *testing.T) {
 			next: spb.MapMetadata{Sources: []*spb.MapMetadata_SourceSlice{
 				{LogId: 0, LowestInclusive: 10, HighestExclusive: 10},
 				{LogId: 1, HighestExclusive: 20}}}},
+		{desc: "fair", batchSize: 10, count: 10,
+			next: spb.MapMetadata{Sources: []*spb.MapMetadata_SourceSlice{
+				{LogId: 0, HighestExclusive: 5},
+				{LogId: 1, HighestExclusive: 5}}}},
+		{desc: "unused share", batchSize: 25, count: 25,
+			last: spb.MapMetadata{Sources: []*spb.MapMetadata_SourceSlice{
+				{LogId: 0, HighestExclusive: 5}}},
+			next: spb.MapMetadata{Sources: []*spb.MapMetadata_SourceSlice{
+				{LogId: 0, LowestInclusive: 5, HighestExclusive: 10},
+				{LogId: 1, HighestExclusive: 20}}}},
 		// Don't drop existing watermarks.
 		{desc: "keep existing", batchSize: 1, count: 1,
 			last: spb.MapMetadata{Sources: []*spb.MapMetadata_SourceSlice{
@@ -229,3 +372,58 @@ func TestHighWatermarks(t *testing.T) {
 		})
 	}
 }
+
+func TestFairShares(t *testing.T) {
+	for _, tc := range []struct {
+		desc      string
+		batchSize int32
+		available map[int64]int32
+		want      map[int64]int32
+	}{
+		{desc: "enough", batchSize: 10, available: map[int64]int32{1: 2, 2: 3},
+			want: map[int64]int32{1: 2, 2: 3}},
+		{desc: "even", batchSize: 10, available: map[int64]int32{1: 10, 2: 10},
+			want: map[int64]int32{1: 5, 2: 5}},
+		{desc: "hot log", batchSize: 10, available: map[int64]int32{1: 10, 2: 2, 3: 1},
+			want: map[int64]int32{1: 7, 2: 2, 3: 1}},
+		{desc: "remainder", batchSize: 10, available: map[int64]int32{1: 10, 2: 10, 3: 10},
+			want: map[int64]int32{1: 4, 2: 3, 3: 3}},
+		{desc: "empty", batchSize: 0, available: map[int64]int32{1: 0, 2: 0},
+			want: map[int64]int32{1: 0, 2: 0}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			logIDs := make([]int64, 0, len(tc.available))
+			for logID := range tc.available {
+				logIDs = append(logIDs, logID)
+			}
+			got := fairShares(tc.batchSize, logIDs, tc.available)
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("fairShares(): diff(-got, +want): %v", cmp.Diff(got, tc.want))
+			}
+		})
+	}
+}
+
+func TestRunBatchPaused(t *testing.T) {
+	ctx := context.Background()
+	directories := fake.NewDirectoryStorage()
+	if err := directories.Write(ctx, &directory.Directory{DirectoryID: directoryID}); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+	if err := directories.SetPaused(ctx, directoryID, true, "maintenance"); err != nil {
+		t.Fatalf("SetPaused(): %v", err)
+	}
+	s := Server{directories: directories}
+	for _, tc := range []struct {
+		directoryID string
+		want        codes.Code
+	}{
+		{directoryID: directoryID, want: codes.FailedPrecondition},
+		{directoryID: "unknown", want: codes.NotFound},
+	} {
+		_, err := s.RunBatch(ctx, &spb.RunBatchRequest{DirectoryId: tc.directoryID})
+		if got := status.Code(err); got != tc.want {
+			t.Errorf("RunBatch(%v): %v, want %v", tc.directoryID, err, tc.want)
+		}
+	}
+}
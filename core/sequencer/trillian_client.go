@@ -16,8 +16,13 @@ package sequencer
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -32,6 +37,11 @@ import (
 type trillianFactory interface {
 	MapClient(ctx context.Context, dirID string) (trillianMap, error)
 	LogClient(ctx context.Context, dirID string) (trillianLog, error)
+	// NewMap creates and initializes a new map tree configured like the
+	// directory's map, and returns it with a client for it.
+	NewMap(ctx context.Context, dirID string) (*tpb.Tree, trillianMap, error)
+	// OpenMap returns a client for a map created by NewMap for dirID.
+	OpenMap(ctx context.Context, dirID string, mapID int64) (*tpb.Tree, trillianMap, error)
 }
 
 // trillianMap communicates with the Trilian map and verifies the responses.
@@ -47,6 +57,7 @@ type trillianLog interface {
 	WaitForInclusion(ctx context.Context, data []byte) error
 	UpdateRoot(ctx context.Context) (*types.LogRootV1, error)
 	AddSequencedLeaves(ctx context.Context, dataByIndex map[int64][]byte) error
+	ListByIndex(ctx context.Context, start, count int64) ([]*tpb.LogLeaf, error)
 }
 
 // Trillian contains Trillian gRPC clients and metadata about them.
@@ -54,6 +65,7 @@ type Trillian struct {
 	directories directory.Storage
 	tmap        tpb.TrillianMapClient
 	tlog        tpb.TrillianLogClient
+	mapAdmin    tpb.TrillianAdminClient
 }
 
 // MapClient returns a verifying MapClient
@@ -84,6 +96,77 @@ func (t *Trillian) LogClient(ctx context.Context, dirID string) (trillianLog, er
 	return tclient.NewFromTree(t.tlog, directory.Log, trustedRoot)
 }
 
+// NewMap creates and initializes a new map tree with the same hash strategy
+// and signature algorithm as the map of dirID. Trillian generates its key.
+func (t *Trillian) NewMap(ctx context.Context, dirID string) (*tpb.Tree, trillianMap, error) {
+	directory, err := t.directories.Read(ctx, dirID, false)
+	if err != nil {
+		glog.Errorf("directories.Read(%v): %v", dirID, err)
+		return nil, nil, status.Errorf(codes.Internal, "Cannot fetch directory info for %v", dirID)
+	}
+	if t.mapAdmin == nil {
+		return nil, nil, status.Errorf(codes.Unimplemented, "no map admin client configured")
+	}
+	if got := directory.Map.GetSignatureAlgorithm(); got != sigpb.DigitallySigned_ECDSA {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "unsupported map signature algorithm %v", got)
+	}
+
+	tree, err := tclient.CreateAndInitTree(ctx, &tpb.CreateTreeRequest{
+		Tree: &tpb.Tree{
+			TreeState:          tpb.TreeState_ACTIVE,
+			TreeType:           tpb.TreeType_MAP,
+			HashStrategy:       directory.Map.GetHashStrategy(),
+			HashAlgorithm:      directory.Map.GetHashAlgorithm(),
+			SignatureAlgorithm: directory.Map.GetSignatureAlgorithm(),
+			Description:        fmt.Sprintf("Rebuild of map %v for %v", directory.Map.GetTreeId(), dirID),
+			MaxRootDuration:    ptypes.DurationProto(0 * time.Millisecond),
+		},
+		KeySpec: &keyspb.Specification{
+			Params: &keyspb.Specification_EcdsaParams{
+				EcdsaParams: &keyspb.Specification_ECDSA{
+					Curve: keyspb.Specification_ECDSA_P256,
+				},
+			},
+		},
+	}, t.mapAdmin, t.tmap, t.tlog)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "CreateAndInitTree(): %v", err)
+	}
+	c, err := tclient.NewMapClientFromTree(t.tmap, tree)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tree, &MapClient{MapClient: c}, nil
+}
+
+// OpenMap returns a client for the map tree mapID, which must be configured
+// like the map of dirID without being that map.
+func (t *Trillian) OpenMap(ctx context.Context, dirID string, mapID int64) (*tpb.Tree, trillianMap, error) {
+	directory, err := t.directories.Read(ctx, dirID, false)
+	if err != nil {
+		glog.Errorf("directories.Read(%v): %v", dirID, err)
+		return nil, nil, status.Errorf(codes.Internal, "Cannot fetch directory info for %v", dirID)
+	}
+	if t.mapAdmin == nil {
+		return nil, nil, status.Errorf(codes.Unimplemented, "no map admin client configured")
+	}
+	if mapID == directory.Map.GetTreeId() {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "map %v is in use by directory %v", mapID, dirID)
+	}
+	tree, err := t.mapAdmin.GetTree(ctx, &tpb.GetTreeRequest{TreeId: mapID})
+	if err != nil {
+		return nil, nil, err
+	}
+	if tree.GetTreeType() != tpb.TreeType_MAP || tree.GetHashStrategy() != directory.Map.GetHashStrategy() {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "tree %v is not a map like that of directory %v", mapID, dirID)
+	}
+	c, err := tclient.NewMapClientFromTree(t.tmap, tree)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tree, &MapClient{MapClient: c}, nil
+}
+
 // MapClient interacts with the Trillian Map and verifies its responses.
 type MapClient struct {
 	*tclient.MapClient
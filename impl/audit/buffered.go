@@ -0,0 +1,102 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/glog"
+
+	"github.com/google/keytransparency/impl/authorization"
+)
+
+const (
+	// queueSize is the number of records Buffered holds before Write blocks.
+	queueSize = 4096
+	// maxBatch is the largest number of records written at once.
+	maxBatch = 256
+	// writeTimeout bounds each WriteBatch call.
+	writeTimeout = 10 * time.Second
+)
+
+// BatchSink stores several audit records at once.
+type BatchSink interface {
+	// WriteBatch appends records to the audit log.
+	WriteBatch(ctx context.Context, records []*authorization.AuditRecord) error
+}
+
+// Buffered writes audit records to a BatchSink in the background, so that an
+// RPC only waits for its record to be queued rather than for a disk sync or a
+// database insert. Records that arrive while a batch is being written are
+// written together in the next one.
+type Buffered struct {
+	sink  BatchSink
+	queue chan *authorization.AuditRecord
+	done  chan struct{}
+}
+
+// NewBuffered starts writing records queued by Write to sink.
+func NewBuffered(sink BatchSink) *Buffered {
+	b := &Buffered{
+		sink:  sink,
+		queue: make(chan *authorization.AuditRecord, queueSize),
+		done:  make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Write queues r. It only blocks while the queue is full, until ctx is done.
+func (b *Buffered) Write(ctx context.Context, r *authorization.AuditRecord) error {
+	select {
+	case b.queue <- r:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close writes the records that are still queued and stops the background
+// writer. Write must not be called after Close.
+func (b *Buffered) Close() error {
+	close(b.queue)
+	<-b.done
+	return nil
+}
+
+func (b *Buffered) run() {
+	defer close(b.done)
+	for r := range b.queue {
+		batch := []*authorization.AuditRecord{r}
+	fill:
+		for len(batch) < maxBatch {
+			select {
+			case r, ok := <-b.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, r)
+			default:
+				break fill
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		if err := b.sink.WriteBatch(ctx, batch); err != nil {
+			glog.Errorf("audit: failed to write %v records: %v", len(batch), err)
+		}
+		cancel()
+	}
+}
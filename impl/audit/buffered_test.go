@@ -0,0 +1,69 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/keytransparency/impl/authorization"
+)
+
+// fakeBatchSink records the size of each batch. It blocks in WriteBatch until
+// release is closed.
+type fakeBatchSink struct {
+	release chan struct{}
+
+	mu      sync.Mutex
+	batches []int
+}
+
+func (s *fakeBatchSink) WriteBatch(ctx context.Context, records []*authorization.AuditRecord) error {
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, len(records))
+	return nil
+}
+
+func TestBuffered(t *testing.T) {
+	ctx := context.Background()
+	sink := &fakeBatchSink{release: make(chan struct{})}
+	b := NewBuffered(sink)
+
+	// Writes return while the sink is still busy.
+	for i := 0; i < 10; i++ {
+		if err := b.Write(ctx, &authorization.AuditRecord{}); err != nil {
+			t.Fatalf("Write(): %v", err)
+		}
+	}
+	close(sink.release)
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	var total int
+	for _, n := range sink.batches {
+		total += n
+	}
+	if total != 10 {
+		t.Errorf("wrote %v records, want 10", total)
+	}
+	// The first record is written alone; the rest queue up behind it.
+	if got := len(sink.batches); got > 2 {
+		t.Errorf("wrote %v batches %v, want at most 2", got, sink.batches)
+	}
+}
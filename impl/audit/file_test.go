@@ -0,0 +1,88 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/keytransparency/impl/authorization"
+)
+
+func TestFile(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.jsonl")
+
+	records := []*authorization.AuditRecord{
+		{
+			Time:        time.Unix(1, 0).UTC(),
+			Principal:   "alice@example.com",
+			Method:      "/google.keytransparency.v1.KeyTransparency/QueueEntryUpdate",
+			DirectoryID: "default",
+			UserIDs:     []string{"alice@example.com"},
+			Decision:    authorization.Allow,
+			Rule:        "self",
+		},
+		{
+			Time:     time.Unix(2, 0).UTC(),
+			Method:   "/google.keytransparency.v1.KeyTransparency/QueueEntryUpdate",
+			Decision: authorization.Deny,
+			Rule:     "authentication failed",
+			Error:    "Request unauthenticated with FakeCredential",
+		},
+	}
+	// Records are appended across reopening the file.
+	for _, r := range records {
+		f, err := NewFile(path)
+		if err != nil {
+			t.Fatalf("NewFile(): %v", err)
+		}
+		if err := f.Write(ctx, r); err != nil {
+			t.Fatalf("Write(): %v", err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("Close(): %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var got []*authorization.AuditRecord
+	for s := bufio.NewScanner(file); s.Scan(); {
+		var r authorization.AuditRecord
+		if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+			t.Fatalf("json.Unmarshal(%s): %v", s.Bytes(), err)
+		}
+		got = append(got, &r)
+	}
+	if !cmp.Equal(got, records) {
+		t.Errorf("records: %v", cmp.Diff(got, records))
+	}
+}
@@ -25,6 +25,9 @@ import "context"
 // SecurityContext is the auth value stored in the Contexts.
 type SecurityContext struct {
 	Email string
+	// EmailVerified is true if the identity provider verified that Email
+	// belongs to the caller.
+	EmailVerified bool
 }
 
 // securityContextKey identifies SecurityContext within context.Context.
@@ -36,3 +39,8 @@ func FromContext(ctx context.Context) (*SecurityContext, bool) {
 	v, ok := ctx.Value(securityContextKey).(*SecurityContext)
 	return v, ok
 }
+
+// NewContext returns a copy of ctx that carries sctx.
+func NewContext(ctx context.Context, sctx *SecurityContext) context.Context {
+	return context.WithValue(ctx, securityContextKey, sctx)
+}
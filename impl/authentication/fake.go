@@ -35,7 +35,8 @@ func FakeAuthFunc(ctx context.Context) (context.Context, error) {
 	}
 
 	return context.WithValue(ctx, securityContextKey, &SecurityContext{
-		Email: token,
+		Email:         token,
+		EmailVerified: true,
 	}), nil
 }
 
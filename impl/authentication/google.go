@@ -73,7 +73,8 @@ func (a *GAuth) AuthFunc(ctx context.Context) (context.Context, error) {
 		return nil, status.Error(codes.Unauthenticated, "auth: missing scope")
 	}
 	return context.WithValue(ctx, securityContextKey, &SecurityContext{
-		Email: tokenInfo.Email,
+		Email:         tokenInfo.Email,
+		EmailVerified: true,
 	}), nil
 }
 
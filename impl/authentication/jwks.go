@@ -0,0 +1,293 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authentication
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// minKeySetRefresh bounds how often a KeySet is fetched again because a
+	// token names a key it does not contain.
+	minKeySetRefresh = time.Minute
+	// keySetFetchTimeout bounds each fetch of a KeySet.
+	keySetFetchTimeout = 10 * time.Second
+	// minKeySetBackoff and maxKeySetBackoff bound the time a KeySet waits
+	// before fetching again after a failed fetch. The wait doubles with each
+	// consecutive failure.
+	minKeySetBackoff = time.Second
+	maxKeySetBackoff = 5 * time.Minute
+)
+
+// KeySet is a JSON Web Key Set (RFC 7517) of token signing keys. The set is
+// cached, and fetched again once it is older than its maximum age, or when a
+// token is signed by a key that is not in the set, so that identity providers
+// can rotate their keys. Only one fetch runs at a time, and callers do not
+// hold up each other while it runs.
+type KeySet struct {
+	fetch  func(ctx context.Context) ([]byte, error)
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]crypto.PublicKey
+	// fetched is the time of the last successful fetch, and attempted the
+	// time of the last fetch.
+	fetched   time.Time
+	attempted time.Time
+	// backoff is the time to wait after attempted before fetching again.
+	backoff time.Duration
+	// inflight is closed when the running fetch finishes, if there is one.
+	inflight chan struct{}
+}
+
+// NewKeySetFromFile returns a KeySet that is read from the file at path.
+func NewKeySetFromFile(path string, maxAge time.Duration) *KeySet {
+	return newKeySet(func(context.Context) ([]byte, error) {
+		return ioutil.ReadFile(path)
+	}, maxAge)
+}
+
+// NewKeySetFromURL returns a KeySet that is fetched from url with client.
+// A client with a timeout of keySetFetchTimeout is used if client is nil.
+func NewKeySetFromURL(url string, client *http.Client, maxAge time.Duration) *KeySet {
+	if client == nil {
+		client = &http.Client{Timeout: keySetFetchTimeout}
+	}
+	return newKeySet(func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("jwks: GET %v: %v", url, resp.Status)
+		}
+		return ioutil.ReadAll(resp.Body)
+	}, maxAge)
+}
+
+func newKeySet(fetch func(ctx context.Context) ([]byte, error), maxAge time.Duration) *KeySet {
+	return &KeySet{fetch: fetch, maxAge: maxAge, now: time.Now}
+}
+
+// Key returns the key with the key ID kid. If kid is empty, and the set
+// contains exactly one key, Key returns that key.
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if (s.keys == nil || s.now().Sub(s.fetched) > s.maxAge) && s.canFetch() {
+		s.refresh(ctx)
+	}
+	key, ok := s.find(kid)
+	if !ok && s.keys != nil && s.now().Sub(s.attempted) > minKeySetRefresh && s.canFetch() {
+		// The key may have been added since the set was fetched.
+		s.refresh(ctx)
+		key, ok = s.find(kid)
+	}
+	if s.keys == nil {
+		return nil, fmt.Errorf("jwks: no keys available")
+	}
+	if !ok {
+		return nil, fmt.Errorf("jwks: key %q not found", kid)
+	}
+	return key, nil
+}
+
+// find looks up kid in the cached keys. s.mu must be held.
+func (s *KeySet) find(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, k := range s.keys {
+			return k, true
+		}
+	}
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// canFetch returns false while the KeySet backs off after a failed fetch.
+// s.mu must be held.
+func (s *KeySet) canFetch() bool {
+	return s.inflight != nil || s.now().Sub(s.attempted) >= s.backoff
+}
+
+// refresh fetches the key set, or waits for the running fetch to finish. The
+// cached keys are kept if the fetch fails. s.mu must be held; it is released
+// while the fetch runs.
+func (s *KeySet) refresh(ctx context.Context) {
+	if done := s.inflight; done != nil {
+		s.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+		s.mu.Lock()
+		return
+	}
+	done := make(chan struct{})
+	s.inflight = done
+	s.attempted = s.now()
+	s.mu.Unlock()
+
+	// The fetch is shared by every waiting caller, so it is not bound to
+	// the context of the caller that started it.
+	fctx, cancel := context.WithTimeout(context.Background(), keySetFetchTimeout)
+	keys, err := s.fetchKeys(fctx)
+	cancel()
+
+	s.mu.Lock()
+	s.inflight = nil
+	close(done)
+	if err != nil {
+		s.backoff *= 2
+		if s.backoff < minKeySetBackoff {
+			s.backoff = minKeySetBackoff
+		}
+		if s.backoff > maxKeySetBackoff {
+			s.backoff = maxKeySetBackoff
+		}
+		glog.Errorf("jwks: %v, retrying in %v", err, s.backoff)
+		return
+	}
+	s.keys = keys
+	s.fetched = s.attempted
+	s.backoff = 0
+}
+
+// fetchKeys fetches and parses the key set.
+func (s *KeySet) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	b, err := s.fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching key set: %v", err)
+	}
+	keys, err := parseKeySet(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing key set: %v", err)
+	}
+	return keys, nil
+}
+
+// jsonWebKey contains the JWK fields of RSA and EC public keys.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseKeySet returns the signing keys in a JWKS document keyed by key ID.
+// Keys of unsupported types are skipped.
+func parseKeySet(b []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			glog.V(2).Infof("jwks: skipping key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no supported signing keys")
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %v", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("empty integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,227 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authentication
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+
+	_ "crypto/sha256" // Register SHA-256 and SHA-384.
+	_ "crypto/sha512" // Register SHA-512.
+)
+
+// allowedClockSkew is the tolerance for exp and nbf claims.
+const allowedClockSkew = time.Minute
+
+// signingAlgs maps the supported JWS algorithms to their hash functions.
+var signingAlgs = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+}
+
+// JWTAuth authenticates callers with OpenID Connect ID tokens, which are JSON
+// Web Tokens signed by an identity provider.
+type JWTAuth struct {
+	issuer   string
+	audience string
+	keys     *KeySet
+	now      func() time.Time
+}
+
+// NewJWTAuth returns an authenticator that accepts ID tokens issued by issuer
+// for audience, and signed by one of keys.
+func NewJWTAuth(issuer, audience string, keys *KeySet) *JWTAuth {
+	return &JWTAuth{issuer: issuer, audience: audience, keys: keys, now: time.Now}
+}
+
+// idTokenClaims are the ID token claims checked by JWTAuth.
+type idTokenClaims struct {
+	Issuer        string       `json:"iss"`
+	Audience      audience     `json:"aud"`
+	Expiry        int64        `json:"exp"`
+	NotBefore     int64        `json:"nbf"`
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+}
+
+// audience is an aud claim, which is either a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	var l []string
+	if err := json.Unmarshal(b, &l); err != nil {
+		return err
+	}
+	*a = l
+	return nil
+}
+
+func (a audience) contains(s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// flexibleBool is a boolean claim. Some identity providers send
+// email_verified as the string "true".
+type flexibleBool bool
+
+func (f *flexibleBool) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case bool:
+		*f = flexibleBool(t)
+	case string:
+		*f = t == "true"
+	default:
+		return fmt.Errorf("invalid boolean %s", b)
+	}
+	return nil
+}
+
+// AuthFunc implements go-grpc-middleware/auth.AuthFunc.
+// AuthFunc verifies the bearer ID token in ctx and puts a SecurityContext with
+// the token's email and email_verified claims in the returned ctx.
+func (a *JWTAuth) AuthFunc(ctx context.Context) (context.Context, error) {
+	token, err := grpc_auth.AuthFromMD(ctx, "bearer")
+	if err != nil {
+		return nil, err
+	}
+	claims, err := a.verify(ctx, token)
+	if err != nil {
+		glog.V(2).Infof("jwt: rejecting token: %v", err)
+		return nil, status.Error(codes.Unauthenticated, "auth: invalid ID token")
+	}
+	return context.WithValue(ctx, securityContextKey, &SecurityContext{
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+	}), nil
+}
+
+// verify checks the signature and claims of token and returns its claims.
+func (a *JWTAuth) verify(ctx context.Context, token string) (*idTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("header: %v", err)
+	}
+	hash, ok := signingAlgs[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+	key, err := a.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("signature: %v", err)
+	}
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	if err := verifySignature(header.Alg, key, hash, h.Sum(nil), sig); err != nil {
+		return nil, err
+	}
+
+	var claims idTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("claims: %v", err)
+	}
+	now := a.now()
+	switch {
+	case claims.Issuer != a.issuer:
+		return nil, fmt.Errorf("issuer %q, want %q", claims.Issuer, a.issuer)
+	case !claims.Audience.contains(a.audience):
+		return nil, fmt.Errorf("audience %q does not contain %q", claims.Audience, a.audience)
+	case claims.Expiry == 0:
+		return nil, errors.New("no expiry")
+	case now.After(time.Unix(claims.Expiry, 0).Add(allowedClockSkew)):
+		return nil, errors.New("token expired")
+	case claims.NotBefore != 0 && now.Add(allowedClockSkew).Before(time.Unix(claims.NotBefore, 0)):
+		return nil, errors.New("token not valid yet")
+	case claims.Email == "":
+		return nil, errors.New("no email claim")
+	}
+	return &claims, nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, hash crypto.Hash, digest, sig []byte) error {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %v does not match RSA key", alg)
+		}
+		return rsa.VerifyPKCS1v15(k, hash, digest, sig)
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || hash.Size() != size {
+			return fmt.Errorf("algorithm %v does not match %v key", alg, k.Curve.Params().Name)
+		}
+		if len(sig) != 2*size {
+			return errors.New("invalid signature length")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,390 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authentication
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "keytransparency"
+)
+
+// testKey is a signing key of a test identity provider.
+type testKey struct {
+	kid string
+	alg string
+	key crypto.Signer
+}
+
+func newRSAKey(t *testing.T, kid string) *testKey {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey(): %v", err)
+	}
+	return &testKey{kid: kid, alg: "RS256", key: k}
+}
+
+func newECKey(t *testing.T, kid string) *testKey {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey(): %v", err)
+	}
+	return &testKey{kid: kid, alg: "ES256", key: k}
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+// padded returns i as a big-endian byte string of length n.
+func padded(i *big.Int, n int) []byte {
+	b := i.Bytes()
+	return append(make([]byte, n-len(b)), b...)
+}
+
+// jwks returns a JWKS document containing keys.
+func jwks(t *testing.T, keys ...*testKey) []byte {
+	t.Helper()
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for _, k := range keys {
+		switch pub := k.key.Public().(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "RSA", "kid": k.kid, "use": "sig",
+				"n": b64(pub.N.Bytes()), "e": b64(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "EC", "kid": k.kid, "crv": "P-256",
+				"x": b64(padded(pub.X, 32)), "y": b64(padded(pub.Y, 32)),
+			})
+		}
+	}
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("json.Marshal(): %v", err)
+	}
+	return b
+}
+
+// sign returns a token with claims signed by k.
+func (k *testKey) sign(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": k.alg, "kid": k.kid, "typ": "JWT"})
+	if err != nil {
+		t.Fatalf("json.Marshal(): %v", err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("json.Marshal(): %v", err)
+	}
+	signed := b64(header) + "." + b64(payload)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+	var sig []byte
+	switch priv := k.key.(type) {
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest.Sum(nil))
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, priv, digest.Sum(nil))
+		sig = append(padded(r, 32), padded(s, 32)...)
+	}
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return signed + "." + b64(sig)
+}
+
+func claims(now time.Time, overrides map[string]interface{}) map[string]interface{} {
+	c := map[string]interface{}{
+		"iss":            testIssuer,
+		"aud":            testAudience,
+		"exp":            now.Add(time.Hour).Unix(),
+		"iat":            now.Unix(),
+		"email":          "alice@example.com",
+		"email_verified": true,
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(c, k)
+			continue
+		}
+		c[k] = v
+	}
+	return c
+}
+
+func bearerContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "bearer "+token))
+}
+
+func writeFile(t *testing.T, path string, b []byte) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+}
+
+func TestJWTAuthFunc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jwks.json")
+
+	rsaKey := newRSAKey(t, "rsa1")
+	ecKey := newECKey(t, "ec1")
+	otherKey := newRSAKey(t, "rsa1")
+	writeFile(t, path, jwks(t, rsaKey, ecKey))
+
+	now := time.Now()
+	auth := NewJWTAuth(testIssuer, testAudience, NewKeySetFromFile(path, time.Hour))
+
+	for _, tc := range []struct {
+		desc         string
+		token        string
+		wantEmail    string
+		wantVerified bool
+		wantCode     codes.Code
+	}{
+		{
+			desc:         "rsa",
+			token:        rsaKey.sign(t, claims(now, nil)),
+			wantEmail:    "alice@example.com",
+			wantVerified: true,
+		},
+		{
+			desc:         "ecdsa",
+			token:        ecKey.sign(t, claims(now, nil)),
+			wantEmail:    "alice@example.com",
+			wantVerified: true,
+		},
+		{
+			desc:         "audience list",
+			token:        rsaKey.sign(t, claims(now, map[string]interface{}{"aud": []string{"other", testAudience}})),
+			wantEmail:    "alice@example.com",
+			wantVerified: true,
+		},
+		{
+			desc:         "email_verified string",
+			token:        rsaKey.sign(t, claims(now, map[string]interface{}{"email_verified": "true"})),
+			wantEmail:    "alice@example.com",
+			wantVerified: true,
+		},
+		{
+			desc:      "unverified email",
+			token:     rsaKey.sign(t, claims(now, map[string]interface{}{"email_verified": false})),
+			wantEmail: "alice@example.com",
+		},
+		{
+			desc:      "no email_verified",
+			token:     rsaKey.sign(t, claims(now, map[string]interface{}{"email_verified": nil})),
+			wantEmail: "alice@example.com",
+		},
+		{
+			desc:         "expired within skew",
+			token:        rsaKey.sign(t, claims(now, map[string]interface{}{"exp": now.Add(-allowedClockSkew / 2).Unix()})),
+			wantEmail:    "alice@example.com",
+			wantVerified: true,
+		},
+		{
+			desc:     "expired",
+			token:    rsaKey.sign(t, claims(now, map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "no expiry",
+			token:    rsaKey.sign(t, claims(now, map[string]interface{}{"exp": nil})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "not valid yet",
+			token:    rsaKey.sign(t, claims(now, map[string]interface{}{"nbf": now.Add(time.Hour).Unix()})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "wrong issuer",
+			token:    rsaKey.sign(t, claims(now, map[string]interface{}{"iss": "https://evil.example.com"})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "wrong audience",
+			token:    rsaKey.sign(t, claims(now, map[string]interface{}{"aud": "other"})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "no email",
+			token:    rsaKey.sign(t, claims(now, map[string]interface{}{"email": nil})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "unknown signer",
+			token:    otherKey.sign(t, claims(now, nil)),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "unknown key id",
+			token:    (&testKey{kid: "rsa2", alg: "RS256", key: rsaKey.key}).sign(t, claims(now, nil)),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "algorithm does not match key",
+			token:    (&testKey{kid: "ec1", alg: "RS256", key: rsaKey.key}).sign(t, claims(now, nil)),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "unsigned",
+			token:    b64([]byte(`{"alg":"none"}`)) + "." + b64([]byte(`{}`)) + ".",
+			wantCode: codes.Unauthenticated,
+		},
+		{desc: "malformed", token: "not.a-token", wantCode: codes.Unauthenticated},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			sctx, err := auth.AuthFunc(bearerContext(tc.token))
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("AuthFunc(): %v, want %v", err, want)
+			}
+			if err != nil {
+				return
+			}
+			validated, ok := FromContext(sctx)
+			if !ok {
+				t.Fatalf("FromContext(): no SecurityContext found")
+			}
+			if got, want := validated.Email, tc.wantEmail; got != want {
+				t.Errorf("SecurityContext.Email: %v, want %v", got, want)
+			}
+			if got, want := validated.EmailVerified, tc.wantVerified; got != want {
+				t.Errorf("SecurityContext.EmailVerified: %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	ctx := context.Background()
+	key1 := newECKey(t, "key1")
+	key2 := newECKey(t, "key2")
+	served := jwks(t, key1)
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write(served)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	keys := NewKeySetFromURL(srv.URL, srv.Client(), time.Hour)
+	keys.now = func() time.Time { return now }
+
+	for _, step := range []struct {
+		desc        string
+		advance     time.Duration
+		serve       []byte
+		kid         string
+		wantErr     bool
+		wantFetches int
+	}{
+		{desc: "first use", kid: "key1", wantFetches: 1},
+		{desc: "cached", kid: "key1", wantFetches: 1},
+		{desc: "unknown key, recently fetched", serve: jwks(t, key1, key2), kid: "key2", wantErr: true, wantFetches: 1},
+		{desc: "unknown key, refetched", advance: 2 * minKeySetRefresh, kid: "key2", wantFetches: 2},
+		{desc: "old key still cached", kid: "key1", wantFetches: 2},
+		{desc: "expired", advance: 2 * time.Hour, serve: jwks(t, key2), kid: "key1", wantErr: true, wantFetches: 3},
+		{desc: "fetch failure keeps keys", advance: 2 * time.Hour, serve: []byte("garbage"), kid: "key2", wantFetches: 4},
+		{desc: "backing off", advance: minKeySetBackoff / 2, kid: "key2", wantFetches: 4},
+		{desc: "retried after backoff", advance: minKeySetBackoff, kid: "key2", wantFetches: 5},
+		{desc: "backoff doubled", advance: minKeySetBackoff, kid: "key2", wantFetches: 5},
+		{desc: "recovered", advance: 2 * minKeySetBackoff, serve: jwks(t, key2), kid: "key2", wantFetches: 6},
+		{desc: "fresh after recovery", kid: "key2", wantFetches: 6},
+	} {
+		now = now.Add(step.advance)
+		if step.serve != nil {
+			served = step.serve
+		}
+		_, err := keys.Key(ctx, step.kid)
+		if got := err != nil; got != step.wantErr {
+			t.Errorf("%v: Key(%v): %v, wantErr %v", step.desc, step.kid, err, step.wantErr)
+		}
+		if fetches != step.wantFetches {
+			t.Errorf("%v: %v fetches, want %v", step.desc, fetches, step.wantFetches)
+		}
+	}
+}
+
+func TestKeySetConcurrentFetch(t *testing.T) {
+	served := jwks(t, newECKey(t, "key1"))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&fetches, 1) == 1 {
+			close(started)
+		}
+		<-release
+		w.Write(served)
+	}))
+	defer srv.Close()
+	keys := NewKeySetFromURL(srv.URL, srv.Client(), time.Hour)
+
+	first := make(chan error)
+	go func() {
+		_, err := keys.Key(context.Background(), "key1")
+		first <- err
+	}()
+	<-started
+
+	// A caller that gives up does not wait for the running fetch, and does
+	// not start another one.
+	cctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := keys.Key(cctx, "key1"); err == nil {
+		t.Errorf("Key() during fetch with canceled context: nil error, want error")
+	}
+
+	close(release)
+	if err := <-first; err != nil {
+		t.Errorf("Key(): %v", err)
+	}
+	if got := atomic.LoadInt32(&fetches); got != 1 {
+		t.Errorf("%v fetches, want 1", got)
+	}
+}
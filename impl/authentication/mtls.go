@@ -0,0 +1,113 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authentication
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+
+	"github.com/golang/glog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// MTLSAuth authenticates callers by the client certificates they present
+// during the TLS handshake.
+type MTLSAuth struct {
+	roots *x509.CertPool
+}
+
+// NewMTLSAuth returns an authenticator that accepts client certificates
+// issued by the certificate authorities in caPEM.
+func NewMTLSAuth(caPEM []byte) (*MTLSAuth, error) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("mtls: no certificates found in client CA bundle")
+	}
+	return &MTLSAuth{roots: roots}, nil
+}
+
+// NewMTLSAuthFromFile returns an authenticator that accepts client
+// certificates issued by the certificate authorities in the PEM file caFile.
+func NewMTLSAuthFromFile(caFile string) (*MTLSAuth, error) {
+	caPEM, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewMTLSAuth(caPEM)
+}
+
+// ConfigureTLS makes servers using config ask for client certificates issued
+// by the client certificate authorities. Connections without a client
+// certificate are still accepted, so that unauthenticated methods keep working.
+func (a *MTLSAuth) ConfigureTLS(config *tls.Config) {
+	config.ClientCAs = a.roots
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+}
+
+// AuthFunc implements go-grpc-middleware/auth.AuthFunc.
+// AuthFunc verifies the client certificate of the connection and puts a
+// SecurityContext in the returned ctx. The principal is the first email
+// address in the certificate's subject alternative names, or the first URI if
+// there are no email addresses.
+func (a *MTLSAuth) AuthFunc(ctx context.Context) (context.Context, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "mtls: no peer information")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "mtls: connection does not use TLS")
+	}
+	certs := tlsInfo.State.PeerCertificates
+	if len(certs) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "mtls: no client certificate")
+	}
+
+	// Verify the chain here too, so that authentication does not depend on
+	// how the server's TLS config was set up.
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         a.roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		glog.V(2).Infof("mtls: client certificate %v: %v", certs[0].Subject, err)
+		return nil, status.Error(codes.Unauthenticated, "mtls: invalid client certificate")
+	}
+
+	var principal string
+	switch {
+	case len(certs[0].EmailAddresses) > 0:
+		principal = certs[0].EmailAddresses[0]
+	case len(certs[0].URIs) > 0:
+		principal = certs[0].URIs[0].String()
+	default:
+		return nil, status.Error(codes.Unauthenticated, "mtls: client certificate has no email or URI")
+	}
+	// The certificate authority vouches for the names in the certificate.
+	return context.WithValue(ctx, securityContextKey, &SecurityContext{
+		Email:         principal,
+		EmailVerified: true,
+	}), nil
+}
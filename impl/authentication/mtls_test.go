@@ -0,0 +1,259 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authentication
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// testCA issues certificates for tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatalf("CreateCertificate(): %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate(): %v", err)
+	}
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns a certificate and its key for the given names.
+func (ca *testCA) issue(t *testing.T, usage x509.ExtKeyUsage, emails []string, uris ...string) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:   big.NewInt(2),
+		Subject:        pkix.Name{CommonName: "test client"},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{usage},
+		EmailAddresses: emails,
+		DNSNames:       []string{"localhost"},
+	}
+	for _, u := range uris {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			t.Fatalf("url.Parse(%v): %v", u, err)
+		}
+		tmpl.URIs = append(tmpl.URIs, parsed)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate(): %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate(): %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func peerContext(ctx context.Context, certs ...tls.Certificate) context.Context {
+	var state tls.ConnectionState
+	for _, c := range certs {
+		state.PeerCertificates = append(state.PeerCertificates, c.Leaf)
+	}
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
+
+func TestMTLSAuthFunc(t *testing.T) {
+	ctx := context.Background()
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+	auth, err := NewMTLSAuth(ca.pem)
+	if err != nil {
+		t.Fatalf("NewMTLSAuth(): %v", err)
+	}
+	clientAuth := x509.ExtKeyUsageClientAuth
+
+	for _, tc := range []struct {
+		desc          string
+		ctx           context.Context
+		wantPrincipal string
+		wantCode      codes.Code
+	}{
+		{
+			desc:          "email",
+			ctx:           peerContext(ctx, ca.issue(t, clientAuth, []string{"frontend@example.com"})),
+			wantPrincipal: "frontend@example.com",
+		},
+		{
+			desc:          "uri",
+			ctx:           peerContext(ctx, ca.issue(t, clientAuth, nil, "spiffe://example.com/frontend")),
+			wantPrincipal: "spiffe://example.com/frontend",
+		},
+		{
+			desc:          "email before uri",
+			ctx:           peerContext(ctx, ca.issue(t, clientAuth, []string{"frontend@example.com"}, "spiffe://example.com/frontend")),
+			wantPrincipal: "frontend@example.com",
+		},
+		{
+			desc:     "no names",
+			ctx:      peerContext(ctx, ca.issue(t, clientAuth, nil)),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "untrusted issuer",
+			ctx:      peerContext(ctx, otherCA.issue(t, clientAuth, []string{"frontend@example.com"})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "server certificate",
+			ctx:      peerContext(ctx, ca.issue(t, x509.ExtKeyUsageServerAuth, []string{"frontend@example.com"})),
+			wantCode: codes.Unauthenticated,
+		},
+		{desc: "no certificate", ctx: peerContext(ctx), wantCode: codes.Unauthenticated},
+		{desc: "no peer", ctx: ctx, wantCode: codes.Unauthenticated},
+		{
+			desc:     "not tls",
+			ctx:      peer.NewContext(ctx, &peer.Peer{}),
+			wantCode: codes.Unauthenticated,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			sctx, err := auth.AuthFunc(tc.ctx)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("AuthFunc(): %v, want %v", err, want)
+			}
+			if err != nil {
+				return
+			}
+			validated, ok := FromContext(sctx)
+			if !ok {
+				t.Fatalf("FromContext(): no SecurityContext found")
+			}
+			if got, want := validated.Email, tc.wantPrincipal; got != want {
+				t.Errorf("SecurityContext.Email: %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestMTLSHandshake checks that ConfigureTLS asks clients for certificates
+// that AuthFunc accepts.
+func TestMTLSHandshake(t *testing.T) {
+	ca := newTestCA(t)
+	auth, err := NewMTLSAuth(ca.pem)
+	if err != nil {
+		t.Fatalf("NewMTLSAuth(): %v", err)
+	}
+	serverCert := ca.issue(t, x509.ExtKeyUsageServerAuth, nil)
+	serverConfig := &tls.Config{Certificates: []tls.Certificate{serverCert}}
+	auth.ConfigureTLS(serverConfig)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	for _, tc := range []struct {
+		desc          string
+		clientCerts   []tls.Certificate
+		wantPrincipal string
+		wantCode      codes.Code
+	}{
+		{
+			desc:          "client certificate",
+			clientCerts:   []tls.Certificate{ca.issue(t, x509.ExtKeyUsageClientAuth, []string{"frontend@example.com"})},
+			wantPrincipal: "frontend@example.com",
+		},
+		{desc: "no client certificate", wantCode: codes.Unauthenticated},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			c, s := net.Pipe()
+			defer c.Close()
+			defer s.Close()
+			client := tls.Client(c, &tls.Config{
+				RootCAs:      roots,
+				ServerName:   "localhost",
+				Certificates: tc.clientCerts,
+			})
+			server := tls.Server(s, serverConfig)
+			errc := make(chan error, 1)
+			go func() { errc <- client.Handshake() }()
+			if err := server.Handshake(); err != nil {
+				t.Fatalf("server Handshake(): %v", err)
+			}
+			if err := <-errc; err != nil {
+				t.Fatalf("client Handshake(): %v", err)
+			}
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{
+				AuthInfo: credentials.TLSInfo{State: server.ConnectionState()},
+			})
+			sctx, err := auth.AuthFunc(ctx)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("AuthFunc(): %v, want %v", err, want)
+			}
+			if err != nil {
+				return
+			}
+			validated, _ := FromContext(sctx)
+			if got, want := validated.Email, tc.wantPrincipal; got != want {
+				t.Errorf("SecurityContext.Email: %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestNewMTLSAuth(t *testing.T) {
+	if _, err := NewMTLSAuth([]byte("not a certificate")); err == nil {
+		t.Errorf("NewMTLSAuth(garbage): nil error, want error")
+	}
+}
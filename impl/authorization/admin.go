@@ -0,0 +1,151 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/keytransparency/impl/authentication"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	authzpb "github.com/google/keytransparency/impl/authorization/authz_go_proto"
+)
+
+const (
+	// AdminService is the full name of the KeyTransparencyAdmin service.
+	AdminService = "google.keytransparency.v1.KeyTransparencyAdmin"
+	// allDirectories is the admin resource that covers every directory.
+	allDirectories = "directories"
+)
+
+// adminMethods are the methods of AdminService.
+var adminMethods = []string{
+	"ListDirectories",
+	"GetDirectory",
+	"CreateDirectory",
+	"DeleteDirectory",
+	"UndeleteDirectory",
+	"PauseDirectory",
+	"ResumeDirectory",
+	"ListInputLogs",
+	"CreateInputLog",
+	"UpdateInputLog",
+	"GarbageCollect",
+	"PruneQueue",
+	"RebuildMap",
+	"GetMapRebuild",
+	"CancelMapRebuild",
+}
+
+// AdminAuthPairs returns AuthPairs for every method of AdminService that
+// authenticate calls with authFunc and authorize them with authzFunc, which is
+// typically AuthzPolicy.AuthorizeAdmin.
+func AdminAuthPairs(authFunc grpc_auth.AuthFunc, authzFunc AuthzFunc) map[string]AuthPair {
+	pairs := make(map[string]AuthPair, len(adminMethods))
+	for _, m := range adminMethods {
+		pairs[fmt.Sprintf("/%v/%v", AdminService, m)] = AuthPair{
+			AuthnFunc: authFunc,
+			AuthzFunc: authzFunc,
+		}
+	}
+	return pairs
+}
+
+// adminRole returns the admin role required to make the request m.
+func adminRole(m interface{}) (authzpb.AuthorizationPolicy_AdminRole, bool) {
+	switch m.(type) {
+	case *pb.ListDirectoriesRequest,
+		*pb.GetDirectoryRequest,
+		*pb.ListInputLogsRequest,
+		*pb.GetMapRebuildRequest:
+		return authzpb.AuthorizationPolicy_VIEWER, true
+	case *pb.PauseDirectoryRequest,
+		*pb.ResumeDirectoryRequest,
+		*pb.CreateInputLogRequest,
+		*pb.UpdateInputLogRequest,
+		*pb.PruneQueueRequest:
+		return authzpb.AuthorizationPolicy_OPERATOR, true
+	case *pb.CreateDirectoryRequest,
+		*pb.DeleteDirectoryRequest,
+		*pb.UndeleteDirectoryRequest,
+		*pb.GarbageCollectRequest,
+		*pb.RebuildMapRequest,
+		*pb.CancelMapRebuildRequest:
+		return authzpb.AuthorizationPolicy_OWNER, true
+	default:
+		return authzpb.AuthorizationPolicy_ADMIN_ROLE_UNSPECIFIED, false
+	}
+}
+
+// AuthorizeAdmin verifies that the identity issuing a KeyTransparencyAdmin call
+// holds the admin role that the call requires.
+// ctx must contain an authentication.SecurityContext.
+// A call is authorized if SecurityContext.EmailVerified is set, and
+// SecurityContext.Email is in a role with a sufficient
+// admin_role for directories/directoryID, or for all directories.
+// Calls that are not about one directory, such as ListDirectories, require a
+// role for all directories. Deny rules for the directory, or for all
+// directories, take precedence over roles.
+func (a *AuthzPolicy) AuthorizeAdmin(ctx context.Context, m interface{}) error {
+	sctx, ok := authentication.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "Request does not contain a ValidatedSecurity object")
+	}
+	if err := checkVerified(ctx, sctx); err != nil {
+		return err
+	}
+	want, ok := adminRole(m)
+	if !ok {
+		recordRule(ctx, "unrecognized request")
+		return status.Errorf(codes.PermissionDenied, "message type %T not recognized", m)
+	}
+	var directoryID string
+	if d, ok := m.(interface{ GetDirectoryId() string }); ok {
+		directoryID = d.GetDirectoryId()
+	}
+	return a.checkAdminPermission(ctx, sctx, directoryID, want)
+}
+
+func (a *AuthzPolicy) checkAdminPermission(ctx context.Context, sctx *authentication.SecurityContext, directoryID string,
+	want authzpb.AuthorizationPolicy_AdminRole) error {
+	resources := []string{allDirectories}
+	if directoryID != "" {
+		rLabel, err := resourceLabel(directoryID)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, rLabel)
+	}
+	if err := a.checkDenied(ctx, sctx, resources...); err != nil {
+		return err
+	}
+	for _, r := range resources {
+		for _, l := range a.Policy.GetAdminResourceToRoleLabels()[r].GetLabels() {
+			role := a.Policy.GetRoles()[l]
+			if role.GetAdminRole() >= want && a.isPrincipalIn(role.GetPrincipals(), sctx.Email) {
+				recordRule(ctx, "admin role %q on %v", l, r)
+				return nil
+			}
+		}
+	}
+	recordRule(ctx, "no matching admin role on %v", resources[len(resources)-1])
+	return status.Errorf(codes.PermissionDenied, "%v does not have the %v admin role on %v",
+		sctx.Email, want, resources[len(resources)-1])
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/keytransparency/impl/authentication"
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	authzpb "github.com/google/keytransparency/impl/authorization/authz_go_proto"
+)
+
+const (
+	viewer   = "viewer@example.com"
+	operator = "operator@example.com"
+	owner    = "owner@example.com"
+	dirOwner = "dir-owner@example.com"
+)
+
+var adminAuthz = AuthzPolicy{
+	Policy: &authzpb.AuthorizationPolicy{
+		Roles: map[string]*authzpb.AuthorizationPolicy_Role{
+			"viewers": {
+				Principals: []string{viewer},
+				AdminRole:  authzpb.AuthorizationPolicy_VIEWER,
+			},
+			"operators": {
+				Principals: []string{operator},
+				AdminRole:  authzpb.AuthorizationPolicy_OPERATOR,
+			},
+			"owners": {
+				Principals: []string{owner},
+				AdminRole:  authzpb.AuthorizationPolicy_OWNER,
+			},
+			"dir-owners": {
+				Principals: []string{dirOwner},
+				AdminRole:  authzpb.AuthorizationPolicy_OWNER,
+			},
+			"users": {
+				Principals: []string{testUser},
+			},
+		},
+		ResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+			"directories/1": {Labels: []string{"users"}},
+		},
+		AdminResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+			"directories":   {Labels: []string{"viewers", "operators", "owners"}},
+			"directories/1": {Labels: []string{"dir-owners", "users"}},
+		},
+	},
+}
+
+func TestAuthorizeAdmin(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc      string
+		principal string
+		req       proto.Message
+		wantCode  codes.Code
+	}{
+		{desc: "viewer lists", principal: viewer, req: &pb.ListDirectoriesRequest{}},
+		{desc: "viewer reads", principal: viewer, req: &pb.GetDirectoryRequest{DirectoryId: "1"}},
+		{desc: "viewer pauses", principal: viewer, req: &pb.PauseDirectoryRequest{DirectoryId: "1"},
+			wantCode: codes.PermissionDenied},
+		{desc: "operator pauses", principal: operator, req: &pb.PauseDirectoryRequest{DirectoryId: "1"}},
+		{desc: "operator prunes", principal: operator, req: &pb.PruneQueueRequest{DirectoryId: "2"}},
+		{desc: "operator deletes", principal: operator, req: &pb.DeleteDirectoryRequest{DirectoryId: "1"},
+			wantCode: codes.PermissionDenied},
+		{desc: "owner creates", principal: owner, req: &pb.CreateDirectoryRequest{DirectoryId: "3"}},
+		{desc: "operator rebuilds", principal: operator, req: &pb.RebuildMapRequest{DirectoryId: "1"},
+			wantCode: codes.PermissionDenied},
+		{desc: "viewer reads rebuild", principal: viewer, req: &pb.GetMapRebuildRequest{DirectoryId: "1"}},
+		{desc: "operator cancels rebuild", principal: operator, req: &pb.CancelMapRebuildRequest{DirectoryId: "1"},
+			wantCode: codes.PermissionDenied},
+		{desc: "directory owner cancels rebuild", principal: dirOwner, req: &pb.CancelMapRebuildRequest{DirectoryId: "1"}},
+		{desc: "owner garbage collects", principal: owner, req: &pb.GarbageCollectRequest{}},
+		{desc: "directory owner rebuilds", principal: dirOwner, req: &pb.RebuildMapRequest{DirectoryId: "1"}},
+		{desc: "directory owner deletes", principal: dirOwner, req: &pb.DeleteDirectoryRequest{DirectoryId: "1"}},
+		{desc: "directory owner deletes other", principal: dirOwner, req: &pb.DeleteDirectoryRequest{DirectoryId: "2"},
+			wantCode: codes.PermissionDenied},
+		{desc: "directory owner lists", principal: dirOwner, req: &pb.ListDirectoriesRequest{},
+			wantCode: codes.PermissionDenied},
+		{desc: "user role without admin role", principal: testUser, req: &pb.GetDirectoryRequest{DirectoryId: "1"},
+			wantCode: codes.PermissionDenied},
+		{desc: "invalid directory", principal: owner, req: &pb.GetDirectoryRequest{DirectoryId: "1/1"},
+			wantCode: codes.InvalidArgument},
+		{desc: "not an admin request", principal: owner, req: &pb.UpdateEntryRequest{DirectoryId: "1"},
+			wantCode: codes.PermissionDenied},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			inCtx := metautils.ExtractOutgoing(authentication.WithOutgoingFakeAuth(ctx, tc.principal)).ToIncoming(ctx)
+			sctx, err := authentication.FakeAuthFunc(inCtx)
+			if err != nil {
+				t.Fatalf("FakeAuthFunc(): %v", err)
+			}
+			err = adminAuthz.AuthorizeAdmin(sctx, tc.req)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Errorf("AuthorizeAdmin(%T): %v, want %v", tc.req, err, want)
+			}
+		})
+	}
+}
+
+type fakeAdminServer struct {
+	pb.KeyTransparencyAdminServer
+}
+
+func TestAdminAuthPairs(t *testing.T) {
+	s := grpc.NewServer()
+	pb.RegisterKeyTransparencyAdminServer(s, &fakeAdminServer{})
+	pairs := AdminAuthPairs(authentication.FakeAuthFunc, adminAuthz.AuthorizeAdmin)
+	if err := CheckAllMethods(s.GetServiceInfo(), AdminService, pairs, nil); err != nil {
+		t.Errorf("CheckAllMethods(): %v", err)
+	}
+	if got, want := len(pairs), len(s.GetServiceInfo()[AdminService].Methods); got != want {
+		t.Errorf("len(AdminAuthPairs()): %v, want %v", got, want)
+	}
+}
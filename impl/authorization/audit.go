@@ -0,0 +1,126 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/keytransparency/impl/authentication"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+)
+
+// Decisions recorded in AuditRecord.Decision.
+const (
+	Allow = "allow"
+	Deny  = "deny"
+)
+
+// AuditRecord describes an authentication and authorization decision.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// Principal is empty if the caller was not authenticated.
+	Principal   string   `json:"principal,omitempty"`
+	Method      string   `json:"method"`
+	DirectoryID string   `json:"directory_id,omitempty"`
+	UserIDs     []string `json:"user_ids,omitempty"`
+	Decision    string   `json:"decision"`
+	// Rule describes the part of the policy that decided the call, such as
+	// `role "admins" on directories/1` or `denies[0] on directories`.
+	Rule string `json:"rule"`
+	// Error is the error returned to the caller, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditSink stores AuditRecords.
+type AuditSink interface {
+	// Write appends r to the audit log.
+	Write(ctx context.Context, r *AuditRecord) error
+}
+
+type auditKey struct{}
+
+// withAuditRecord returns a context in which recordRule fills in r.
+func withAuditRecord(ctx context.Context, r *AuditRecord) context.Context {
+	return context.WithValue(ctx, auditKey{}, r)
+}
+
+// recordRule sets the rule of the AuditRecord in ctx, if there is one, along
+// with the principal of the SecurityContext in ctx.
+func recordRule(ctx context.Context, format string, args ...interface{}) {
+	r, ok := ctx.Value(auditKey{}).(*AuditRecord)
+	if !ok {
+		return
+	}
+	r.Rule = fmt.Sprintf(format, args...)
+	if sctx, ok := authentication.FromContext(ctx); ok {
+		r.Principal = sctx.Email
+	}
+}
+
+// auditTimeout bounds how long writeAudit waits for the sink.
+const auditTimeout = 5 * time.Second
+
+// writeAudit completes r with the principal of ctx, the request req and the
+// outcome err, and writes it to sink. Failures to write are logged rather than returned, so
+// that an unavailable sink does not stop the server. The write is not canceled
+// with the request, so a caller that hangs up still leaves a record.
+func writeAudit(ctx context.Context, sink AuditSink, r *AuditRecord, req interface{}, err error) {
+	if sink == nil {
+		return
+	}
+	r.Time = time.Now()
+	if sctx, ok := authentication.FromContext(ctx); ok && r.Principal == "" {
+		r.Principal = sctx.Email
+	}
+	r.DirectoryID, r.UserIDs = requestUsers(req)
+	r.Decision = Allow
+	if err != nil {
+		r.Decision = Deny
+		r.Error = status.Convert(err).Message()
+	}
+	wctx, cancel := context.WithTimeout(context.Background(), auditTimeout)
+	defer cancel()
+	if werr := sink.Write(wctx, r); werr != nil {
+		glog.Errorf("AuditSink.Write(%+v): %v", r, werr)
+	}
+}
+
+// requestUsers returns the directory and users that req is about.
+func requestUsers(req interface{}) (string, []string) {
+	var directoryID string
+	if d, ok := req.(interface{ GetDirectoryId() string }); ok {
+		directoryID = d.GetDirectoryId()
+	}
+	switch t := req.(type) {
+	case *pb.UpdateEntryRequest:
+		return directoryID, []string{t.GetEntryUpdate().GetUserId()}
+	case *pb.BatchQueueUserUpdateRequest:
+		userIDs := make([]string, 0, len(t.GetUpdates()))
+		for _, u := range t.GetUpdates() {
+			userIDs = append(userIDs, u.GetUserId())
+		}
+		return directoryID, userIDs
+	case interface{ GetUserId() string }:
+		return directoryID, []string{t.GetUserId()}
+	case interface{ GetUserIds() []string }:
+		return directoryID, t.GetUserIds()
+	}
+	return directoryID, nil
+}
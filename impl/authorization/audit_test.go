@@ -0,0 +1,104 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/keytransparency/impl/authentication"
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"google.golang.org/grpc"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+)
+
+// fakeAuditSink collects audit records.
+type fakeAuditSink struct {
+	records []*AuditRecord
+}
+
+func (s *fakeAuditSink) Write(ctx context.Context, r *AuditRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.records = append(s.records, r)
+	return nil
+}
+
+func TestWriteAuditCanceledRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sink := &fakeAuditSink{}
+	writeAudit(ctx, sink, &AuditRecord{Method: "/test"}, &pb.GetUserRequest{}, nil)
+	if got, want := len(sink.records), 1; got != want {
+		t.Errorf("writeAudit() wrote %v records, want %v", got, want)
+	}
+}
+
+func TestUnaryServerInterceptorAudit(t *testing.T) {
+	ctx := context.Background()
+	const method = "/" + KTService + "/QueueEntryUpdate"
+	sink := &fakeAuditSink{}
+	interceptor := UnaryServerInterceptor(map[string]AuthPair{
+		method: {AuthnFunc: authentication.FakeAuthFunc, AuthzFunc: groupPolicy.Authorize},
+	}, sink)
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+
+	for _, tc := range []struct {
+		desc      string
+		principal string
+		userID    string
+		want      AuditRecord
+	}{
+		{desc: "unauthenticated", userID: "alice@example.com",
+			want: AuditRecord{Decision: Deny, Rule: "authentication failed"}},
+		{desc: "self", principal: "alice@example.com", userID: "alice@example.com",
+			want: AuditRecord{Principal: "alice@example.com", Decision: Allow, Rule: "self"}},
+		{desc: "role", principal: "agent@support.example.com", userID: "alice@example.com",
+			want: AuditRecord{Principal: "agent@support.example.com", Decision: Allow, Rule: `role "support" on directories/1`}},
+		{desc: "deny rule", principal: "mallory@example.com", userID: "mallory@example.com",
+			want: AuditRecord{Principal: "mallory@example.com", Decision: Deny, Rule: "denies[0] on directories/1"}},
+		{desc: "no role", principal: "carol@other.com", userID: "alice@example.com",
+			want: AuditRecord{Principal: "carol@other.com", Decision: Deny, Rule: "no matching role on directories/1"}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			inCtx := ctx
+			if tc.principal != "" {
+				inCtx = metautils.ExtractOutgoing(authentication.WithOutgoingFakeAuth(ctx, tc.principal)).ToIncoming(ctx)
+			}
+			sink.records = nil
+			req := &pb.UpdateEntryRequest{DirectoryId: "1", EntryUpdate: &pb.EntryUpdate{UserId: tc.userID}}
+			_, err := interceptor(inCtx, req, info, handler)
+			if gotErr := err != nil; gotErr != (tc.want.Decision == Deny) {
+				t.Errorf("interceptor(): %v, want decision %v", err, tc.want.Decision)
+			}
+			if len(sink.records) != 1 {
+				t.Fatalf("got %v audit records, want 1", len(sink.records))
+			}
+			want := tc.want
+			want.Method = method
+			want.DirectoryID = "1"
+			want.UserIDs = []string{tc.userID}
+			if got := sink.records[0]; got.Time.IsZero() ||
+				!cmp.Equal(*got, want, cmpopts.IgnoreFields(AuditRecord{}, "Time", "Error")) {
+				t.Errorf("audit record: %+v, want %+v", got, want)
+			}
+		})
+	}
+}
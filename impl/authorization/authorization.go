@@ -38,46 +38,172 @@ type AuthzPolicy struct {
 
 // Authorize verifies that the identity issuing the call.
 // ctx must contain an authentication.SecurityContext.
-// A call is authorized if:
-//  1. userID matches SecurityContext.Email,
-//  2. or, SecurityContext.Email is authorized to do the action in directories/directoryID.
+// Calls are evaluated in this order:
+//  1. calls with an unverified SecurityContext.Email are denied,
+//  2. calls by principals in a deny rule for directories/directoryID are denied,
+//  3. calls where userID matches SecurityContext.Email are authorized,
+//  4. calls where SecurityContext.Email is in a role for directories/directoryID are authorized,
+//  5. all other calls are denied.
 func (a *AuthzPolicy) Authorize(ctx context.Context, m interface{}) error {
 	sctx, ok := authentication.FromContext(ctx)
 	if !ok {
 		return status.Error(codes.Unauthenticated, "Request does not contain a ValidatedSecurity object")
 	}
+	if err := checkVerified(ctx, sctx); err != nil {
+		return err
+	}
 
 	switch t := m.(type) {
 	case *pb.UpdateEntryRequest:
-		return a.checkPermission(sctx, t.DirectoryId, t.EntryUpdate.UserId)
+		return a.checkPermission(ctx, sctx, t.DirectoryId, t.GetEntryUpdate().GetUserId())
+	case *pb.BatchQueueUserUpdateRequest:
+		if len(t.GetUpdates()) == 0 {
+			// An empty batch has no users to act on, so only directory roles apply.
+			return a.checkDirectoryPermission(ctx, sctx, t.DirectoryId)
+		}
+		for _, u := range t.GetUpdates() {
+			if err := a.checkPermission(ctx, sctx, t.DirectoryId, u.GetUserId()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *pb.ListUserRejectionsRequest:
+		return a.checkPermission(ctx, sctx, t.DirectoryId, t.UserId)
 		// Can't authorize any other requests
 	default:
+		recordRule(ctx, "unrecognized request")
 		return status.Errorf(codes.PermissionDenied, "message type %T not recognized", t)
 	}
 
 }
 
-func (a *AuthzPolicy) checkPermission(sctx *authentication.SecurityContext, directoryID, userID string) error {
-	// Case 1.
+// AuthorizeRead verifies that the identity issuing a call may read the users or
+// mutations that it asks for. ctx must contain an authentication.SecurityContext.
+// Calls are evaluated like Authorize, except that principals in a role of
+// reader_resource_to_role_labels for directories/directoryID or directories
+// are also authorized.
+func (a *AuthzPolicy) AuthorizeRead(ctx context.Context, m interface{}) error {
+	sctx, ok := authentication.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "Request does not contain a ValidatedSecurity object")
+	}
+	if err := checkVerified(ctx, sctx); err != nil {
+		return err
+	}
+
+	var directoryID string
+	var userIDs []string
+	switch t := m.(type) {
+	case *pb.GetUserRequest:
+		directoryID, userIDs = t.DirectoryId, []string{t.UserId}
+	case *pb.BatchGetUserRequest:
+		directoryID, userIDs = t.DirectoryId, t.UserIds
+	case *pb.BatchGetUserIndexRequest:
+		directoryID, userIDs = t.DirectoryId, t.UserIds
+	case *pb.ListEntryHistoryRequest:
+		directoryID, userIDs = t.DirectoryId, []string{t.UserId}
+	case *pb.ListUserRevisionsRequest:
+		directoryID, userIDs = t.DirectoryId, []string{t.UserId}
+	case *pb.BatchListUserRevisionsRequest:
+		directoryID, userIDs = t.DirectoryId, t.UserIds
+	case *pb.ListMutationsRequest:
+		// Mutations belong to many users, so only roles apply.
+		directoryID = t.DirectoryId
+	default:
+		recordRule(ctx, "unrecognized request")
+		return status.Errorf(codes.PermissionDenied, "message type %T not recognized", t)
+	}
+
+	rLabel, err := resourceLabel(directoryID)
+	if err != nil {
+		return err
+	}
+	if err := a.checkDenied(ctx, sctx, rLabel); err != nil {
+		return err
+	}
+	if isOnly(sctx.Email, userIDs) {
+		recordRule(ctx, "self")
+		return nil
+	}
+	if a.checkRoles(ctx, sctx, rLabel) == nil {
+		return nil
+	}
+	for _, r := range []string{rLabel, allDirectories} {
+		for _, l := range a.Policy.GetReaderResourceToRoleLabels()[r].GetLabels() {
+			if a.isPrincipalIn(a.Policy.GetRoles()[l].GetPrincipals(), sctx.Email) {
+				recordRule(ctx, "reader role %q on %v", l, r)
+				return nil
+			}
+		}
+	}
+	recordRule(ctx, "no matching role on %v", rLabel)
+	return status.Errorf(codes.PermissionDenied, "%v is not authorized to read %v", sctx.Email, rLabel)
+}
+
+// isOnly returns true if userIDs is not empty and every element is userID.
+func isOnly(userID string, userIDs []string) bool {
+	for _, u := range userIDs {
+		if u != userID {
+			return false
+		}
+	}
+	return len(userIDs) > 0
+}
+
+// checkVerified returns an error if the email address of sctx is not verified.
+// Unverified addresses may belong to anyone, so they match no user or role.
+func checkVerified(ctx context.Context, sctx *authentication.SecurityContext) error {
+	if !sctx.EmailVerified {
+		recordRule(ctx, "unverified email")
+		return status.Errorf(codes.PermissionDenied, "email address %v is not verified", sctx.Email)
+	}
+	return nil
+}
+
+func (a *AuthzPolicy) checkPermission(ctx context.Context, sctx *authentication.SecurityContext,
+	directoryID, userID string) error {
+	rLabel, err := resourceLabel(directoryID)
+	if err != nil {
+		return err
+	}
+	if err := a.checkDenied(ctx, sctx, rLabel); err != nil {
+		return err
+	}
 	if sctx.Email == userID {
+		recordRule(ctx, "self")
 		return nil
 	}
+	return a.checkRoles(ctx, sctx, rLabel)
+}
 
-	// Case 2.
+// checkDirectoryPermission checks the roles of the policy for directoryID,
+// without regard to which users are acted on.
+func (a *AuthzPolicy) checkDirectoryPermission(ctx context.Context, sctx *authentication.SecurityContext,
+	directoryID string) error {
 	rLabel, err := resourceLabel(directoryID)
 	if err != nil {
 		return err
 	}
+	if err := a.checkDenied(ctx, sctx, rLabel); err != nil {
+		return err
+	}
+	return a.checkRoles(ctx, sctx, rLabel)
+}
+
+func (a *AuthzPolicy) checkRoles(ctx context.Context, sctx *authentication.SecurityContext, rLabel string) error {
 	roles, ok := a.Policy.GetResourceToRoleLabels()[rLabel]
 	if !ok {
+		recordRule(ctx, "no policy for %v", rLabel)
 		return status.Errorf(codes.PermissionDenied, "%v does not have a defined policy", rLabel)
 	}
 	for _, l := range roles.GetLabels() {
 		role := a.Policy.GetRoles()[l]
-		if isPrincipalInRole(role, sctx.Email) {
+		if a.isPrincipalIn(role.GetPrincipals(), sctx.Email) {
+			recordRule(ctx, "role %q on %v", l, rLabel)
 			return nil
 		}
 	}
+	recordRule(ctx, "no matching role on %v", rLabel)
 	return status.Errorf(codes.PermissionDenied, "%v is not authorized to act on %v", sctx.Email, rLabel)
 }
 
@@ -87,12 +213,3 @@ func resourceLabel(directoryID string) (string, error) {
 	}
 	return fmt.Sprintf("directories/%v", directoryID), nil
 }
-
-func isPrincipalInRole(role *authzpb.AuthorizationPolicy_Role, identity string) bool {
-	for _, p := range role.GetPrincipals() {
-		if p == identity {
-			return true
-		}
-	}
-	return false
-}
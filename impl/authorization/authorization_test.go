@@ -153,10 +153,96 @@ func TestIsAuthorized(t *testing.T) {
 			if got, want := status.Code(err), tc.wantCode; got != want {
 				t.Errorf("IsAuthorized(): %v, want %v", err, want)
 			}
+
+			rejectionsReq := &pb.ListUserRejectionsRequest{
+				DirectoryId: tc.directoryID,
+				UserId:      tc.userID,
+			}
+			err = authz.Authorize(sctx, rejectionsReq)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Errorf("IsAuthorized(ListUserRejectionsRequest): %v, want %v", err, want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeBatch(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		description string
+		principal   string
+		directoryID string
+		userIDs     []string
+		wantCode    codes.Code
+	}{
+		{
+			description: "self updating own profile",
+			principal:   testUser,
+			directoryID: "5",
+			userIDs:     []string{testUser},
+		},
+		{
+			description: "admin updating many profiles",
+			principal:   admin1,
+			directoryID: "1",
+			userIDs:     []string{"alice", "bob", testUser},
+		},
+		{
+			description: "user updating another profile",
+			principal:   testUser,
+			directoryID: "1",
+			userIDs:     []string{testUser, "alice"},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			description: "admin of another directory",
+			principal:   admin3,
+			directoryID: "1",
+			userIDs:     []string{"alice"},
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			description: "admin sending empty batch",
+			principal:   admin1,
+			directoryID: "1",
+		},
+		{
+			description: "user sending empty batch",
+			principal:   testUser,
+			directoryID: "1",
+			wantCode:    codes.PermissionDenied,
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			inCtx := metautils.ExtractOutgoing(authentication.WithOutgoingFakeAuth(ctx, tc.principal)).ToIncoming(ctx)
+			sctx, err := authentication.FakeAuthFunc(inCtx)
+			if err != nil {
+				t.Fatalf("FakeAuthFunc(): %v", err)
+			}
+			req := &pb.BatchQueueUserUpdateRequest{DirectoryId: tc.directoryID}
+			for _, userID := range tc.userIDs {
+				req.Updates = append(req.Updates, &pb.EntryUpdate{UserId: userID})
+			}
+			err = authz.Authorize(sctx, req)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Errorf("Authorize(BatchQueueUserUpdateRequest): %v, want %v", err, want)
+			}
 		})
 	}
 }
 
+func TestUnverifiedEmail(t *testing.T) {
+	ctx := authentication.NewContext(context.Background(), &authentication.SecurityContext{Email: admin1})
+	for _, req := range []interface{}{
+		&pb.UpdateEntryRequest{DirectoryId: "1", EntryUpdate: &pb.EntryUpdate{UserId: admin1}},
+		&pb.ListUserRejectionsRequest{DirectoryId: "1", UserId: admin1},
+	} {
+		if got, want := status.Code(authz.Authorize(ctx, req)), codes.PermissionDenied; got != want {
+			t.Errorf("Authorize(%T): %v, want %v", req, got, want)
+		}
+	}
+}
+
 func TestResouceLabel(t *testing.T) {
 	for _, tc := range []struct {
 		directoryID string
@@ -20,15 +20,68 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// AdminRole is a level of access to the admin API. Each level includes the
+// permissions of the levels before it.
+type AuthorizationPolicy_AdminRole int32
+
+const (
+	// ADMIN_ROLE_UNSPECIFIED grants no access to the admin API.
+	AuthorizationPolicy_ADMIN_ROLE_UNSPECIFIED AuthorizationPolicy_AdminRole = 0
+	// VIEWER may read directories and their input logs.
+	AuthorizationPolicy_VIEWER AuthorizationPolicy_AdminRole = 1
+	// OPERATOR may also pause and resume directories, add and update input
+	// logs, and prune queues.
+	AuthorizationPolicy_OPERATOR AuthorizationPolicy_AdminRole = 2
+	// OWNER may also create, delete and undelete directories, and garbage
+	// collect deleted directories.
+	AuthorizationPolicy_OWNER AuthorizationPolicy_AdminRole = 3
+)
+
+var AuthorizationPolicy_AdminRole_name = map[int32]string{
+	0: "ADMIN_ROLE_UNSPECIFIED",
+	1: "VIEWER",
+	2: "OPERATOR",
+	3: "OWNER",
+}
+
+var AuthorizationPolicy_AdminRole_value = map[string]int32{
+	"ADMIN_ROLE_UNSPECIFIED": 0,
+	"VIEWER":                 1,
+	"OPERATOR":               2,
+	"OWNER":                  3,
+}
+
+func (x AuthorizationPolicy_AdminRole) String() string {
+	return proto.EnumName(AuthorizationPolicy_AdminRole_name, int32(x))
+}
+
+func (AuthorizationPolicy_AdminRole) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_6b30dada73a254d2, []int{0, 0}
+}
+
 // AuthorizationPolicy contains an authorization policy.
 type AuthorizationPolicy struct {
 	// roles is a map of roles keyed by labels used in RoleLabels.
 	Roles map[string]*AuthorizationPolicy_Role `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	// resource_to_role_labels specifies the authorization policy keyed by resource directory_id.
 	ResourceToRoleLabels map[string]*AuthorizationPolicy_RoleLabels `protobuf:"bytes,3,rep,name=resource_to_role_labels,json=resourceToRoleLabels,proto3" json:"resource_to_role_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}                                   `json:"-"`
-	XXX_unrecognized     []byte                                     `json:"-"`
-	XXX_sizecache        int32                                      `json:"-"`
+	// admin_resource_to_role_labels specifies the admin API policy keyed by
+	// resource. "directories/{directory_id}" covers calls about one directory,
+	// and "directories" covers calls about every directory, such as
+	// ListDirectories.
+	AdminResourceToRoleLabels map[string]*AuthorizationPolicy_RoleLabels `protobuf:"bytes,4,rep,name=admin_resource_to_role_labels,json=adminResourceToRoleLabels,proto3" json:"admin_resource_to_role_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// groups is a map of groups keyed by the names used in "group:{name}".
+	Groups map[string]*AuthorizationPolicy_Group `protobuf:"bytes,5,rep,name=groups,proto3" json:"groups,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// denies are checked before any role.
+	Denies []*AuthorizationPolicy_Deny `protobuf:"bytes,6,rep,name=denies,proto3" json:"denies,omitempty"`
+	// reader_resource_to_role_labels lists the roles that may read users and
+	// mutations of private directories, keyed by "directories/{directory_id}",
+	// or "directories" for every directory. Roles of resource_to_role_labels
+	// may also read.
+	ReaderResourceToRoleLabels map[string]*AuthorizationPolicy_RoleLabels `protobuf:"bytes,7,rep,name=reader_resource_to_role_labels,json=readerResourceToRoleLabels,proto3" json:"reader_resource_to_role_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral       struct{}                                   `json:"-"`
+	XXX_unrecognized           []byte                                     `json:"-"`
+	XXX_sizecache              int32                                      `json:"-"`
 }
 
 func (m *AuthorizationPolicy) Reset()         { *m = AuthorizationPolicy{} }
@@ -70,6 +123,34 @@ func (m *AuthorizationPolicy) GetResourceToRoleLabels() map[string]*Authorizatio
 	return nil
 }
 
+func (m *AuthorizationPolicy) GetAdminResourceToRoleLabels() map[string]*AuthorizationPolicy_RoleLabels {
+	if m != nil {
+		return m.AdminResourceToRoleLabels
+	}
+	return nil
+}
+
+func (m *AuthorizationPolicy) GetGroups() map[string]*AuthorizationPolicy_Group {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *AuthorizationPolicy) GetDenies() []*AuthorizationPolicy_Deny {
+	if m != nil {
+		return m.Denies
+	}
+	return nil
+}
+
+func (m *AuthorizationPolicy) GetReaderResourceToRoleLabels() map[string]*AuthorizationPolicy_RoleLabels {
+	if m != nil {
+		return m.ReaderResourceToRoleLabels
+	}
+	return nil
+}
+
 // Resource contains the resource being accessed.
 type AuthorizationPolicy_Resource struct {
 	// directory_id contains the Key Transparency directory of this entry.
@@ -113,11 +194,18 @@ func (m *AuthorizationPolicy_Resource) GetDirectoryId() string {
 
 // Role contains a specific identity of an authorization entry.
 type AuthorizationPolicy_Role struct {
-	// principals contains an application specific identifier for this entry.
-	Principals           []string `protobuf:"bytes,1,rep,name=principals,proto3" json:"principals,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// principals contains an application specific identifier for this entry,
+	// such as "alice@example.com", a domain wildcard, such as "*@example.com",
+	// which matches the email addresses of the domain but no other kind of
+	// identifier, or a reference to a group of this policy, such as
+	// "group:support".
+	Principals []string `protobuf:"bytes,1,rep,name=principals,proto3" json:"principals,omitempty"`
+	// admin_role is the access that principals have to the resources of
+	// admin_resource_to_role_labels that list this role.
+	AdminRole            AuthorizationPolicy_AdminRole `protobuf:"varint,2,opt,name=admin_role,json=adminRole,proto3,enum=google.keytransparency.impl.AuthorizationPolicy_AdminRole" json:"admin_role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
 }
 
 func (m *AuthorizationPolicy_Role) Reset()         { *m = AuthorizationPolicy_Role{} }
@@ -152,6 +240,106 @@ func (m *AuthorizationPolicy_Role) GetPrincipals() []string {
 	return nil
 }
 
+func (m *AuthorizationPolicy_Role) GetAdminRole() AuthorizationPolicy_AdminRole {
+	if m != nil {
+		return m.AdminRole
+	}
+	return AuthorizationPolicy_ADMIN_ROLE_UNSPECIFIED
+}
+
+// Group is a named set of principals. Groups may contain other groups.
+type AuthorizationPolicy_Group struct {
+	// members are written like Role.principals.
+	Members              []string `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthorizationPolicy_Group) Reset()         { *m = AuthorizationPolicy_Group{} }
+func (m *AuthorizationPolicy_Group) String() string { return proto.CompactTextString(m) }
+func (*AuthorizationPolicy_Group) ProtoMessage()    {}
+func (*AuthorizationPolicy_Group) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6b30dada73a254d2, []int{0, 2}
+}
+
+func (m *AuthorizationPolicy_Group) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthorizationPolicy_Group.Unmarshal(m, b)
+}
+func (m *AuthorizationPolicy_Group) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthorizationPolicy_Group.Marshal(b, m, deterministic)
+}
+func (m *AuthorizationPolicy_Group) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthorizationPolicy_Group.Merge(m, src)
+}
+func (m *AuthorizationPolicy_Group) XXX_Size() int {
+	return xxx_messageInfo_AuthorizationPolicy_Group.Size(m)
+}
+func (m *AuthorizationPolicy_Group) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthorizationPolicy_Group.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthorizationPolicy_Group proto.InternalMessageInfo
+
+func (m *AuthorizationPolicy_Group) GetMembers() []string {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+// Deny prevents principals from acting on resources. Deny rules take
+// precedence over roles, and over users acting on their own entries.
+type AuthorizationPolicy_Deny struct {
+	// principals are written like Role.principals.
+	Principals []string `protobuf:"bytes,1,rep,name=principals,proto3" json:"principals,omitempty"`
+	// resources are "directories/{directory_id}", or "directories" for every
+	// directory.
+	Resources            []string `protobuf:"bytes,2,rep,name=resources,proto3" json:"resources,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthorizationPolicy_Deny) Reset()         { *m = AuthorizationPolicy_Deny{} }
+func (m *AuthorizationPolicy_Deny) String() string { return proto.CompactTextString(m) }
+func (*AuthorizationPolicy_Deny) ProtoMessage()    {}
+func (*AuthorizationPolicy_Deny) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6b30dada73a254d2, []int{0, 3}
+}
+
+func (m *AuthorizationPolicy_Deny) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthorizationPolicy_Deny.Unmarshal(m, b)
+}
+func (m *AuthorizationPolicy_Deny) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthorizationPolicy_Deny.Marshal(b, m, deterministic)
+}
+func (m *AuthorizationPolicy_Deny) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthorizationPolicy_Deny.Merge(m, src)
+}
+func (m *AuthorizationPolicy_Deny) XXX_Size() int {
+	return xxx_messageInfo_AuthorizationPolicy_Deny.Size(m)
+}
+func (m *AuthorizationPolicy_Deny) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthorizationPolicy_Deny.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthorizationPolicy_Deny proto.InternalMessageInfo
+
+func (m *AuthorizationPolicy_Deny) GetPrincipals() []string {
+	if m != nil {
+		return m.Principals
+	}
+	return nil
+}
+
+func (m *AuthorizationPolicy_Deny) GetResources() []string {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
 // RoleLabels contains a lot of role labels identifying each role.
 type AuthorizationPolicy_RoleLabels struct {
 	Labels               []string `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
@@ -164,7 +352,7 @@ func (m *AuthorizationPolicy_RoleLabels) Reset()         { *m = AuthorizationPol
 func (m *AuthorizationPolicy_RoleLabels) String() string { return proto.CompactTextString(m) }
 func (*AuthorizationPolicy_RoleLabels) ProtoMessage()    {}
 func (*AuthorizationPolicy_RoleLabels) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6b30dada73a254d2, []int{0, 2}
+	return fileDescriptor_6b30dada73a254d2, []int{0, 4}
 }
 
 func (m *AuthorizationPolicy_RoleLabels) XXX_Unmarshal(b []byte) error {
@@ -193,39 +381,60 @@ func (m *AuthorizationPolicy_RoleLabels) GetLabels() []string {
 }
 
 func init() {
+	proto.RegisterEnum("google.keytransparency.impl.AuthorizationPolicy_AdminRole", AuthorizationPolicy_AdminRole_name, AuthorizationPolicy_AdminRole_value)
 	proto.RegisterType((*AuthorizationPolicy)(nil), "google.keytransparency.impl.AuthorizationPolicy")
+	proto.RegisterMapType((map[string]*AuthorizationPolicy_RoleLabels)(nil), "google.keytransparency.impl.AuthorizationPolicy.AdminResourceToRoleLabelsEntry")
+	proto.RegisterMapType((map[string]*AuthorizationPolicy_Group)(nil), "google.keytransparency.impl.AuthorizationPolicy.GroupsEntry")
+	proto.RegisterMapType((map[string]*AuthorizationPolicy_RoleLabels)(nil), "google.keytransparency.impl.AuthorizationPolicy.ReaderResourceToRoleLabelsEntry")
 	proto.RegisterMapType((map[string]*AuthorizationPolicy_RoleLabels)(nil), "google.keytransparency.impl.AuthorizationPolicy.ResourceToRoleLabelsEntry")
 	proto.RegisterMapType((map[string]*AuthorizationPolicy_Role)(nil), "google.keytransparency.impl.AuthorizationPolicy.RolesEntry")
 	proto.RegisterType((*AuthorizationPolicy_Resource)(nil), "google.keytransparency.impl.AuthorizationPolicy.Resource")
 	proto.RegisterType((*AuthorizationPolicy_Role)(nil), "google.keytransparency.impl.AuthorizationPolicy.Role")
+	proto.RegisterType((*AuthorizationPolicy_Group)(nil), "google.keytransparency.impl.AuthorizationPolicy.Group")
+	proto.RegisterType((*AuthorizationPolicy_Deny)(nil), "google.keytransparency.impl.AuthorizationPolicy.Deny")
 	proto.RegisterType((*AuthorizationPolicy_RoleLabels)(nil), "google.keytransparency.impl.AuthorizationPolicy.RoleLabels")
 }
 
 func init() { proto.RegisterFile("authz.proto", fileDescriptor_6b30dada73a254d2) }
 
 var fileDescriptor_6b30dada73a254d2 = []byte{
-	// 358 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0x41, 0x4b, 0xe3, 0x40,
-	0x14, 0xc7, 0x49, 0xd2, 0x96, 0xf6, 0x65, 0x0f, 0x65, 0x76, 0xd9, 0xcd, 0x66, 0x61, 0xc9, 0x2e,
-	0xcb, 0x92, 0x53, 0x02, 0x2d, 0x82, 0xe8, 0x49, 0xa5, 0x07, 0xab, 0x07, 0x1b, 0x3c, 0x79, 0x09,
-	0xd3, 0x74, 0x48, 0x87, 0x4e, 0xf3, 0xc2, 0x64, 0x22, 0xa4, 0x37, 0xc1, 0xef, 0xea, 0xd7, 0x90,
-	0x64, 0xa2, 0x56, 0xa9, 0x82, 0x3d, 0xcd, 0xbc, 0x37, 0xef, 0xfd, 0xfe, 0xff, 0x79, 0x33, 0x60,
-	0xd3, 0x52, 0x2d, 0x37, 0x41, 0x2e, 0x51, 0x21, 0xf9, 0x95, 0x22, 0xa6, 0x82, 0x05, 0x2b, 0x56,
-	0x29, 0x49, 0xb3, 0x22, 0xa7, 0x92, 0x65, 0x49, 0x15, 0xf0, 0x75, 0x2e, 0xfe, 0x3e, 0x74, 0xe0,
-	0xeb, 0x49, 0xa9, 0x96, 0x28, 0xf9, 0x86, 0x2a, 0x8e, 0xd9, 0x15, 0x0a, 0x9e, 0x54, 0x64, 0x06,
-	0x5d, 0x89, 0x82, 0x15, 0x8e, 0xe9, 0x59, 0xbe, 0x3d, 0x3a, 0x0e, 0x3e, 0x80, 0x04, 0x3b, 0x00,
-	0x41, 0x54, 0x77, 0x4f, 0x32, 0x25, 0xab, 0x48, 0x93, 0xc8, 0x9d, 0x01, 0x3f, 0x24, 0x2b, 0xb0,
-	0x94, 0x09, 0x8b, 0x15, 0xc6, 0x75, 0x36, 0x16, 0x74, 0xce, 0x44, 0xe1, 0x58, 0x8d, 0xca, 0xf4,
-	0xf3, 0x2a, 0x2d, 0xef, 0x1a, 0x6b, 0xbd, 0xcb, 0x06, 0xa6, 0x45, 0xbf, 0xc9, 0x1d, 0x47, 0xee,
-	0x18, 0xfa, 0x4f, 0x2d, 0xe4, 0x0f, 0x7c, 0x59, 0x70, 0xc9, 0x12, 0x85, 0xb2, 0x8a, 0xf9, 0xc2,
-	0x31, 0x3c, 0xc3, 0x1f, 0x44, 0xf6, 0x73, 0xee, 0x7c, 0x31, 0xed, 0xf4, 0xcd, 0xa1, 0xe5, 0xfe,
-	0x87, 0x4e, 0x8d, 0x20, 0xbf, 0x01, 0x72, 0xc9, 0xb3, 0x84, 0xe7, 0x54, 0x14, 0x8e, 0xe1, 0x59,
-	0xfe, 0x20, 0xda, 0xca, 0xb8, 0xff, 0x00, 0x5e, 0xa4, 0xc8, 0x77, 0xe8, 0xb5, 0x97, 0xd3, 0x95,
-	0x6d, 0xe4, 0xa2, 0xae, 0xd2, 0x36, 0xc9, 0x10, 0xac, 0x15, 0xab, 0x5a, 0xed, 0x7a, 0x4b, 0x2e,
-	0xa0, 0x7b, 0x4b, 0x45, 0xc9, 0x1c, 0xd3, 0x33, 0x7c, 0x7b, 0x74, 0xb0, 0xd7, 0xe4, 0x23, 0xcd,
-	0x38, 0x32, 0x0f, 0x0d, 0xf7, 0xde, 0x80, 0x9f, 0xef, 0xce, 0x69, 0x87, 0x81, 0xd9, 0x6b, 0x03,
-	0xfb, 0x3d, 0xbd, 0x96, 0xd8, 0xb2, 0x71, 0x3a, 0xb9, 0x39, 0x4b, 0xb9, 0x5a, 0x96, 0xf3, 0x20,
-	0xc1, 0x75, 0xa8, 0x99, 0xe1, 0x1b, 0x66, 0x58, 0x33, 0x43, 0xba, 0xcd, 0x6c, 0xa2, 0x4d, 0x9c,
-	0x62, 0xdc, 0xfc, 0xe6, 0x79, 0xaf, 0x59, 0xc6, 0x8f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xfc, 0xbb,
-	0x8c, 0xbc, 0xe3, 0x02, 0x00, 0x00,
+	// 606 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x95, 0x4d, 0x6f, 0xd3, 0x30,
+	0x18, 0xc7, 0x71, 0xdf, 0xb6, 0x3c, 0x1d, 0xa8, 0x32, 0x68, 0x64, 0x01, 0x46, 0x37, 0x71, 0xe8,
+	0x29, 0x95, 0x36, 0x81, 0xd0, 0xe0, 0x52, 0xd6, 0x80, 0x3a, 0xba, 0xb5, 0x33, 0x83, 0x09, 0x2e,
+	0x91, 0x9b, 0x58, 0x5d, 0xb4, 0x34, 0x0e, 0x4e, 0x82, 0x94, 0xde, 0x26, 0x71, 0x81, 0x1b, 0x12,
+	0x9f, 0x8d, 0xcf, 0x83, 0xe2, 0xa4, 0x23, 0x43, 0xcd, 0xa6, 0xf6, 0xb0, 0x53, 0xe3, 0x27, 0xf1,
+	0xff, 0xff, 0xf3, 0xf3, 0xe2, 0x42, 0x9d, 0x46, 0xe1, 0xd9, 0x54, 0xf7, 0x05, 0x0f, 0x39, 0x7e,
+	0x34, 0xe6, 0x7c, 0xec, 0x32, 0xfd, 0x9c, 0xc5, 0xa1, 0xa0, 0x5e, 0xe0, 0x53, 0xc1, 0x3c, 0x2b,
+	0xd6, 0x9d, 0x89, 0xef, 0x6e, 0xff, 0xb9, 0x0b, 0xf7, 0x3b, 0x51, 0x78, 0xc6, 0x85, 0x33, 0xa5,
+	0xa1, 0xc3, 0xbd, 0x21, 0x77, 0x1d, 0x2b, 0xc6, 0xc7, 0x50, 0x15, 0xdc, 0x65, 0x81, 0x5a, 0x6a,
+	0x96, 0x5b, 0xf5, 0x9d, 0x57, 0xfa, 0x35, 0x22, 0xfa, 0x1c, 0x01, 0x9d, 0x24, 0xbb, 0x0d, 0x2f,
+	0x14, 0x31, 0x49, 0x95, 0xf0, 0x05, 0x82, 0x87, 0x82, 0x05, 0x3c, 0x12, 0x16, 0x33, 0x43, 0x6e,
+	0x26, 0x51, 0xd3, 0xa5, 0x23, 0xe6, 0x06, 0x6a, 0x59, 0xba, 0x1c, 0x2c, 0xee, 0x92, 0xe9, 0x9d,
+	0xf0, 0xc4, 0xaf, 0x2f, 0xc5, 0x52, 0xd3, 0x07, 0x62, 0xce, 0x2b, 0xfc, 0x0b, 0xc1, 0x13, 0x6a,
+	0x4f, 0x1c, 0xcf, 0x2c, 0x22, 0xa9, 0x48, 0x92, 0xc1, 0xc2, 0x24, 0x9d, 0x44, 0xb5, 0x18, 0x67,
+	0x83, 0x16, 0xbd, 0xc7, 0x27, 0x50, 0x1b, 0x0b, 0x1e, 0xf9, 0x81, 0x5a, 0x95, 0xde, 0xaf, 0x17,
+	0xf6, 0x7e, 0x27, 0xb7, 0xa7, 0x46, 0x99, 0x16, 0x3e, 0x84, 0x9a, 0xcd, 0x3c, 0x87, 0x05, 0x6a,
+	0x4d, 0xaa, 0x3e, 0x5f, 0x58, 0xb5, 0xcb, 0xbc, 0x98, 0x64, 0x22, 0xf8, 0x37, 0x82, 0x4d, 0xc1,
+	0xa8, 0xcd, 0x44, 0x61, 0xe6, 0x56, 0xa4, 0xcf, 0x70, 0x89, 0x1a, 0x26, 0xb2, 0xc5, 0xa9, 0xd3,
+	0x44, 0xe1, 0x07, 0xda, 0x2e, 0xac, 0xce, 0xe2, 0x78, 0x0b, 0xd6, 0x6c, 0x47, 0x30, 0x2b, 0xe4,
+	0x22, 0x36, 0x1d, 0x5b, 0x45, 0x4d, 0xd4, 0x52, 0x48, 0xfd, 0x32, 0xd6, 0xb3, 0x0f, 0x2a, 0xab,
+	0xa5, 0x46, 0x59, 0xbb, 0x40, 0x50, 0x49, 0x34, 0xf0, 0x26, 0x80, 0x2f, 0x1c, 0xcf, 0x72, 0x7c,
+	0xea, 0x06, 0x2a, 0x6a, 0x96, 0x5b, 0x0a, 0xc9, 0x45, 0xf0, 0x67, 0x80, 0xac, 0x59, 0xb8, 0xcb,
+	0xd4, 0x52, 0x13, 0xb5, 0xee, 0xed, 0xec, 0x2d, 0xd9, 0x19, 0xdc, 0x65, 0x44, 0xa1, 0xb3, 0x47,
+	0x6d, 0x0b, 0xaa, 0xb2, 0x6a, 0x58, 0x85, 0x95, 0x09, 0x9b, 0x8c, 0x98, 0x98, 0x01, 0xcc, 0x96,
+	0x5a, 0x17, 0x2a, 0x49, 0x09, 0x6e, 0xa4, 0x7c, 0x0c, 0xca, 0xac, 0x24, 0xe9, 0xb8, 0x2a, 0xe4,
+	0x5f, 0x40, 0x7b, 0x06, 0x90, 0xeb, 0xb5, 0x75, 0xa8, 0x65, 0xd5, 0x4a, 0x75, 0xb2, 0x95, 0xc6,
+	0xd3, 0xaf, 0xd2, 0x8c, 0xe3, 0x06, 0x94, 0xcf, 0x59, 0x9c, 0x25, 0x30, 0x79, 0xc4, 0xef, 0xa1,
+	0xfa, 0x8d, 0xba, 0x51, 0x9a, 0x84, 0x65, 0x9a, 0x49, 0x9e, 0x3f, 0xd5, 0xd8, 0x2b, 0xbd, 0x44,
+	0xda, 0x77, 0x04, 0x1b, 0x85, 0x25, 0x9f, 0x03, 0x70, 0x7c, 0x15, 0x60, 0xb9, 0xfb, 0x28, 0xb5,
+	0xc8, 0x63, 0xfc, 0x40, 0xb0, 0x79, 0xfd, 0xe4, 0xde, 0x1e, 0xcb, 0x57, 0xa8, 0xe7, 0x06, 0x79,
+	0x8e, 0x6f, 0xff, 0xaa, 0xef, 0x8b, 0xe5, 0xee, 0x89, 0xbc, 0xe5, 0x4f, 0x04, 0x4f, 0x6f, 0x18,
+	0xbf, 0x5b, 0x3b, 0xff, 0x76, 0x1f, 0x94, 0xcb, 0x51, 0xc1, 0x1a, 0xac, 0x77, 0xba, 0x87, 0xbd,
+	0x23, 0x93, 0x0c, 0xfa, 0x86, 0xf9, 0xf1, 0xe8, 0xc3, 0xd0, 0xd8, 0xef, 0xbd, 0xed, 0x19, 0xdd,
+	0xc6, 0x1d, 0x0c, 0x50, 0xfb, 0xd4, 0x33, 0x4e, 0x0d, 0xd2, 0x40, 0x78, 0x0d, 0x56, 0x07, 0x43,
+	0x83, 0x74, 0x4e, 0x06, 0xa4, 0x51, 0xc2, 0x0a, 0x54, 0x07, 0xa7, 0x47, 0x06, 0x69, 0x94, 0xdf,
+	0x18, 0x5f, 0xf6, 0xc7, 0x4e, 0x78, 0x16, 0x8d, 0x74, 0x8b, 0x4f, 0xda, 0x29, 0x61, 0xfb, 0x3f,
+	0xc2, 0x76, 0x42, 0xd8, 0xa6, 0x79, 0x42, 0xb9, 0x9a, 0x9a, 0x63, 0x6e, 0xca, 0x3f, 0xcf, 0x51,
+	0x4d, 0xfe, 0xec, 0xfe, 0x1d, 0x00, 0xb6, 0x9a, 0xe6, 0x40, 0x52, 0x07, 0x00, 0x00,
 }
@@ -31,7 +31,8 @@ type AuthPair struct {
 }
 
 // UnaryServerInterceptor returns a new unary server interceptor that performs per-request auth.
-func UnaryServerInterceptor(authFuncs map[string]AuthPair) grpc.UnaryServerInterceptor {
+// Each decision is written to sink, unless sink is nil.
+func UnaryServerInterceptor(authFuncs map[string]AuthPair, sink AuditSink) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		policy, ok := authFuncs[info.FullMethod]
 		if !ok {
@@ -40,11 +41,16 @@ func UnaryServerInterceptor(authFuncs map[string]AuthPair) grpc.UnaryServerInter
 			return handler(ctx, req)
 
 		}
-		newCtx, err := policy.AuthnFunc(ctx)
+		record := &AuditRecord{Method: info.FullMethod}
+		newCtx, err := policy.AuthnFunc(withAuditRecord(ctx, record))
 		if err != nil {
+			record.Rule = "authentication failed"
+			writeAudit(ctx, sink, record, req, err)
 			return nil, err
 		}
-		if err := policy.AuthzFunc(newCtx, req); err != nil {
+		err = policy.AuthzFunc(newCtx, req)
+		writeAudit(newCtx, sink, record, req, err)
+		if err != nil {
 			return nil, err
 		}
 		return handler(newCtx, req)
@@ -52,7 +58,10 @@ func UnaryServerInterceptor(authFuncs map[string]AuthPair) grpc.UnaryServerInter
 }
 
 // StreamServerInterceptor returns a new stream server interceptor that performs per-request auth.
-func StreamServerInterceptor(authFuncs map[string]AuthPair) grpc.StreamServerInterceptor {
+// Streams are authenticated when they start, and each message received from
+// the client is authorized before it is passed to the method. Each decision
+// is written to sink, unless sink is nil.
+func StreamServerInterceptor(authFuncs map[string]AuthPair, sink AuditSink) grpc.StreamServerInterceptor {
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		policy, ok := authFuncs[info.FullMethod]
 		if !ok {
@@ -63,13 +72,36 @@ func StreamServerInterceptor(authFuncs map[string]AuthPair) grpc.StreamServerInt
 
 		newCtx, err := policy.AuthnFunc(stream.Context())
 		if err != nil {
-			return err
-		}
-		if err := policy.AuthzFunc(newCtx, stream); err != nil {
+			record := &AuditRecord{Method: info.FullMethod, Rule: "authentication failed"}
+			writeAudit(stream.Context(), sink, record, nil, err)
 			return err
 		}
 		wrapped := grpc_middleware.WrapServerStream(stream)
 		wrapped.WrappedContext = newCtx
-		return handler(srv, wrapped)
+		return handler(srv, &authzServerStream{
+			WrappedServerStream: wrapped,
+			method:              info.FullMethod,
+			authzFunc:           policy.AuthzFunc,
+			sink:                sink,
+		})
+	}
+}
+
+// authzServerStream authorizes the messages it receives.
+type authzServerStream struct {
+	*grpc_middleware.WrappedServerStream
+	method    string
+	authzFunc AuthzFunc
+	sink      AuditSink
+}
+
+// RecvMsg receives a message and returns an error if it is not authorized.
+func (s *authzServerStream) RecvMsg(m interface{}) error {
+	if err := s.WrappedServerStream.RecvMsg(m); err != nil {
+		return err
 	}
+	record := &AuditRecord{Method: s.method}
+	err := s.authzFunc(withAuditRecord(s.Context(), record), m)
+	writeAudit(s.Context(), s.sink, record, m, err)
+	return err
 }
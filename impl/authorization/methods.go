@@ -0,0 +1,82 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// KTService is the name of the KeyTransparency gRPC service.
+const KTService = "google.keytransparency.v1.KeyTransparency"
+
+// readPrefixes are the method name prefixes of read-only RPCs.
+// Every other method is treated as a write, so that new RPCs fail closed.
+var readPrefixes = []string{"Get", "List", "BatchGet", "BatchList"}
+
+// IsWriteMethod returns true if the RPC named method modifies server state.
+func IsWriteMethod(method string) bool {
+	for _, p := range readPrefixes {
+		if strings.HasPrefix(method, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckWriteMethods returns an error if any write RPC of service, as registered
+// in services, does not have an AuthPair in unary or stream.
+// services is typically the result of grpc.Server.GetServiceInfo.
+func CheckWriteMethods(services map[string]grpc.ServiceInfo, service string, unary, stream map[string]AuthPair) error {
+	return checkMethods(services, service, unary, stream, IsWriteMethod)
+}
+
+// CheckAllMethods returns an error if any RPC of service, as registered in
+// services, does not have an AuthPair in unary or stream.
+func CheckAllMethods(services map[string]grpc.ServiceInfo, service string, unary, stream map[string]AuthPair) error {
+	return checkMethods(services, service, unary, stream, func(string) bool { return true })
+}
+
+// checkMethods returns an error if any RPC of service for which needsAuth
+// returns true does not have an AuthPair in unary or stream.
+func checkMethods(services map[string]grpc.ServiceInfo, service string, unary, stream map[string]AuthPair,
+	needsAuth func(method string) bool) error {
+	info, ok := services[service]
+	if !ok {
+		return fmt.Errorf("service %v is not registered", service)
+	}
+	var missing []string
+	for _, m := range info.Methods {
+		if !needsAuth(m.Name) {
+			continue
+		}
+		fullMethod := fmt.Sprintf("/%v/%v", service, m.Name)
+		authFuncs := unary
+		if m.IsClientStream || m.IsServerStream {
+			authFuncs = stream
+		}
+		if _, ok := authFuncs[fullMethod]; !ok {
+			missing = append(missing, fullMethod)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("methods without authentication: %v", strings.Join(missing, ", "))
+	}
+	return nil
+}
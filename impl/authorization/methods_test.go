@@ -0,0 +1,81 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func TestCheckWriteMethods(t *testing.T) {
+	const service = "google.keytransparency.v1.KeyTransparency"
+	services := map[string]grpc.ServiceInfo{
+		service: {Methods: []grpc.MethodInfo{
+			{Name: "GetUser"},
+			{Name: "BatchListUserRevisions"},
+			{Name: "ListMutationsStream", IsServerStream: true},
+			{Name: "QueueEntryUpdate"},
+			{Name: "BatchQueueUserUpdate"},
+		}},
+	}
+	both := map[string]AuthPair{
+		"/" + service + "/QueueEntryUpdate":     {},
+		"/" + service + "/BatchQueueUserUpdate": {},
+	}
+	for _, tc := range []struct {
+		desc    string
+		service string
+		unary   map[string]AuthPair
+		stream  map[string]AuthPair
+		wantErr bool
+	}{
+		{desc: "all writes", service: service, unary: both},
+		{desc: "old method name", service: service, wantErr: true, unary: map[string]AuthPair{
+			"/" + service + "/UpdateEntry":          {},
+			"/" + service + "/BatchQueueUserUpdate": {},
+		}},
+		{desc: "batch missing", service: service, wantErr: true, unary: map[string]AuthPair{
+			"/" + service + "/QueueEntryUpdate": {},
+		}},
+		{desc: "wrong interceptor", service: service, stream: both, wantErr: true},
+		{desc: "unregistered service", service: "other", unary: both, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := CheckWriteMethods(services, tc.service, tc.unary, tc.stream)
+			if got := err != nil; got != tc.wantErr {
+				t.Errorf("CheckWriteMethods(): %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsWriteMethod(t *testing.T) {
+	for _, tc := range []struct {
+		method string
+		want   bool
+	}{
+		{method: "GetDirectory", want: false},
+		{method: "ListUserRejections", want: false},
+		{method: "BatchGetUserIndex", want: false},
+		{method: "QueueEntryUpdate", want: true},
+		{method: "BatchQueueUserUpdate", want: true},
+		{method: "DeleteDirectory", want: true},
+	} {
+		if got := IsWriteMethod(tc.method); got != tc.want {
+			t.Errorf("IsWriteMethod(%v): %v, want %v", tc.method, got, tc.want)
+		}
+	}
+}
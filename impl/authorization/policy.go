@@ -0,0 +1,202 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	authzpb "github.com/google/keytransparency/impl/authorization/authz_go_proto"
+)
+
+// ReadPolicyFile reads an AuthorizationPolicy in text or JSON proto format
+// from path and validates it.
+func ReadPolicyFile(path string) (*authzpb.AuthorizationPolicy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := parsePolicy(b)
+	if err != nil {
+		return nil, fmt.Errorf("authorization: %v: %v", path, err)
+	}
+	return policy, nil
+}
+
+// parsePolicy parses and validates an AuthorizationPolicy in JSON proto format
+// if b starts with '{', and in text proto format otherwise.
+func parsePolicy(b []byte) (*authzpb.AuthorizationPolicy, error) {
+	var policy authzpb.AuthorizationPolicy
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		if err := jsonpb.Unmarshal(bytes.NewReader(b), &policy); err != nil {
+			return nil, fmt.Errorf("parsing JSON: %v", err)
+		}
+	} else if err := proto.UnmarshalText(string(b), &policy); err != nil {
+		return nil, fmt.Errorf("parsing text proto: %v", err)
+	}
+	if err := ValidatePolicy(&policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// ValidatePolicy returns an error if p refers to roles or groups it does not
+// define, if its groups contain themselves, or if any of its principals or
+// resources are malformed.
+func ValidatePolicy(p *authzpb.AuthorizationPolicy) error {
+	for name, g := range p.GetGroups() {
+		if name == "" {
+			return errors.New("group with empty name")
+		}
+		for _, m := range g.GetMembers() {
+			if err := validatePrincipal(p, m); err != nil {
+				return fmt.Errorf("group %q: %v", name, err)
+			}
+		}
+	}
+	if err := checkGroupCycles(p); err != nil {
+		return err
+	}
+	for label, r := range p.GetRoles() {
+		for _, m := range r.GetPrincipals() {
+			if err := validatePrincipal(p, m); err != nil {
+				return fmt.Errorf("role %q: %v", label, err)
+			}
+		}
+	}
+	for resource, labels := range p.GetResourceToRoleLabels() {
+		if !isDirectoryResource(resource) {
+			return fmt.Errorf("resource_to_role_labels: invalid resource %q", resource)
+		}
+		if err := validateRoleLabels(p, resource, labels.GetLabels(), false); err != nil {
+			return fmt.Errorf("resource_to_role_labels: %v", err)
+		}
+	}
+	for resource, labels := range p.GetAdminResourceToRoleLabels() {
+		if resource != allDirectories && !isDirectoryResource(resource) {
+			return fmt.Errorf("admin_resource_to_role_labels: invalid resource %q", resource)
+		}
+		if err := validateRoleLabels(p, resource, labels.GetLabels(), true); err != nil {
+			return fmt.Errorf("admin_resource_to_role_labels: %v", err)
+		}
+	}
+	for resource, labels := range p.GetReaderResourceToRoleLabels() {
+		if resource != allDirectories && !isDirectoryResource(resource) {
+			return fmt.Errorf("reader_resource_to_role_labels: invalid resource %q", resource)
+		}
+		if err := validateRoleLabels(p, resource, labels.GetLabels(), false); err != nil {
+			return fmt.Errorf("reader_resource_to_role_labels: %v", err)
+		}
+	}
+	for i, d := range p.GetDenies() {
+		if len(d.GetPrincipals()) == 0 || len(d.GetResources()) == 0 {
+			return fmt.Errorf("deny %d: principals and resources are required", i)
+		}
+		for _, m := range d.GetPrincipals() {
+			if err := validatePrincipal(p, m); err != nil {
+				return fmt.Errorf("deny %d: %v", i, err)
+			}
+		}
+		for _, r := range d.GetResources() {
+			if r != allDirectories && !isDirectoryResource(r) {
+				return fmt.Errorf("deny %d: invalid resource %q", i, r)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePrincipal returns an error unless principal is an identifier, a
+// domain wildcard, or a reference to a group of p.
+func validatePrincipal(p *authzpb.AuthorizationPolicy, principal string) error {
+	switch {
+	case principal == "":
+		return errors.New("empty principal")
+	case strings.HasPrefix(principal, groupPrefix):
+		if _, ok := p.GetGroups()[strings.TrimPrefix(principal, groupPrefix)]; !ok {
+			return fmt.Errorf("undefined group in %q", principal)
+		}
+	case strings.HasPrefix(principal, wildcardPrefix):
+		domain := strings.TrimPrefix(principal, wildcardPrefix)
+		if domain == "" || strings.ContainsAny(domain, "*@") {
+			return fmt.Errorf("invalid wildcard %q, want *@domain", principal)
+		}
+	case strings.Contains(principal, "*"):
+		return fmt.Errorf("invalid wildcard %q, want *@domain", principal)
+	}
+	return nil
+}
+
+// validateRoleLabels returns an error if any of labels is not a role of p.
+// Roles listed for the admin API must have an admin role.
+func validateRoleLabels(p *authzpb.AuthorizationPolicy, resource string, labels []string, admin bool) error {
+	for _, l := range labels {
+		role, ok := p.GetRoles()[l]
+		if !ok {
+			return fmt.Errorf("%v: undefined role %q", resource, l)
+		}
+		if admin && role.GetAdminRole() == authzpb.AuthorizationPolicy_ADMIN_ROLE_UNSPECIFIED {
+			return fmt.Errorf("%v: role %q has no admin_role", resource, l)
+		}
+	}
+	return nil
+}
+
+// isDirectoryResource returns true if resource is "directories/{directory_id}".
+func isDirectoryResource(resource string) bool {
+	id := strings.TrimPrefix(resource, allDirectories+"/")
+	return id != resource && id != "" && !strings.Contains(id, "/")
+}
+
+// checkGroupCycles returns an error if any group of p contains itself.
+func checkGroupCycles(p *authzpb.AuthorizationPolicy) error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("group %q contains itself", name)
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		for _, m := range p.GetGroups()[name].GetMembers() {
+			if strings.HasPrefix(m, groupPrefix) {
+				if err := visit(strings.TrimPrefix(m, groupPrefix)); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = done
+		return nil
+	}
+	for name := range p.GetGroups() {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
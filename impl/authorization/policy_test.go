@@ -0,0 +1,154 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	authzpb "github.com/google/keytransparency/impl/authorization/authz_go_proto"
+)
+
+func TestReadPolicyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		desc    string
+		text    string
+		want    *authzpb.AuthorizationPolicy
+		wantErr bool
+	}{
+		{desc: "empty", text: "", want: &authzpb.AuthorizationPolicy{}},
+		{
+			desc: "admin role",
+			text: `roles: <key: "owners" value: <principals: "owner@example.com" admin_role: OWNER>>
+			       admin_resource_to_role_labels: <key: "directories" value: <labels: "owners">>`,
+			want: &authzpb.AuthorizationPolicy{
+				Roles: map[string]*authzpb.AuthorizationPolicy_Role{
+					"owners": {
+						Principals: []string{"owner@example.com"},
+						AdminRole:  authzpb.AuthorizationPolicy_OWNER,
+					},
+				},
+				AdminResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+					"directories": {Labels: []string{"owners"}},
+				},
+			},
+		},
+		{desc: "unknown field", text: `admins: "owner@example.com"`, wantErr: true},
+		{desc: "invalid policy", text: `roles: <key: "r" value: <principals: "*">>`, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			path := filepath.Join(dir, "policy.textproto")
+			if err := ioutil.WriteFile(path, []byte(tc.text), 0600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadPolicyFile(path)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("ReadPolicyFile(): %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && !proto.Equal(got, tc.want) {
+				t.Errorf("ReadPolicyFile(): %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidatePolicy(t *testing.T) {
+	type policy = authzpb.AuthorizationPolicy
+	type role = authzpb.AuthorizationPolicy_Role
+	type group = authzpb.AuthorizationPolicy_Group
+	type labels = authzpb.AuthorizationPolicy_RoleLabels
+	type deny = authzpb.AuthorizationPolicy_Deny
+	for _, tc := range []struct {
+		desc    string
+		p       *policy
+		wantErr bool
+	}{
+		{desc: "empty", p: &policy{}},
+		{desc: "valid", p: groupPolicy.Policy},
+		{desc: "undefined role", wantErr: true, p: &policy{
+			ResourceToRoleLabels: map[string]*labels{"directories/1": {Labels: []string{"missing"}}},
+		}},
+		{desc: "undefined group", wantErr: true, p: &policy{
+			Roles: map[string]*role{"r": {Principals: []string{"group:missing"}}},
+		}},
+		{desc: "group cycle", wantErr: true, p: &policy{
+			Groups: map[string]*group{
+				"a": {Members: []string{"group:b"}},
+				"b": {Members: []string{"group:c"}},
+				"c": {Members: []string{"group:a"}},
+			},
+		}},
+		{desc: "group contains itself", wantErr: true, p: &policy{
+			Groups: map[string]*group{"a": {Members: []string{"group:a"}}},
+		}},
+		{desc: "shared nested group", p: &policy{
+			Groups: map[string]*group{
+				"a": {Members: []string{"group:c"}},
+				"b": {Members: []string{"group:c"}},
+				"c": {Members: []string{"alice@example.com"}},
+			},
+		}},
+		{desc: "bare wildcard", wantErr: true, p: &policy{
+			Roles: map[string]*role{"r": {Principals: []string{"*"}}},
+		}},
+		{desc: "partial wildcard", wantErr: true, p: &policy{
+			Roles: map[string]*role{"r": {Principals: []string{"admin*@example.com"}}},
+		}},
+		{desc: "wildcard without domain", wantErr: true, p: &policy{
+			Roles: map[string]*role{"r": {Principals: []string{"*@"}}},
+		}},
+		{desc: "empty principal", wantErr: true, p: &policy{
+			Groups: map[string]*group{"a": {Members: []string{""}}},
+		}},
+		{desc: "user resource for all directories", wantErr: true, p: &policy{
+			Roles:                map[string]*role{"r": {}},
+			ResourceToRoleLabels: map[string]*labels{"directories": {Labels: []string{"r"}}},
+		}},
+		{desc: "malformed resource", wantErr: true, p: &policy{
+			Roles:                map[string]*role{"r": {}},
+			ResourceToRoleLabels: map[string]*labels{"directories/1/users": {Labels: []string{"r"}}},
+		}},
+		{desc: "admin resource without admin role", wantErr: true, p: &policy{
+			Roles:                     map[string]*role{"r": {Principals: []string{"alice@example.com"}}},
+			AdminResourceToRoleLabels: map[string]*labels{"directories": {Labels: []string{"r"}}},
+		}},
+		{desc: "deny without resources", wantErr: true, p: &policy{
+			Denies: []*deny{{Principals: []string{"alice@example.com"}}},
+		}},
+		{desc: "deny without principals", wantErr: true, p: &policy{
+			Denies: []*deny{{Resources: []string{"directories"}}},
+		}},
+		{desc: "deny with malformed resource", wantErr: true, p: &policy{
+			Denies: []*deny{{Principals: []string{"alice@example.com"}, Resources: []string{"users"}}},
+		}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := ValidatePolicy(tc.p)
+			if got := err != nil; got != tc.wantErr {
+				t.Errorf("ValidatePolicy(): %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
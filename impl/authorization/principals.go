@@ -0,0 +1,104 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	"github.com/google/keytransparency/impl/authentication"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// groupPrefix starts references to the groups of a policy.
+	groupPrefix = "group:"
+	// wildcardPrefix starts domain wildcards.
+	wildcardPrefix = "*@"
+)
+
+// isPrincipalIn returns true if identity matches any of principals.
+func (a *AuthzPolicy) isPrincipalIn(principals []string, identity string) bool {
+	visited := make(map[string]bool)
+	for _, p := range principals {
+		if a.matches(p, identity, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns true if identity matches principal, which is an identifier,
+// a domain wildcard or a group reference. visited holds the groups that have
+// already been searched, so that each group is searched at most once.
+func (a *AuthzPolicy) matches(principal, identity string, visited map[string]bool) bool {
+	switch {
+	case strings.HasPrefix(principal, groupPrefix):
+		name := strings.TrimPrefix(principal, groupPrefix)
+		if visited[name] {
+			return false
+		}
+		visited[name] = true
+		for _, m := range a.Policy.GetGroups()[name].GetMembers() {
+			if a.matches(m, identity, visited) {
+				return true
+			}
+		}
+		return false
+	case strings.HasPrefix(principal, wildcardPrefix):
+		// Only email addresses belong to a domain. Other identities, such
+		// as the URIs of mTLS certificates, may contain an @ anywhere.
+		if !isEmail(identity) {
+			return false
+		}
+		// Domains are case insensitive.
+		i := strings.LastIndex(identity, "@")
+		return strings.EqualFold(identity[i+1:], principal[len(wildcardPrefix):])
+	default:
+		return principal == identity
+	}
+}
+
+// isEmail returns true if identity is a bare email address.
+func isEmail(identity string) bool {
+	addr, err := mail.ParseAddress(identity)
+	return err == nil && addr.Address == identity
+}
+
+// checkDenied returns PermissionDenied if a deny rule of the policy covers
+// sctx.Email on any of resources.
+func (a *AuthzPolicy) checkDenied(ctx context.Context, sctx *authentication.SecurityContext, resources ...string) error {
+	for i, d := range a.Policy.GetDenies() {
+		for _, r := range resources {
+			if denyCovers(d.GetResources(), r) && a.isPrincipalIn(d.GetPrincipals(), sctx.Email) {
+				recordRule(ctx, "denies[%d] on %v", i, r)
+				return status.Errorf(codes.PermissionDenied, "%v is denied on %v", sctx.Email, r)
+			}
+		}
+	}
+	return nil
+}
+
+// denyCovers returns true if the resources of a deny rule include resource.
+func denyCovers(resources []string, resource string) bool {
+	for _, r := range resources {
+		if r == allDirectories || r == resource {
+			return true
+		}
+	}
+	return false
+}
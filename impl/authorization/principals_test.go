@@ -0,0 +1,204 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/keytransparency/impl/authentication"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	authzpb "github.com/google/keytransparency/impl/authorization/authz_go_proto"
+)
+
+var groupPolicy = &AuthzPolicy{
+	Policy: &authzpb.AuthorizationPolicy{
+		Groups: map[string]*authzpb.AuthorizationPolicy_Group{
+			"support": {Members: []string{"*@support.example.com", "group:oncall"}},
+			"oncall":  {Members: []string{"sre@example.com", "mallory@example.com"}},
+			"blocked": {Members: []string{"mallory@example.com"}},
+		},
+		Roles: map[string]*authzpb.AuthorizationPolicy_Role{
+			"support": {Principals: []string{"group:support"}},
+			"owners": {
+				Principals: []string{"*@example.com"},
+				AdminRole:  authzpb.AuthorizationPolicy_OWNER,
+			},
+		},
+		ResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+			"directories/1": {Labels: []string{"support"}},
+			"directories/2": {Labels: []string{"support"}},
+		},
+		AdminResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+			"directories": {Labels: []string{"owners"}},
+		},
+		Denies: []*authzpb.AuthorizationPolicy_Deny{
+			{Principals: []string{"group:blocked"}, Resources: []string{"directories"}},
+			{Principals: []string{"sre@example.com"}, Resources: []string{"directories/2"}},
+		},
+	},
+}
+
+func TestMatches(t *testing.T) {
+	for _, tc := range []struct {
+		principal string
+		identity  string
+		want      bool
+	}{
+		{principal: "alice@example.com", identity: "alice@example.com", want: true},
+		{principal: "alice@example.com", identity: "bob@example.com", want: false},
+		{principal: "*@example.com", identity: "alice@example.com", want: true},
+		{principal: "*@example.com", identity: "alice@EXAMPLE.com", want: true},
+		{principal: "*@example.com", identity: "alice@sub.example.com", want: false},
+		{principal: "*@example.com", identity: "alice@evilexample.com", want: false},
+		{principal: "*@example.com", identity: "@example.com", want: false},
+		{principal: "*@example.com", identity: "example.com", want: false},
+		{principal: "*@example.com", identity: "spiffe://evil/x@example.com", want: false},
+		{principal: "*@example.com", identity: "Alice <alice@example.com>", want: false},
+		{principal: "*@example.com", identity: "alice@evil.com@example.com", want: false},
+		{principal: "group:support", identity: "bob@support.example.com", want: true},
+		{principal: "group:support", identity: "sre@example.com", want: true},
+		{principal: "group:support", identity: "alice@example.com", want: false},
+		{principal: "group:undefined", identity: "alice@example.com", want: false},
+	} {
+		if got := groupPolicy.isPrincipalIn([]string{tc.principal}, tc.identity); got != tc.want {
+			t.Errorf("isPrincipalIn(%v, %v): %v, want %v", tc.principal, tc.identity, got, tc.want)
+		}
+	}
+}
+
+func TestMatchesGroupCycle(t *testing.T) {
+	// ValidatePolicy rejects cycles, but matching must still terminate.
+	a := &AuthzPolicy{Policy: &authzpb.AuthorizationPolicy{
+		Groups: map[string]*authzpb.AuthorizationPolicy_Group{
+			"a": {Members: []string{"group:b"}},
+			"b": {Members: []string{"group:a", "bob@example.com"}},
+		},
+	}}
+	if !a.isPrincipalIn([]string{"group:a"}, "bob@example.com") {
+		t.Errorf("isPrincipalIn(group:a, bob): false, want true")
+	}
+	if a.isPrincipalIn([]string{"group:a"}, "alice@example.com") {
+		t.Errorf("isPrincipalIn(group:a, alice): true, want false")
+	}
+}
+
+// TestEvaluationOrder checks that deny rules come first, then users acting on
+// their own entries, then roles.
+func TestEvaluationOrder(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc        string
+		sctx        *authentication.SecurityContext
+		directoryID string
+		userID      string
+		wantCode    codes.Code
+	}{
+		{
+			desc:        "unverified before self",
+			sctx:        &authentication.SecurityContext{Email: "carol@example.org"},
+			directoryID: "1",
+			userID:      "carol@example.org",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			desc:        "deny before self",
+			sctx:        &authentication.SecurityContext{Email: "mallory@example.com", EmailVerified: true},
+			directoryID: "3",
+			userID:      "mallory@example.com",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			desc:        "deny before role",
+			sctx:        &authentication.SecurityContext{Email: "mallory@example.com", EmailVerified: true},
+			directoryID: "1",
+			userID:      "alice@example.org",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			desc:        "deny on one directory",
+			sctx:        &authentication.SecurityContext{Email: "sre@example.com", EmailVerified: true},
+			directoryID: "2",
+			userID:      "alice@example.org",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			desc:        "nested group role on other directory",
+			sctx:        &authentication.SecurityContext{Email: "sre@example.com", EmailVerified: true},
+			directoryID: "1",
+			userID:      "alice@example.org",
+		},
+		{
+			desc:        "self without role",
+			sctx:        &authentication.SecurityContext{Email: "carol@example.org", EmailVerified: true},
+			directoryID: "3",
+			userID:      "carol@example.org",
+		},
+		{
+			desc:        "wildcard role",
+			sctx:        &authentication.SecurityContext{Email: "bob@support.example.com", EmailVerified: true},
+			directoryID: "2",
+			userID:      "alice@example.org",
+		},
+		{
+			desc:        "wildcard role for a URI",
+			sctx:        &authentication.SecurityContext{Email: "spiffe://evil/x@support.example.com", EmailVerified: true},
+			directoryID: "2",
+			userID:      "alice@example.org",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			desc:        "no role",
+			sctx:        &authentication.SecurityContext{Email: "carol@example.org", EmailVerified: true},
+			directoryID: "1",
+			userID:      "alice@example.org",
+			wantCode:    codes.PermissionDenied,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			req := &pb.UpdateEntryRequest{
+				DirectoryId: tc.directoryID,
+				EntryUpdate: &pb.EntryUpdate{UserId: tc.userID},
+			}
+			err := groupPolicy.Authorize(authentication.NewContext(ctx, tc.sctx), req)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Errorf("Authorize(): %v, want %v", err, want)
+			}
+		})
+	}
+}
+
+func TestAdminDeny(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		email    string
+		req      interface{}
+		wantCode codes.Code
+	}{
+		{email: "sre@example.com", req: &pb.DeleteDirectoryRequest{DirectoryId: "1"}},
+		{email: "sre@example.com", req: &pb.DeleteDirectoryRequest{DirectoryId: "2"}, wantCode: codes.PermissionDenied},
+		{email: "sre@example.com", req: &pb.ListDirectoriesRequest{}},
+		{email: "mallory@example.com", req: &pb.ListDirectoriesRequest{}, wantCode: codes.PermissionDenied},
+	} {
+		sctx := &authentication.SecurityContext{Email: tc.email, EmailVerified: true}
+		err := groupPolicy.AuthorizeAdmin(authentication.NewContext(ctx, sctx), tc.req)
+		if got, want := status.Code(err), tc.wantCode; got != want {
+			t.Errorf("AuthorizeAdmin(%v, %T): %v, want %v", tc.email, tc.req, err, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/google/keytransparency/core/directory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+)
+
+var (
+	// privateMethods are the unary methods of KTService that return users or
+	// mutations. Revisions only contain signed roots, and stay public so
+	// that monitors and gossip can verify them.
+	privateMethods = []string{
+		"GetUser",
+		"BatchGetUser",
+		"BatchGetUserIndex",
+		"ListEntryHistory",
+		"ListUserRevisions",
+		"BatchListUserRevisions",
+		"ListMutations",
+	}
+	// privateStreamMethods are the streaming methods of KTService that
+	// return users or mutations.
+	privateStreamMethods = []string{
+		"ListMutationsStream",
+	}
+)
+
+// PrivateReadAuthPairs returns unary and stream AuthPairs for the methods of
+// KTService that read users and mutations. Calls about directories that are
+// not private are allowed without authentication. Calls about private
+// directories are authenticated with authFunc and authorized with authzFunc,
+// which is typically AuthzPolicy.AuthorizeRead.
+func PrivateReadAuthPairs(directories directory.Storage, authFunc grpc_auth.AuthFunc,
+	authzFunc AuthzFunc) (unary, stream map[string]AuthPair) {
+	pair := AuthPair{
+		// Authentication is deferred until the directory is known to be private.
+		AuthnFunc: func(ctx context.Context) (context.Context, error) { return ctx, nil },
+		AuthzFunc: authorizePrivate(directories, authFunc, authzFunc),
+	}
+	unary = make(map[string]AuthPair, len(privateMethods))
+	for _, m := range privateMethods {
+		unary[fmt.Sprintf("/%v/%v", KTService, m)] = pair
+	}
+	stream = make(map[string]AuthPair, len(privateStreamMethods))
+	for _, m := range privateStreamMethods {
+		stream[fmt.Sprintf("/%v/%v", KTService, m)] = pair
+	}
+	return unary, stream
+}
+
+// authorizePrivate returns an AuthzFunc that authenticates and authorizes
+// requests about private directories, and allows all other requests.
+func authorizePrivate(directories directory.Storage, authFunc grpc_auth.AuthFunc, authzFunc AuthzFunc) AuthzFunc {
+	return func(ctx context.Context, m interface{}) error {
+		req, ok := m.(interface{ GetDirectoryId() string })
+		if !ok {
+			return status.Errorf(codes.PermissionDenied, "message type %T not recognized", m)
+		}
+		d, err := directories.Read(ctx, req.GetDirectoryId(), false)
+		if status.Code(err) == codes.NotFound {
+			// The method reports missing directories itself.
+			recordRule(ctx, "directory not found")
+			return nil
+		} else if err != nil {
+			glog.Errorf("directories.Read(%v): %v", req.GetDirectoryId(), err)
+			return status.Errorf(codes.Internal, "Cannot fetch directory info")
+		}
+		if !d.Private {
+			recordRule(ctx, "public directory")
+			return nil
+		}
+		authCtx, err := authFunc(ctx)
+		if err != nil {
+			recordRule(ctx, "authentication failed")
+			return err
+		}
+		return authzFunc(authCtx, m)
+	}
+}
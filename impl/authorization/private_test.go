@@ -0,0 +1,194 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/keytransparency/core/directory"
+	"github.com/google/keytransparency/core/fake"
+	"github.com/google/keytransparency/impl/authentication"
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	authzpb "github.com/google/keytransparency/impl/authorization/authz_go_proto"
+)
+
+const (
+	reader    = "reader@example.com"
+	allReader = "auditor@example.com"
+)
+
+var readPolicy = &AuthzPolicy{
+	Policy: &authzpb.AuthorizationPolicy{
+		Roles: map[string]*authzpb.AuthorizationPolicy_Role{
+			"writers":  {Principals: []string{admin1}},
+			"readers":  {Principals: []string{reader}},
+			"auditors": {Principals: []string{allReader}},
+		},
+		ResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+			res1: {Labels: []string{"writers"}},
+		},
+		ReaderResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+			res1:           {Labels: []string{"readers"}},
+			allDirectories: {Labels: []string{"auditors"}},
+		},
+		Denies: []*authzpb.AuthorizationPolicy_Deny{
+			{Principals: []string{allReader}, Resources: []string{res2}},
+		},
+	},
+}
+
+func TestAuthorizeRead(t *testing.T) {
+	for _, tc := range []struct {
+		desc      string
+		principal string
+		req       proto.Message
+		wantCode  codes.Code
+	}{
+		{desc: "self", principal: testUser, req: &pb.GetUserRequest{DirectoryId: "1", UserId: testUser}},
+		{desc: "other user", principal: testUser, req: &pb.GetUserRequest{DirectoryId: "1", UserId: admin2},
+			wantCode: codes.PermissionDenied},
+		{desc: "reader", principal: reader, req: &pb.ListEntryHistoryRequest{DirectoryId: "1", UserId: testUser}},
+		{desc: "reader of other directory", principal: reader, req: &pb.ListMutationsRequest{DirectoryId: "2"},
+			wantCode: codes.PermissionDenied},
+		{desc: "writer", principal: admin1, req: &pb.ListMutationsRequest{DirectoryId: "1"}},
+		{desc: "all directories", principal: allReader, req: &pb.ListUserRevisionsRequest{DirectoryId: "3", UserId: testUser}},
+		{desc: "denied", principal: allReader, req: &pb.ListMutationsRequest{DirectoryId: "2"},
+			wantCode: codes.PermissionDenied},
+		{desc: "mutations of self", principal: testUser, req: &pb.ListMutationsRequest{DirectoryId: "1"},
+			wantCode: codes.PermissionDenied},
+		{desc: "batch of self", principal: testUser,
+			req: &pb.BatchGetUserRequest{DirectoryId: "1", UserIds: []string{testUser, testUser}}},
+		{desc: "batch with others", principal: testUser,
+			req:      &pb.BatchListUserRevisionsRequest{DirectoryId: "1", UserIds: []string{testUser, admin2}},
+			wantCode: codes.PermissionDenied},
+		{desc: "empty batch", principal: testUser, req: &pb.BatchGetUserIndexRequest{DirectoryId: "1"},
+			wantCode: codes.PermissionDenied},
+		{desc: "write", principal: reader, req: &pb.UpdateEntryRequest{DirectoryId: "1"},
+			wantCode: codes.PermissionDenied},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := authentication.NewContext(context.Background(),
+				&authentication.SecurityContext{Email: tc.principal, EmailVerified: true})
+			err := readPolicy.AuthorizeRead(ctx, tc.req)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Errorf("AuthorizeRead(): %v, want %v", err, want)
+			}
+		})
+	}
+	if err := readPolicy.AuthorizeRead(context.Background(), &pb.ListMutationsRequest{DirectoryId: "1"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("AuthorizeRead(no SecurityContext): %v, want %v", err, codes.Unauthenticated)
+	}
+}
+
+func newPrivateStorage(ctx context.Context, t *testing.T) directory.Storage {
+	t.Helper()
+	directories := fake.NewDirectoryStorage()
+	for _, d := range []*directory.Directory{
+		{DirectoryID: "1", Private: true},
+		{DirectoryID: "2"},
+	} {
+		if err := directories.Write(ctx, d); err != nil {
+			t.Fatalf("Write(): %v", err)
+		}
+	}
+	return directories
+}
+
+func TestPrivateReadAuthPairs(t *testing.T) {
+	ctx := context.Background()
+	unary, stream := PrivateReadAuthPairs(newPrivateStorage(ctx, t), authentication.FakeAuthFunc, readPolicy.AuthorizeRead)
+	if err := CheckAllMethods(map[string]grpc.ServiceInfo{
+		KTService: {Methods: []grpc.MethodInfo{{Name: "GetUser"}, {Name: "ListMutationsStream", IsServerStream: true}}},
+	}, KTService, unary, stream); err != nil {
+		t.Errorf("CheckAllMethods(): %v", err)
+	}
+
+	getUser := unary["/"+KTService+"/GetUser"]
+	for _, tc := range []struct {
+		desc        string
+		principal   string
+		directoryID string
+		wantCode    codes.Code
+	}{
+		{desc: "public", directoryID: "2"},
+		{desc: "missing directory", directoryID: "3"},
+		{desc: "unauthenticated", directoryID: "1", wantCode: codes.Unauthenticated},
+		{desc: "reader", principal: reader, directoryID: "1"},
+		{desc: "not a reader", principal: admin2, directoryID: "1", wantCode: codes.PermissionDenied},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			inCtx := ctx
+			if tc.principal != "" {
+				inCtx = metautils.ExtractOutgoing(authentication.WithOutgoingFakeAuth(ctx, tc.principal)).ToIncoming(ctx)
+			}
+			authCtx, err := getUser.AuthnFunc(inCtx)
+			if err != nil {
+				t.Fatalf("AuthnFunc(): %v", err)
+			}
+			err = getUser.AuthzFunc(authCtx, &pb.GetUserRequest{DirectoryId: tc.directoryID, UserId: testUser})
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Errorf("AuthzFunc(): %v, want %v", err, want)
+			}
+		})
+	}
+}
+
+// fakeServerStream receives a single request.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req proto.Message
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	ctx := context.Background()
+	_, stream := PrivateReadAuthPairs(newPrivateStorage(ctx, t), authentication.FakeAuthFunc, readPolicy.AuthorizeRead)
+	interceptor := StreamServerInterceptor(stream, nil)
+	info := &grpc.StreamServerInfo{FullMethod: "/" + KTService + "/ListMutationsStream", IsServerStream: true}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		var req pb.ListMutationsRequest
+		return ss.RecvMsg(&req)
+	}
+	for _, tc := range []struct {
+		desc        string
+		directoryID string
+		wantCode    codes.Code
+	}{
+		{desc: "public", directoryID: "2"},
+		{desc: "private", directoryID: "1", wantCode: codes.Unauthenticated},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ss := &fakeServerStream{ctx: ctx, req: &pb.ListMutationsRequest{DirectoryId: tc.directoryID}}
+			err := interceptor(nil, ss, info, handler)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Errorf("ListMutationsStream: %v, want %v", err, want)
+			}
+		})
+	}
+}
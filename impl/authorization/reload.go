@@ -0,0 +1,155 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/monitoring"
+)
+
+const resultLabel = "result"
+
+var (
+	initMetrics   sync.Once
+	policyReloads monitoring.Counter
+	policyVersion monitoring.Gauge
+)
+
+func createMetrics(mf monitoring.MetricFactory) {
+	policyReloads = mf.NewCounter(
+		"authz_policy_reloads",
+		"Number of attempts to load a changed authorization policy file, by result",
+		resultLabel)
+	policyVersion = mf.NewGauge(
+		"authz_policy_version",
+		"Version of the authorization policy in use, as the integer whose hexadecimal form is the version")
+}
+
+// PolicyFile is an authorization policy that is read from a file, and read
+// again when the file changes.
+type PolicyFile struct {
+	path string
+
+	// current holds the *AuthzPolicy in use. It is swapped atomically so
+	// that each call is authorized by a single version of the policy.
+	current atomic.Value
+	// version is the version of the policy in current, and failed the
+	// latest version that was rejected. They are only accessed by reload.
+	version string
+	failed  string
+	// mu serializes calls to reload.
+	mu sync.Mutex
+}
+
+// NewPolicyFile reads and validates the policy at path, which is in text or
+// JSON proto format.
+func NewPolicyFile(path string, mf monitoring.MetricFactory) (*PolicyFile, error) {
+	initMetrics.Do(func() { createMetrics(mf) })
+	f := &PolicyFile{path: path}
+	if err := f.reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Policy returns the policy in use.
+func (f *PolicyFile) Policy() *AuthzPolicy {
+	return f.current.Load().(*AuthzPolicy)
+}
+
+// Authorize calls Authorize on the policy in use.
+func (f *PolicyFile) Authorize(ctx context.Context, m interface{}) error {
+	return f.Policy().Authorize(ctx, m)
+}
+
+// AuthorizeAdmin calls AuthorizeAdmin on the policy in use.
+func (f *PolicyFile) AuthorizeAdmin(ctx context.Context, m interface{}) error {
+	return f.Policy().AuthorizeAdmin(ctx, m)
+}
+
+// AuthorizeRead calls AuthorizeRead on the policy in use.
+func (f *PolicyFile) AuthorizeRead(ctx context.Context, m interface{}) error {
+	return f.Policy().AuthorizeRead(ctx, m)
+}
+
+// Watch checks the file for changes every interval until ctx is done. A
+// changed policy replaces the one in use if it is valid. Otherwise, the
+// policy in use is kept and the error is logged once for that version.
+func (f *PolicyFile) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.reload(); err != nil {
+				glog.Errorf("Keeping authorization policy version %v: %v", f.Version(), err)
+			}
+		}
+	}
+}
+
+// Version returns the version of the policy in use, which is derived from the
+// contents of the file.
+func (f *PolicyFile) Version() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.version
+}
+
+// reload reads the file and swaps in its policy if the file has changed.
+func (f *PolicyFile) reload() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		policyReloads.Inc("failure")
+		return err
+	}
+	// The version fits in a float64 gauge without rounding.
+	sum := sha256.Sum256(b)
+	version := hex.EncodeToString(sum[:6])
+	if version == f.version || version == f.failed {
+		// Unchanged, or already rejected and reported.
+		return nil
+	}
+	policy, err := parsePolicy(b)
+	if err != nil {
+		f.failed = version
+		policyReloads.Inc("failure")
+		return fmt.Errorf("authorization: %v version %v: %v", f.path, version, err)
+	}
+
+	f.current.Store(&AuthzPolicy{Policy: policy})
+	policyReloads.Inc("success")
+	var n [8]byte
+	copy(n[2:], sum[:6])
+	policyVersion.Set(float64(binary.BigEndian.Uint64(n[:])))
+	glog.Infof("Loaded authorization policy %v version %v (previous version %q)", f.path, version, f.version)
+	f.version = version
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian/monitoring"
+
+	authzpb "github.com/google/keytransparency/impl/authorization/authz_go_proto"
+)
+
+func TestPolicyFileReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policy")
+
+	owners := &authzpb.AuthorizationPolicy{
+		Roles: map[string]*authzpb.AuthorizationPolicy_Role{
+			"owners": {Principals: []string{"owner@example.com"}, AdminRole: authzpb.AuthorizationPolicy_OWNER},
+		},
+		AdminResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+			"directories": {Labels: []string{"owners"}},
+		},
+	}
+	viewers := &authzpb.AuthorizationPolicy{
+		Roles: map[string]*authzpb.AuthorizationPolicy_Role{
+			"viewers": {Principals: []string{"*@example.com"}, AdminRole: authzpb.AuthorizationPolicy_VIEWER},
+		},
+		AdminResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+			"directories": {Labels: []string{"viewers"}},
+		},
+	}
+
+	if err := ioutil.WriteFile(path, []byte(proto.MarshalTextString(owners)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewPolicyFile(path, monitoring.InertMetricFactory{})
+	if err != nil {
+		t.Fatalf("NewPolicyFile(): %v", err)
+	}
+	if got := f.Policy().Policy; !proto.Equal(got, owners) {
+		t.Fatalf("Policy(): %v, want %v", got, owners)
+	}
+	firstVersion := f.Version()
+
+	for _, tc := range []struct {
+		desc        string
+		contents    string
+		wantErr     bool
+		want        *authzpb.AuthorizationPolicy
+		wantFailure float64
+		wantSuccess float64
+	}{
+		{desc: "unchanged", contents: proto.MarshalTextString(owners), want: owners},
+		{desc: "invalid", contents: `roles: <key: "r" value: <principals: "*">>`, wantErr: true,
+			want: owners, wantFailure: 1},
+		{desc: "unparsable", contents: `{"roles": `, wantErr: true, want: owners, wantFailure: 1},
+		{desc: "unparsable, already rejected", contents: `{"roles": `, want: owners},
+		{desc: "json", contents: `{"roles": {"viewers": {"principals": ["*@example.com"], "adminRole": "VIEWER"}},
+		                          "adminResourceToRoleLabels": {"directories": {"labels": ["viewers"]}}}`,
+			want: viewers, wantSuccess: 1},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			failures := policyReloads.Value("failure")
+			successes := policyReloads.Value("success")
+			if err := ioutil.WriteFile(path, []byte(tc.contents), 0600); err != nil {
+				t.Fatal(err)
+			}
+			err := f.reload()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("reload(): %v, wantErr %v", err, tc.wantErr)
+			}
+			if got := f.Policy().Policy; !proto.Equal(got, tc.want) {
+				t.Errorf("Policy(): %v, want %v", got, tc.want)
+			}
+			if got := policyReloads.Value("failure") - failures; got != tc.wantFailure {
+				t.Errorf("failed reloads: %v, want %v", got, tc.wantFailure)
+			}
+			if got := policyReloads.Value("success") - successes; got != tc.wantSuccess {
+				t.Errorf("successful reloads: %v, want %v", got, tc.wantSuccess)
+			}
+		})
+	}
+
+	if f.Version() == firstVersion {
+		t.Errorf("Version() = %v after reload, want a new version", firstVersion)
+	}
+	want, err := strconv.ParseInt(f.Version(), 16, 64)
+	if err != nil {
+		t.Fatalf("ParseInt(%v): %v", f.Version(), err)
+	}
+	if got := policyVersion.Value(); got != float64(want) {
+		t.Errorf("version gauge: %v, want %v (%v)", got, want, f.Version())
+	}
+}
+
+func TestNewPolicyFileInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policy")
+	if _, err := NewPolicyFile(path, monitoring.InertMetricFactory{}); err == nil {
+		t.Errorf("NewPolicyFile(missing file): nil error, want error")
+	}
+	if err := ioutil.WriteFile(path, []byte(`roles: <key: "r" value: <principals: "">>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPolicyFile(path, monitoring.InertMetricFactory{}); err == nil {
+		t.Errorf("NewPolicyFile(invalid policy): nil error, want error")
+	}
+}
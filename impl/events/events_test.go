@@ -0,0 +1,124 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/keytransparency/core/sequencer"
+)
+
+var testEvents = []*sequencer.Event{
+	{DirectoryID: "dir", Revision: 1, UserID: "alice", Index: []byte{1}, NewCommitment: []byte("c1")},
+	{DirectoryID: "dir", Revision: 1, UserID: "bob", Index: []byte{2},
+		OldCommitment: []byte("c2"), NewCommitment: []byte("c3")},
+}
+
+func TestWebhook(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc    string
+		status  int
+		delay   time.Duration
+		wantErr bool
+	}{
+		{desc: "ok", status: http.StatusOK},
+		{desc: "accepted", status: http.StatusAccepted},
+		{desc: "server error", status: http.StatusInternalServerError, wantErr: true},
+		{desc: "not modified", status: http.StatusNotModified, wantErr: true},
+		{desc: "timeout", status: http.StatusOK, delay: time.Second, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var got []*sequencer.Event
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("Method: %v, want POST", r.Method)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("Decode(): %v", err)
+				}
+				time.Sleep(tc.delay)
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			client := &http.Client{Timeout: 100 * time.Millisecond}
+			err := NewWebhook(srv.URL, client).Send(ctx, testEvents)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Send(): %v, wantErr %v", err, tc.wantErr)
+			}
+			if !cmp.Equal(got, testEvents) {
+				t.Errorf("Received %v, want %v", got, testEvents)
+			}
+		})
+	}
+}
+
+func TestFile(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "events")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.json")
+
+	// Events are appended across reopening the file.
+	var want []*sequencer.Event
+	for i := 0; i < 2; i++ {
+		f, err := NewFile(path)
+		if err != nil {
+			t.Fatalf("NewFile(): %v", err)
+		}
+		if err := f.Send(ctx, testEvents); err != nil {
+			t.Fatalf("Send(): %v", err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("Close(): %v", err)
+		}
+		want = append(want, testEvents...)
+	}
+
+	r, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open(): %v", err)
+	}
+	defer r.Close()
+	var got []*sequencer.Event
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		var e sequencer.Event
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", s.Bytes(), err)
+		}
+		got = append(got, &e)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scan(): %v", err)
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("File contains %v, want %v", got, want)
+	}
+}
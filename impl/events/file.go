@@ -0,0 +1,50 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/google/keytransparency/core/sequencer"
+	"github.com/google/keytransparency/impl/jsonlines"
+)
+
+// File appends events to a local file, one JSON object per line.
+type File struct {
+	w *jsonlines.Writer
+}
+
+// NewFile opens path for appending, creating it if necessary.
+func NewFile(path string) (*File, error) {
+	w, err := jsonlines.Open(path, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &File{w: w}, nil
+}
+
+// Send appends events to the file and syncs it to disk.
+func (f *File) Send(ctx context.Context, events []*sequencer.Event) error {
+	values := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		values = append(values, e)
+	}
+	return f.w.Append(values...)
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	return f.w.Close()
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events implements sinks that deliver sequencer events.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/google/keytransparency/core/sequencer"
+)
+
+// DefaultWebhookTimeout bounds each POST made by a Webhook created without
+// its own client. Events are delivered inline with publishing revisions, so an
+// unresponsive endpoint must not block the sequencer indefinitely.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// Webhook POSTs events to a URL as a JSON array.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook returns a sink that POSTs events to url using client.
+// A client with DefaultWebhookTimeout is used if client is nil.
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	return &Webhook{url: url, client: client}
+}
+
+// Send POSTs events and returns an error unless the response status is 2xx.
+func (w *Webhook) Send(ctx context.Context, events []*sequencer.Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused.
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("POST %v: %v", w.url, resp.Status)
+	}
+	return nil
+}
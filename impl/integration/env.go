@@ -16,6 +16,9 @@ package integration
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"database/sql"
 	"encoding/pem"
 	"fmt"
@@ -33,7 +36,6 @@ import (
 	"github.com/google/keytransparency/core/client"
 	"github.com/google/keytransparency/core/integration"
 	"github.com/google/keytransparency/core/keyserver"
-	"github.com/google/keytransparency/core/mutator/entry"
 	"github.com/google/keytransparency/core/sequencer"
 	"github.com/google/keytransparency/impl/authentication"
 	"github.com/google/keytransparency/impl/authorization"
@@ -46,11 +48,14 @@ import (
 
 	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
 	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
+	authzpb "github.com/google/keytransparency/impl/authorization/authz_go_proto"
+	tcrypto "github.com/google/trillian/crypto"
 	ttest "github.com/google/trillian/testonly/integration"
 
-	_ "github.com/google/trillian/merkle/coniks"  // Register hasher
-	_ "github.com/google/trillian/merkle/rfc6962" // Register hasher
-	_ "github.com/mattn/go-sqlite3"               // Use sqlite database for testing.
+	_ "github.com/google/keytransparency/core/mutator/entry" // Register mutator
+	_ "github.com/google/trillian/merkle/coniks"             // Register hasher
+	_ "github.com/google/trillian/merkle/rfc6962"            // Register hasher
+	_ "github.com/mattn/go-sqlite3"                          // Use sqlite database for testing.
 )
 
 var (
@@ -161,7 +166,16 @@ func NewEnv(ctx context.Context) (*Env, error) {
 	glog.V(5).Infof("Directory: %# v", pretty.Formatter(directoryPB))
 
 	// Common data structures.
-	authz := &authorization.AuthzPolicy{}
+	authz := &authorization.AuthzPolicy{
+		Policy: &authzpb.AuthorizationPolicy{
+			Roles: map[string]*authzpb.AuthorizationPolicy_Role{
+				"admin": {Principals: []string{integration.DirectoryAdmin}},
+			},
+			ResourceToRoleLabels: map[string]*authzpb.AuthorizationPolicy_RoleLabels{
+				"directories/" + directoryID: {Labels: []string{"admin"}},
+			},
+		},
+	}
 
 	lis, cc, err := Listen()
 	if err != nil {
@@ -171,28 +185,48 @@ func NewEnv(ctx context.Context) (*Env, error) {
 	gsvr := grpc.NewServer(
 		grpc.UnaryInterceptor(
 			authorization.UnaryServerInterceptor(map[string]authorization.AuthPair{
-				"/google.keytransparency.v1.KeyTransparency/UpdateEntry": {
+				"/google.keytransparency.v1.KeyTransparency/QueueEntryUpdate": {
 					AuthnFunc: authentication.FakeAuthFunc,
 					AuthzFunc: authz.Authorize,
 				},
-			}),
+				"/google.keytransparency.v1.KeyTransparency/BatchQueueUserUpdate": {
+					AuthnFunc: authentication.FakeAuthFunc,
+					AuthzFunc: authz.Authorize,
+				},
+				"/google.keytransparency.v1.KeyTransparency/ListUserRejections": {
+					AuthnFunc: authentication.FakeAuthFunc,
+					AuthzFunc: authz.Authorize,
+				},
+			}, nil),
 		),
 	)
 
-	pb.RegisterKeyTransparencyServer(gsvr, keyserver.New(
-		logEnv.Log, mapEnv.Map,
-		entry.MutateFn, directoryStorage,
-		mutations, mutations,
+	ksvr := keyserver.New(
+		logEnv.Log, mapEnv.Map, directoryStorage,
+		mutations, mutations, mutations,
 		monitoring.InertMetricFactory{},
-	))
+	)
+	receiptKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("env: GenerateKey(): %v", err)
+	}
+	ksvr.ReceiptSigner = tcrypto.NewSHA256Signer(receiptKey)
+	ksvr.ReceiptDeadline = time.Minute
+	if directoryPB.ReceiptKey, err = der.ToPublicProto(receiptKey.Public()); err != nil {
+		return nil, fmt.Errorf("env: ToPublicProto(): %v", err)
+	}
+	pb.RegisterKeyTransparencyServer(gsvr, ksvr)
 
-	spb.RegisterKeyTransparencySequencerServer(gsvr, sequencer.NewServer(
+	ssvr := sequencer.NewServer(
 		directoryStorage,
-		logEnv.Log, mapEnv.Map,
-		mutations, mutations,
+		logEnv.Log, mapEnv.Map, mapEnv.Admin,
+		mutations, mutations, mutations,
 		spb.NewKeyTransparencySequencerClient(cc),
 		monitoring.InertMetricFactory{},
-	))
+	)
+	spb.RegisterKeyTransparencySequencerServer(gsvr, ssvr)
+	adminSvr.Rebuilder = ssvr
+	pb.RegisterKeyTransparencyAdminServer(gsvr, adminSvr)
 
 	go gsvr.Serve(lis)
 
@@ -208,6 +242,7 @@ func NewEnv(ctx context.Context) (*Env, error) {
 			Client:    client,
 			Cli:       pb.NewKeyTransparencyClient(cc),
 			Sequencer: spb.NewKeyTransparencySequencerClient(cc),
+			Admin:     pb.NewKeyTransparencyAdminClient(cc),
 			Directory: directoryPB,
 			Timeout:   timeout,
 			CallOpts: func(userID string) []grpc.CallOption {
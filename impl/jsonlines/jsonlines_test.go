@@ -0,0 +1,62 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonlines
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonlines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "values.jsonl")
+
+	type value struct {
+		N int
+	}
+	for _, values := range [][]interface{}{
+		{value{1}, value{2}},
+		{},
+		{value{3}},
+	} {
+		w, err := Open(path, 0600)
+		if err != nil {
+			t.Fatalf("Open(): %v", err)
+		}
+		if err := w.Append(values...); err != nil {
+			t.Fatalf("Append(): %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close(): %v", err)
+		}
+	}
+	if err := (&Writer{}).Append(func() {}); err == nil {
+		t.Errorf("Append(func): nil error, want error for a value that cannot be encoded")
+	}
+
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\"N\":1}\n{\"N\":2}\n{\"N\":3}\n"; string(got) != want {
+		t.Errorf("file contains %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit limits the rate of writes by each principal and to each
+// user with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian/monitoring"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/keytransparency/impl/authentication"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+)
+
+const (
+	directoryIDLabel = "directoryid"
+	keyLabel         = "key"
+
+	principalKey = "principal"
+	userKey      = "user"
+)
+
+// pruneInterval is how often buckets that have refilled are forgotten.
+var pruneInterval = time.Minute
+
+var (
+	initMetrics sync.Once
+	allowed     monitoring.Counter
+	rejected    monitoring.Counter
+	buckets     monitoring.Gauge
+)
+
+func createMetrics(mf monitoring.MetricFactory) {
+	allowed = mf.NewCounter(
+		"ratelimit_allowed",
+		"Number of writes allowed by the rate limiter",
+		directoryIDLabel)
+	rejected = mf.NewCounter(
+		"ratelimit_rejected",
+		"Number of writes rejected by the rate limiter, by the key whose limit was exceeded",
+		directoryIDLabel, keyLabel)
+	buckets = mf.NewGauge(
+		"ratelimit_buckets",
+		"Number of principals or users with a partially used token bucket",
+		directoryIDLabel, keyLabel)
+}
+
+// Limit is a token bucket that holds up to Burst writes and refills at Rate
+// writes per second. A zero Rate disables the limit.
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Limits are the limits of a directory.
+type Limits struct {
+	// Principal limits the writes of each authenticated principal.
+	Principal Limit `json:"principal"`
+	// User limits the writes to each user.
+	User Limit `json:"user"`
+}
+
+// Config holds the limits of every directory.
+type Config struct {
+	// Default applies to directories that are not in Directories.
+	Default     Limits            `json:"default"`
+	Directories map[string]Limits `json:"directories"`
+}
+
+// ReadConfig reads a Config in JSON format from path.
+func ReadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("ratelimit: parsing %v: %v", path, err)
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("ratelimit: %v: %v", path, err)
+	}
+	return &c, nil
+}
+
+func (c *Config) validate() error {
+	check := func(name string, l Limits) error {
+		for _, lim := range []Limit{l.Principal, l.User} {
+			if lim.Rate < 0 || (lim.Rate > 0 && lim.Burst < 1) {
+				return fmt.Errorf("%v: invalid limit %+v, want a burst of at least 1", name, lim)
+			}
+		}
+		return nil
+	}
+	if err := check("default", c.Default); err != nil {
+		return err
+	}
+	for id, l := range c.Directories {
+		if err := check(id, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) limits(directoryID string) Limits {
+	if l, ok := c.Directories[directoryID]; ok {
+		return l
+	}
+	return c.Default
+}
+
+// bucketKey identifies the token bucket of a principal or user in a directory.
+type bucketKey struct {
+	directoryID string
+	key         string // principalKey or userKey.
+	id          string
+}
+
+// Limiter holds a token bucket for each principal and user that has written
+// recently.
+type Limiter struct {
+	config *Config
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*rate.Limiter
+	lastPrune time.Time
+}
+
+// New returns a Limiter that enforces config.
+func New(config *Config, mf monitoring.MetricFactory) *Limiter {
+	initMetrics.Do(func() { createMetrics(mf) })
+	return &Limiter{
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[bucketKey]*rate.Limiter),
+	}
+}
+
+// UnaryServerInterceptor returns a unary server interceptor that rate limits
+// QueueEntryUpdate and BatchQueueUserUpdate. It must run after authentication,
+// so that the principal's authentication.SecurityContext is in the context.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var directoryID string
+		var userIDs []string
+		switch t := req.(type) {
+		case *pb.UpdateEntryRequest:
+			directoryID, userIDs = t.GetDirectoryId(), []string{t.GetEntryUpdate().GetUserId()}
+		case *pb.BatchQueueUserUpdateRequest:
+			directoryID = t.GetDirectoryId()
+			for _, u := range t.GetUpdates() {
+				userIDs = append(userIDs, u.GetUserId())
+			}
+		default:
+			return handler(ctx, req)
+		}
+		var principal string
+		if sctx, ok := authentication.FromContext(ctx); ok {
+			principal = sctx.Email
+		}
+		if err := l.Allow(directoryID, principal, userIDs); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Allow takes a token from the bucket of principal for each of userIDs, and
+// one from the bucket of each user. An empty principal is not limited. If any
+// bucket is empty, no tokens are taken, and Allow returns ResourceExhausted
+// with a RetryInfo detail that says when the write will be allowed.
+func (l *Limiter) Allow(directoryID, principal string, userIDs []string) error {
+	limits := l.config.limits(directoryID)
+	perUser := make(map[string]int)
+	for _, u := range userIDs {
+		perUser[u]++
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.maybePrune(now)
+
+	var reservations []*rate.Reservation
+	cancel := func() {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+	}
+	reserve := func(key, id string, limit Limit, n int) error {
+		if limit.Rate == 0 || (key == principalKey && id == "") {
+			return nil
+		}
+		r := l.bucket(bucketKey{directoryID, key, id}, limit).ReserveN(now, n)
+		if !r.OK() {
+			cancel()
+			rejected.Inc(directoryID, key)
+			return status.Errorf(codes.ResourceExhausted,
+				"%v writes for %v %v exceed the burst limit of %v", n, key, id, limit.Burst)
+		}
+		reservations = append(reservations, r)
+		if delay := r.DelayFrom(now); delay > 0 {
+			cancel()
+			rejected.Inc(directoryID, key)
+			return retryError(status.Newf(codes.ResourceExhausted,
+				"Too many writes for %v %v in directory %v, please retry later", key, id, directoryID), delay)
+		}
+		return nil
+	}
+
+	if err := reserve(principalKey, principal, limits.Principal, len(userIDs)); err != nil {
+		return err
+	}
+	for u, n := range perUser {
+		if err := reserve(userKey, u, limits.User, n); err != nil {
+			return err
+		}
+	}
+	allowed.Inc(directoryID)
+	return nil
+}
+
+// bucket returns the token bucket for k, creating a full one if needed.
+// l.mu must be held.
+func (l *Limiter) bucket(k bucketKey, limit Limit) *rate.Limiter {
+	b, ok := l.buckets[k]
+	if !ok {
+		b = rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+		l.buckets[k] = b
+		buckets.Add(1, k.directoryID, k.key)
+	}
+	return b
+}
+
+// maybePrune forgets buckets that are full again, since they behave like new
+// ones. l.mu must be held.
+func (l *Limiter) maybePrune(now time.Time) {
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+	l.lastPrune = now
+	for k, b := range l.buckets {
+		// A reservation of a full burst only succeeds without delay if the
+		// bucket is full. Cancelling it restores the tokens.
+		r := b.ReserveN(now, b.Burst())
+		full := r.OK() && r.DelayFrom(now) == 0
+		r.CancelAt(now)
+		if full {
+			delete(l.buckets, k)
+			buckets.Add(-1, k.directoryID, k.key)
+		}
+	}
+}
+
+// retryError returns st with a RetryInfo detail of delay.
+func retryError(st *status.Status, delay time.Duration) error {
+	stWithInfo, err := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: ptypes.DurationProto(delay),
+	})
+	if err != nil {
+		glog.Errorf("WithDetails(): %v", err)
+		return st.Err()
+	}
+	return stWithInfo.Err()
+}
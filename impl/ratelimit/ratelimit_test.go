@@ -0,0 +1,214 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian/monitoring"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/keytransparency/impl/authentication"
+
+	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+)
+
+const (
+	alice = "alice@example.com"
+	bob   = "bob@example.com"
+)
+
+var testConfig = &Config{
+	Default: Limits{
+		Principal: Limit{Rate: 1, Burst: 3},
+		User:      Limit{Rate: 0.5, Burst: 2},
+	},
+	Directories: map[string]Limits{
+		"unlimited": {},
+	},
+}
+
+// retryDelay returns the delay of the RetryInfo detail of err.
+func retryDelay(t *testing.T, err error) time.Duration {
+	t.Helper()
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			d, err := ptypes.Duration(info.GetRetryDelay())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return d
+		}
+	}
+	return 0
+}
+
+func TestAllow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := New(testConfig, monitoring.InertMetricFactory{})
+	l.now = func() time.Time { return now }
+
+	for _, tc := range []struct {
+		desc        string
+		advance     time.Duration
+		directoryID string
+		principal   string
+		userIDs     []string
+		wantCode    codes.Code
+		wantRetry   time.Duration
+	}{
+		{desc: "first", principal: alice, userIDs: []string{alice}},
+		{desc: "user burst", principal: alice, userIDs: []string{alice}},
+		{desc: "user limit", principal: alice, userIDs: []string{alice},
+			wantCode: codes.ResourceExhausted, wantRetry: 2 * time.Second},
+		{desc: "other principal, same user", principal: bob, userIDs: []string{alice},
+			wantCode: codes.ResourceExhausted, wantRetry: 2 * time.Second},
+		{desc: "principal burst", principal: alice, userIDs: []string{bob}},
+		{desc: "principal limit", principal: alice, userIDs: []string{"carol@example.com"},
+			wantCode: codes.ResourceExhausted, wantRetry: time.Second},
+		{desc: "refilled", advance: 2 * time.Second, principal: alice, userIDs: []string{alice}},
+		{desc: "batch exceeds burst", principal: bob, userIDs: []string{"a", "b", "c", "d"},
+			wantCode: codes.ResourceExhausted},
+		{desc: "batch", principal: bob, userIDs: []string{"a", "b", "c"}},
+		{desc: "unauthenticated", userIDs: []string{"d"}},
+		{desc: "unlimited directory", directoryID: "unlimited", principal: bob, userIDs: []string{"a", "a", "a"}},
+	} {
+		now = now.Add(tc.advance)
+		err := l.Allow(tc.directoryID, tc.principal, tc.userIDs)
+		if got := status.Code(err); got != tc.wantCode {
+			t.Errorf("%v: Allow(): %v, want %v", tc.desc, err, tc.wantCode)
+		}
+		if got := retryDelay(t, err); got != tc.wantRetry {
+			t.Errorf("%v: retry delay %v, want %v", tc.desc, got, tc.wantRetry)
+		}
+	}
+}
+
+func TestRejectedWriteTakesNoTokens(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := New(testConfig, monitoring.InertMetricFactory{})
+	l.now = func() time.Time { return now }
+
+	// Use up alice's user bucket.
+	for i := 0; i < 2; i++ {
+		if err := l.Allow("", bob, []string{alice}); err != nil {
+			t.Fatalf("Allow(): %v", err)
+		}
+	}
+	// Rejected by alice's user limit, after bob's principal bucket was checked.
+	if err := l.Allow("", bob, []string{alice}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Allow(): %v, want %v", err, codes.ResourceExhausted)
+	}
+	// bob still has one token left.
+	if err := l.Allow("", bob, []string{"carol@example.com"}); err != nil {
+		t.Errorf("Allow(): %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := New(testConfig, monitoring.InertMetricFactory{})
+	l.now = func() time.Time { return now }
+	before := buckets.Value("", userKey)
+
+	for _, u := range []string{alice, bob} {
+		if err := l.Allow("", alice, []string{u}); err != nil {
+			t.Fatalf("Allow(): %v", err)
+		}
+	}
+	if got, want := len(l.buckets), 3; got != want {
+		t.Errorf("%v buckets, want %v", got, want)
+	}
+	if got, want := buckets.Value("", userKey)-before, 2.0; got != want {
+		t.Errorf("user buckets gauge: +%v, want +%v", got, want)
+	}
+
+	// All buckets have refilled after a pruneInterval.
+	now = now.Add(pruneInterval)
+	if err := l.Allow("", "", nil); err != nil {
+		t.Fatalf("Allow(): %v", err)
+	}
+	if got := len(l.buckets); got != 0 {
+		t.Errorf("%v buckets after pruning, want 0", got)
+	}
+	if got := buckets.Value("", userKey) - before; got != 0 {
+		t.Errorf("user buckets gauge: +%v after pruning, want +0", got)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	l := New(&Config{Default: Limits{Principal: Limit{Rate: 1, Burst: 1}}}, monitoring.InertMetricFactory{})
+	interceptor := l.UnaryServerInterceptor()
+	ctx := authentication.NewContext(context.Background(), &authentication.SecurityContext{Email: alice})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	info := &grpc.UnaryServerInfo{}
+
+	for _, tc := range []struct {
+		desc     string
+		req      interface{}
+		wantCode codes.Code
+	}{
+		{desc: "read", req: &pb.GetUserRequest{UserId: alice}},
+		{desc: "write", req: &pb.UpdateEntryRequest{EntryUpdate: &pb.EntryUpdate{UserId: alice}}},
+		{desc: "batch", req: &pb.BatchQueueUserUpdateRequest{Updates: []*pb.EntryUpdate{{UserId: bob}}},
+			wantCode: codes.ResourceExhausted},
+		{desc: "read after limit", req: &pb.GetUserRequest{UserId: alice}},
+	} {
+		if _, err := interceptor(ctx, tc.req, info, handler); status.Code(err) != tc.wantCode {
+			t.Errorf("%v: interceptor(): %v, want %v", tc.desc, err, tc.wantCode)
+		}
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ratelimit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "limits.json")
+
+	for _, tc := range []struct {
+		desc    string
+		json    string
+		wantErr bool
+	}{
+		{desc: "empty", json: `{}`},
+		{desc: "limits", json: `{"default": {"principal": {"rate": 10, "burst": 20}},
+		                         "directories": {"default": {"user": {"rate": 0.1, "burst": 1}}}}`},
+		{desc: "no burst", json: `{"default": {"user": {"rate": 1}}}`, wantErr: true},
+		{desc: "negative rate", json: `{"directories": {"d": {"principal": {"rate": -1, "burst": 1}}}}`, wantErr: true},
+		{desc: "malformed", json: `{"default": `, wantErr: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := ioutil.WriteFile(path, []byte(tc.json), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := ReadConfig(path)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("ReadConfig(): %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auditlog implements the authorization.AuditSink interface with an
+// SQL table.
+package auditlog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/keytransparency/impl/authorization"
+)
+
+const (
+	createSQL = `
+CREATE TABLE IF NOT EXISTS AuditLog(
+  TimeNanos             BIGINT NOT NULL,
+  Principal             TEXT NOT NULL,
+  Method                TEXT NOT NULL,
+  DirectoryID           TEXT NOT NULL,
+  UserIDs               TEXT NOT NULL,
+  Decision              VARCHAR(10) NOT NULL,
+  PolicyRule            TEXT NOT NULL,
+  Error                 TEXT NOT NULL
+);`
+	insertSQL = `INSERT INTO AuditLog
+(TimeNanos, Principal, Method, DirectoryID, UserIDs, Decision, PolicyRule, Error)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+)
+
+// Table appends audit records to the AuditLog table. Rows are never updated
+// or deleted.
+type Table struct {
+	db *sql.DB
+}
+
+// New creates the AuditLog table if it does not exist.
+func New(db *sql.DB) (*Table, error) {
+	if _, err := db.Exec(createSQL); err != nil {
+		return nil, fmt.Errorf("failed to create audit log table: %v", err)
+	}
+	return &Table{db: db}, nil
+}
+
+// Write inserts r into the table.
+func (t *Table) Write(ctx context.Context, r *authorization.AuditRecord) error {
+	return t.WriteBatch(ctx, []*authorization.AuditRecord{r})
+}
+
+// WriteBatch inserts records into the table in a single transaction. User IDs
+// are stored as a JSON array. Apart from Decision, every column is TEXT
+// because callers control the values, and a record must not be lost because
+// a principal or directory ID is unusually long.
+func (t *Table) WriteBatch(ctx context.Context, records []*authorization.AuditRecord) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := insert(ctx, tx, records); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return fmt.Errorf("%v, and rollback failed: %v", err, rerr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func insert(ctx context.Context, tx *sql.Tx, records []*authorization.AuditRecord) error {
+	stmt, err := tx.PrepareContext(ctx, insertSQL)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, r := range records {
+		ids := r.UserIDs
+		if ids == nil {
+			ids = []string{}
+		}
+		userIDs, err := json.Marshal(ids)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx,
+			r.Time.UnixNano(), r.Principal, r.Method, r.DirectoryID, string(userIDs),
+			r.Decision, r.Rule, r.Error); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/keytransparency/impl/authorization"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestWrite(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open(): %v", err)
+	}
+	defer db.Close()
+	table, err := New(db)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	// New must tolerate an existing table.
+	if _, err := New(db); err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+
+	for _, r := range []*authorization.AuditRecord{
+		{
+			Time:        time.Unix(1, 5),
+			Principal:   "alice@example.com",
+			Method:      "/google.keytransparency.v1.KeyTransparency/BatchQueueUserUpdate",
+			DirectoryID: "default",
+			UserIDs:     []string{"alice@example.com", "bob@example.com"},
+			Decision:    authorization.Deny,
+			Rule:        `no matching role on directories/default`,
+			Error:       "alice@example.com is not authorized to act on directories/default",
+		},
+		{
+			Time:     time.Unix(2, 0),
+			Method:   "/google.keytransparency.v1.KeyTransparency/GetUser",
+			Decision: authorization.Allow,
+			Rule:     "public directory",
+		},
+	} {
+		if err := table.Write(ctx, r); err != nil {
+			t.Fatalf("Write(): %v", err)
+		}
+	}
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT TimeNanos, Principal, UserIDs, Decision, PolicyRule FROM AuditLog ORDER BY TimeNanos;`)
+	if err != nil {
+		t.Fatalf("Query(): %v", err)
+	}
+	defer rows.Close()
+	type row struct {
+		nanos                              int64
+		principal, userIDs, decision, rule string
+	}
+	var got []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.nanos, &r.principal, &r.userIDs, &r.decision, &r.rule); err != nil {
+			t.Fatalf("Scan(): %v", err)
+		}
+		got = append(got, r)
+	}
+	want := []row{
+		{1000000005, "alice@example.com", `["alice@example.com","bob@example.com"]`, "deny",
+			"no matching role on directories/default"},
+		{2000000000, "", "[]", "allow", "public directory"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v rows, want %v", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %v: %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -41,29 +41,48 @@ CREATE TABLE IF NOT EXISTS Directories(
   VRFPrivateKey         MEDIUMBLOB NOT NULL,
   MinInterval           BIGINT NOT NULL,
   MaxInterval           BIGINT NOT NULL,
+  Mutator               VARCHAR(40) NOT NULL DEFAULT '',
+  Paused                INTEGER NOT NULL DEFAULT 0,
+  PausedReason          VARCHAR(255) NOT NULL DEFAULT '',
+  Private               INTEGER NOT NULL DEFAULT 0,
+  ReceiptKey            MEDIUMBLOB,
   Deleted               INTEGER,
   DeleteTimeSeconds      BIGINT,
   PRIMARY KEY(DirectoryId)
 );`
 	writeSQL = `INSERT INTO Directories
-(DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Deleted, DeleteTimeSeconds)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
+(DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Mutator, Paused, PausedReason, Private, ReceiptKey, Deleted, DeleteTimeSeconds)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
 	readSQL = `
-SELECT DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Deleted, DeleteTimeSeconds
+SELECT DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Mutator, Paused, PausedReason, Private, ReceiptKey, Deleted, DeleteTimeSeconds
 FROM Directories WHERE DirectoryId = ? AND Deleted = 0;`
 	readDeletedSQL = `
-SELECT DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Deleted, DeleteTimeSeconds
+SELECT DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Mutator, Paused, PausedReason, Private, ReceiptKey, Deleted, DeleteTimeSeconds
 FROM Directories WHERE DirectoryId = ?;`
 	listSQL = `
-SELECT DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Deleted
+SELECT DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Mutator, Paused, PausedReason, Private, ReceiptKey, Deleted
 FROM Directories WHERE Deleted = 0;`
 	listDeletedSQL = `
-SELECT DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Deleted
+SELECT DirectoryId, Map, Log, VRFPublicKey, VRFPrivateKey, MinInterval, MaxInterval, Mutator, Paused, PausedReason, Private, ReceiptKey, Deleted
 FROM Directories;`
 	setDeletedSQL = `UPDATE Directories SET Deleted = ?, DeleteTimeSeconds = ? WHERE DirectoryId = ?`
+	setPausedSQL  = `UPDATE Directories SET Paused = ?, PausedReason = ? WHERE DirectoryId = ?`
+	existsSQL     = `SELECT COUNT(*) FROM Directories WHERE DirectoryId = ? AND Deleted = 0`
 	deleteSQL     = `DELETE FROM Directories WHERE DirectoryId = ?`
 )
 
+// columns lists the columns added to Directories after it was first created.
+// Tables created by older releases are migrated by adding them.
+var columns = []struct {
+	name, definition string
+}{
+	{name: "Mutator", definition: "VARCHAR(40) NOT NULL DEFAULT ''"},
+	{name: "Paused", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{name: "PausedReason", definition: "VARCHAR(255) NOT NULL DEFAULT ''"},
+	{name: "Private", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{name: "ReceiptKey", definition: "MEDIUMBLOB"},
+}
+
 type storage struct {
 	db *sql.DB
 }
@@ -85,6 +104,24 @@ func (s *storage) create() error {
 	if err != nil {
 		return fmt.Errorf("failed to create commitments tables: %v", err)
 	}
+	return s.migrate()
+}
+
+// migrate adds any columns missing from a Directories table created by an
+// older release. It is a no-op for tables that are already up to date.
+func (s *storage) migrate() error {
+	for _, c := range columns {
+		// Probing the column works on every supported engine, unlike
+		// ADD COLUMN IF NOT EXISTS or information_schema.
+		rows, err := s.db.Query(fmt.Sprintf("SELECT %s FROM Directories LIMIT 1", c.name))
+		if err == nil {
+			rows.Close()
+			continue
+		}
+		if _, err := s.db.Exec(fmt.Sprintf("ALTER TABLE Directories ADD COLUMN %s %s", c.name, c.definition)); err != nil {
+			return fmt.Errorf("failed to add column %v: %v", c.name, err)
+		}
+	}
 	return nil
 }
 
@@ -108,7 +145,7 @@ func (s *storage) List(ctx context.Context, showDeleted bool) ([]*directory.Dire
 	defer rows.Close()
 	ret := []*directory.Directory{}
 	for rows.Next() {
-		var pubkey, anyData, mapByte, logByte []byte
+		var pubkey, anyData, mapByte, logByte, receiptKey []byte
 		var logTree tpb.Tree
 		var mapTree tpb.Tree
 		d := &directory.Directory{}
@@ -117,11 +154,16 @@ func (s *storage) List(ctx context.Context, showDeleted bool) ([]*directory.Dire
 			&mapByte, &logByte,
 			&pubkey, &anyData,
 			&d.MinInterval, &d.MaxInterval,
+			&d.Mutator,
+			&d.Paused, &d.PausedReason,
+			&d.Private,
+			&receiptKey,
 			&d.Deleted); err != nil {
 			return nil, err
 		}
 		// Unwrap protos.
 		d.VRF = &keyspb.PublicKey{Der: pubkey}
+		d.ReceiptKey = publicKey(receiptKey)
 		d.VRFPriv, err = unwrapAnyProto(anyData)
 		if err != nil {
 			return nil, err
@@ -170,6 +212,10 @@ func (s *storage) Write(ctx context.Context, d *directory.Directory) error {
 		mapTree, logTree,
 		d.VRF.Der, anyData,
 		d.MinInterval.Nanoseconds(), d.MaxInterval.Nanoseconds(),
+		d.Mutator,
+		d.Paused, d.PausedReason,
+		d.Private,
+		d.ReceiptKey.GetDer(),
 		false,
 		// Store January 1, year 1, 00:00:00 UTC, the time.Time zero value.
 		// Store this as unix seconds till Jan 1 1970, a large negative number.
@@ -190,7 +236,7 @@ func (s *storage) Read(ctx context.Context, directoryID string, showDeleted bool
 	}
 	defer readStmt.Close()
 	d := &directory.Directory{}
-	var pubkey, anyData []byte
+	var pubkey, anyData, receiptKey []byte
 	var deletedUnix int64
 	var mapByte []byte
 	var logByte []byte
@@ -202,6 +248,10 @@ func (s *storage) Read(ctx context.Context, directoryID string, showDeleted bool
 		&mapByte, &logByte,
 		&pubkey, &anyData,
 		&d.MinInterval, &d.MaxInterval,
+		&d.Mutator,
+		&d.Paused, &d.PausedReason,
+		&d.Private,
+		&receiptKey,
 		&d.Deleted,
 		&deletedUnix,
 	); err == sql.ErrNoRows {
@@ -211,6 +261,7 @@ func (s *storage) Read(ctx context.Context, directoryID string, showDeleted bool
 	}
 	// Unwrap protos.
 	d.VRF = &keyspb.PublicKey{Der: pubkey}
+	d.ReceiptKey = publicKey(receiptKey)
 	d.VRFPriv, err = unwrapAnyProto(anyData)
 	d.DeletedTimestamp = time.Unix(deletedUnix, 0)
 	if err != nil {
@@ -230,6 +281,14 @@ func (s *storage) Read(ctx context.Context, directoryID string, showDeleted bool
 	return d, nil
 }
 
+// publicKey returns the public key stored as der, or nil if der is empty.
+func publicKey(der []byte) *keyspb.PublicKey {
+	if len(der) == 0 {
+		return nil
+	}
+	return &keyspb.PublicKey{Der: der}
+}
+
 // unwrapAnyProto returns the proto object seralized inside a serialized any.Any
 func unwrapAnyProto(anyData []byte) (proto.Message, error) {
 	var anyPB any.Any
@@ -248,6 +307,24 @@ func (s *storage) SetDelete(ctx context.Context, directoryID string, isDeleted b
 	return err
 }
 
+// SetPaused pauses or resumes sequencing of a directory.
+func (s *storage) SetPaused(ctx context.Context, directoryID string, isPaused bool, reason string) error {
+	if !isPaused {
+		reason = ""
+	}
+	// RowsAffected cannot detect a missing directory: MySQL only counts rows
+	// that actually changed, so pausing a paused directory would report 0.
+	var count int
+	if err := s.db.QueryRowContext(ctx, existsSQL, directoryID).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return status.Errorf(codes.NotFound, "directory %v not found", directoryID)
+	}
+	_, err := s.db.ExecContext(ctx, setPausedSQL, isPaused, reason, directoryID)
+	return err
+}
+
 // Delete permanently deletes a directory.
 func (s *storage) Delete(ctx context.Context, directoryID string) error {
 	_, err := s.db.ExecContext(ctx, deleteSQL, directoryID)
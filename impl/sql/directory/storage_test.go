@@ -17,6 +17,7 @@ package directory
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
@@ -81,6 +82,9 @@ func TestList(t *testing.T) {
 					VRFPriv:     &keyspb.PrivateKey{Der: []byte("privkeybytes")},
 					MinInterval: 5 * time.Hour,
 					MaxInterval: 500 * time.Hour,
+					Mutator:     "entry",
+					Private:     true,
+					ReceiptKey:  &keyspb.PublicKey{Der: []byte("receiptkeybytes")},
 				},
 			},
 		},
@@ -283,3 +287,107 @@ func TestDelete(t *testing.T) {
 		}
 	}
 }
+
+func TestSetPaused(t *testing.T) {
+	ctx := context.Background()
+	s, closeF := newStorage(t)
+	defer closeF()
+	d := &directory.Directory{
+		DirectoryID: "test",
+		Map:         &tpb.Tree{TreeId: 1},
+		Log:         &tpb.Tree{TreeId: 2},
+		VRF:         &keyspb.PublicKey{Der: []byte("pubkeybytes")},
+		VRFPriv:     &keyspb.PrivateKey{Der: []byte("privkeybytes")},
+	}
+	if err := s.Write(ctx, d); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+	deleted := *d
+	deleted.DirectoryID = "deleted"
+	if err := s.Write(ctx, &deleted); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+	if err := s.SetDelete(ctx, deleted.DirectoryID, true); err != nil {
+		t.Fatalf("SetDelete(): %v", err)
+	}
+	for _, tc := range []struct {
+		desc        string
+		directoryID string
+		paused      bool
+		reason      string
+		wantReason  string
+		wantCode    codes.Code
+	}{
+		{desc: "pause", directoryID: "test", paused: true, reason: "tree maintenance", wantReason: "tree maintenance"},
+		{desc: "resume", directoryID: "test", reason: "ignored"},
+		{desc: "resume again", directoryID: "test"},
+		{desc: "not found", directoryID: "unknown", paused: true, wantCode: codes.NotFound},
+		{desc: "deleted", directoryID: "deleted", paused: true, wantCode: codes.NotFound},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := s.SetPaused(ctx, tc.directoryID, tc.paused, tc.reason)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("SetPaused(): %v, want %v", err, want)
+			}
+			if err != nil {
+				return
+			}
+			got, err := s.Read(ctx, tc.directoryID, false)
+			if err != nil {
+				t.Fatalf("Read(): %v", err)
+			}
+			if got.Paused != tc.paused || got.PausedReason != tc.wantReason {
+				t.Errorf("Read(): Paused: %v, PausedReason: %q, want %v, %q",
+					got.Paused, got.PausedReason, tc.paused, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open(): %v", err)
+	}
+	defer db.Close()
+	// The Directories table as created by the first release.
+	if _, err := db.Exec(`
+CREATE TABLE Directories(
+  DirectoryId           VARCHAR(40) NOT NULL,
+  Map                   BLOB NOT NULL,
+  Log                   BLOB NOT NULL,
+  VRFPublicKey          MEDIUMBLOB NOT NULL,
+  VRFPrivateKey         MEDIUMBLOB NOT NULL,
+  MinInterval           BIGINT NOT NULL,
+  MaxInterval           BIGINT NOT NULL,
+  Deleted               INTEGER,
+  DeleteTimeSeconds      BIGINT,
+  PRIMARY KEY(DirectoryId)
+);`); err != nil {
+		t.Fatalf("create old table: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO Directories VALUES ('old', '', '', '', '', 1, 2, 0, 0);`); err != nil {
+		t.Fatalf("insert old row: %v", err)
+	}
+
+	// Migrating twice must be a no-op the second time.
+	for i := 0; i < 2; i++ {
+		if _, err := NewStorage(db); err != nil {
+			t.Fatalf("NewStorage() #%v: %v", i, err)
+		}
+	}
+	for _, c := range columns {
+		var v interface{}
+		if err := db.QueryRow(fmt.Sprintf("SELECT %s FROM Directories WHERE DirectoryId = 'old'", c.name)).Scan(&v); err != nil {
+			t.Errorf("SELECT %s: %v", c.name, err)
+		}
+	}
+	// Existing directories must stay public.
+	var private bool
+	if err := db.QueryRow("SELECT Private FROM Directories WHERE DirectoryId = 'old'").Scan(&private); err != nil {
+		t.Fatalf("SELECT Private: %v", err)
+	}
+	if private {
+		t.Errorf("Private: true, want false")
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package election implements election2.Factory with leases stored in an SQL
+// table. It allows several sequencers that share a database to elect masters
+// without running etcd.
+//
+// Lease expiry is compared against the local clock of each instance. To
+// tolerate clocks that disagree by up to a maximum skew, a master stops acting
+// as master that long before its lease expires, and other instances only take
+// the lease once it expired that long ago.
+package election
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/util/election2"
+)
+
+const (
+	createSQL = `
+CREATE TABLE IF NOT EXISTS Leases(
+  ResourceId VARCHAR(255) NOT NULL,
+  Holder     VARCHAR(255) NOT NULL,
+  Expiry     BIGINT NOT NULL,
+  PRIMARY KEY(ResourceId)
+);`
+	// acquireSQL takes the lease if it is expired or already held by us.
+	acquireSQL = `UPDATE Leases SET Holder = ?, Expiry = ?
+WHERE ResourceId = ? AND (Holder = ? OR Expiry < ?);`
+	// renewSQL extends the lease only if it is still held by us.
+	renewSQL = `UPDATE Leases SET Expiry = ?
+WHERE ResourceId = ? AND Holder = ? AND Expiry >= ?;`
+	insertSQL  = `INSERT INTO Leases (ResourceId, Holder, Expiry) VALUES (?, ?, ?);`
+	existsSQL  = `SELECT COUNT(*) FROM Leases WHERE ResourceId = ?;`
+	releaseSQL = `UPDATE Leases SET Holder = '', Expiry = 0 WHERE ResourceId = ? AND Holder = ?;`
+)
+
+// Factory creates Elections that hold leases in an SQL table.
+type Factory struct {
+	db         *sql.DB
+	instanceID string
+	ttl        time.Duration
+	maxSkew    time.Duration
+}
+
+// NewFactory returns a Factory for instanceID that acquires leases of
+// duration ttl in db. Leases are renewed and retried every ttl/3. The clocks
+// of the instances must differ by less than maxSkew, which must be less than
+// ttl/3.
+func NewFactory(db *sql.DB, instanceID string, ttl, maxSkew time.Duration) (*Factory, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("election: lease duration must be positive, got %v", ttl)
+	}
+	if maxSkew < 0 || maxSkew >= ttl/3 {
+		return nil, fmt.Errorf("election: clock skew must be in [0, %v), got %v", ttl/3, maxSkew)
+	}
+	if _, err := db.Exec(createSQL); err != nil {
+		return nil, fmt.Errorf("election: failed to create leases table: %v", err)
+	}
+	return &Factory{db: db, instanceID: instanceID, ttl: ttl, maxSkew: maxSkew}, nil
+}
+
+// NewElection returns an Election for resourceID.
+func (f *Factory) NewElection(ctx context.Context, resourceID string) (election2.Election, error) {
+	return &Election{
+		db:         f.db,
+		resourceID: resourceID,
+		instanceID: f.instanceID,
+		ttl:        f.ttl,
+		maxSkew:    f.maxSkew,
+		interval:   f.ttl / 3,
+	}, nil
+}
+
+// term is a period of mastership.
+type term struct {
+	stop chan struct{} // Closed to stop renewing the lease.
+	done chan struct{} // Closed when the lease is no longer held.
+}
+
+// Election is an election2.Election for a single resource.
+type Election struct {
+	db         *sql.DB
+	resourceID string
+	instanceID string
+	ttl        time.Duration
+	maxSkew    time.Duration
+	interval   time.Duration
+
+	mu   sync.Mutex
+	term *term
+}
+
+// Await blocks until the instance holds the lease for the resource.
+func (e *Election) Await(ctx context.Context) error {
+	if e.isMaster() {
+		return nil
+	}
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		expiry, ok, err := e.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			t := &term{stop: make(chan struct{}), done: make(chan struct{})}
+			e.mu.Lock()
+			e.term = t
+			e.mu.Unlock()
+			go e.renew(t, expiry)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// isMaster returns true if the current term has not ended.
+func (e *Election) isMaster() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.term == nil {
+		return false
+	}
+	select {
+	case <-e.term.done:
+		e.term = nil // The lease was lost.
+		return false
+	default:
+		return true
+	}
+}
+
+// acquire attempts to take the lease once and returns its expiry.
+func (e *Election) acquire(ctx context.Context) (time.Time, bool, error) {
+	now := time.Now()
+	expiry := now.Add(e.ttl)
+	// The previous holder may still be acting as master if its clock is
+	// behind ours.
+	result, err := e.db.ExecContext(ctx, acquireSQL,
+		e.instanceID, expiry.UnixNano(), e.resourceID, e.instanceID, now.Add(-e.maxSkew).UnixNano())
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return time.Time{}, false, err
+	} else if n == 1 {
+		return expiry, true, nil
+	}
+
+	// Create the lease row the first time the resource is seen.
+	var count int
+	if err := e.db.QueryRowContext(ctx, existsSQL, e.resourceID).Scan(&count); err != nil {
+		return time.Time{}, false, err
+	}
+	if count > 0 {
+		return time.Time{}, false, nil
+	}
+	if _, err := e.db.ExecContext(ctx, insertSQL, e.resourceID, e.instanceID, expiry.UnixNano()); err != nil {
+		// Another instance may have inserted the row first.
+		if qerr := e.db.QueryRowContext(ctx, existsSQL, e.resourceID).Scan(&count); qerr == nil && count > 0 {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+	return expiry, true, nil
+}
+
+// renew extends the lease until t.stop is closed or the lease is lost.
+// t.done is closed on return. The lease counts as lost maxSkew before it
+// expires, since another instance whose clock is ahead may take it then.
+func (e *Election) renew(t *term, expiry time.Time) {
+	defer close(t.done)
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-time.After(time.Until(expiry.Add(-e.maxSkew))):
+			glog.Warningf("%s: lease expired before it could be renewed", e.resourceID)
+			return
+		case <-ticker.C:
+		}
+		now := time.Now()
+		next := now.Add(e.ttl)
+		result, err := e.db.Exec(renewSQL, next.UnixNano(), e.resourceID, e.instanceID, now.Add(e.maxSkew).UnixNano())
+		if err != nil {
+			glog.Errorf("%s: failed to renew lease: %v", e.resourceID, err)
+			continue // Retry until the lease expires.
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			glog.Errorf("%s: failed to renew lease: %v", e.resourceID, err)
+			continue
+		}
+		if n != 1 {
+			glog.Warningf("%s: lease lost to another instance", e.resourceID)
+			return
+		}
+		expiry = next
+	}
+}
+
+// WithMastership returns a context that is canceled when the instance stops
+// holding the lease, or when ctx is canceled.
+func (e *Election) WithMastership(ctx context.Context) (context.Context, error) {
+	cctx, cancel := context.WithCancel(ctx)
+	e.mu.Lock()
+	t := e.term
+	e.mu.Unlock()
+	if t == nil {
+		cancel()
+		return cctx, nil
+	}
+	go func() {
+		defer cancel()
+		select {
+		case <-t.done:
+		case <-cctx.Done():
+		}
+	}()
+	return cctx, nil
+}
+
+// Resign releases the lease if the instance holds it.
+func (e *Election) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.term == nil {
+		return nil
+	}
+	close(e.term.stop)
+	<-e.term.done
+	e.term = nil
+	_, err := e.db.ExecContext(ctx, releaseSQL, e.resourceID, e.instanceID)
+	return err
+}
+
+// Close resigns. No other method should be called after Close.
+func (e *Election) Close(ctx context.Context) error {
+	// Release the lease even if ctx is already canceled, so that other
+	// instances do not have to wait for it to expire.
+	return e.Resign(context.Background())
+}
@@ -0,0 +1,236 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package election
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/util/election2/testonly"
+
+	tracker "github.com/google/keytransparency/core/sequencer/election"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newDBs opens n connection pools to the same sqlite database, one for each
+// simulated sequencer.
+func newDBs(t *testing.T, n int) (dbs []*sql.DB, done func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "election")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	path := filepath.Join(dir, "leases.db")
+	for i := 0; i < n; i++ {
+		db, err := sql.Open("sqlite3", path)
+		if err != nil {
+			t.Fatalf("sql.Open(): %v", err)
+		}
+		dbs = append(dbs, db)
+	}
+	return dbs, func() {
+		for _, db := range dbs {
+			db.Close()
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+func TestElection(t *testing.T) {
+	for _, nt := range testonly.Tests {
+		t.Run(nt.Name, func(t *testing.T) {
+			// Use a new database for each test so leases do not carry over.
+			dbs, done := newDBs(t, 1)
+			defer done()
+			f, err := NewFactory(dbs[0], "testID", time.Second, 100*time.Millisecond)
+			if err != nil {
+				t.Fatalf("NewFactory(): %v", err)
+			}
+			nt.Run(t, f)
+		})
+	}
+}
+
+func TestTwoInstances(t *testing.T) {
+	ctx := context.Background()
+	dbs, done := newDBs(t, 2)
+	defer done()
+	ttl := 300 * time.Millisecond
+	f1, err := NewFactory(dbs[0], "instance1", ttl, ttl/10)
+	if err != nil {
+		t.Fatalf("NewFactory(): %v", err)
+	}
+	f2, err := NewFactory(dbs[1], "instance2", ttl, ttl/10)
+	if err != nil {
+		t.Fatalf("NewFactory(): %v", err)
+	}
+	e1, err := f1.NewElection(ctx, "res")
+	if err != nil {
+		t.Fatalf("NewElection(): %v", err)
+	}
+	e2, err := f2.NewElection(ctx, "res")
+	if err != nil {
+		t.Fatalf("NewElection(): %v", err)
+	}
+
+	if err := e1.Await(ctx); err != nil {
+		t.Fatalf("Await(1): %v", err)
+	}
+	mctx1, err := e1.WithMastership(ctx)
+	if err != nil {
+		t.Fatalf("WithMastership(1): %v", err)
+	}
+
+	// The second instance cannot acquire the lease while it is renewed.
+	actx, cancel := context.WithTimeout(ctx, 3*ttl)
+	defer cancel()
+	if err := e2.Await(actx); err != context.DeadlineExceeded {
+		t.Fatalf("Await(2): %v, want %v", err, context.DeadlineExceeded)
+	}
+	if mctx1.Err() != nil {
+		t.Fatalf("Mastership of instance1 lost while renewing")
+	}
+
+	// After the first instance resigns, the second takes over.
+	if err := e1.Resign(ctx); err != nil {
+		t.Fatalf("Resign(1): %v", err)
+	}
+	if mctx1.Err() == nil {
+		t.Errorf("Mastership of instance1 not canceled after Resign")
+	}
+	actx, cancel = context.WithTimeout(ctx, 3*ttl)
+	defer cancel()
+	if err := e2.Await(actx); err != nil {
+		t.Fatalf("Await(2): %v", err)
+	}
+	mctx1, err = e1.WithMastership(ctx)
+	if err != nil {
+		t.Fatalf("WithMastership(1): %v", err)
+	}
+	if mctx1.Err() == nil {
+		t.Errorf("WithMastership(1) not canceled after instance2 took over")
+	}
+
+	for _, e := range []interface{ Close(context.Context) error }{e1, e2} {
+		if err := e.Close(ctx); err != nil {
+			t.Errorf("Close(): %v", err)
+		}
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	ctx := context.Background()
+	dbs, done := newDBs(t, 1)
+	defer done()
+	const ttl, skew = 3 * time.Second, 500 * time.Millisecond
+	if _, err := NewFactory(dbs[0], "instance1", ttl, ttl/3); err == nil {
+		t.Errorf("NewFactory(skew: ttl/3): nil error, want error")
+	}
+	f, err := NewFactory(dbs[0], "instance1", ttl, skew)
+	if err != nil {
+		t.Fatalf("NewFactory(): %v", err)
+	}
+	el, err := f.NewElection(ctx, "res")
+	if err != nil {
+		t.Fatalf("NewElection(): %v", err)
+	}
+	e := el.(*Election)
+
+	// A lease that expired less than skew ago may still be in use.
+	for _, tc := range []struct {
+		expired time.Duration
+		want    bool
+	}{
+		{expired: skew / 2, want: false},
+		{expired: 2 * skew, want: true},
+	} {
+		if _, err := dbs[0].Exec(`REPLACE INTO Leases (ResourceId, Holder, Expiry) VALUES (?, ?, ?);`,
+			"res", "instance2", time.Now().Add(-tc.expired).UnixNano()); err != nil {
+			t.Fatalf("REPLACE INTO Leases: %v", err)
+		}
+		_, got, err := e.acquire(ctx)
+		if err != nil {
+			t.Fatalf("acquire(): %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("acquire() with a lease expired %v ago: %v, want %v", tc.expired, got, tc.want)
+		}
+	}
+
+	// A master that cannot renew stops skew before its lease expires.
+	e.interval = time.Hour
+	expiry := time.Now().Add(skew + 100*time.Millisecond)
+	term := &term{stop: make(chan struct{}), done: make(chan struct{})}
+	go e.renew(term, expiry)
+	select {
+	case <-term.done:
+		if now := time.Now(); !now.Before(expiry) {
+			t.Errorf("term ended at %v, want before expiry %v", now, expiry)
+		}
+	case <-time.After(ttl):
+		t.Fatalf("term did not end")
+	}
+}
+
+// TestTrackers runs two mastership trackers that share a database and checks
+// that at most one of them is master for a resource at any time.
+func TestTrackers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dbs, done := newDBs(t, 2)
+	defer done()
+
+	const res = "directory"
+	trackers := make([]*tracker.Tracker, 0, len(dbs))
+	for i, db := range dbs {
+		f, err := NewFactory(db, fmt.Sprintf("instance%d", i), time.Second, 100*time.Millisecond)
+		if err != nil {
+			t.Fatalf("NewFactory(): %v", err)
+		}
+		// A short maxHold makes masters resign and hand over repeatedly.
+		mt := tracker.NewTracker(f, 200*time.Millisecond, monitoring.InertMetricFactory{})
+		go mt.Run(ctx)
+		mt.AddResource(res)
+		trackers = append(trackers, mt)
+	}
+
+	held := make(map[int]bool)
+	for end := time.Now().Add(2 * time.Second); time.Now().Before(end); time.Sleep(20 * time.Millisecond) {
+		var masters []int
+		for i, mt := range trackers {
+			mctx, err := mt.Masterships(ctx)
+			if err != nil {
+				t.Fatalf("Masterships(): %v", err)
+			}
+			if m, ok := mctx[res]; ok && m.Err() == nil {
+				masters = append(masters, i)
+				held[i] = true
+			}
+		}
+		if len(masters) > 1 {
+			t.Fatalf("Instances %v are master at the same time", masters)
+		}
+	}
+	if len(held) == 0 {
+		t.Errorf("No instance obtained mastership")
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutationstorage
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ReadEventCursor returns the highest revision of directoryID whose events
+// have been delivered, or 0 if none have.
+func (m *Mutations) ReadEventCursor(ctx context.Context, directoryID string) (int64, error) {
+	var rev int64
+	err := m.db.QueryRowContext(ctx,
+		`SELECT Revision FROM EventCursors WHERE DirectoryID = ?;`,
+		directoryID).Scan(&rev)
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, nil
+	case err != nil:
+		return 0, err
+	}
+	return rev, nil
+}
+
+// WriteEventCursor records that the events of directoryID up to and including
+// revision have been delivered.
+func (m *Mutations) WriteEventCursor(ctx context.Context, directoryID string, revision int64) error {
+	// REPLACE INTO is supported by both MySQL and SQLite.
+	_, err := m.db.ExecContext(ctx,
+		`REPLACE INTO EventCursors (DirectoryID, Revision) VALUES (?, ?);`,
+		directoryID, revision)
+	return err
+}
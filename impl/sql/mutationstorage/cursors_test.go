@@ -0,0 +1,54 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutationstorage
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestEventCursor(t *testing.T) {
+	ctx := context.Background()
+	m := newForTest(ctx, t)
+
+	for _, tc := range []struct {
+		desc        string
+		directoryID string
+		write       int64
+		want        int64
+	}{
+		{desc: "unset", directoryID: directoryID, want: 0},
+		{desc: "first", directoryID: directoryID, write: 3, want: 3},
+		{desc: "advance", directoryID: directoryID, write: 5, want: 5},
+		{desc: "other directory", directoryID: "other", want: 0},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if tc.write != 0 {
+				if err := m.WriteEventCursor(ctx, tc.directoryID, tc.write); err != nil {
+					t.Fatalf("WriteEventCursor(): %v", err)
+				}
+			}
+			got, err := m.ReadEventCursor(ctx, tc.directoryID)
+			if err != nil {
+				t.Fatalf("ReadEventCursor(): %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("ReadEventCursor(): %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -47,6 +47,21 @@ var (
 		LogID    BIGINT           NOT NULL,
 		Enabled  INTEGER          NOT NULL,
 		PRIMARY KEY(DirectoryID, LogID)
+	);`,
+		`CREATE TABLE IF NOT EXISTS Rejections (
+		DirectoryID VARCHAR(30)   NOT NULL,
+		LogID     BIGINT          NOT NULL,
+		Time      BIGINT          NOT NULL,
+		LocalID   BIGINT          NOT NULL,
+		UserIndex VARBINARY(64)   NOT NULL,
+		Revision  BIGINT          NOT NULL,
+		Reason    TEXT            NOT NULL,
+		PRIMARY KEY(DirectoryID, LogID, Time, LocalID)
+	);`,
+		`CREATE TABLE IF NOT EXISTS EventCursors (
+		DirectoryID VARCHAR(30)   NOT NULL,
+		Revision    BIGINT        NOT NULL,
+		PRIMARY KEY(DirectoryID)
 	);`,
 	}
 )
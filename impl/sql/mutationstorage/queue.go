@@ -31,19 +31,120 @@ import (
 )
 
 // AddLogs creates and adds new logs for writing to a directory.
-func (m *Mutations) AddLogs(ctx context.Context, directoryID string, logIDs ...int64) error {
+// Logs that already exist are left unchanged, so AddLogs may be retried.
+func (m *Mutations) AddLogs(ctx context.Context, directoryID string, logIDs ...int64) (ret error) {
 	glog.Infof("mutationstorage: AddLog(%v, %v)", directoryID, logIDs)
+	// MySQL and SQLite do not have the same syntax for INSERT IGNORE, so check
+	// for existing rows inside a transaction instead.
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if ret != nil {
+			if err := tx.Rollback(); err != nil {
+				ret = status.Errorf(codes.Internal, "%v, and could not rollback: %v", ret, err)
+			}
+		}
+	}()
 	for _, logID := range logIDs {
-		// TODO(gdbelvin): Use INSERT IGNORE to allow this function to be retried.
-		// TODO(gdbelvin): Migrate to a MySQL Docker image for unit tests.
-		// MySQL and SQLite do not have the same syntax for INSERT IGNORE.
-		if _, err := m.db.ExecContext(ctx,
+		var count int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM Logs WHERE DirectoryID = ? AND LogID = ?;`,
+			directoryID, logID).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
 			`INSERT INTO Logs (DirectoryID, LogID, Enabled)  Values(?, ?, ?);`,
 			directoryID, logID, true); err != nil {
 			return err
 		}
 	}
-	return nil
+	return tx.Commit()
+}
+
+// AddNextLog adds a writable log to directoryID whose ID is one more than the
+// highest existing log ID, or 1 if there are none, and returns its ID.
+func (m *Mutations) AddNextLog(ctx context.Context, directoryID string) (logID int64, ret error) {
+	// The transaction keeps concurrent calls from picking the same ID.
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if ret != nil {
+			if err := tx.Rollback(); err != nil {
+				ret = status.Errorf(codes.Internal, "%v, and could not rollback: %v", ret, err)
+			}
+		}
+	}()
+	var highest sql.NullInt64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT MAX(LogID) FROM Logs WHERE DirectoryID = ?;`,
+		directoryID).Scan(&highest); err != nil {
+		return 0, err
+	}
+	logID = highest.Int64 + 1
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO Logs (DirectoryID, LogID, Enabled)  Values(?, ?, ?);`,
+		directoryID, logID, true); err != nil {
+		return 0, err
+	}
+	glog.Infof("mutationstorage: AddNextLog(%v): %v", directoryID, logID)
+	return logID, tx.Commit()
+}
+
+// SetWritable enables or disables writes to a log. Disabled logs are still
+// returned by ListLogs(writable=false) so that they are drained by the
+// sequencer. Disabling the last writable log of a directory fails with
+// FailedPrecondition, since writes would fail.
+func (m *Mutations) SetWritable(ctx context.Context, directoryID string, logID int64, enabled bool) (ret error) {
+	glog.Infof("mutationstorage: SetWritable(%v, %v, %v)", directoryID, logID, enabled)
+	// The transaction keeps concurrent calls from disabling the last two
+	// writable logs.
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if ret != nil {
+			if err := tx.Rollback(); err != nil {
+				ret = status.Errorf(codes.Internal, "%v, and could not rollback: %v", ret, err)
+			}
+		}
+	}()
+	// RowsAffected cannot detect a missing log: MySQL only counts rows that
+	// actually changed, so disabling a disabled log would report 0.
+	var count int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM Logs WHERE DirectoryID = ? AND LogID = ?;`,
+		directoryID, logID).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return status.Errorf(codes.NotFound, "log %v not found for directory %v", logID, directoryID)
+	}
+	if !enabled {
+		var others int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM Logs WHERE DirectoryID = ? AND LogID <> ? AND Enabled = ?;`,
+			directoryID, logID, true).Scan(&others); err != nil {
+			return err
+		}
+		if others == 0 {
+			return status.Errorf(codes.FailedPrecondition,
+				"log %v is the last writable log of directory %v", logID, directoryID)
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE Logs SET Enabled = ? WHERE DirectoryID = ? AND LogID = ?;`,
+		enabled, directoryID, logID); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // Send writes mutations to the leading edge (by sequence number) of the mutations table.
@@ -75,6 +176,67 @@ func (m *Mutations) Send(ctx context.Context, directoryID string, updates ...*pb
 	return &keyserver.WriteWatermark{LogID: logID, Watermark: ts.UnixNano()}, nil
 }
 
+// PruneLogs deletes the mutations in directoryID that were included in revision
+// or earlier and were queued before the given time. The newest mutation in each
+// log is always kept so that Send continues to write increasing timestamps.
+// PruneLogs returns the number of mutations deleted.
+func (m *Mutations) PruneLogs(ctx context.Context, directoryID string, revision int64,
+	before time.Time) (_ int64, ret error) {
+	meta, err := m.ReadBatch(ctx, directoryID, revision)
+	if err == sql.ErrNoRows {
+		return 0, nil // Nothing has been sequenced yet.
+	} else if err != nil {
+		return 0, err
+	}
+	highs := make(map[int64]int64)
+	for _, source := range meta.GetSources() {
+		if highs[source.LogId] < source.HighestExclusive {
+			highs[source.LogId] = source.HighestExclusive
+		}
+	}
+
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if ret != nil {
+			if err := tx.Rollback(); err != nil {
+				ret = status.Errorf(codes.Internal, "%v, and could not rollback: %v", ret, err)
+			}
+		}
+	}()
+
+	var pruned int64
+	for logID, high := range highs {
+		var maxTime int64
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COALESCE(MAX(Time), 0) FROM Queue WHERE DirectoryID = ? AND LogID = ?;`,
+			directoryID, logID).Scan(&maxTime); err != nil {
+			return 0, status.Errorf(codes.Internal, "could not find max timestamp: %v", err)
+		}
+		cutoff := high
+		if t := before.UnixNano(); t < cutoff {
+			cutoff = t
+		}
+		if maxTime < cutoff {
+			cutoff = maxTime
+		}
+		result, err := tx.ExecContext(ctx,
+			`DELETE FROM Queue WHERE DirectoryID = ? AND LogID = ? AND Time < ?;`,
+			directoryID, logID, cutoff)
+		if err != nil {
+			return 0, status.Errorf(codes.Internal, "failed pruning queue: %v", err)
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return 0, status.Errorf(codes.Internal, "failed pruning queue: %v", err)
+		}
+		pruned += count
+	}
+	return pruned, tx.Commit()
+}
+
 // ListLogs returns a list of all logs for directoryID, optionally filtered for writable logs.
 func (m *Mutations) ListLogs(ctx context.Context, directoryID string, writable bool) ([]int64, error) {
 	var query string
@@ -193,7 +355,7 @@ func (m *Mutations) ReadLog(ctx context.Context, directoryID string,
 		return nil, err
 	}
 	defer rows.Close()
-	msgs, err := readQueueMessages(rows)
+	msgs, err := readQueueMessages(logID, rows)
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +372,7 @@ func (m *Mutations) ReadLog(ctx context.Context, directoryID string,
 			return nil, err
 		}
 		defer restRows.Close()
-		rest, err := readQueueMessages(restRows)
+		rest, err := readQueueMessages(logID, restRows)
 		if err != nil {
 			return nil, err
 		}
@@ -220,7 +382,7 @@ func (m *Mutations) ReadLog(ctx context.Context, directoryID string,
 	return msgs, nil
 }
 
-func readQueueMessages(rows *sql.Rows) ([]*mutator.LogMessage, error) {
+func readQueueMessages(logID int64, rows *sql.Rows) ([]*mutator.LogMessage, error) {
 	results := make([]*mutator.LogMessage, 0)
 	for rows.Next() {
 		var timestamp, localID int64
@@ -233,10 +395,12 @@ func readQueueMessages(rows *sql.Rows) ([]*mutator.LogMessage, error) {
 			return nil, err
 		}
 		results = append(results, &mutator.LogMessage{
+			LogID:     logID,
 			ID:        timestamp,
 			LocalID:   localID,
 			Mutation:  entryUpdate.Mutation,
 			ExtraData: entryUpdate.Committed,
+			UserID:    entryUpdate.UserId,
 		})
 	}
 	if err := rows.Err(); err != nil {
@@ -17,6 +17,7 @@ package mutationstorage
 import (
 	"context"
 	"fmt"
+	"sort"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	pb "github.com/google/keytransparency/core/api/v1/keytransparency_go_proto"
+	spb "github.com/google/keytransparency/core/sequencer/sequencer_go_proto"
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -224,3 +226,132 @@ func TestReadLog(t *testing.T) {
 		}
 	}
 }
+
+func TestPruneLogs(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		desc     string
+		revision int64
+		before   int64
+		want     int64
+	}{
+		{desc: "all published", revision: 1, before: 100, want: 4},
+		{desc: "retention", revision: 1, before: 15, want: 2},
+		{desc: "unsequenced", revision: 2, before: 100, want: 0},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			m := newForTest(ctx, t, 1, 2)
+			for logID, times := range map[int64][]int64{1: {10, 20, 30, 40}, 2: {10, 20}} {
+				for _, ts := range times {
+					if err := m.send(ctx, time.Unix(0, ts), directoryID, logID, []byte("mutation")); err != nil {
+						t.Fatalf("send(): %v", err)
+					}
+				}
+			}
+			if err := m.WriteBatchSources(ctx, directoryID, 1, &spb.MapMetadata{
+				Sources: []*spb.MapMetadata_SourceSlice{
+					{LogId: 1, HighestExclusive: 35},
+					{LogId: 2, HighestExclusive: 25},
+				}}); err != nil {
+				t.Fatalf("WriteBatchSources(): %v", err)
+			}
+
+			pruned, err := m.PruneLogs(ctx, directoryID, tc.revision, time.Unix(0, tc.before))
+			if err != nil {
+				t.Fatalf("PruneLogs(): %v", err)
+			}
+			if pruned != tc.want {
+				t.Errorf("PruneLogs(): %v, want %v", pruned, tc.want)
+			}
+			// Pruning is idempotent.
+			if pruned, err := m.PruneLogs(ctx, directoryID, tc.revision, time.Unix(0, tc.before)); err != nil || pruned != 0 {
+				t.Errorf("PruneLogs(): %v, %v, want 0, nil", pruned, err)
+			}
+		})
+	}
+}
+
+func TestAddLogsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	m := newForTest(ctx, t, 1, 2)
+	if err := m.SetWritable(ctx, directoryID, 2, false); err != nil {
+		t.Fatalf("SetWritable(): %v", err)
+	}
+	// Retrying AddLogs succeeds and does not re-enable disabled logs.
+	if err := m.AddLogs(ctx, directoryID, 1, 2, 3); err != nil {
+		t.Fatalf("AddLogs(): %v", err)
+	}
+	for _, tc := range []struct {
+		writable bool
+		want     []int64
+	}{
+		{writable: false, want: []int64{1, 2, 3}},
+		{writable: true, want: []int64{1, 3}},
+	} {
+		got, err := m.ListLogs(ctx, directoryID, tc.writable)
+		if err != nil {
+			t.Fatalf("ListLogs(): %v", err)
+		}
+		sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+		if !cmp.Equal(got, tc.want) {
+			t.Errorf("ListLogs(writable: %v): %v, want %v", tc.writable, got, tc.want)
+		}
+	}
+}
+
+func TestSetWritable(t *testing.T) {
+	ctx := context.Background()
+	m := newForTest(ctx, t, 1, 2)
+	for _, tc := range []struct {
+		desc     string
+		logID    int64
+		enabled  bool
+		wantCode codes.Code
+		wantLogs []int64
+	}{
+		{desc: "disable", logID: 1, wantLogs: []int64{2}},
+		{desc: "disable again", logID: 1, wantLogs: []int64{2}},
+		{desc: "enable", logID: 1, enabled: true, wantLogs: []int64{1, 2}},
+		{desc: "not found", logID: 3, wantCode: codes.NotFound, wantLogs: []int64{1, 2}},
+		{desc: "disable other", logID: 2, wantLogs: []int64{1}},
+		{desc: "disable last", logID: 1, wantCode: codes.FailedPrecondition, wantLogs: []int64{1}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := m.SetWritable(ctx, directoryID, tc.logID, tc.enabled)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Errorf("SetWritable(): %v, want %v", err, want)
+			}
+			got, err := m.ListLogs(ctx, directoryID, true)
+			if err != nil {
+				t.Fatalf("ListLogs(): %v", err)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if !cmp.Equal(got, tc.wantLogs) {
+				t.Errorf("ListLogs(writable): %v, want %v", got, tc.wantLogs)
+			}
+		})
+	}
+}
+
+func TestAddNextLog(t *testing.T) {
+	ctx := context.Background()
+	m := newForTest(ctx, t)
+	for _, want := range []int64{1, 2, 3} {
+		got, err := m.AddNextLog(ctx, directoryID)
+		if err != nil {
+			t.Fatalf("AddNextLog(): %v", err)
+		}
+		if got != want {
+			t.Errorf("AddNextLog(): %v, want %v", got, want)
+		}
+	}
+	if err := m.AddLogs(ctx, directoryID, 10); err != nil {
+		t.Fatalf("AddLogs(): %v", err)
+	}
+	if got, err := m.AddNextLog(ctx, directoryID); err != nil || got != 11 {
+		t.Errorf("AddNextLog(): %v, %v, want 11", got, err)
+	}
+	if got, err := m.AddNextLog(ctx, "otherdirectory"); err != nil || got != 1 {
+		t.Errorf("AddNextLog(otherdirectory): %v, %v, want 1", got, err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutationstorage
+
+import (
+	"context"
+
+	"github.com/google/keytransparency/core/mutator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WriteRejections saves rejections for directoryID.
+// Rejections that have already been written are overwritten.
+func (m *Mutations) WriteRejections(ctx context.Context, directoryID string,
+	rejections []*mutator.Rejection) (ret error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if ret != nil {
+			if err := tx.Rollback(); err != nil {
+				ret = status.Errorf(codes.Internal, "%v, and could not rollback: %v", ret, err)
+			}
+		}
+	}()
+
+	for _, r := range rejections {
+		// REPLACE INTO is supported by both MySQL and SQLite.
+		if _, err := tx.ExecContext(ctx,
+			`REPLACE INTO Rejections (DirectoryID, LogID, Time, LocalID, UserIndex, Revision, Reason)
+			VALUES (?, ?, ?, ?, ?, ?, ?);`,
+			directoryID, r.LogID, r.ID, r.LocalID, r.Index, r.Revision, r.Reason); err != nil {
+			return status.Errorf(codes.Internal, "failed inserting into rejections: %v", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ReadRejections returns up to limit of the most recent rejections for index.
+func (m *Mutations) ReadRejections(ctx context.Context, directoryID string,
+	index []byte, limit int32) ([]*mutator.Rejection, error) {
+	rows, err := m.db.QueryContext(ctx,
+		`SELECT LogID, Time, LocalID, UserIndex, Revision, Reason FROM Rejections
+		WHERE DirectoryID = ? AND UserIndex = ?
+		ORDER BY Time DESC, LocalID DESC
+		LIMIT ?;`,
+		directoryID, index, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rejections := make([]*mutator.Rejection, 0)
+	for rows.Next() {
+		var r mutator.Rejection
+		if err := rows.Scan(&r.LogID, &r.ID, &r.LocalID, &r.Index, &r.Revision, &r.Reason); err != nil {
+			return nil, err
+		}
+		rejections = append(rejections, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rejections, nil
+}
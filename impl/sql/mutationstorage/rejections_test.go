@@ -0,0 +1,71 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutationstorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/keytransparency/core/mutator"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestRejections(t *testing.T) {
+	ctx := context.Background()
+	m := newForTest(ctx, t)
+	userA, userB := []byte("A"), []byte("B")
+	rejections := []*mutator.Rejection{
+		{LogID: 1, ID: 10, LocalID: 0, Index: userA, Revision: 1, Reason: mutator.ErrReplay.Error()},
+		{LogID: 1, ID: 10, LocalID: 1, Index: userB, Revision: 1, Reason: mutator.ErrUnauthorized.Error()},
+		{LogID: 2, ID: 20, LocalID: 0, Index: userA, Revision: 2, Reason: mutator.ErrPreviousHash.Error()},
+	}
+	if err := m.WriteRejections(ctx, directoryID, rejections); err != nil {
+		t.Fatalf("WriteRejections(): %v", err)
+	}
+	// Writing the same rejections again is not an error.
+	if err := m.WriteRejections(ctx, directoryID, rejections[2:]); err != nil {
+		t.Fatalf("WriteRejections(): %v", err)
+	}
+
+	for _, tc := range []struct {
+		desc        string
+		directoryID string
+		index       []byte
+		limit       int32
+		want        []*mutator.Rejection
+	}{
+		{desc: "newest first", directoryID: directoryID, index: userA, limit: 10,
+			want: []*mutator.Rejection{rejections[2], rejections[0]}},
+		{desc: "limit", directoryID: directoryID, index: userA, limit: 1,
+			want: []*mutator.Rejection{rejections[2]}},
+		{desc: "other user", directoryID: directoryID, index: userB, limit: 10,
+			want: []*mutator.Rejection{rejections[1]}},
+		{desc: "other directory", directoryID: "other", index: userA, limit: 10,
+			want: []*mutator.Rejection{}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := m.ReadRejections(ctx, tc.directoryID, tc.index, tc.limit)
+			if err != nil {
+				t.Fatalf("ReadRejections(): %v", err)
+			}
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("ReadRejections(): diff(-got, +want): %v", cmp.Diff(got, tc.want))
+			}
+		})
+	}
+}